@@ -14,12 +14,25 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/williamkoller/system-education/config"
+	audit_router "github.com/williamkoller/system-education/internal/audit/presentation/router"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	infra_cryptography "github.com/williamkoller/system-education/internal/auth/infra/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
 	auth_router "github.com/williamkoller/system-education/internal/auth/presentation/router"
+	eventstore_router "github.com/williamkoller/system-education/internal/eventstore/presentation/router"
+	permission_middleware "github.com/williamkoller/system-education/internal/permission/presentation/middleware"
 	permission_router "github.com/williamkoller/system-education/internal/permission/presentation/router"
+	role_router "github.com/williamkoller/system-education/internal/role/presentation/router"
 	school_router "github.com/williamkoller/system-education/internal/school/presentation/router"
 	student_router "github.com/williamkoller/system-education/internal/student/presentation/router"
 	user_router "github.com/williamkoller/system-education/internal/user/presentation/router"
+	webhook_router "github.com/williamkoller/system-education/internal/webhook/presentation/router"
+	"github.com/williamkoller/system-education/pkg/logger"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_broker "github.com/williamkoller/system-education/shared/infra/broker"
+	shared_outbox "github.com/williamkoller/system-education/shared/infra/outbox"
 	"github.com/williamkoller/system-education/shared/middleware"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -29,18 +42,62 @@ func main() {
 		log.Fatalf("Error loading config: %v", err)
 	}
 
+	if err := logger.InitLogger(loggerMode(cfg.App.Env)); err != nil {
+		log.Fatalf("Error initializing logger: %v", err)
+	}
+	defer logger.Sync()
+
+	tokenManager, err := infra_cryptography.LoadJWTTokenManager(cfg.Secret, cfg.SigningKeys, cfg.ActiveKeyID, cfg.Issuer, cfg.Audience, cfg.ExpiresIn)
+	if err != nil {
+		log.Fatalf("Error loading signing keys: %v", err)
+	}
+
 	database := config.NewDatabaseConnection()
 	config.RunMigrations(database, "")
 
+	if cfg.Bootstrap.Enabled() {
+		bootstrapAdmin(database, cfg.Bootstrap)
+	}
+
 	g := gin.Default()
+	// ClientIP keys the login throttle, so only configured proxies may set it.
+	if err := g.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Fatalf("Error setting trusted proxies: %v", err)
+	}
 	g.Use(gin.Recovery())
+	g.Use(middleware.RequestIDMiddleware())
 	g.Use(middleware.GlobalErrorHandler())
 	g.Use(middleware.CORSMiddleware())
-	user_router.UserRouter(g, database, cfg.Resend.ApiKey, cfg.Resend.FromAddress, cfg.Secret, cfg.ExpiresIn)
-	auth_router.AuthRouter(g, database, cfg.Secret, cfg.ExpiresIn)
-	permission_router.PermissionRouter(g, database, cfg.Secret, cfg.ExpiresIn)
-	school_router.SchoolRouter(g, database, cfg.Secret, cfg.ExpiresIn)
-	student_router.StudentRouter(g, database, cfg.Secret, cfg.ExpiresIn)
+
+	apiKeys := auth_router.NewAPIKeyAuthenticator(database, cfg.MFA.RequiredModules)
+	dispatcher := shared_event.NewDispatcher(cfg.Dispatcher.Workers, cfg.Dispatcher.QueueSize)
+	relay := shared_outbox.NewRelay(database, dispatcher, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize, cfg.Outbox.MaxAttempts, cfg.Outbox.RetryBackoff)
+	grants := auth_router.NewGrantRefresher(database, relay, cfg.Authorization.Live(), cfg.Authorization.CacheTTL, cfg.MFA.RequiredModules)
+	permissions := permission_middleware.NewPermissionMiddleware(grants, cfg.Authorization.Explain)
+
+	// Without a broker, broker stays nil and the modules publish nothing.
+	var publisher shared_broker.Publisher
+	var broker *shared_broker.EventPublisher
+	if cfg.Broker.Enabled() {
+		publisher, err = newPublisher(cfg.Broker)
+		if err != nil {
+			log.Fatalf("Error connecting to the %s broker: %v", cfg.Broker.Driver, err)
+		}
+		topics := shared_broker.NewTopicMapper(cfg.Broker.TopicPrefix, cfg.Broker.Topics)
+		broker = shared_broker.NewEventPublisher(publisher, topics, cfg.Broker.Source, cfg.Broker.Events)
+	}
+
+	user_router.UserRouter(g, database, cfg.Resend.ApiKey, cfg.Resend.FromAddress, cfg.EmailVerification.URL, cfg.EmailVerification.ExpiresIn, tokenManager, apiKeys, permissions, relay, broker)
+	auth_router.AuthRouter(g, database, tokenManager, tokenManager, cfg.RefreshExpiresIn, cfg.Resend.ApiKey, cfg.Resend.FromAddress, cfg.PasswordReset.URL, cfg.PasswordReset.ExpiresIn, cfg.EmailVerification.URL, cfg.EmailVerification.ExpiresIn, cfg.EmailVerification.Required, cfg.MFA.Issuer, cfg.MFA.RequiredModules, permissions, grants)
+	permission_router.PermissionRouter(g, database, tokenManager, apiKeys, permissions, relay, broker)
+	role_router.RoleRouter(g, database, tokenManager, apiKeys, permissions, relay, broker)
+	school_router.SchoolRouter(g, database, tokenManager, apiKeys, permissions, relay, broker)
+	student_router.StudentRouter(g, database, tokenManager, apiKeys, permissions, relay, broker)
+	audit_router.AuditRouter(g, database, tokenManager, apiKeys, permissions)
+	eventstore_router.EventStoreRouter(g, database, tokenManager, apiKeys, permissions)
+
+	webhooks := webhook_router.NewDeliveryUsecase(database, cfg.Webhook.PollInterval, cfg.Webhook.Timeout, cfg.Webhook.MaxAttempts, cfg.Webhook.RetryBackoff, cfg.Webhook.AllowPrivateNetworks)
+	webhook_router.WebhookRouter(g, database, tokenManager, apiKeys, permissions, relay, webhooks, cfg.Webhook.AllowPrivateNetworks)
 
 	address := ":" + strconv.Itoa(cfg.App.Port)
 	srv := &http.Server{
@@ -49,6 +106,23 @@ func main() {
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
+	// Handlers are all subscribed by now, so nothing is relayed before
+	// someone is listening for it.
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		relay.Run(relayCtx)
+		close(relayDone)
+	}()
+	webhooksDone := make(chan struct{})
+	go func() {
+		webhooks.Run(relayCtx)
+		close(webhooksDone)
+	}()
+
+	go purgeRevokedTokens(relayCtx, auth_router.NewTokenRevocationPurger(database), revocationPurgeInterval)
+	go logDispatchMetrics(relayCtx, dispatcher, cfg.Dispatcher.MetricsInterval)
+
 	log.Println("Server running at http://localhost:8080")
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -71,5 +145,91 @@ func main() {
 		log.Println("Server Shutdown: ", err)
 	}
 
+	stopRelay()
+	<-relayDone
+	<-webhooksDone
+
+	// The server may have used up ctx, so draining gets a deadline of its own.
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.Dispatcher.DrainTimeout)
+	defer cancelDrain()
+	if err := dispatcher.Shutdown(drainCtx); err != nil {
+		log.Println("Event dispatcher shutdown: ", err)
+	}
+	logMetrics(dispatcher)
+	if publisher != nil {
+		if err := publisher.Close(); err != nil {
+			log.Println("Broker close: ", err)
+		}
+	}
+
 	log.Println("Server exiting")
 }
+
+// revocationPurgeInterval is how often revoked tokens past their expiry are
+// deleted; by then the expiry alone rejects them.
+const revocationPurgeInterval = time.Hour
+
+func purgeRevokedTokens(ctx context.Context, purger port_auth_repository.TokenRevocationPurger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if purged, err := purger.PurgeExpired(ctx, time.Now()); err != nil {
+			log.Println("Purging revoked tokens: ", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d expired revoked tokens", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// logDispatchMetrics logs the dispatcher's counters every interval until ctx
+// ends. They are cumulative since the process started.
+func logDispatchMetrics(ctx context.Context, dispatcher *shared_event.Dispatcher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			logMetrics(dispatcher)
+		}
+	}
+}
+
+func logMetrics(dispatcher *shared_event.Dispatcher) {
+	for name, m := range dispatcher.Metrics() {
+		logger.Info("event dispatch metrics", "event", name, "dispatched", m.Dispatched, "succeeded", m.Succeeded, "failed", m.Failed, "panicked", m.Panicked, "total_duration", m.TotalDuration)
+	}
+}
+
+func loggerMode(env string) string {
+	if env == "development" {
+		return "dev"
+	}
+	return env
+}
+
+func newPublisher(cfg config.BrokerConfiguration) (shared_broker.Publisher, error) {
+	if cfg.Driver == config.BrokerDriverAMQP {
+		return shared_broker.NewAMQPPublisher(cfg.URL, cfg.Exchange, cfg.Timeout)
+	}
+	return shared_broker.NewNATSPublisher(cfg.URL, cfg.Timeout)
+}
+
+func bootstrapAdmin(database *gorm.DB, cfg config.BootstrapConfiguration) {
+	admin, err := auth_router.NewBootstrapUsecase(database).Run(context.Background(), cfg.Name, cfg.Email, cfg.Password)
+	switch {
+	case errors.Is(err, auth_entity.ErrAlreadyBootstrapped):
+		log.Println("Bootstrap admin skipped: permissions already exist")
+	case err != nil:
+		log.Fatalf("Error bootstrapping admin: %v", err)
+	default:
+		log.Printf("Bootstrap admin created: %s", admin.Email)
+	}
+}
@@ -0,0 +1,170 @@
+// Command replay re-dispatches stored domain events to a single handler, for
+// instance to backfill a new webhook subscription or a broker consumer:
+//
+//	replay -handler webhooks -event student.enrolled -from 2026-01-01T00:00:00Z
+//	replay -handler broker -event school.created -after 1200
+//
+// Each handler only receives the events it is sent live: webhooks the ones
+// subscriptions can ask for, broker the ones in BROKER_EVENTS. stdout prints
+// the events of every module.
+// When a replay stops early it prints the position to pass to -after.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/williamkoller/system-education/config"
+	eventstore_router "github.com/williamkoller/system-education/internal/eventstore/presentation/router"
+	permission_router "github.com/williamkoller/system-education/internal/permission/presentation/router"
+	role_router "github.com/williamkoller/system-education/internal/role/presentation/router"
+	school_router "github.com/williamkoller/system-education/internal/school/presentation/router"
+	student_router "github.com/williamkoller/system-education/internal/student/presentation/router"
+	user_router "github.com/williamkoller/system-education/internal/user/presentation/router"
+	webhook_router "github.com/williamkoller/system-education/internal/webhook/presentation/router"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_broker "github.com/williamkoller/system-education/shared/infra/broker"
+)
+
+func main() {
+	handlerName := flag.String("handler", "", "where to replay the events: webhooks, broker or stdout")
+	aggregateID := flag.String("aggregate", "", "only events of this aggregate id")
+	eventName := flag.String("event", "", "only events with this name")
+	from := flag.String("from", "", "only events that occurred at or after this RFC 3339 time")
+	to := flag.String("to", "", "only events that occurred before this RFC 3339 time")
+	after := flag.Int64("after", 0, "only events recorded after this position")
+	flag.Parse()
+
+	filter, err := buildFilter(*aggregateID, *eventName, *from, *to, *after)
+	if err != nil {
+		log.Fatalf("Invalid filter: %v", err)
+	}
+
+	_ = godotenv.Load()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+	database := config.NewDatabaseConnection()
+
+	var handler shared_event.DeliveryHandler
+	var prototypes []shared_event.Event
+	switch *handlerName {
+	case "webhooks":
+		// Enqueue is keyed by event id, so events already delivered to a
+		// subscription are not sent to it again.
+		deliveries := webhook_router.NewDeliveryUsecase(database, cfg.Webhook.PollInterval, cfg.Webhook.Timeout, cfg.Webhook.MaxAttempts, cfg.Webhook.RetryBackoff, cfg.Webhook.AllowPrivateNetworks)
+		handler = deliveries.Enqueue
+		prototypes = webhook_router.Events
+	case "broker":
+		if !cfg.Broker.Enabled() {
+			log.Fatalf("No broker configured, set BROKER_DRIVER")
+		}
+		publisher, err := newPublisher(cfg.Broker)
+		if err != nil {
+			log.Fatalf("Error connecting to the %s broker: %v", cfg.Broker.Driver, err)
+		}
+		defer publisher.Close()
+		// The outbox id is the CloudEvent id, so consumers drop the events
+		// they already received.
+		topics := shared_broker.NewTopicMapper(cfg.Broker.TopicPrefix, cfg.Broker.Topics)
+		handler = shared_broker.NewEventPublisher(publisher, topics, cfg.Broker.Source, cfg.Broker.Events).Publish
+		prototypes = named(catalogue(), cfg.Broker.Events)
+	case "stdout":
+		handler = printEvent(os.Stdout)
+		prototypes = catalogue()
+	default:
+		log.Fatalf("Unknown handler %q, use webhooks, broker or stdout", *handlerName)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	replayed, err := eventstore_router.NewEventStoreUsecase(database).Replay(ctx, filter, prototypes, handler)
+	if err != nil {
+		log.Fatalf("Replayed %d events: %v", replayed, err)
+	}
+	log.Printf("Replayed %d events", replayed)
+}
+
+// catalogue lists the events every module writes to the outbox, once each.
+func catalogue() []shared_event.Event {
+	var events []shared_event.Event
+	for _, module := range [][]shared_event.Event{
+		user_router.Events,
+		school_router.Events,
+		student_router.Events,
+		permission_router.Events,
+		role_router.Events,
+		webhook_router.Events,
+	} {
+		for _, event := range module {
+			if !slices.ContainsFunc(events, func(e shared_event.Event) bool { return e.EventName() == event.EventName() }) {
+				events = append(events, event)
+			}
+		}
+	}
+	return events
+}
+
+// named keeps the events whose name is in names.
+func named(events []shared_event.Event, names []string) []shared_event.Event {
+	var kept []shared_event.Event
+	for _, event := range events {
+		if slices.Contains(names, event.EventName()) {
+			kept = append(kept, event)
+		}
+	}
+	return kept
+}
+
+func newPublisher(cfg config.BrokerConfiguration) (shared_broker.Publisher, error) {
+	if cfg.Driver == config.BrokerDriverAMQP {
+		return shared_broker.NewAMQPPublisher(cfg.URL, cfg.Exchange, cfg.Timeout)
+	}
+	return shared_broker.NewNATSPublisher(cfg.URL, cfg.Timeout)
+}
+
+func buildFilter(aggregateID, eventName, from, to string, after int64) (shared_event.EventFilter, error) {
+	filter := shared_event.EventFilter{AggregateID: aggregateID, EventName: eventName, AfterPosition: after}
+	if after < 0 {
+		return filter, fmt.Errorf("-after must not be negative")
+	}
+	if from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, fmt.Errorf("-from: %w", err)
+		}
+		filter.From = &t
+	}
+	if to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, fmt.Errorf("-to: %w", err)
+		}
+		filter.To = &t
+	}
+	return filter, nil
+}
+
+// printEvent writes each event as a JSON line.
+func printEvent(w io.Writer) shared_event.DeliveryHandler {
+	encoder := json.NewEncoder(w)
+	return func(ctx context.Context, event shared_event.Event) error {
+		return encoder.Encode(map[string]any{
+			"id":    shared_event.EventIDFrom(ctx),
+			"event": event.EventName(),
+			"data":  event,
+		})
+	}
+}
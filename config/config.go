@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,18 +20,155 @@ type AppConfiguration struct {
 	Port    int
 	AppName string
 	Env     string
+	// TrustedProxies are the addresses whose X-Forwarded-For header is
+	// believed when resolving the client IP. None are trusted by default.
+	TrustedProxies []string
 }
 
 type Config struct {
-	Database  DatabaseConfiguration
-	App       AppConfiguration
-	Resend    ResendConfiguration
-	Secret    string
+	Database          DatabaseConfiguration
+	App               AppConfiguration
+	Resend            ResendConfiguration
+	Secret            string
+	SigningKeys       map[string]string
+	ActiveKeyID       string
+	Issuer            string
+	Audience          string
+	ExpiresIn         time.Duration
+	RefreshExpiresIn  time.Duration
+	PasswordReset     PasswordResetConfiguration
+	EmailVerification EmailVerificationConfiguration
+	MFA               MFAConfiguration
+	Bootstrap         BootstrapConfiguration
+	Authorization     AuthorizationConfiguration
+	Outbox            OutboxConfiguration
+	Webhook           WebhookConfiguration
+	Dispatcher        DispatcherConfiguration
+	Broker            BrokerConfiguration
+}
+
+const (
+	AuthorizationModeToken = "token"
+	AuthorizationModeLive  = "live"
+)
+
+// AuthorizationConfiguration selects where route checks read grants from:
+// the access token (token) or the database through a cache that permission
+// and role changes invalidate (live). Invalidation only reaches the instance
+// that relays the change, so CacheTTL bounds how long the others lag.
+// Explain adds the reasons behind a denial to 403 responses; it discloses the
+// caller's grants, so it is only on by default in development.
+type AuthorizationConfiguration struct {
+	Mode     string
+	CacheTTL time.Duration
+	Explain  bool
+}
+
+func (a AuthorizationConfiguration) Live() bool {
+	return a.Mode == AuthorizationModeLive
+}
+
+// OutboxConfiguration tunes the relay that delivers domain events written to
+// the outbox. A failing message is retried after RetryBackoff, doubling each
+// time, until it has been attempted MaxAttempts times.
+type OutboxConfiguration struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	RetryBackoff time.Duration
+}
+
+// DispatcherConfiguration sizes the worker pool that runs event handlers.
+// Each worker has its own queue of QueueSize events. Its metrics are logged
+// every MetricsInterval, and queued events get DrainTimeout to be handled on
+// shutdown.
+type DispatcherConfiguration struct {
+	Workers         int
+	QueueSize       int
+	MetricsInterval time.Duration
+	DrainTimeout    time.Duration
+}
+
+const (
+	BrokerDriverNATS = "nats"
+	BrokerDriverAMQP = "amqp"
+)
+
+// BrokerConfiguration selects the message broker domain events are published
+// to; an empty Driver publishes nothing. Events go to TopicPrefix followed by
+// the event name unless Topics maps the name to another topic. Exchange is
+// only used by AMQP. Only the event names in Events are published.
+type BrokerConfiguration struct {
+	Driver      string
+	URL         string
+	Exchange    string
+	Source      string
+	TopicPrefix string
+	Topics      map[string]string
+	Events      []string
+	Timeout     time.Duration
+}
+
+// defaultBrokerEvents are the events whose payload is a fixed set of typed
+// fields other services can rely on. student.updated carries free-form
+// changes, and permission and role events describe internal authorization.
+var defaultBrokerEvents = []string{
+	"user.created",
+	"school.created",
+	"school.updated",
+	"student.enrolled",
+	"student.deactivated",
+	"student.transferred",
+	"student.deleted",
+}
+
+func (b BrokerConfiguration) Enabled() bool {
+	return b.Driver != ""
+}
+
+// WebhookConfiguration tunes the worker that POSTs deliveries to webhook
+// subscribers. Timeout bounds a single request. AllowPrivateNetworks lets
+// subscribers point at loopback, private and link-local addresses, such as a
+// receiver on localhost during development.
+type WebhookConfiguration struct {
+	PollInterval         time.Duration
+	Timeout              time.Duration
+	MaxAttempts          int
+	RetryBackoff         time.Duration
+	AllowPrivateNetworks bool
+}
+
+// BootstrapConfiguration describes the first administrator created on a fresh
+// deployment. It is ignored once any permission exists.
+type BootstrapConfiguration struct {
+	Name     string
+	Email    string
+	Password string
+}
+
+func (b BootstrapConfiguration) Enabled() bool {
+	return b.Email != "" && b.Password != ""
+}
+
+type EmailVerificationConfiguration struct {
+	URL       string
+	ExpiresIn time.Duration
+	// Required makes login refuse accounts whose e-mail is not verified yet.
+	Required bool
+}
+
+type MFAConfiguration struct {
+	Issuer          string
+	RequiredModules []string
+}
+
+type PasswordResetConfiguration struct {
+	URL       string
 	ExpiresIn time.Duration
 }
 
 type ResendConfiguration struct {
-	ApiKey string
+	ApiKey      string
 	FromAddress string
 }
 
@@ -47,14 +185,77 @@ func LoadConfig() (*Config, error) {
 
 	resend := loadResend()
 	secret := loadSecret()
-	expiresIn := loadTimeDuration()
+
+	signingKeys, err := loadSigningKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresIn, err := getEnvDuration("JWT_EXPIRES_IN", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshExpiresIn, err := getEnvDuration("JWT_REFRESH_EXPIRES_IN", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordReset, err := loadPasswordReset()
+	if err != nil {
+		return nil, err
+	}
+
+	emailVerification, err := loadEmailVerification()
+	if err != nil {
+		return nil, err
+	}
+
+	authorization, err := loadAuthorization(appCfg.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox, err := loadOutbox()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := loadWebhook()
+	if err != nil {
+		return nil, err
+	}
+
+	dispatcher, err := loadDispatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	broker, err := loadBroker()
+	if err != nil {
+		return nil, err
+	}
 
 	return &Config{
-		Database:  *dbCfg,
-		App:       *appCfg,
-		Resend:    resend,
-		Secret:    secret,
-		ExpiresIn: expiresIn,
+		Database:          *dbCfg,
+		App:               *appCfg,
+		Resend:            resend,
+		Secret:            secret,
+		SigningKeys:       signingKeys,
+		ActiveKeyID:       getEnv("JWT_ACTIVE_KEY_ID", ""),
+		Issuer:            getEnv("JWT_ISSUER", "system-education"),
+		Audience:          getEnv("JWT_AUDIENCE", "system-education"),
+		ExpiresIn:         expiresIn,
+		RefreshExpiresIn:  refreshExpiresIn,
+		PasswordReset:     passwordReset,
+		EmailVerification: emailVerification,
+		MFA:               loadMFA(),
+		Bootstrap:         loadBootstrap(),
+		Authorization:     authorization,
+		Outbox:            outbox,
+		Webhook:           webhook,
+		Dispatcher:        dispatcher,
+		Broker:            broker,
 	}, nil
 }
 
@@ -88,14 +289,26 @@ func loadAppConfiguration() (*AppConfiguration, error) {
 	}
 
 	cfg := &AppConfiguration{
-		Port:    port,
-		AppName: getEnv("APP_NAME", "myapp"),
-		Env:     getEnv("APP_ENV", "development"),
+		Port:           port,
+		AppName:        getEnv("APP_NAME", "myapp"),
+		Env:            getEnv("APP_ENV", "development"),
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 	}
 
 	return cfg, nil
 }
 
+// getEnvList splits a comma separated variable, dropping empty entries.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -105,15 +318,287 @@ func getEnv(key, fallback string) string {
 
 func loadResend() ResendConfiguration {
 	return ResendConfiguration{
-		ApiKey:       getEnv("RESEND_API_KEY", ""),
-		FromAddress:  getEnv("RESEND_FROM_ADDRESS", ""),
+		ApiKey:      getEnv("RESEND_API_KEY", ""),
+		FromAddress: getEnv("RESEND_FROM_ADDRESS", ""),
+	}
+}
+
+func loadPasswordReset() (PasswordResetConfiguration, error) {
+	expiresIn, err := getEnvDuration("PASSWORD_RESET_EXPIRES_IN", 30*time.Minute)
+	if err != nil {
+		return PasswordResetConfiguration{}, err
+	}
+
+	return PasswordResetConfiguration{
+		URL:       getEnv("PASSWORD_RESET_URL", "https://systemeducation.com/reset-password"),
+		ExpiresIn: expiresIn,
+	}, nil
+}
+
+func loadEmailVerification() (EmailVerificationConfiguration, error) {
+	expiresIn, err := getEnvDuration("EMAIL_VERIFICATION_EXPIRES_IN", 24*time.Hour)
+	if err != nil {
+		return EmailVerificationConfiguration{}, err
+	}
+
+	required, err := getEnvBool("EMAIL_VERIFICATION_REQUIRED", false)
+	if err != nil {
+		return EmailVerificationConfiguration{}, err
+	}
+
+	return EmailVerificationConfiguration{
+		URL:       getEnv("EMAIL_VERIFICATION_URL", "https://systemeducation.com/verify-email"),
+		ExpiresIn: expiresIn,
+		Required:  required,
+	}, nil
+}
+
+func loadBootstrap() BootstrapConfiguration {
+	return BootstrapConfiguration{
+		Name:     getEnv("BOOTSTRAP_ADMIN_NAME", "Admin"),
+		Email:    getEnv("BOOTSTRAP_ADMIN_EMAIL", ""),
+		Password: getEnv("BOOTSTRAP_ADMIN_PASSWORD", ""),
+	}
+}
+
+func loadAuthorization(env string) (AuthorizationConfiguration, error) {
+	mode := getEnv("AUTHZ_MODE", AuthorizationModeToken)
+	if mode != AuthorizationModeToken && mode != AuthorizationModeLive {
+		return AuthorizationConfiguration{}, fmt.Errorf("AUTHZ_MODE inválida: %q", mode)
+	}
+
+	cacheTTL, err := getEnvDuration("AUTHZ_CACHE_TTL", 30*time.Second)
+	if err != nil {
+		return AuthorizationConfiguration{}, err
+	}
+
+	explain, err := getEnvBool("AUTHZ_EXPLAIN", env == "development")
+	if err != nil {
+		return AuthorizationConfiguration{}, err
 	}
+
+	return AuthorizationConfiguration{Mode: mode, CacheTTL: cacheTTL, Explain: explain}, nil
+}
+
+func loadOutbox() (OutboxConfiguration, error) {
+	pollInterval, err := getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return OutboxConfiguration{}, err
+	}
+
+	batchSize, err := getEnvInt("OUTBOX_BATCH_SIZE", 100)
+	if err != nil {
+		return OutboxConfiguration{}, err
+	}
+
+	maxAttempts, err := getEnvInt("OUTBOX_MAX_ATTEMPTS", 10)
+	if err != nil {
+		return OutboxConfiguration{}, err
+	}
+
+	retryBackoff, err := getEnvDuration("OUTBOX_RETRY_BACKOFF", time.Second)
+	if err != nil {
+		return OutboxConfiguration{}, err
+	}
+
+	return OutboxConfiguration{
+		PollInterval: pollInterval,
+		BatchSize:    batchSize,
+		MaxAttempts:  maxAttempts,
+		RetryBackoff: retryBackoff,
+	}, nil
+}
+
+func loadDispatcher() (DispatcherConfiguration, error) {
+	workers, err := getEnvInt("EVENT_WORKERS", 4)
+	if err != nil {
+		return DispatcherConfiguration{}, err
+	}
+
+	queueSize, err := getEnvInt("EVENT_QUEUE_SIZE", 100)
+	if err != nil {
+		return DispatcherConfiguration{}, err
+	}
+
+	metricsInterval, err := getEnvDuration("EVENT_METRICS_INTERVAL", time.Minute)
+	if err != nil {
+		return DispatcherConfiguration{}, err
+	}
+
+	drainTimeout, err := getEnvDuration("EVENT_DRAIN_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return DispatcherConfiguration{}, err
+	}
+
+	return DispatcherConfiguration{
+		Workers:         workers,
+		QueueSize:       queueSize,
+		MetricsInterval: metricsInterval,
+		DrainTimeout:    drainTimeout,
+	}, nil
+}
+
+func loadBroker() (BrokerConfiguration, error) {
+	driver := getEnv("BROKER_DRIVER", "")
+	if driver != "" && driver != BrokerDriverNATS && driver != BrokerDriverAMQP {
+		return BrokerConfiguration{}, fmt.Errorf("BROKER_DRIVER inválido: %q", driver)
+	}
+
+	url := getEnv("BROKER_URL", "")
+	if driver != "" && url == "" {
+		return BrokerConfiguration{}, fmt.Errorf("BROKER_URL é obrigatória quando BROKER_DRIVER está definido")
+	}
+
+	topics, err := loadBrokerTopics()
+	if err != nil {
+		return BrokerConfiguration{}, err
+	}
+
+	timeout, err := getEnvDuration("BROKER_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return BrokerConfiguration{}, err
+	}
+
+	events := getEnvList("BROKER_EVENTS")
+	if len(events) == 0 {
+		events = defaultBrokerEvents
+	}
+
+	return BrokerConfiguration{
+		Driver:      driver,
+		URL:         url,
+		Exchange:    getEnv("BROKER_EXCHANGE", "system-education.events"),
+		Source:      getEnv("BROKER_SOURCE", "/system-education"),
+		TopicPrefix: getEnv("BROKER_TOPIC_PREFIX", "system-education."),
+		Topics:      topics,
+		Events:      events,
+		Timeout:     timeout,
+	}, nil
+}
+
+// loadBrokerTopics reads BROKER_TOPICS as a comma separated list of
+// event=topic pairs.
+func loadBrokerTopics() (map[string]string, error) {
+	value := os.Getenv("BROKER_TOPICS")
+	if value == "" {
+		return nil, nil
+	}
+
+	topics := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		event, topic, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || event == "" || topic == "" {
+			return nil, fmt.Errorf("BROKER_TOPICS inválida: %q", entry)
+		}
+		topics[event] = topic
+	}
+	return topics, nil
+}
+
+func loadWebhook() (WebhookConfiguration, error) {
+	pollInterval, err := getEnvDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second)
+	if err != nil {
+		return WebhookConfiguration{}, err
+	}
+
+	timeout, err := getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return WebhookConfiguration{}, err
+	}
+
+	maxAttempts, err := getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8)
+	if err != nil {
+		return WebhookConfiguration{}, err
+	}
+
+	retryBackoff, err := getEnvDuration("WEBHOOK_RETRY_BACKOFF", 30*time.Second)
+	if err != nil {
+		return WebhookConfiguration{}, err
+	}
+
+	allowPrivateNetworks, err := getEnvBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false)
+	if err != nil {
+		return WebhookConfiguration{}, err
+	}
+
+	return WebhookConfiguration{
+		PollInterval:         pollInterval,
+		Timeout:              timeout,
+		MaxAttempts:          maxAttempts,
+		RetryBackoff:         retryBackoff,
+		AllowPrivateNetworks: allowPrivateNetworks,
+	}, nil
 }
 
 func loadSecret() string {
 	return getEnv("JWT_SECRET", "")
 }
 
-func loadTimeDuration() time.Duration {
-	return 3*time.Hour + 15*time.Minute + 30*time.Second
+func loadMFA() MFAConfiguration {
+	return MFAConfiguration{
+		Issuer:          getEnv("MFA_ISSUER", "System Education"),
+		RequiredModules: getEnvList("MFA_REQUIRED_MODULES"),
+	}
+}
+
+// loadSigningKeys reads JWT_SIGNING_KEYS as a comma separated list of
+// kid=path pairs pointing to PEM files.
+func loadSigningKeys() (map[string]string, error) {
+	value := os.Getenv("JWT_SIGNING_KEYS")
+	if value == "" {
+		return nil, nil
+	}
+
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		kid, path, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || kid == "" || path == "" {
+			return nil, fmt.Errorf("JWT_SIGNING_KEYS inválida: %q", entry)
+		}
+		keys[kid] = path
+	}
+
+	return keys, nil
+}
+
+func getEnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s inválida: %v", key, err)
+	}
+
+	return duration, nil
+}
+
+func getEnvBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s inválida: %v", key, err)
+	}
+
+	return parsed, nil
+}
+
+func getEnvInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("%s inválida: %q", key, value)
+	}
+
+	return parsed, nil
 }
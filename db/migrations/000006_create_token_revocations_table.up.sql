@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti TEXT PRIMARY KEY,
+    user_id UUID NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+-- No foreign key to users: the revocation must outlive a deleted account.
+CREATE TABLE IF NOT EXISTS user_token_revocations (
+    user_id UUID PRIMARY KEY,
+    revoked_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL UNIQUE,
+    key_hash TEXT NOT NULL,
+    modules TEXT[] NOT NULL,
+    actions TEXT[] NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
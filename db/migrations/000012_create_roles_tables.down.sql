@@ -0,0 +1,11 @@
+-- Assignments of the roles created from direct permissions go back to being
+-- direct permissions, so dropping the tables takes nobody's access away.
+INSERT INTO permissions (id, user_id, modules, actions, level, description)
+SELECT gen_random_uuid(), ur.user_id, r.modules, r.actions, 'allowed', 'restored from role ' || r.name
+FROM user_roles ur
+JOIN roles r ON r.id = ur.role_id
+WHERE r.description = 'migrated from direct permissions';
+
+DROP TABLE IF EXISTS user_roles;
+
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS roles (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    modules TEXT[] NOT NULL,
+    actions TEXT[] NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE INDEX idx_user_roles_role_id ON user_roles(role_id);
+
+-- Every distinct grant set among the existing permission rows becomes a role,
+-- assigned to the users holding it, and the rows it replaces are removed.
+-- Grants are merged at login, so keeping both would hand out every grant
+-- twice and revoking one copy would not take the access away.
+INSERT INTO roles (id, name, description, modules, actions)
+SELECT gen_random_uuid(),
+       array_to_string(grants.modules, ',') || ':' || array_to_string(grants.actions, ','),
+       'migrated from direct permissions',
+       grants.modules,
+       grants.actions
+FROM (
+    SELECT DISTINCT modules, actions
+    FROM permissions
+    WHERE deleted_at IS NULL AND user_id IS NOT NULL
+) AS grants
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO user_roles (user_id, role_id)
+SELECT DISTINCT p.user_id, r.id
+FROM permissions p
+JOIN roles r ON r.modules = p.modules AND r.actions = p.actions
+WHERE p.deleted_at IS NULL AND p.user_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+DELETE FROM permissions p
+USING roles r
+WHERE r.modules = p.modules AND r.actions = p.actions
+  AND p.deleted_at IS NULL AND p.user_id IS NOT NULL;
@@ -0,0 +1,42 @@
+-- Splitting pairs back into independent lists widens access again; wildcard
+-- actions are expanded to the CRUD actions the routes check.
+ALTER TABLE permissions ADD COLUMN modules TEXT[] NOT NULL DEFAULT '{}', ADD COLUMN actions TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE roles ADD COLUMN modules TEXT[] NOT NULL DEFAULT '{}', ADD COLUMN actions TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE api_keys ADD COLUMN modules TEXT[] NOT NULL DEFAULT '{}', ADD COLUMN actions TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE permissions SET
+    modules = ARRAY(SELECT DISTINCT split_part(g, ':', 1) FROM unnest(grants) AS g),
+    actions = ARRAY(
+        SELECT DISTINCT a
+        FROM unnest(grants) AS g,
+             unnest(CASE split_part(g, ':', 2)
+                 WHEN '*' THEN ARRAY['create', 'read', 'update', 'delete']
+                 ELSE ARRAY[split_part(g, ':', 2)]
+             END) AS a
+    );
+
+UPDATE roles SET
+    modules = ARRAY(SELECT DISTINCT split_part(g, ':', 1) FROM unnest(grants) AS g),
+    actions = ARRAY(
+        SELECT DISTINCT a
+        FROM unnest(grants) AS g,
+             unnest(CASE split_part(g, ':', 2)
+                 WHEN '*' THEN ARRAY['create', 'read', 'update', 'delete']
+                 ELSE ARRAY[split_part(g, ':', 2)]
+             END) AS a
+    );
+
+UPDATE api_keys SET
+    modules = ARRAY(SELECT DISTINCT split_part(g, ':', 1) FROM unnest(grants) AS g),
+    actions = ARRAY(
+        SELECT DISTINCT a
+        FROM unnest(grants) AS g,
+             unnest(CASE split_part(g, ':', 2)
+                 WHEN '*' THEN ARRAY['create', 'read', 'update', 'delete']
+                 ELSE ARRAY[split_part(g, ':', 2)]
+             END) AS a
+    );
+
+ALTER TABLE permissions DROP COLUMN grants;
+ALTER TABLE roles DROP COLUMN grants;
+ALTER TABLE api_keys DROP COLUMN grants;
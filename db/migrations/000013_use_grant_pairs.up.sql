@@ -0,0 +1,31 @@
+-- Grants become explicit module:action pairs. Each row keeps exactly the
+-- combinations it could already satisfy on its own (its modules x its
+-- actions), so nobody gains access. What goes away is the accidental
+-- combination of one row's module with another row's action, and any legacy
+-- "*" or ":" value, which never matched a route before and would now be read
+-- as a wildcard or a malformed grant.
+ALTER TABLE permissions ADD COLUMN grants TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE roles ADD COLUMN grants TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE api_keys ADD COLUMN grants TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE permissions SET grants = ARRAY(
+    SELECT DISTINCT m || ':' || a
+    FROM unnest(modules) AS m, unnest(actions) AS a
+    WHERE m NOT IN ('', '*') AND a NOT IN ('', '*') AND position(':' IN m || a) = 0
+);
+
+UPDATE roles SET grants = ARRAY(
+    SELECT DISTINCT m || ':' || a
+    FROM unnest(modules) AS m, unnest(actions) AS a
+    WHERE m NOT IN ('', '*') AND a NOT IN ('', '*') AND position(':' IN m || a) = 0
+);
+
+UPDATE api_keys SET grants = ARRAY(
+    SELECT DISTINCT m || ':' || a
+    FROM unnest(modules) AS m, unnest(actions) AS a
+    WHERE m NOT IN ('', '*') AND a NOT IN ('', '*') AND position(':' IN m || a) = 0
+);
+
+ALTER TABLE permissions DROP COLUMN modules, DROP COLUMN actions;
+ALTER TABLE roles DROP COLUMN modules, DROP COLUMN actions;
+ALTER TABLE api_keys DROP COLUMN modules, DROP COLUMN actions;
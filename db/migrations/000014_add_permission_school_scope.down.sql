@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_students_school_id;
+
+-- Without the column a school-limited permission would apply everywhere, so
+-- those rows are retired instead of widened.
+UPDATE permissions SET deleted_at = now() WHERE cardinality(school_ids) > 0 AND deleted_at IS NULL;
+
+ALTER TABLE permissions DROP COLUMN school_ids;
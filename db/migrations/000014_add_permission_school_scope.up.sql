@@ -0,0 +1,5 @@
+-- An empty list keeps a permission network-wide, which is what every
+-- existing row is.
+ALTER TABLE permissions ADD COLUMN school_ids TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_students_school_id ON students(school_id);
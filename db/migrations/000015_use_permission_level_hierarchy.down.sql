@@ -0,0 +1,6 @@
+ALTER TABLE permissions DROP CONSTRAINT IF EXISTS permissions_level_check;
+
+UPDATE permissions SET level = 'allowed';
+
+ALTER TABLE permissions ADD CONSTRAINT permissions_level_check
+    CHECK (level IN ('allowed', 'restricted', 'denied'));
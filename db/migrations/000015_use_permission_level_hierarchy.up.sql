@@ -0,0 +1,9 @@
+-- Levels now cap what a permission grants. The old values (allowed,
+-- restricted, denied) were stored but never evaluated, so every row maps to
+-- admin to keep the access it already had.
+ALTER TABLE permissions DROP CONSTRAINT IF EXISTS permissions_level_check;
+
+UPDATE permissions SET level = 'admin';
+
+ALTER TABLE permissions ADD CONSTRAINT permissions_level_check
+    CHECK (level IN ('viewer', 'editor', 'manager', 'admin'));
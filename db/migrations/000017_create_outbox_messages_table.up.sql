@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    position BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL UNIQUE,
+    event_name TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- The relay only ever looks at pending messages.
+CREATE INDEX idx_outbox_messages_pending ON outbox_messages(next_attempt_at) WHERE delivered_at IS NULL;
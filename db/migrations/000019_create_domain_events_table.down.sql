@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS domain_events;
+
+DROP FUNCTION IF EXISTS domain_events_append_only();
@@ -0,0 +1,26 @@
+-- Every event pulled from an aggregate, in the order it was recorded.
+-- position gives replays a stable cursor; id matches the outbox message.
+CREATE TABLE IF NOT EXISTS domain_events (
+    position BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    event_name TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    occurred_on TIMESTAMP WITH TIME ZONE NOT NULL,
+    schema_version INT NOT NULL DEFAULT 1,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_domain_events_aggregate_id ON domain_events(aggregate_id, position);
+CREATE INDEX idx_domain_events_event_name ON domain_events(event_name);
+CREATE INDEX idx_domain_events_occurred_on ON domain_events(occurred_on);
+
+CREATE OR REPLACE FUNCTION domain_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'domain_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER domain_events_append_only
+    BEFORE UPDATE OR DELETE ON domain_events
+    FOR EACH ROW EXECUTE FUNCTION domain_events_append_only();
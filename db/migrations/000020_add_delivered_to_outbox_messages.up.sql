@@ -0,0 +1,3 @@
+-- Subscribers that already handled a message, so a retry only reaches the
+-- ones that failed.
+ALTER TABLE outbox_messages ADD COLUMN IF NOT EXISTS delivered_to TEXT[] NOT NULL DEFAULT '{}';
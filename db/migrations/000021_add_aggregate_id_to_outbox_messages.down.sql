@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_outbox_messages_aggregate_id;
+ALTER TABLE outbox_messages DROP COLUMN IF EXISTS aggregate_id;
@@ -0,0 +1,4 @@
+-- The aggregate a message belongs to, so the relay can hold back its later
+-- messages while an earlier one is pending.
+ALTER TABLE outbox_messages ADD COLUMN IF NOT EXISTS aggregate_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_aggregate_id ON outbox_messages(aggregate_id, position) WHERE delivered_at IS NULL;
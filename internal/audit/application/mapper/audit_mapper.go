@@ -0,0 +1,54 @@
+package audit_mapper
+
+import (
+	"time"
+
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	audit_dtos "github.com/williamkoller/system-education/internal/audit/presentation/dtos"
+)
+
+type AuditEventResponse struct {
+	ID         string               `json:"id"`
+	ActorID    string               `json:"actorId,omitempty"`
+	Action     string               `json:"action"`
+	EntityType string               `json:"entityType"`
+	EntityID   string               `json:"entityId"`
+	Changes    audit_entity.Changes `json:"changes"`
+	RequestID  string               `json:"requestId,omitempty"`
+	OccurredAt time.Time            `json:"occurredAt"`
+}
+
+func ToAuditEventResponse(e *audit_entity.AuditEvent) *AuditEventResponse {
+	return &AuditEventResponse{
+		ID:         e.ID,
+		ActorID:    e.ActorID,
+		Action:     string(e.Action),
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Changes:    e.Changes,
+		RequestID:  e.RequestID,
+		OccurredAt: e.OccurredAt,
+	}
+}
+
+func ToAuditEventResponses(es []*audit_entity.AuditEvent) []*AuditEventResponse {
+	responses := make([]*AuditEventResponse, 0, len(es))
+	for _, e := range es {
+		responses = append(responses, ToAuditEventResponse(e))
+	}
+	return responses
+}
+
+func ToFilter(input audit_dtos.FindAuditEventsDto) audit_entity.Filter {
+	return audit_entity.Filter{
+		ActorID:    input.ActorID,
+		Action:     audit_entity.Action(input.Action),
+		EntityType: input.EntityType,
+		EntityID:   input.EntityID,
+		RequestID:  input.RequestID,
+		From:       input.From,
+		To:         input.To,
+		Limit:      input.Limit,
+		Offset:     input.Offset,
+	}
+}
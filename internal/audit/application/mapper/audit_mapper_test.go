@@ -0,0 +1,38 @@
+package audit_mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	audit_dtos "github.com/williamkoller/system-education/internal/audit/presentation/dtos"
+)
+
+func TestToAuditEventResponses(t *testing.T) {
+	now := time.Now()
+	events := []*audit_entity.AuditEvent{
+		{ID: "event-1", ActorID: "user-1", Action: audit_entity.ActionDelete, EntityType: "school", EntityID: "school-1", RequestID: "req-1", OccurredAt: now},
+	}
+
+	responses := ToAuditEventResponses(events)
+
+	assert.Len(t, responses, 1)
+	assert.Equal(t, &AuditEventResponse{
+		ID:         "event-1",
+		ActorID:    "user-1",
+		Action:     "delete",
+		EntityType: "school",
+		EntityID:   "school-1",
+		RequestID:  "req-1",
+		OccurredAt: now,
+	}, responses[0])
+}
+
+func TestToFilter(t *testing.T) {
+	from := time.Now()
+
+	filter := ToFilter(audit_dtos.FindAuditEventsDto{ActorID: "user-1", Action: "update", EntityType: "student", From: &from, Limit: 10})
+
+	assert.Equal(t, audit_entity.Filter{ActorID: "user-1", Action: audit_entity.ActionUpdate, EntityType: "student", From: &from, Limit: 10}, filter)
+}
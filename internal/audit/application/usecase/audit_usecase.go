@@ -0,0 +1,41 @@
+package audit_usecase
+
+import (
+	"context"
+	"fmt"
+
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	port_audit_repository "github.com/williamkoller/system-education/internal/audit/port/repository"
+	port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	"github.com/williamkoller/system-education/shared/middleware"
+)
+
+type AuditUsecase struct {
+	repo port_audit_repository.AuditRepository
+}
+
+func NewAuditUsecase(repo port_audit_repository.AuditRepository) *AuditUsecase {
+	return &AuditUsecase{repo: repo}
+}
+
+var _ port_audit_usecase.AuditUsecase = &AuditUsecase{}
+
+// Record saves an event for a write made in the same transaction as ctx,
+// so a failure is returned and rolls the write back with it.
+func (a *AuditUsecase) Record(ctx context.Context, action audit_entity.Action, entityType, entityID string, before, after map[string]any) error {
+	var actorID string
+	if principal, ok := auth_entity.PrincipalFrom(ctx); ok {
+		actorID = principal.UserID
+	}
+
+	event := audit_entity.NewAuditEvent(actorID, action, entityType, entityID, middleware.RequestIDFrom(ctx), audit_entity.Diff(before, after))
+	if err := a.repo.Save(ctx, event); err != nil {
+		return fmt.Errorf("failed to record audit event %s %s %s: %w", action, entityType, entityID, err)
+	}
+	return nil
+}
+
+func (a *AuditUsecase) Find(ctx context.Context, filter audit_entity.Filter) ([]*audit_entity.AuditEvent, error) {
+	return a.repo.Find(ctx, filter.Normalized())
+}
@@ -0,0 +1,76 @@
+package audit_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	"github.com/williamkoller/system-education/shared/middleware"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Save(ctx context.Context, e *audit_entity.AuditEvent) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) Find(ctx context.Context, filter audit_entity.Filter) ([]*audit_entity.AuditEvent, error) {
+	args := m.Called(ctx, filter)
+	result, _ := args.Get(0).([]*audit_entity.AuditEvent)
+	return result, args.Error(1)
+}
+
+func TestAuditUsecase_Record(t *testing.T) {
+	ctx := auth_entity.WithPrincipal(context.Background(), &auth_entity.Claims{UserID: "user-1"})
+	ctx = middleware.WithRequestID(ctx, "req-1")
+
+	repo := new(MockAuditRepository)
+	var saved *audit_entity.AuditEvent
+	repo.On("Save", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*audit_entity.AuditEvent)
+	}).Return(nil)
+
+	err := NewAuditUsecase(repo).Record(ctx, audit_entity.ActionUpdate, "student", "student-1",
+		map[string]any{"Guardian.CPF": "123", "Name": "Ana"},
+		map[string]any{"Guardian.CPF": "456", "Name": "Ana"},
+	)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, saved)
+	assert.Equal(t, "user-1", saved.ActorID)
+	assert.Equal(t, "req-1", saved.RequestID)
+	assert.Equal(t, audit_entity.ActionUpdate, saved.Action)
+	assert.Equal(t, "student", saved.EntityType)
+	assert.Equal(t, "student-1", saved.EntityID)
+	assert.Equal(t, audit_entity.Changes{"Guardian.CPF": {From: "123", To: "456"}}, saved.Changes)
+}
+
+func TestAuditUsecase_Record_WithoutPrincipal(t *testing.T) {
+	repo := new(MockAuditRepository)
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(e *audit_entity.AuditEvent) bool {
+		return e.ActorID == "" && e.RequestID == ""
+	})).Return(errors.New("db down"))
+
+	err := NewAuditUsecase(repo).Record(context.Background(), audit_entity.ActionCreate, "user", "user-1", nil, map[string]any{"ID": "user-1"})
+
+	assert.ErrorContains(t, err, "db down")
+	repo.AssertExpectations(t)
+}
+
+func TestAuditUsecase_Find(t *testing.T) {
+	repo := new(MockAuditRepository)
+	events := []*audit_entity.AuditEvent{{ID: "event-1"}}
+	repo.On("Find", mock.Anything, audit_entity.Filter{EntityType: "school", Limit: audit_entity.DefaultLimit}).Return(events, nil)
+
+	found, err := NewAuditUsecase(repo).Find(context.Background(), audit_entity.Filter{EntityType: "school"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, events, found)
+}
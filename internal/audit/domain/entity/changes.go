@@ -0,0 +1,83 @@
+package audit_entity
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const Redacted = "[redacted]"
+
+// redactedFields are recorded as changed without their values.
+var redactedFields = []string{"Password", "Secret"}
+
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Changes maps a field path, such as "Guardian.CPF", to its old and new
+// values.
+type Changes map[string]Change
+
+// Snapshot flattens the exported fields of v into field paths. Take it
+// before mutating an entity in place.
+func Snapshot(v any) map[string]any {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil
+	}
+	fields := make(map[string]any)
+	flatten("", decoded, fields)
+	return fields
+}
+
+func flatten(prefix string, value map[string]any, into map[string]any) {
+	for key, field := range value {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := field.(map[string]any); ok {
+			flatten(path, nested, into)
+			continue
+		}
+		into[path] = field
+	}
+}
+
+// Diff returns the fields that differ between two snapshots. A nil before
+// records a creation, a nil after a deletion.
+func Diff(before, after map[string]any) Changes {
+	changes := make(Changes)
+	for path, from := range before {
+		if to, ok := after[path]; !ok || !reflect.DeepEqual(from, to) {
+			changes[path] = redact(path, Change{From: from, To: after[path]})
+		}
+	}
+	for path, to := range after {
+		if _, ok := before[path]; !ok {
+			changes[path] = redact(path, Change{To: to})
+		}
+	}
+	return changes
+}
+
+func redact(path string, change Change) Change {
+	field := path[strings.LastIndex(path, ".")+1:]
+	if !slices.Contains(redactedFields, field) {
+		return change
+	}
+	if change.From != nil {
+		change.From = Redacted
+	}
+	if change.To != nil {
+		change.To = Redacted
+	}
+	return change
+}
@@ -0,0 +1,63 @@
+package audit_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type guardian struct {
+	Name string
+	CPF  string
+}
+
+type record struct {
+	ID       string
+	Password string
+	Grants   []string
+	Guardian guardian
+}
+
+func TestSnapshot(t *testing.T) {
+	snapshot := Snapshot(&record{ID: "1", Grants: []string{"students:read"}, Guardian: guardian{CPF: "123"}})
+
+	assert.Equal(t, "1", snapshot["ID"])
+	assert.Equal(t, "123", snapshot["Guardian.CPF"])
+	assert.Equal(t, []any{"students:read"}, snapshot["Grants"])
+	assert.NotContains(t, snapshot, "Guardian")
+}
+
+func TestDiff(t *testing.T) {
+	before := Snapshot(&record{ID: "1", Password: "old-hash", Guardian: guardian{Name: "Ana", CPF: "123"}})
+	after := Snapshot(&record{ID: "1", Password: "new-hash", Guardian: guardian{Name: "Ana", CPF: "456"}})
+
+	assert.Equal(t, Changes{
+		"Guardian.CPF": {From: "123", To: "456"},
+		"Password":     {From: Redacted, To: Redacted},
+	}, Diff(before, after))
+
+	t.Run("creation and deletion", func(t *testing.T) {
+		created := Diff(nil, after)
+		assert.Equal(t, Change{To: "1"}, created["ID"])
+		assert.Equal(t, Change{To: Redacted}, created["Password"])
+
+		deleted := Diff(before, nil)
+		assert.Equal(t, Change{From: "123"}, deleted["Guardian.CPF"])
+	})
+
+	t.Run("secrets", func(t *testing.T) {
+		type webhook struct{ Secret string }
+		changes := Diff(Snapshot(&webhook{Secret: "old"}), Snapshot(&webhook{Secret: "new"}))
+		assert.Equal(t, Change{From: Redacted, To: Redacted}, changes["Secret"])
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		assert.Empty(t, Diff(before, before))
+	})
+}
+
+func TestFilter_Normalized(t *testing.T) {
+	assert.Equal(t, DefaultLimit, Filter{}.Normalized().Limit)
+	assert.Equal(t, MaxLimit, Filter{Limit: MaxLimit + 1}.Normalized().Limit)
+	assert.Equal(t, 0, Filter{Offset: -5}.Normalized().Offset)
+}
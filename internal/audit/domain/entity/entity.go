@@ -0,0 +1,41 @@
+package audit_entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// AuditEvent records one write: who made it, to which record, what changed
+// and the request it was part of. Events are never updated or deleted.
+type AuditEvent struct {
+	ID         string
+	ActorID    string
+	Action     Action
+	EntityType string
+	EntityID   string
+	Changes    Changes
+	RequestID  string
+	OccurredAt time.Time
+}
+
+func NewAuditEvent(actorID string, action Action, entityType, entityID, requestID string, changes Changes) *AuditEvent {
+	return &AuditEvent{
+		ID:         uuid.New().String(),
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		RequestID:  requestID,
+		OccurredAt: time.Now(),
+	}
+}
@@ -0,0 +1,36 @@
+package audit_entity
+
+import "time"
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// Filter selects audit events. Empty fields match everything; From and To
+// bound OccurredAt inclusively.
+type Filter struct {
+	ActorID    string
+	Action     Action
+	EntityType string
+	EntityID   string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// Normalized clamps Limit to (0, MaxLimit] and Offset to zero or more.
+func (f Filter) Normalized() Filter {
+	if f.Limit <= 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	return f
+}
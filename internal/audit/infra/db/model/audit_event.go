@@ -0,0 +1,70 @@
+package audit_model
+
+import (
+	"time"
+
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+)
+
+type AuditEvent struct {
+	ID         string  `gorm:"primaryKey;type:uuid"`
+	ActorID    *string `gorm:"type:uuid"`
+	Action     string
+	EntityType string
+	EntityID   string
+	Changes    audit_entity.Changes `gorm:"serializer:json;type:jsonb"`
+	RequestID  string
+	OccurredAt time.Time
+}
+
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+func FromEntity(e *audit_entity.AuditEvent) *AuditEvent {
+	if e == nil {
+		return nil
+	}
+	var actorID *string
+	if e.ActorID != "" {
+		actorID = &e.ActorID
+	}
+	return &AuditEvent{
+		ID:         e.ID,
+		ActorID:    actorID,
+		Action:     string(e.Action),
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Changes:    e.Changes,
+		RequestID:  e.RequestID,
+		OccurredAt: e.OccurredAt,
+	}
+}
+
+func ToEntity(e *AuditEvent) *audit_entity.AuditEvent {
+	if e == nil {
+		return nil
+	}
+	var actorID string
+	if e.ActorID != nil {
+		actorID = *e.ActorID
+	}
+	return &audit_entity.AuditEvent{
+		ID:         e.ID,
+		ActorID:    actorID,
+		Action:     audit_entity.Action(e.Action),
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Changes:    e.Changes,
+		RequestID:  e.RequestID,
+		OccurredAt: e.OccurredAt,
+	}
+}
+
+func ToEntities(es []*AuditEvent) []*audit_entity.AuditEvent {
+	entities := make([]*audit_entity.AuditEvent, 0, len(es))
+	for _, e := range es {
+		entities = append(entities, ToEntity(e))
+	}
+	return entities
+}
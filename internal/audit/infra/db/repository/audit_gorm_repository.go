@@ -0,0 +1,60 @@
+package audit_repository
+
+import (
+	"context"
+
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	audit_model "github.com/williamkoller/system-education/internal/audit/infra/db/model"
+	port_audit_repository "github.com/williamkoller/system-education/internal/audit/port/repository"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	"gorm.io/gorm"
+)
+
+type AuditGormRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditGormRepository(db *gorm.DB) *AuditGormRepository {
+	return &AuditGormRepository{db: db}
+}
+
+var _ port_audit_repository.AuditRepository = &AuditGormRepository{}
+
+func (r *AuditGormRepository) Save(ctx context.Context, e *audit_entity.AuditEvent) error {
+	return shared_database.Conn(ctx, r.db).Create(audit_model.FromEntity(e)).Error
+}
+
+// Find returns matching events, newest first.
+func (r *AuditGormRepository) Find(ctx context.Context, filter audit_entity.Filter) ([]*audit_entity.AuditEvent, error) {
+	query := r.db.WithContext(ctx).Model(&audit_model.AuditEvent{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", string(filter.Action))
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at <= ?", *filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var models []*audit_model.AuditEvent
+	if err := query.Order("occurred_at DESC, id").Offset(filter.Offset).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return audit_model.ToEntities(models), nil
+}
@@ -0,0 +1,98 @@
+package audit_repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	audit_model "github.com/williamkoller/system-education/internal/audit/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type AuditGormRepositorySuite struct {
+	suite.Suite
+	repository *AuditGormRepository
+	start      time.Time
+}
+
+func (s *AuditGormRepositorySuite) SetupTest() {
+	s.repository = NewAuditGormRepository(setupTestDB(s.T()))
+	s.start = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&audit_model.AuditEvent{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func (s *AuditGormRepositorySuite) save(actorID string, action audit_entity.Action, entityType, entityID string, minutes int) *audit_entity.AuditEvent {
+	event := audit_entity.NewAuditEvent(actorID, action, entityType, entityID, "req-"+entityID, audit_entity.Changes{
+		"Name": {From: "old", To: "new"},
+	})
+	event.OccurredAt = s.start.Add(time.Duration(minutes) * time.Minute)
+	s.Require().NoError(s.repository.Save(context.Background(), event))
+	return event
+}
+
+func (s *AuditGormRepositorySuite) TestSaveAndFind() {
+	saved := s.save("11111111-1111-1111-1111-111111111111", audit_entity.ActionUpdate, "student", "student-1", 0)
+
+	events, err := s.repository.Find(context.Background(), audit_entity.Filter{})
+	s.NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal(saved.ID, events[0].ID)
+	s.Equal(saved.ActorID, events[0].ActorID)
+	s.Equal(audit_entity.ActionUpdate, events[0].Action)
+	s.Equal("req-student-1", events[0].RequestID)
+	s.Equal(audit_entity.Changes{"Name": {From: "old", To: "new"}}, events[0].Changes)
+}
+
+func (s *AuditGormRepositorySuite) TestSave_WithoutActor() {
+	s.save("", audit_entity.ActionCreate, "user", "user-1", 0)
+
+	events, err := s.repository.Find(context.Background(), audit_entity.Filter{})
+	s.NoError(err)
+	s.Require().Len(events, 1)
+	s.Empty(events[0].ActorID)
+}
+
+func (s *AuditGormRepositorySuite) TestFind_Filters() {
+	actor := "11111111-1111-1111-1111-111111111111"
+	s.save(actor, audit_entity.ActionCreate, "student", "student-1", 0)
+	s.save(actor, audit_entity.ActionUpdate, "student", "student-1", 10)
+	s.save("22222222-2222-2222-2222-222222222222", audit_entity.ActionDelete, "school", "school-1", 20)
+
+	find := func(filter audit_entity.Filter) []string {
+		events, err := s.repository.Find(context.Background(), filter)
+		s.Require().NoError(err)
+		ids := make([]string, 0, len(events))
+		for _, e := range events {
+			ids = append(ids, e.EntityID+":"+string(e.Action))
+		}
+		return ids
+	}
+
+	s.Equal([]string{"school-1:delete", "student-1:update", "student-1:create"}, find(audit_entity.Filter{}), "newest first")
+	s.Equal([]string{"student-1:update", "student-1:create"}, find(audit_entity.Filter{ActorID: actor}))
+	s.Equal([]string{"student-1:update"}, find(audit_entity.Filter{Action: audit_entity.ActionUpdate}))
+	s.Equal([]string{"school-1:delete"}, find(audit_entity.Filter{EntityType: "school", EntityID: "school-1"}))
+	s.Equal([]string{"school-1:delete"}, find(audit_entity.Filter{RequestID: "req-school-1"}))
+
+	from, to := s.start.Add(5*time.Minute), s.start.Add(10*time.Minute)
+	s.Equal([]string{"student-1:update"}, find(audit_entity.Filter{From: &from, To: &to}))
+
+	s.Equal([]string{"student-1:update"}, find(audit_entity.Filter{Limit: 1, Offset: 1}))
+}
+
+func TestAuditGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AuditGormRepositorySuite))
+}
@@ -0,0 +1,7 @@
+package port_audit_handler
+
+import "github.com/gin-gonic/gin"
+
+type AuditHandler interface {
+	FindAuditEvents(c *gin.Context)
+}
@@ -0,0 +1,14 @@
+package port_audit_repository
+
+import (
+	"context"
+
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+)
+
+// AuditRepository is append-only: events are saved and queried, never
+// changed.
+type AuditRepository interface {
+	Save(ctx context.Context, e *audit_entity.AuditEvent) error
+	Find(ctx context.Context, filter audit_entity.Filter) ([]*audit_entity.AuditEvent, error)
+}
@@ -0,0 +1,20 @@
+package port_audit_usecase
+
+import (
+	"context"
+
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+)
+
+// Recorder is what the audited use cases depend on. before and after are
+// snapshots (audit_entity.Snapshot) of the record; before is nil on create
+// and after is nil on delete. Callers record inside the transaction of the
+// write they audit, so a write is never committed without its event.
+type Recorder interface {
+	Record(ctx context.Context, action audit_entity.Action, entityType, entityID string, before, after map[string]any) error
+}
+
+type AuditUsecase interface {
+	Recorder
+	Find(ctx context.Context, filter audit_entity.Filter) ([]*audit_entity.AuditEvent, error)
+}
@@ -0,0 +1,17 @@
+package audit_dtos
+
+import "time"
+
+// FindAuditEventsDto is bound from the query string of GET /audit. from and
+// to are RFC 3339 timestamps.
+type FindAuditEventsDto struct {
+	ActorID    string     `form:"actor_id"`
+	Action     string     `form:"action" binding:"omitempty,oneof=create update delete"`
+	EntityType string     `form:"entity_type" example:"student"`
+	EntityID   string     `form:"entity_id"`
+	RequestID  string     `form:"request_id"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int        `form:"limit" binding:"omitempty,min=1"`
+	Offset     int        `form:"offset" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,38 @@
+package audit_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	audit_mapper "github.com/williamkoller/system-education/internal/audit/application/mapper"
+	port_audit_handler "github.com/williamkoller/system-education/internal/audit/port/handler"
+	port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+	audit_dtos "github.com/williamkoller/system-education/internal/audit/presentation/dtos"
+)
+
+type AuditHandler struct {
+	usecase port_audit_usecase.AuditUsecase
+}
+
+var _ port_audit_handler.AuditHandler = &AuditHandler{}
+
+func NewAuditHandler(usecase port_audit_usecase.AuditUsecase) *AuditHandler {
+	return &AuditHandler{usecase: usecase}
+}
+
+func (h *AuditHandler) FindAuditEvents(c *gin.Context) {
+	var input audit_dtos.FindAuditEventsDto
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	events, err := h.usecase.Find(c.Request.Context(), audit_mapper.ToFilter(input))
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+	c.JSON(http.StatusOK, audit_mapper.ToAuditEventResponses(events))
+}
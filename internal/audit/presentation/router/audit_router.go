@@ -0,0 +1,28 @@
+package audit_router
+
+import (
+	"github.com/gin-gonic/gin"
+	audit_usecase "github.com/williamkoller/system-education/internal/audit/application/usecase"
+	audit_repository "github.com/williamkoller/system-education/internal/audit/infra/db/repository"
+	port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+	audit_handler "github.com/williamkoller/system-education/internal/audit/presentation/handler"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	"gorm.io/gorm"
+)
+
+func AuditRouter(g *gin.Engine, db *gorm.DB, jwt port_auth_cryptography.TokenManager, apiKeys port_auth_usecase.APIKeyAuthenticator, middleware port_permission_middleware.PermissionMiddleware) {
+	handler := audit_handler.NewAuditHandler(NewAuditUsecase(db))
+	revocations := auth_repository.NewTokenRevocationGormRepository(db)
+
+	g.GET("/audit", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"audit"}, []string{"read"}), handler.FindAuditEvents)
+}
+
+// NewAuditUsecase builds the recorder the audited modules' routers pass to
+// their use cases.
+func NewAuditUsecase(db *gorm.DB) port_audit_usecase.AuditUsecase {
+	return audit_usecase.NewAuditUsecase(audit_repository.NewAuditGormRepository(db))
+}
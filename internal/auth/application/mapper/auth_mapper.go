@@ -0,0 +1,212 @@
+package auth_mapper
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	user_mapper "github.com/williamkoller/system-education/internal/user/application/mapper"
+)
+
+type TokenResponse struct {
+	Token                 string    `json:"token"`
+	TokenType             string    `json:"tokenType"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+}
+
+func ToTokenResponse(pair *auth_entity.TokenPair) *TokenResponse {
+	return &TokenResponse{
+		Token:                 pair.AccessToken,
+		TokenType:             "Bearer",
+		RefreshToken:          pair.RefreshToken,
+		RefreshTokenExpiresAt: pair.RefreshTokenExpiresAt,
+	}
+}
+
+type MFAChallengeResponse struct {
+	MFARequired       bool      `json:"mfaRequired"`
+	MFAToken          string    `json:"mfaToken"`
+	MFATokenExpiresAt time.Time `json:"mfaTokenExpiresAt"`
+}
+
+func ToMFAChallengeResponse(challenge *auth_entity.MFARequiredError) *MFAChallengeResponse {
+	return &MFAChallengeResponse{
+		MFARequired:       true,
+		MFAToken:          challenge.Token,
+		MFATokenExpiresAt: challenge.ExpiresAt,
+	}
+}
+
+type MFASetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+func ToMFASetupResponse(setup *auth_entity.MFASetup) *MFASetupResponse {
+	return &MFASetupResponse{
+		Secret:          setup.Secret,
+		ProvisioningURI: setup.ProvisioningURI,
+	}
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type ProfileSchoolResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Code string `json:"code"`
+}
+
+type SessionResponse struct {
+	TokenID   string    `json:"tokenId"`
+	Issuer    string    `json:"issuer"`
+	Audience  []string  `json:"audience"`
+	IssuedAt  time.Time `json:"issuedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type ProfileResponse struct {
+	User         *user_mapper.UserResponse `json:"user"`
+	Grants       []string                  `json:"grants"`
+	SchoolGrants map[string][]string       `json:"schoolGrants,omitempty"`
+	Schools      []*ProfileSchoolResponse  `json:"schools"`
+	Session      *SessionResponse          `json:"session"`
+}
+
+func ToProfileResponse(profile *auth_entity.Profile, claims *auth_entity.Claims) *ProfileResponse {
+	schools := make([]*ProfileSchoolResponse, 0, len(profile.Schools))
+	for _, s := range profile.Schools {
+		schools = append(schools, &ProfileSchoolResponse{ID: s.ID, Name: s.Name, Code: s.Code})
+	}
+
+	return &ProfileResponse{
+		User:         user_mapper.ToUser(profile.User),
+		Grants:       profile.Grants,
+		SchoolGrants: profile.SchoolGrants,
+		Schools:      schools,
+		Session: &SessionResponse{
+			TokenID:   claims.ID,
+			Issuer:    claims.Issuer,
+			Audience:  claims.Audience,
+			IssuedAt:  claims.IssuedAt,
+			ExpiresAt: claims.ExpiresAt,
+		},
+	}
+}
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Grants     []string   `json:"grants"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type CreatedAPIKeyResponse struct {
+	*APIKeyResponse
+	Key string `json:"key"`
+}
+
+func ToAPIKeyResponse(k *auth_entity.APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Grants:     k.Grants,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+func ToAPIKeyResponses(keys []*auth_entity.APIKey) []*APIKeyResponse {
+	responses := make([]*APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		responses = append(responses, ToAPIKeyResponse(k))
+	}
+	return responses
+}
+
+// ToCreatedAPIKeyResponse is the only response carrying the plain key.
+func ToCreatedAPIKeyResponse(k *auth_entity.APIKey, key string) *CreatedAPIKeyResponse {
+	return &CreatedAPIKeyResponse{APIKeyResponse: ToAPIKeyResponse(k), Key: key}
+}
+
+type AccessCheckResourceResponse struct {
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	SchoolID string `json:"schoolId"`
+}
+
+type AccessCheckPermissionResponse struct {
+	ID        string   `json:"id"`
+	Level     string   `json:"level"`
+	Grants    []string `json:"grants"`
+	SchoolIDs []string `json:"schoolIds"`
+}
+
+type AccessCheckRoleResponse struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Grants []string `json:"grants"`
+}
+
+type AccessCheckResponse struct {
+	Allowed      bool                             `json:"allowed"`
+	Reason       string                           `json:"reason,omitempty"`
+	UserID       string                           `json:"userId"`
+	Module       string                           `json:"module"`
+	Action       string                           `json:"action"`
+	Resource     *AccessCheckResourceResponse     `json:"resource,omitempty"`
+	MFARequired  bool                             `json:"mfaRequired"`
+	Grants       []string                         `json:"grants"`
+	SchoolGrants map[string][]string              `json:"schoolGrants,omitempty"`
+	Permissions  []*AccessCheckPermissionResponse `json:"permissions"`
+	Roles        []*AccessCheckRoleResponse       `json:"roles"`
+}
+
+func ToAccessCheckResponse(check *auth_entity.AccessCheck) *AccessCheckResponse {
+	permissions := make([]*AccessCheckPermissionResponse, 0, len(check.Permissions))
+	for _, p := range check.Permissions {
+		permissions = append(permissions, &AccessCheckPermissionResponse{
+			ID:        p.ID,
+			Level:     p.Level,
+			Grants:    p.Grants,
+			SchoolIDs: p.SchoolIDs,
+		})
+	}
+
+	roles := make([]*AccessCheckRoleResponse, 0, len(check.Roles))
+	for _, r := range check.Roles {
+		roles = append(roles, &AccessCheckRoleResponse{ID: r.ID, Name: r.Name, Grants: r.Grants})
+	}
+
+	var resource *AccessCheckResourceResponse
+	if check.Resource != nil {
+		resource = &AccessCheckResourceResponse{
+			Type:     check.Resource.Type,
+			ID:       check.Resource.ID,
+			SchoolID: check.Resource.SchoolID,
+		}
+	}
+
+	return &AccessCheckResponse{
+		Allowed:      check.Allowed,
+		Reason:       check.Reason,
+		UserID:       check.UserID,
+		Module:       check.Module,
+		Action:       check.Action,
+		Resource:     resource,
+		MFARequired:  check.MFARequired,
+		Grants:       check.Grants,
+		SchoolGrants: check.SchoolGrants,
+		Permissions:  permissions,
+		Roles:        roles,
+	}
+}
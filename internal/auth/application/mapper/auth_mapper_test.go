@@ -0,0 +1,76 @@
+package auth_mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	school_entity "github.com/williamkoller/system-education/internal/school/domain/entity"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+)
+
+func TestToTokenResponse(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	pair := &auth_entity.TokenPair{
+		AccessToken:           "access",
+		RefreshToken:          "refresh",
+		RefreshTokenExpiresAt: expiresAt,
+	}
+
+	resp := ToTokenResponse(pair)
+
+	assert.Equal(t, "access", resp.Token)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, "refresh", resp.RefreshToken)
+	assert.Equal(t, expiresAt, resp.RefreshTokenExpiresAt)
+}
+
+func TestToProfileResponse(t *testing.T) {
+	issuedAt := time.Now()
+	profile := &auth_entity.Profile{
+		User:    &user_entity.User{ID: "u1", Name: "Alice", Email: "alice@example.com", Password: "hash"},
+		Grants:  []string{"schools:read"},
+		Schools: []*school_entity.School{{ID: "s1", Name: "Escola", Code: "E1"}},
+	}
+	claims := &auth_entity.Claims{
+		ID:        "jti-1",
+		Issuer:    "system-education",
+		Audience:  []string{"system-education"},
+		IssuedAt:  issuedAt,
+		ExpiresAt: issuedAt.Add(time.Hour),
+	}
+
+	resp := ToProfileResponse(profile, claims)
+
+	assert.Equal(t, "u1", resp.User.ID)
+	assert.Equal(t, "alice@example.com", resp.User.Email)
+	assert.Equal(t, []string{"schools:read"}, resp.Grants)
+	assert.Equal(t, []*ProfileSchoolResponse{{ID: "s1", Name: "Escola", Code: "E1"}}, resp.Schools)
+	assert.Equal(t, "jti-1", resp.Session.TokenID)
+	assert.Equal(t, issuedAt.Add(time.Hour), resp.Session.ExpiresAt)
+}
+
+func TestToAccessCheckResponse(t *testing.T) {
+	check := &auth_entity.AccessCheck{
+		UserID:      "user-1",
+		Module:      "students",
+		Action:      "read",
+		Resource:    &auth_entity.Resource{Type: auth_entity.ResourceStudent, ID: "student-1", SchoolID: "school-1"},
+		Allowed:     true,
+		Grants:      []string{"students:read"},
+		Permissions: []*permission_entity.Permission{{ID: "p-1", Level: "viewer", Grants: []string{"students:*"}, SchoolIDs: []string{"school-1"}}},
+		Roles:       []*role_entity.Role{{ID: "r-1", Name: "teacher", Grants: []string{"students:read"}}},
+	}
+
+	resp := ToAccessCheckResponse(check)
+
+	assert.True(t, resp.Allowed)
+	assert.Equal(t, "user-1", resp.UserID)
+	assert.Equal(t, &AccessCheckResourceResponse{Type: "student", ID: "student-1", SchoolID: "school-1"}, resp.Resource)
+	assert.Equal(t, []string{"students:read"}, resp.Grants)
+	assert.Equal(t, []*AccessCheckPermissionResponse{{ID: "p-1", Level: "viewer", Grants: []string{"students:*"}, SchoolIDs: []string{"school-1"}}}, resp.Permissions)
+	assert.Equal(t, []*AccessCheckRoleResponse{{ID: "r-1", Name: "teacher", Grants: []string{"students:read"}}}, resp.Roles)
+}
@@ -0,0 +1,178 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	port_school_repository "github.com/williamkoller/system-education/internal/school/port/repository"
+	port_student_repository "github.com/williamkoller/system-education/internal/student/port/repository"
+)
+
+// AccessCheckUsecase answers "may this user do this" with the same
+// evaluation the route middleware applies, and shows its working.
+type AccessCheckUsecase struct {
+	sources     port_auth_usecase.GrantSources
+	refresher   port_auth_usecase.GrantRefresher
+	schoolRepo  port_school_repository.SchoolRepository
+	studentRepo port_student_repository.StudentRepository
+	mfaPolicy   auth_entity.MFAPolicy
+}
+
+var _ port_auth_usecase.AccessCheckUsecase = &AccessCheckUsecase{}
+
+// NewAccessCheckUsecase takes the refresher the middleware uses, or nil when
+// routes are checked against the token alone.
+func NewAccessCheckUsecase(
+	sources port_auth_usecase.GrantSources,
+	refresher port_auth_usecase.GrantRefresher,
+	schoolRepo port_school_repository.SchoolRepository,
+	studentRepo port_student_repository.StudentRepository,
+	mfaPolicy auth_entity.MFAPolicy,
+) *AccessCheckUsecase {
+	return &AccessCheckUsecase{
+		sources:     sources,
+		refresher:   refresher,
+		schoolRepo:  schoolRepo,
+		studentRepo: studentRepo,
+		mfaPolicy:   mfaPolicy,
+	}
+}
+
+// Check evaluates the principal's own token when userID is empty or their
+// own, and otherwise the stored grants of userID as a fully verified
+// session would hold them. MFARequired flags modules that such a session
+// needs a second factor for.
+func (a *AccessCheckUsecase) Check(ctx context.Context, principal *auth_entity.Claims, userID, module, action string, resource *auth_entity.Resource) (*auth_entity.AccessCheck, error) {
+	subject := principal.UserID
+	if userID != "" && userID != principal.UserID {
+		if !principal.Allows("permissions", "read") {
+			return nil, auth_entity.ErrAccessCheckDenied
+		}
+		subject = userID
+	}
+
+	if resource != nil {
+		if err := a.locate(ctx, resource); err != nil {
+			return nil, err
+		}
+	}
+
+	permissions, roles, err := a.sources.Sources(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	held := mergeSources(permissions, roles)
+	if subject == principal.UserID {
+		held = a.current(ctx, principal).Scoped()
+	}
+
+	decision := permission_entity.Decide(held, []string{module}, []string{action})
+	matching := held.Matching(module, action)
+	if resource != nil {
+		matching.Schools = map[string][]string{resource.SchoolID: matching.Schools[resource.SchoolID]}
+		if decision.Allowed && !decision.Scope.Includes(resource.SchoolID) {
+			decision = permission_entity.Decision{Reason: permission_entity.ErrSchoolOutOfScope.Error()}
+		}
+	}
+
+	check := &auth_entity.AccessCheck{
+		UserID:       subject,
+		Module:       module,
+		Action:       action,
+		Resource:     resource,
+		Allowed:      decision.Allowed,
+		MFARequired:  a.mfaPolicy.Requires([]string{module}),
+		Grants:       matching.Grants,
+		SchoolGrants: nonEmpty(matching.Schools),
+		Permissions:  contributingPermissions(permissions, module, action, resource),
+		Roles:        contributingRoles(roles, module, action),
+	}
+	if !decision.Allowed {
+		check.Reason = decision.Reason + ": " + permission_entity.Explain(held, []string{module}, []string{action})
+	}
+
+	return check, nil
+}
+
+// current is the principal as ModuleAccessMiddleware would see it.
+func (a *AccessCheckUsecase) current(ctx context.Context, principal *auth_entity.Claims) *auth_entity.Claims {
+	if a.refresher == nil {
+		return principal
+	}
+	refreshed, err := a.refresher.Refresh(ctx, principal)
+	if err != nil {
+		return principal
+	}
+	return refreshed
+}
+
+func (a *AccessCheckUsecase) locate(ctx context.Context, resource *auth_entity.Resource) error {
+	// Finding where the resource lives comes before deciding whether the
+	// principal may reach it, so the lookup is not narrowed to any scope.
+	ctx = permission_entity.WithSchoolScope(ctx, permission_entity.AllSchools())
+	switch resource.Type {
+	case auth_entity.ResourceSchool:
+		school, err := a.schoolRepo.FindById(ctx, resource.ID)
+		if err != nil {
+			if errors.Is(err, port_school_repository.ErrNotFound) {
+				return auth_entity.ErrResourceNotFound
+			}
+			return err
+		}
+		resource.SchoolID = school.ID
+	case auth_entity.ResourceStudent:
+		student, err := a.studentRepo.FindById(ctx, resource.ID)
+		if err != nil {
+			if errors.Is(err, port_student_repository.ErrNotFound) {
+				return auth_entity.ErrResourceNotFound
+			}
+			return err
+		}
+		resource.SchoolID = student.School.SchoolID
+	default:
+		return auth_entity.ErrUnknownResourceType
+	}
+	return nil
+}
+
+func contributingPermissions(permissions []*permission_entity.Permission, module, action string, resource *auth_entity.Resource) []*permission_entity.Permission {
+	contributing := []*permission_entity.Permission{}
+	for _, permission := range permissions {
+		schoolIDs := permission.GetSchoolIDs()
+		if resource != nil && len(schoolIDs) > 0 && !slices.Contains(schoolIDs, resource.SchoolID) {
+			continue
+		}
+		matching := permission_entity.ScopedGrants{Grants: permission.EffectiveGrants()}.Matching(module, action)
+		if len(matching.Grants) > 0 {
+			contributing = append(contributing, permission)
+		}
+	}
+	return contributing
+}
+
+func contributingRoles(roles []*role_entity.Role, module, action string) []*role_entity.Role {
+	contributing := []*role_entity.Role{}
+	for _, role := range roles {
+		matching := permission_entity.ScopedGrants{Grants: role.Grants}.Matching(module, action)
+		if len(matching.Grants) > 0 {
+			contributing = append(contributing, role)
+		}
+	}
+	return contributing
+}
+
+func nonEmpty(schools map[string][]string) map[string][]string {
+	kept := map[string][]string{}
+	for schoolID, grants := range schools {
+		if len(grants) > 0 {
+			kept[schoolID] = grants
+		}
+	}
+	return kept
+}
@@ -0,0 +1,160 @@
+package auth_usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	authEntity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	roleEntity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	port_school_repository "github.com/williamkoller/system-education/internal/school/port/repository"
+	studentEntity "github.com/williamkoller/system-education/internal/student/domain/entity"
+	port_student_repository "github.com/williamkoller/system-education/internal/student/port/repository"
+)
+
+type MockStudentRepository struct {
+	mock.Mock
+}
+
+func (m *MockStudentRepository) Save(ctx context.Context, s *studentEntity.Student) (*studentEntity.Student, error) {
+	args := m.Called(ctx, s)
+	result, _ := args.Get(0).(*studentEntity.Student)
+	return result, args.Error(1)
+}
+
+func (m *MockStudentRepository) FindAll(ctx context.Context) ([]*studentEntity.Student, error) {
+	args := m.Called(ctx)
+	result, _ := args.Get(0).([]*studentEntity.Student)
+	return result, args.Error(1)
+}
+
+func (m *MockStudentRepository) FindById(ctx context.Context, id string) (*studentEntity.Student, error) {
+	args := m.Called(ctx, id)
+	result, _ := args.Get(0).(*studentEntity.Student)
+	return result, args.Error(1)
+}
+
+func (m *MockStudentRepository) Update(ctx context.Context, id string, s *studentEntity.Student) (*studentEntity.Student, error) {
+	args := m.Called(ctx, id, s)
+	result, _ := args.Get(0).(*studentEntity.Student)
+	return result, args.Error(1)
+}
+
+func (m *MockStudentRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestAccessCheckUsecase_Check(t *testing.T) {
+	t.Run("should explain another user's access", func(t *testing.T) {
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockRoleRepo := new(MockRoleRepository)
+		usecase := NewAccessCheckUsecase(NewGrantResolver(mockPermissionRepo, mockRoleRepo), nil, new(MockSchoolRepository), new(MockStudentRepository), authEntity.MFAPolicy{RequiredModules: []string{"students"}})
+
+		admin := &authEntity.Claims{UserID: "admin-1", Grants: []string{"permissions:read"}}
+		direct := &permissionEntity.Permission{ID: "p-1", Grants: []string{"students:*"}, Level: "viewer"}
+		unrelated := &permissionEntity.Permission{ID: "p-2", Grants: []string{"users:read"}, Level: "admin"}
+		teacher := &roleEntity.Role{ID: "r-1", Name: "teacher", Grants: []string{"students:update"}}
+
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{direct, unrelated}, nil)
+		mockRoleRepo.On("FindByUserID", mock.Anything, "user-1").Return([]*roleEntity.Role{teacher}, nil)
+
+		check, err := usecase.Check(context.Background(), admin, "user-1", "students", "read", nil)
+
+		assert.NoError(t, err)
+		assert.True(t, check.Allowed)
+		assert.Empty(t, check.Reason)
+		assert.True(t, check.MFARequired)
+		assert.Equal(t, []string{"students:read"}, check.Grants)
+		assert.Equal(t, []*permissionEntity.Permission{direct}, check.Permissions)
+		assert.Empty(t, check.Roles)
+
+		check, err = usecase.Check(context.Background(), admin, "user-1", "students", "delete", nil)
+
+		assert.NoError(t, err)
+		assert.False(t, check.Allowed)
+		assert.Equal(t, "Access denied to required actions: requires one of students:delete; holds students:read, students:update in every school", check.Reason)
+		assert.Empty(t, check.Permissions, "the viewer level caps students:* below delete")
+	})
+
+	t.Run("should check the caller's own token without permissions:read", func(t *testing.T) {
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockRoleRepo := new(MockRoleRepository)
+		usecase := NewAccessCheckUsecase(NewGrantResolver(mockPermissionRepo, mockRoleRepo), nil, new(MockSchoolRepository), new(MockStudentRepository), authEntity.MFAPolicy{})
+
+		principal := &authEntity.Claims{UserID: "user-1", Grants: []string{"students:read"}}
+
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{
+			{ID: "p-1", Grants: []string{"students:read", "students:delete"}, Level: "admin"},
+		}, nil)
+		mockRoleRepo.On("FindByUserID", mock.Anything, "user-1").Return([]*roleEntity.Role{}, nil)
+
+		check, err := usecase.Check(context.Background(), principal, "", "students", "delete", nil)
+
+		assert.NoError(t, err)
+		assert.False(t, check.Allowed, "the token, not the stored permissions, is what routes check")
+		assert.Equal(t, "user-1", check.UserID)
+	})
+
+	t.Run("should require permissions:read to check another user", func(t *testing.T) {
+		usecase := NewAccessCheckUsecase(NewGrantResolver(new(MockPermissionRepository), new(MockRoleRepository)), nil, new(MockSchoolRepository), new(MockStudentRepository), authEntity.MFAPolicy{})
+
+		principal := &authEntity.Claims{UserID: "user-1", Grants: []string{"students:read"}}
+
+		_, err := usecase.Check(context.Background(), principal, "user-2", "students", "read", nil)
+
+		assert.ErrorIs(t, err, authEntity.ErrAccessCheckDenied)
+	})
+
+	t.Run("should narrow a resource check to its school", func(t *testing.T) {
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockRoleRepo := new(MockRoleRepository)
+		mockStudentRepo := new(MockStudentRepository)
+		usecase := NewAccessCheckUsecase(NewGrantResolver(mockPermissionRepo, mockRoleRepo), nil, new(MockSchoolRepository), mockStudentRepo, authEntity.MFAPolicy{})
+
+		principal := &authEntity.Claims{UserID: "user-1", SchoolGrants: map[string][]string{"school-a": {"students:update"}}}
+		inA := &permissionEntity.Permission{ID: "p-1", Grants: []string{"students:update"}, SchoolIDs: []string{"school-a"}, Level: "admin"}
+
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{inA}, nil)
+		mockRoleRepo.On("FindByUserID", mock.Anything, "user-1").Return([]*roleEntity.Role{}, nil)
+		mockStudentRepo.On("FindById", mock.MatchedBy(func(ctx context.Context) bool {
+			return permissionEntity.SchoolScopeFrom(ctx).All
+		}), "student-a").Return(&studentEntity.Student{ID: "student-a", School: studentEntity.SchoolInfo{SchoolID: "school-a"}}, nil)
+		mockStudentRepo.On("FindById", mock.Anything, "student-b").Return(&studentEntity.Student{ID: "student-b", School: studentEntity.SchoolInfo{SchoolID: "school-b"}}, nil)
+
+		check, err := usecase.Check(context.Background(), principal, "", "students", "update", &authEntity.Resource{Type: authEntity.ResourceStudent, ID: "student-a"})
+		assert.NoError(t, err)
+		assert.True(t, check.Allowed)
+		assert.Equal(t, "school-a", check.Resource.SchoolID)
+		assert.Equal(t, map[string][]string{"school-a": {"students:update"}}, check.SchoolGrants)
+		assert.Equal(t, []*permissionEntity.Permission{inA}, check.Permissions)
+
+		check, err = usecase.Check(context.Background(), principal, "", "students", "update", &authEntity.Resource{Type: authEntity.ResourceStudent, ID: "student-b"})
+		assert.NoError(t, err)
+		assert.False(t, check.Allowed)
+		assert.Contains(t, check.Reason, permissionEntity.ErrSchoolOutOfScope.Error())
+		assert.Empty(t, check.Permissions)
+	})
+
+	t.Run("should return error for an unknown resource", func(t *testing.T) {
+		mockSchoolRepo := new(MockSchoolRepository)
+		mockStudentRepo := new(MockStudentRepository)
+		usecase := NewAccessCheckUsecase(NewGrantResolver(new(MockPermissionRepository), new(MockRoleRepository)), nil, mockSchoolRepo, mockStudentRepo, authEntity.MFAPolicy{})
+
+		principal := &authEntity.Claims{UserID: "user-1"}
+
+		mockSchoolRepo.On("FindById", mock.Anything, "missing").Return(nil, port_school_repository.ErrNotFound)
+		mockStudentRepo.On("FindById", mock.Anything, "missing").Return(nil, port_student_repository.ErrNotFound)
+
+		_, err := usecase.Check(context.Background(), principal, "", "schools", "read", &authEntity.Resource{Type: authEntity.ResourceSchool, ID: "missing"})
+		assert.ErrorIs(t, err, authEntity.ErrResourceNotFound)
+
+		_, err = usecase.Check(context.Background(), principal, "", "students", "read", &authEntity.Resource{Type: authEntity.ResourceStudent, ID: "missing"})
+		assert.ErrorIs(t, err, authEntity.ErrResourceNotFound)
+
+		_, err = usecase.Check(context.Background(), principal, "", "students", "read", &authEntity.Resource{Type: "classroom", ID: "x"})
+		assert.ErrorIs(t, err, authEntity.ErrUnknownResourceType)
+	})
+}
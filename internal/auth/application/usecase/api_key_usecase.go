@@ -0,0 +1,151 @@
+package auth_usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type APIKeyUsecase struct {
+	repo      port_user_repository.UserRepository
+	grants    port_auth_usecase.GrantResolver
+	apiKeys   port_auth_repository.APIKeyRepository
+	generator port_auth_cryptography.APIKeyGenerator
+	mfaPolicy auth_entity.MFAPolicy
+}
+
+var (
+	_ port_auth_usecase.APIKeyUsecase       = &APIKeyUsecase{}
+	_ port_auth_usecase.APIKeyAuthenticator = &APIKeyUsecase{}
+)
+
+func NewAPIKeyUsecase(
+	repo port_user_repository.UserRepository,
+	grants port_auth_usecase.GrantResolver,
+	apiKeys port_auth_repository.APIKeyRepository,
+	generator port_auth_cryptography.APIKeyGenerator,
+	mfaPolicy auth_entity.MFAPolicy,
+) *APIKeyUsecase {
+	return &APIKeyUsecase{
+		repo:      repo,
+		grants:    grants,
+		apiKeys:   apiKeys,
+		generator: generator,
+		mfaPolicy: mfaPolicy,
+	}
+}
+
+// Create issues a key scoped to a subset of the caller's current grants. The
+// plain key is returned once and never stored.
+func (a *APIKeyUsecase) Create(ctx context.Context, principal *auth_entity.Claims, name string, grants []string, expiresAt *time.Time) (*auth_entity.APIKey, string, error) {
+	if slices.Contains(principal.AuthMethods, auth_entity.AuthMethodAPIKey) {
+		return nil, "", auth_entity.ErrAPIKeyNotAllowed
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(grants) == 0 {
+		return nil, "", auth_entity.ErrInvalidAPIKeyScope
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", auth_entity.ErrInvalidAPIKeyScope
+	}
+
+	for _, grant := range grants {
+		if _, _, ok := permission_entity.ParseGrant(grant); !ok {
+			return nil, "", auth_entity.ErrInvalidAPIKeyScope
+		}
+		if !principal.Scoped().Covers(grant) {
+			return nil, "", auth_entity.ErrAPIKeyScopeDenied
+		}
+	}
+
+	key, prefix, err := a.generator.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiKey := auth_entity.NewAPIKey(principal.UserID, name, prefix, a.generator.Hash(key), grants, expiresAt)
+	saved, err := a.apiKeys.Save(ctx, apiKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return saved, key, nil
+}
+
+func (a *APIKeyUsecase) List(ctx context.Context, userID string) ([]*auth_entity.APIKey, error) {
+	return a.apiKeys.FindByUserID(ctx, userID)
+}
+
+func (a *APIKeyUsecase) Revoke(ctx context.Context, userID, id string) error {
+	return a.apiKeys.Revoke(ctx, id, userID)
+}
+
+// Authenticate resolves a key into claims. The key's scope is intersected with
+// the owner's permissions at request time, and modules guarded by the MFA
+// policy are never reachable with a key since it cannot prove a second factor.
+func (a *APIKeyUsecase) Authenticate(ctx context.Context, key string) (*auth_entity.Claims, error) {
+	prefix, ok := a.generator.Prefix(key)
+	if !ok {
+		return nil, auth_entity.ErrInvalidAPIKey
+	}
+
+	apiKey, err := a.apiKeys.FindByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, port_auth_repository.ErrAPIKeyNotFound) {
+			return nil, auth_entity.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(a.generator.Hash(key))) != 1 {
+		return nil, auth_entity.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		return nil, auth_entity.ErrInvalidAPIKey
+	}
+
+	user, err := a.repo.FindByID(ctx, apiKey.UserID)
+	if err != nil {
+		if errors.Is(err, port_user_repository.ErrUserNotFound) {
+			return nil, auth_entity.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	held, err := a.grants.Resolve(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := apiKey.Scope(held).Map(func(g []string) []string {
+		return a.mfaPolicy.Filter(g, false)
+	})
+
+	if err := a.apiKeys.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+		return nil, err
+	}
+
+	return &auth_entity.Claims{
+		UserID:       user.ID,
+		Name:         user.Name,
+		Nickname:     user.Nickname,
+		Email:        user.Email,
+		Grants:       scoped.Grants,
+		SchoolGrants: scoped.Schools,
+		AuthMethods:  []string{auth_entity.AuthMethodAPIKey},
+	}, nil
+}
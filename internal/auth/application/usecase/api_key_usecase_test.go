@@ -0,0 +1,280 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authEntity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Save(ctx context.Context, k *authEntity.APIKey) (*authEntity.APIKey, error) {
+	args := m.Called(ctx, k)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authEntity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*authEntity.APIKey, error) {
+	args := m.Called(ctx, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authEntity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByUserID(ctx context.Context, userID string) ([]*authEntity.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*authEntity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+type MockAPIKeyGenerator struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyGenerator) Generate() (string, string, error) {
+	args := m.Called()
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyGenerator) Prefix(key string) (string, bool) {
+	args := m.Called(key)
+	return args.String(0), args.Bool(1)
+}
+
+func (m *MockAPIKeyGenerator) Hash(key string) string {
+	args := m.Called(key)
+	return args.String(0)
+}
+
+func apiKeyPrincipal() *authEntity.Claims {
+	return &authEntity.Claims{
+		UserID:      "user-123",
+		Grants:      []string{"students:read", "students:update", "schools:read", "schools:update"},
+		AuthMethods: []string{authEntity.AuthMethodPassword},
+	}
+}
+
+func TestAPIKeyUsecase_Create(t *testing.T) {
+	t.Run("should create a key within the principal's grants", func(t *testing.T) {
+		mockAPIKeys := new(MockAPIKeyRepository)
+		mockGenerator := new(MockAPIKeyGenerator)
+		usecase := NewAPIKeyUsecase(new(MockUserRepository), withoutRoles(new(MockPermissionRepository)), mockAPIKeys, mockGenerator, authEntity.MFAPolicy{})
+
+		mockGenerator.On("Generate").Return("se_abc_secret", "abc", nil)
+		mockGenerator.On("Hash", "se_abc_secret").Return("hashed")
+		mockAPIKeys.On("Save", mock.Anything, mock.MatchedBy(func(k *authEntity.APIKey) bool {
+			return k.UserID == "user-123" && k.Name == "sync" && k.Prefix == "abc" && k.KeyHash == "hashed"
+		})).Return(&authEntity.APIKey{ID: "key-1", Grants: []string{"students:read"}}, nil)
+
+		apiKey, key, err := usecase.Create(context.Background(), apiKeyPrincipal(), " sync ", []string{"students:read"}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "se_abc_secret", key)
+		assert.Equal(t, []string{"students:read"}, apiKey.Grants)
+		mockAPIKeys.AssertExpectations(t)
+	})
+
+	t.Run("should reject grants the principal does not hold", func(t *testing.T) {
+		mockGenerator := new(MockAPIKeyGenerator)
+		usecase := NewAPIKeyUsecase(new(MockUserRepository), withoutRoles(new(MockPermissionRepository)), new(MockAPIKeyRepository), mockGenerator, authEntity.MFAPolicy{})
+
+		_, _, err := usecase.Create(context.Background(), apiKeyPrincipal(), "sync", []string{"users:read"}, nil)
+		assert.ErrorIs(t, err, authEntity.ErrAPIKeyScopeDenied)
+
+		_, _, err = usecase.Create(context.Background(), apiKeyPrincipal(), "sync", []string{"students:delete"}, nil)
+		assert.ErrorIs(t, err, authEntity.ErrAPIKeyScopeDenied)
+
+		_, _, err = usecase.Create(context.Background(), apiKeyPrincipal(), "sync", []string{"students:*"}, nil)
+		assert.ErrorIs(t, err, authEntity.ErrAPIKeyScopeDenied)
+
+		mockGenerator.AssertNotCalled(t, "Generate")
+	})
+
+	t.Run("should accept a grant the principal holds for a school", func(t *testing.T) {
+		mockAPIKeys := new(MockAPIKeyRepository)
+		mockGenerator := new(MockAPIKeyGenerator)
+		usecase := NewAPIKeyUsecase(new(MockUserRepository), withoutRoles(new(MockPermissionRepository)), mockAPIKeys, mockGenerator, authEntity.MFAPolicy{})
+
+		principal := apiKeyPrincipal()
+		principal.SchoolGrants = map[string][]string{"school-a": {"users:read"}}
+
+		mockGenerator.On("Generate").Return("se_abc_secret", "abc", nil)
+		mockGenerator.On("Hash", "se_abc_secret").Return("hashed")
+		mockAPIKeys.On("Save", mock.Anything, mock.Anything).Return(&authEntity.APIKey{ID: "key-1", Grants: []string{"users:read"}}, nil)
+
+		_, _, err := usecase.Create(context.Background(), principal, "sync", []string{"users:read"}, nil)
+
+		require.NoError(t, err)
+		mockAPIKeys.AssertExpectations(t)
+	})
+
+	t.Run("should reject an invalid scope or expiry", func(t *testing.T) {
+		usecase := NewAPIKeyUsecase(new(MockUserRepository), withoutRoles(new(MockPermissionRepository)), new(MockAPIKeyRepository), new(MockAPIKeyGenerator), authEntity.MFAPolicy{})
+
+		past := time.Now().Add(-time.Hour)
+
+		_, _, err := usecase.Create(context.Background(), apiKeyPrincipal(), "sync", nil, nil)
+		assert.ErrorIs(t, err, authEntity.ErrInvalidAPIKeyScope)
+
+		_, _, err = usecase.Create(context.Background(), apiKeyPrincipal(), "sync", []string{"students"}, nil)
+		assert.ErrorIs(t, err, authEntity.ErrInvalidAPIKeyScope)
+
+		_, _, err = usecase.Create(context.Background(), apiKeyPrincipal(), "sync", []string{"students:read"}, &past)
+		assert.ErrorIs(t, err, authEntity.ErrInvalidAPIKeyScope)
+	})
+
+	t.Run("should not let an API key create another key", func(t *testing.T) {
+		usecase := NewAPIKeyUsecase(new(MockUserRepository), withoutRoles(new(MockPermissionRepository)), new(MockAPIKeyRepository), new(MockAPIKeyGenerator), authEntity.MFAPolicy{})
+
+		principal := apiKeyPrincipal()
+		principal.AuthMethods = []string{authEntity.AuthMethodAPIKey}
+
+		_, _, err := usecase.Create(context.Background(), principal, "sync", []string{"students:read"}, nil)
+
+		assert.ErrorIs(t, err, authEntity.ErrAPIKeyNotAllowed)
+	})
+}
+
+func TestAPIKeyUsecase_Revoke(t *testing.T) {
+	t.Run("should return error when the key is not the user's", func(t *testing.T) {
+		mockAPIKeys := new(MockAPIKeyRepository)
+		usecase := NewAPIKeyUsecase(new(MockUserRepository), withoutRoles(new(MockPermissionRepository)), mockAPIKeys, new(MockAPIKeyGenerator), authEntity.MFAPolicy{})
+
+		mockAPIKeys.On("Revoke", mock.Anything, "key-1", "user-123").Return(port_auth_repository.ErrAPIKeyNotFound)
+
+		err := usecase.Revoke(context.Background(), "user-123", "key-1")
+
+		assert.ErrorIs(t, err, port_auth_repository.ErrAPIKeyNotFound)
+	})
+}
+
+func TestAPIKeyUsecase_Authenticate(t *testing.T) {
+	t.Run("should narrow the key to the user's current grants", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockAPIKeys := new(MockAPIKeyRepository)
+		mockGenerator := new(MockAPIKeyGenerator)
+		usecase := NewAPIKeyUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockAPIKeys, mockGenerator, authEntity.MFAPolicy{RequiredModules: []string{"users"}})
+
+		apiKey := authEntity.NewAPIKey("user-123", "sync", "abc", "hashed", []string{"students:*", "schools:read", "users:read"}, nil)
+		user := &userEntity.User{ID: "user-123", Name: "John", Email: "john@example.com"}
+		permissions := []*permissionEntity.Permission{
+			{UserID: "user-123", Grants: []string{"students:read", "users:read"}, Level: "admin"},
+		}
+
+		mockGenerator.On("Prefix", "se_abc_secret").Return("abc", true)
+		mockGenerator.On("Hash", "se_abc_secret").Return("hashed")
+		mockAPIKeys.On("FindByPrefix", mock.Anything, "abc").Return(apiKey, nil)
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return(permissions, nil)
+		mockAPIKeys.On("TouchLastUsed", mock.Anything, apiKey.ID, mock.Anything).Return(nil)
+
+		claims, err := usecase.Authenticate(context.Background(), "se_abc_secret")
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-123", claims.UserID)
+		assert.Equal(t, "john@example.com", claims.Email)
+		assert.Equal(t, []string{"students:read"}, claims.Grants, "schools was revoked from the user and users requires mfa")
+		assert.Equal(t, []string{authEntity.AuthMethodAPIKey}, claims.AuthMethods)
+		mockAPIKeys.AssertExpectations(t)
+	})
+
+	expired := time.Now().Add(-time.Minute)
+	revoked := time.Now()
+
+	tests := []struct {
+		name  string
+		setup func(apiKeys *MockAPIKeyRepository, generator *MockAPIKeyGenerator)
+	}{
+		{
+			name: "malformed key",
+			setup: func(_ *MockAPIKeyRepository, generator *MockAPIKeyGenerator) {
+				generator.On("Prefix", "key").Return("", false)
+			},
+		},
+		{
+			name: "unknown prefix",
+			setup: func(apiKeys *MockAPIKeyRepository, generator *MockAPIKeyGenerator) {
+				generator.On("Prefix", "key").Return("abc", true)
+				apiKeys.On("FindByPrefix", mock.Anything, "abc").Return(nil, port_auth_repository.ErrAPIKeyNotFound)
+			},
+		},
+		{
+			name: "hash mismatch",
+			setup: func(apiKeys *MockAPIKeyRepository, generator *MockAPIKeyGenerator) {
+				generator.On("Prefix", "key").Return("abc", true)
+				generator.On("Hash", "key").Return("other")
+				apiKeys.On("FindByPrefix", mock.Anything, "abc").Return(&authEntity.APIKey{KeyHash: "hashed"}, nil)
+			},
+		},
+		{
+			name: "expired",
+			setup: func(apiKeys *MockAPIKeyRepository, generator *MockAPIKeyGenerator) {
+				generator.On("Prefix", "key").Return("abc", true)
+				generator.On("Hash", "key").Return("hashed")
+				apiKeys.On("FindByPrefix", mock.Anything, "abc").Return(&authEntity.APIKey{KeyHash: "hashed", ExpiresAt: &expired}, nil)
+			},
+		},
+		{
+			name: "revoked",
+			setup: func(apiKeys *MockAPIKeyRepository, generator *MockAPIKeyGenerator) {
+				generator.On("Prefix", "key").Return("abc", true)
+				generator.On("Hash", "key").Return("hashed")
+				apiKeys.On("FindByPrefix", mock.Anything, "abc").Return(&authEntity.APIKey{KeyHash: "hashed", RevokedAt: &revoked}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("should reject a "+tt.name, func(t *testing.T) {
+			mockAPIKeys := new(MockAPIKeyRepository)
+			mockGenerator := new(MockAPIKeyGenerator)
+			usecase := NewAPIKeyUsecase(new(MockUserRepository), withoutRoles(new(MockPermissionRepository)), mockAPIKeys, mockGenerator, authEntity.MFAPolicy{})
+			tt.setup(mockAPIKeys, mockGenerator)
+
+			_, err := usecase.Authenticate(context.Background(), "key")
+
+			assert.ErrorIs(t, err, authEntity.ErrInvalidAPIKey)
+			mockAPIKeys.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("should return error when repository fails", func(t *testing.T) {
+		mockAPIKeys := new(MockAPIKeyRepository)
+		mockGenerator := new(MockAPIKeyGenerator)
+		usecase := NewAPIKeyUsecase(new(MockUserRepository), withoutRoles(new(MockPermissionRepository)), mockAPIKeys, mockGenerator, authEntity.MFAPolicy{})
+
+		mockGenerator.On("Prefix", "key").Return("abc", true)
+		mockAPIKeys.On("FindByPrefix", mock.Anything, "abc").Return(nil, errors.New("db down"))
+
+		_, err := usecase.Authenticate(context.Background(), "key")
+
+		assert.EqualError(t, err, "db down")
+	})
+}
@@ -3,85 +3,435 @@ package auth_usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
 	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
-	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
 	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
 	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	port_transaction "github.com/williamkoller/system-education/shared/port/transaction"
 )
 
 type AuthUsecase struct {
-	repo            port_user_repository.UserRepository
-	permissionRepo  port_permission_repository.PermissionRepository
-	jwtTokenManager port_auth_cryptography.TokenManager
-	passwordHasher  port_cryptography.Bcrypt
+	repo             port_user_repository.UserRepository
+	grants           port_auth_usecase.GrantResolver
+	jwtTokenManager  port_auth_cryptography.TokenManager
+	passwordHasher   port_cryptography.Bcrypt
+	refreshRepo      port_auth_repository.RefreshTokenRepository
+	refreshTokens    port_auth_cryptography.OpaqueTokenGenerator
+	refreshExpiresIn time.Duration
+	tx               port_transaction.Transactor
+	revocations      port_auth_repository.TokenRevocationStore
+	attempts         port_auth_repository.LoginAttemptStore
+	accountPolicy    auth_entity.LockoutPolicy
+	ipPolicy         auth_entity.LockoutPolicy
+	mfa              port_auth_repository.MFARepository
+	totp             port_auth_cryptography.TOTP
+	recoveryCodes    port_auth_cryptography.OpaqueTokenGenerator
+	mfaPolicy        auth_entity.MFAPolicy
+	// requireVerifiedEmail refuses logins until the account's e-mail is
+	// verified.
+	requireVerifiedEmail bool
 }
 
+const mfaChallengeExpiresIn = 5 * time.Minute
+
+// dummyPasswordHash is compared against when the e-mail is unknown so that the
+// response takes as long as a wrong password for an existing account.
+const dummyPasswordHash = "$2a$12$v0o/GVYClg6k.SXDtROJE.FmX1QzKqE.rBP0vHyMe9.WTGE5PdQwu"
+
 var _ port_auth_usecase.AuthUsecase = &AuthUsecase{}
 
 func NewAuthUsecase(
 	repo port_user_repository.UserRepository,
-	permissionRepo port_permission_repository.PermissionRepository,
+	grants port_auth_usecase.GrantResolver,
 	jwtTokenManager port_auth_cryptography.TokenManager,
 	passwordHasher port_cryptography.Bcrypt,
+	refreshRepo port_auth_repository.RefreshTokenRepository,
+	refreshTokens port_auth_cryptography.OpaqueTokenGenerator,
+	refreshExpiresIn time.Duration,
+	tx port_transaction.Transactor,
+	revocations port_auth_repository.TokenRevocationStore,
+	attempts port_auth_repository.LoginAttemptStore,
+	mfa port_auth_repository.MFARepository,
+	totp port_auth_cryptography.TOTP,
+	recoveryCodes port_auth_cryptography.OpaqueTokenGenerator,
+	mfaPolicy auth_entity.MFAPolicy,
+	requireVerifiedEmail bool,
 ) *AuthUsecase {
 	return &AuthUsecase{
-		repo:            repo,
-		permissionRepo:  permissionRepo,
-		jwtTokenManager: jwtTokenManager,
-		passwordHasher:  passwordHasher,
+		repo:                 repo,
+		grants:               grants,
+		jwtTokenManager:      jwtTokenManager,
+		passwordHasher:       passwordHasher,
+		refreshRepo:          refreshRepo,
+		refreshTokens:        refreshTokens,
+		refreshExpiresIn:     refreshExpiresIn,
+		tx:                   tx,
+		revocations:          revocations,
+		attempts:             attempts,
+		accountPolicy:        auth_entity.DefaultAccountLockoutPolicy,
+		ipPolicy:             auth_entity.DefaultIPLockoutPolicy,
+		mfa:                  mfa,
+		totp:                 totp,
+		recoveryCodes:        recoveryCodes,
+		mfaPolicy:            mfaPolicy,
+		requireVerifiedEmail: requireVerifiedEmail,
 	}
 }
 
-func (a *AuthUsecase) Login(ctx context.Context, email, password string) (string, error) {
+func (a *AuthUsecase) Login(ctx context.Context, email, password, clientIP string) (*auth_entity.TokenPair, error) {
+	now := time.Now()
+	accountKey := accountAttemptKey(email)
+	ipKey := ipAttemptKey(clientIP)
+
+	if err := a.checkThrottle(ctx, now, accountKey, ipKey); err != nil {
+		return nil, err
+	}
+
 	user, err := a.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, port_user_repository.ErrUserNotFound) {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		_, _ = a.passwordHasher.HashComparer(password, dummyPasswordHash)
+		return nil, a.registerFailure(ctx, now, accountKey, ipKey)
+	}
+
+	if ok, err := a.passwordHasher.HashComparer(password, user.Password); err != nil || !ok {
+		return nil, a.registerFailure(ctx, now, accountKey, ipKey)
+	}
+
+	// Checked after the password so the answer does not reveal whether an
+	// address has an unverified account.
+	if a.requireVerifiedEmail && !user.IsEmailVerified() {
+		return nil, auth_entity.ErrEmailNotVerified
+	}
 
+	enrollment, err := findConfirmedEnrollment(ctx, a.mfa, user.ID)
 	if err != nil {
-		return "", errors.New("user not found")
+		return nil, err
+	}
+	if enrollment != nil {
+		return nil, a.startMFAChallenge(ctx, user.ID)
 	}
 
-	if _, err := a.passwordHasher.HashComparer(password, user.Password); err != nil {
-		return "", errors.New("invalid credentials")
+	if err := a.attempts.Reset(ctx, accountKey); err != nil {
+		return nil, fmt.Errorf("failed to reset login attempts: %w", err)
 	}
 
-	// Fetch user permissions and extract modules
-	modules := []string{}
-	actions := []string{}
-	permissions, err := a.permissionRepo.FindPermissionByUserID(ctx, user.ID)
-	if err == nil && len(permissions) > 0 {
-		for _, permission := range permissions {
-			modules = append(modules, permission.GetModules()...)
-			actions = append(actions, permission.GetActions()...)
+	refreshToken, plain, err := a.newRefreshToken(user.ID, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.issueTokenPair(ctx, user, refreshToken, plain)
+}
+
+// VerifyMFA completes a login that returned MFARequiredError. The code may be
+// a TOTP code or one of the user's recovery codes; wrong codes count towards
+// the same lockout as wrong passwords.
+func (a *AuthUsecase) VerifyMFA(ctx context.Context, mfaToken, code, clientIP string) (*auth_entity.TokenPair, error) {
+	now := time.Now()
+
+	challenge, err := a.mfa.FindChallengeByHash(ctx, a.refreshTokens.Hash(mfaToken))
+	if err != nil {
+		if errors.Is(err, port_auth_repository.ErrMFAChallengeNotFound) {
+			return nil, auth_entity.ErrInvalidMFAToken
 		}
+		return nil, fmt.Errorf("failed to find mfa challenge: %w", err)
 	}
 
-	userSign := map[string]interface{}{
-		"user_id":    user.ID,
-		"name":       user.Name,
-		"nick_name":  user.Nickname,
-		"email":      user.Email,
-		"modules":    modules,
-		"actions":    actions,
-		"created_at": user.CreatedAt,
-		"updated_at": user.UpdatedAt,
+	if challenge.IsUsed() || challenge.IsExpired(now) {
+		return nil, auth_entity.ErrInvalidMFAToken
+	}
+
+	user, err := a.repo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, auth_entity.ErrInvalidMFAToken
 	}
 
-	token, err := a.jwtTokenManager.Sign(userSign)
+	accountKey := accountAttemptKey(user.Email)
+	ipKey := ipAttemptKey(clientIP)
 
+	if err := a.checkThrottle(ctx, now, accountKey, ipKey); err != nil {
+		return nil, err
+	}
+
+	enrollment, err := findConfirmedEnrollment(ctx, a.mfa, user.ID)
 	if err != nil {
-		return "", errors.New("error in generate token")
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, auth_entity.ErrInvalidMFAToken
 	}
 
-	return token, nil
+	if err := verifySecondFactor(ctx, a.mfa, a.totp, a.recoveryCodes, enrollment, code, now); err != nil {
+		if !errors.Is(err, auth_entity.ErrInvalidMFACode) {
+			return nil, err
+		}
+		if err := a.registerFailure(ctx, now, accountKey, ipKey); !errors.Is(err, auth_entity.ErrInvalidCredentials) {
+			return nil, err
+		}
+		return nil, auth_entity.ErrInvalidMFACode
+	}
+
+	ok, err := a.mfa.MarkChallengeUsed(ctx, challenge.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume mfa challenge: %w", err)
+	}
+	if !ok {
+		return nil, auth_entity.ErrInvalidMFAToken
+	}
+
+	if err := a.attempts.Reset(ctx, accountKey); err != nil {
+		return nil, fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+
+	refreshToken, plain, err := a.newRefreshToken(user.ID, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.issueTokenPair(ctx, user, refreshToken, plain)
 }
 
-func (a *AuthUsecase) Profile(ctx context.Context, email string) (string, error) {
-	user, err := a.repo.FindByEmail(ctx, email)
+func (a *AuthUsecase) Refresh(ctx context.Context, refreshToken string) (*auth_entity.TokenPair, error) {
+	current, err := a.refreshRepo.FindByHash(ctx, a.refreshTokens.Hash(refreshToken))
+	if err != nil {
+		return nil, auth_entity.ErrInvalidRefreshToken
+	}
+
+	if current.IsRevoked() {
+		return nil, a.revokeFamily(ctx, current.FamilyID)
+	}
+
+	if current.IsExpired(time.Now()) {
+		return nil, auth_entity.ErrInvalidRefreshToken
+	}
+
+	revoked, err := a.revocations.IsRevoked(ctx, "", current.UserID, current.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check session revocation: %w", err)
+	}
+	if revoked {
+		return nil, auth_entity.ErrInvalidRefreshToken
+	}
+
+	user, err := a.repo.FindByID(ctx, current.UserID)
+	if err != nil {
+		return nil, auth_entity.ErrInvalidRefreshToken
+	}
+
+	next, plain, err := a.newRefreshToken(user.ID, current.FamilyID, current.MFAVerified)
+	if err != nil {
+		return nil, err
+	}
+
+	// The old token is only spent together with saving its replacement, so a
+	// failed save leaves the session usable. Signing waits for the commit.
+	err = a.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := a.refreshRepo.Revoke(ctx, current.ID, next.ID); err != nil {
+			if errors.Is(err, port_auth_repository.ErrRefreshTokenRevoked) {
+				return err
+			}
+			return fmt.Errorf("failed to rotate refresh token: %w", err)
+		}
+		if _, err := a.refreshRepo.Save(ctx, next); err != nil {
+			return fmt.Errorf("failed to save refresh token: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, port_auth_repository.ErrRefreshTokenRevoked) {
+		return nil, a.revokeFamily(ctx, current.FamilyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := a.signAccessToken(ctx, user, next.MFAVerified)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth_entity.TokenPair{
+		AccessToken:           accessToken,
+		RefreshToken:          plain,
+		RefreshTokenExpiresAt: next.ExpiresAt,
+	}, nil
+}
+
+func (a *AuthUsecase) Logout(ctx context.Context, userID, jti string, expiresAt time.Time, refreshToken string) error {
+	if err := a.revocations.Revoke(ctx, jti, userID, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
 
+	if refreshToken == "" {
+		return nil
+	}
+
+	current, err := a.refreshRepo.FindByHash(ctx, a.refreshTokens.Hash(refreshToken))
+	if err != nil || current.UserID != userID {
+		return nil
+	}
+
+	if err := a.refreshRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+func (a *AuthUsecase) Unlock(ctx context.Context, userID string) error {
+	user, err := a.repo.FindByID(ctx, userID)
 	if err != nil {
-		return "", errors.New("user not found")
+		return err
+	}
+
+	if err := a.attempts.Reset(ctx, accountAttemptKey(user.Email)); err != nil {
+		return fmt.Errorf("failed to unlock account: %w", err)
+	}
+
+	return nil
+}
+
+func (a *AuthUsecase) checkThrottle(ctx context.Context, now time.Time, accountKey, ipKey string) error {
+	var blockedUntil time.Time
+
+	keys := map[string]auth_entity.LockoutPolicy{accountKey: a.accountPolicy}
+	if ipKey != "" {
+		keys[ipKey] = a.ipPolicy
+	}
+
+	for key, policy := range keys {
+		attempt, err := a.attempts.Find(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to check login attempts: %w", err)
+		}
+		if until := attempt.BlockedUntil(now, policy); until.After(blockedUntil) {
+			blockedUntil = until
+		}
+	}
+
+	if now.Before(blockedUntil) {
+		return &auth_entity.LoginThrottledError{RetryAfter: blockedUntil.Sub(now)}
+	}
+
+	return nil
+}
+
+func (a *AuthUsecase) registerFailure(ctx context.Context, now time.Time, accountKey, ipKey string) error {
+	if _, err := a.attempts.RegisterFailure(ctx, accountKey, now, a.accountPolicy.Window); err != nil {
+		return fmt.Errorf("failed to register login attempt: %w", err)
+	}
+
+	if ipKey != "" {
+		if _, err := a.attempts.RegisterFailure(ctx, ipKey, now, a.ipPolicy.Window); err != nil {
+			return fmt.Errorf("failed to register login attempt: %w", err)
+		}
 	}
 
-	return user.Email, nil
+	return auth_entity.ErrInvalidCredentials
+}
+
+func accountAttemptKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptKey(clientIP string) string {
+	if clientIP == "" {
+		return ""
+	}
+	return "ip:" + clientIP
+}
+
+func (a *AuthUsecase) revokeFamily(ctx context.Context, familyID string) error {
+	if err := a.refreshRepo.RevokeFamily(ctx, familyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return auth_entity.ErrRefreshTokenReused
+}
+
+func (a *AuthUsecase) newRefreshToken(userID, familyID string, mfaVerified bool) (*auth_entity.RefreshToken, string, error) {
+	plain, err := a.refreshTokens.Generate()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	token := auth_entity.NewRefreshToken(userID, familyID, a.refreshTokens.Hash(plain), a.refreshExpiresIn)
+	token.MFAVerified = mfaVerified
+
+	return token, plain, nil
+}
+
+func (a *AuthUsecase) startMFAChallenge(ctx context.Context, userID string) error {
+	plain, err := a.refreshTokens.Generate()
+	if err != nil {
+		return fmt.Errorf("failed to generate mfa token: %w", err)
+	}
+
+	challenge := auth_entity.NewMFAChallenge(userID, a.refreshTokens.Hash(plain), mfaChallengeExpiresIn)
+	if err := a.mfa.SaveChallenge(ctx, challenge); err != nil {
+		return fmt.Errorf("failed to save mfa challenge: %w", err)
+	}
+
+	return &auth_entity.MFARequiredError{Token: plain, ExpiresAt: challenge.ExpiresAt}
+}
+
+func (a *AuthUsecase) issueTokenPair(ctx context.Context, user *user_entity.User, refreshToken *auth_entity.RefreshToken, plain string) (*auth_entity.TokenPair, error) {
+	accessToken, err := a.signAccessToken(ctx, user, refreshToken.MFAVerified)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := a.refreshRepo.Save(ctx, refreshToken); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return &auth_entity.TokenPair{
+		AccessToken:           accessToken,
+		RefreshToken:          plain,
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt,
+	}, nil
+}
+
+func (a *AuthUsecase) signAccessToken(ctx context.Context, user *user_entity.User, mfaVerified bool) (string, error) {
+	// Direct permissions and role grants are merged; a lookup failure still
+	// issues a token, just without grants.
+	grants, err := a.grants.Resolve(ctx, user.ID)
+	if err != nil {
+		grants = permission_entity.ScopedGrants{Grants: []string{}}
+	}
+	grants = grants.Map(func(g []string) []string {
+		return a.mfaPolicy.Filter(g, mfaVerified)
+	})
+
+	authMethods := []string{auth_entity.AuthMethodPassword}
+	if mfaVerified {
+		authMethods = append(authMethods, auth_entity.AuthMethodMFA)
+	}
+
+	claims := auth_entity.Claims{
+		UserID:       user.ID,
+		Name:         user.Name,
+		Nickname:     user.Nickname,
+		Email:        user.Email,
+		Grants:       grants.Grants,
+		SchoolGrants: grants.Schools,
+		AuthMethods:  authMethods,
+	}
+
+	token, err := a.jwtTokenManager.Sign(claims)
+
+	if err != nil {
+		return "", errors.New("error in generate token")
+	}
+
+	return token, nil
 }
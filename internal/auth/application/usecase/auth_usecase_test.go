@@ -8,8 +8,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	authEntity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_memory "github.com/williamkoller/system-education/internal/auth/infra/memory"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
 	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	roleEntity "github.com/williamkoller/system-education/internal/role/domain/entity"
 	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
 )
 
 type MockUserRepository struct {
@@ -48,6 +53,11 @@ func (m *MockUserRepository) Update(ctx context.Context, id string, user *userEn
 	return args.Get(0).(*userEntity.User), args.Error(1)
 }
 
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error {
+	args := m.Called(ctx, id, verifiedAt)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -65,17 +75,17 @@ type MockTokenManager struct {
 	mock.Mock
 }
 
-func (m *MockTokenManager) Sign(data map[string]interface{}) (string, error) {
-	args := m.Called(data)
+func (m *MockTokenManager) Sign(claims authEntity.Claims) (string, error) {
+	args := m.Called(claims)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockTokenManager) Verify(token string) (map[string]interface{}, error) {
+func (m *MockTokenManager) Verify(token string) (*authEntity.Claims, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(map[string]interface{}), args.Error(1)
+	return args.Get(0).(*authEntity.Claims), args.Error(1)
 }
 
 type MockBcrypt struct {
@@ -141,13 +151,60 @@ func (m *MockPermissionRepository) FindAll(ctx context.Context) ([]*permissionEn
 	return args.Get(0).([]*permissionEntity.Permission), args.Error(1)
 }
 
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Save(ctx context.Context, token *authEntity.RefreshToken) (*authEntity.RefreshToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return token, args.Error(1)
+	}
+	return args.Get(0).(*authEntity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*authEntity.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authEntity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id string, replacedByID string) error {
+	args := m.Called(ctx, id, replacedByID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+type MockOpaqueTokenGenerator struct {
+	mock.Mock
+}
+
+func (m *MockOpaqueTokenGenerator) Generate() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockOpaqueTokenGenerator) Hash(token string) string {
+	args := m.Called(token)
+	return args.String(0)
+}
+
 func TestAuthUsecase_Login_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockPermissionRepo := new(MockPermissionRepository)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
 
 	email := "test@example.com"
 	password := "password123"
@@ -168,19 +225,26 @@ func TestAuthUsecase_Login_Success(t *testing.T) {
 
 	mockRepo.On("FindByEmail", mock.Anything, email).Return(user, nil)
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(true, nil)
+	mockRefreshTokens.On("Generate").Return("refresh-token", nil)
+	mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
 	mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return([]*permissionEntity.Permission{}, nil)
-	mockTokenManager.On("Sign", mock.MatchedBy(func(data map[string]interface{}) bool {
-		return data["email"] == email && data["name"] == "John" && data["user_id"] == "user-123"
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims authEntity.Claims) bool {
+		return claims.Email == email && claims.Name == "John" && claims.UserID == "user-123"
 	})).Return(expectedToken, nil)
+	mockRefreshRepo.On("Save", mock.Anything, mock.MatchedBy(func(rt *authEntity.RefreshToken) bool {
+		return rt.UserID == "user-123" && rt.TokenHash == "refresh-hash"
+	})).Return(nil, nil)
 
-	token, err := usecase.Login(context.Background(), email, password)
+	token, err := usecase.Login(context.Background(), email, password, "127.0.0.1")
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, expectedToken, token.AccessToken)
+	assert.Equal(t, "refresh-token", token.RefreshToken)
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
 	mockPermissionRepo.AssertExpectations(t)
 	mockTokenManager.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
 }
 
 func TestAuthUsecase_Login_UserNotFound(t *testing.T) {
@@ -188,20 +252,25 @@ func TestAuthUsecase_Login_UserNotFound(t *testing.T) {
 	mockPermissionRepo := new(MockPermissionRepository)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
 
 	email := "nonexistent@example.com"
 	password := "password123"
 
-	mockRepo.On("FindByEmail", mock.Anything, email).Return(nil, errors.New("not found"))
+	mockRepo.On("FindByEmail", mock.Anything, email).Return(nil, port_user_repository.ErrUserNotFound)
+	mockBcrypt.On("HashComparer", password, dummyPasswordHash).Return(false, errors.New("password mismatch"))
 
-	token, err := usecase.Login(context.Background(), email, password)
+	token, err := usecase.Login(context.Background(), email, password, "127.0.0.1")
 
 	assert.Error(t, err)
-	assert.Equal(t, "", token)
-	assert.Equal(t, "user not found", err.Error())
+	assert.Nil(t, token)
+	assert.Equal(t, "invalid credentials", err.Error())
 	mockRepo.AssertExpectations(t)
+	mockBcrypt.AssertExpectations(t)
 }
 
 func TestAuthUsecase_Login_InvalidPassword(t *testing.T) {
@@ -209,8 +278,11 @@ func TestAuthUsecase_Login_InvalidPassword(t *testing.T) {
 	mockPermissionRepo := new(MockPermissionRepository)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
 
 	email := "test@example.com"
 	password := "wrongpassword"
@@ -231,11 +303,11 @@ func TestAuthUsecase_Login_InvalidPassword(t *testing.T) {
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(false, errors.New("password mismatch"))
 
 	// Act
-	token, err := usecase.Login(context.Background(), email, password)
+	token, err := usecase.Login(context.Background(), email, password, "127.0.0.1")
 
 	// Assert
 	assert.Error(t, err)
-	assert.Equal(t, "", token)
+	assert.Nil(t, token)
 	assert.Equal(t, "invalid credentials", err.Error())
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
@@ -246,8 +318,11 @@ func TestAuthUsecase_Login_TokenGenerationError(t *testing.T) {
 	mockPermissionRepo := new(MockPermissionRepository)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
 
 	email := "test@example.com"
 	password := "password123"
@@ -266,68 +341,21 @@ func TestAuthUsecase_Login_TokenGenerationError(t *testing.T) {
 
 	mockRepo.On("FindByEmail", mock.Anything, email).Return(user, nil)
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(true, nil)
+	mockRefreshTokens.On("Generate").Return("refresh-token", nil)
+	mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
 	mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return([]*permissionEntity.Permission{}, nil)
 	mockTokenManager.On("Sign", mock.Anything).Return("", errors.New("token generation failed"))
 
-	token, err := usecase.Login(context.Background(), email, password)
+	token, err := usecase.Login(context.Background(), email, password, "127.0.0.1")
 
 	assert.Error(t, err)
-	assert.Equal(t, "", token)
+	assert.Nil(t, token)
 	assert.Equal(t, "error in generate token", err.Error())
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
 	mockPermissionRepo.AssertExpectations(t)
 	mockTokenManager.AssertExpectations(t)
-}
-
-func TestAuthUsecase_Profile_Success(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	mockPermissionRepo := new(MockPermissionRepository)
-	mockTokenManager := new(MockTokenManager)
-	mockBcrypt := new(MockBcrypt)
-
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
-
-	email := "test@example.com"
-
-	user := &userEntity.User{
-		ID:        "user-123",
-		Name:      "John",
-		Surname:   "Doe",
-		Nickname:  "johndoe",
-		Email:     email,
-		Password:  "$2a$10$hashedpassword",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	mockRepo.On("FindByEmail", mock.Anything, email).Return(user, nil)
-
-	result, err := usecase.Profile(context.Background(), email)
-
-	assert.NoError(t, err)
-	assert.Equal(t, email, result)
-	mockRepo.AssertExpectations(t)
-}
-
-func TestAuthUsecase_Profile_UserNotFound(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	mockPermissionRepo := new(MockPermissionRepository)
-	mockTokenManager := new(MockTokenManager)
-	mockBcrypt := new(MockBcrypt)
-
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
-
-	email := "nonexistent@example.com"
-
-	mockRepo.On("FindByEmail", mock.Anything, email).Return(nil, errors.New("not found"))
-
-	result, err := usecase.Profile(context.Background(), email)
-
-	assert.Error(t, err)
-	assert.Equal(t, "", result)
-	assert.Equal(t, "user not found", err.Error())
-	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
 }
 
 func TestAuthUsecase_Login_WithPermissionsAndModules(t *testing.T) {
@@ -335,8 +363,11 @@ func TestAuthUsecase_Login_WithPermissionsAndModules(t *testing.T) {
 	mockPermissionRepo := new(MockPermissionRepository)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
 
 	email := "test@example.com"
 	password := "password123"
@@ -356,11 +387,13 @@ func TestAuthUsecase_Login_WithPermissionsAndModules(t *testing.T) {
 	// Create permissions with modules
 	permission1 := &permissionEntity.Permission{}
 	permission1.UserID = "user-123"
-	permission1.Modules = []string{"admin", "user"}
+	permission1.Grants = []string{"admin:read", "user:read"}
+	permission1.Level = "admin"
 
 	permission2 := &permissionEntity.Permission{}
 	permission2.UserID = "user-123"
-	permission2.Modules = []string{"reports"}
+	permission2.Grants = []string{"reports:read"}
+	permission2.Level = "admin"
 
 	permissions := []*permissionEntity.Permission{permission1, permission2}
 
@@ -368,26 +401,30 @@ func TestAuthUsecase_Login_WithPermissionsAndModules(t *testing.T) {
 
 	mockRepo.On("FindByEmail", mock.Anything, email).Return(user, nil)
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(true, nil)
+	mockRefreshTokens.On("Generate").Return("refresh-token", nil)
+	mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
 	mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return(permissions, nil)
-	mockTokenManager.On("Sign", mock.MatchedBy(func(data map[string]interface{}) bool {
-		modules, ok := data["modules"].([]string)
-		if !ok {
-			return false
-		}
-		// Should have all modules from both permissions
-		return len(modules) == 3 &&
-			data["email"] == email &&
-			data["user_id"] == "user-123"
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims authEntity.Claims) bool {
+		grants := claims.Grants
+		// Should have all grants from both permissions
+		return len(grants) == 3 &&
+			claims.Email == email &&
+			claims.UserID == "user-123"
 	})).Return(expectedToken, nil)
+	mockRefreshRepo.On("Save", mock.Anything, mock.MatchedBy(func(rt *authEntity.RefreshToken) bool {
+		return rt.UserID == "user-123" && rt.TokenHash == "refresh-hash"
+	})).Return(nil, nil)
 
-	token, err := usecase.Login(context.Background(), email, password)
+	token, err := usecase.Login(context.Background(), email, password, "127.0.0.1")
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, expectedToken, token.AccessToken)
+	assert.Equal(t, "refresh-token", token.RefreshToken)
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
 	mockPermissionRepo.AssertExpectations(t)
 	mockTokenManager.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
 }
 
 func TestAuthUsecase_Login_PermissionFetchError(t *testing.T) {
@@ -395,8 +432,11 @@ func TestAuthUsecase_Login_PermissionFetchError(t *testing.T) {
 	mockPermissionRepo := new(MockPermissionRepository)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
 
 	email := "test@example.com"
 	password := "password123"
@@ -417,27 +457,31 @@ func TestAuthUsecase_Login_PermissionFetchError(t *testing.T) {
 
 	mockRepo.On("FindByEmail", mock.Anything, email).Return(user, nil)
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(true, nil)
+	mockRefreshTokens.On("Generate").Return("refresh-token", nil)
+	mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
 	// Permission fetch fails, but login should still succeed with empty modules
 	mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return(nil, errors.New("permission db error"))
-	mockTokenManager.On("Sign", mock.MatchedBy(func(data map[string]interface{}) bool {
-		modules, ok := data["modules"].([]string)
-		if !ok {
-			return false
-		}
-		// Should have empty modules array when permission fetch fails
-		return len(modules) == 0 &&
-			data["email"] == email &&
-			data["user_id"] == "user-123"
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims authEntity.Claims) bool {
+		grants := claims.Grants
+		// Should have empty grants array when permission fetch fails
+		return len(grants) == 0 &&
+			claims.Email == email &&
+			claims.UserID == "user-123"
 	})).Return(expectedToken, nil)
+	mockRefreshRepo.On("Save", mock.Anything, mock.MatchedBy(func(rt *authEntity.RefreshToken) bool {
+		return rt.UserID == "user-123" && rt.TokenHash == "refresh-hash"
+	})).Return(nil, nil)
 
-	token, err := usecase.Login(context.Background(), email, password)
+	token, err := usecase.Login(context.Background(), email, password, "127.0.0.1")
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, expectedToken, token.AccessToken)
+	assert.Equal(t, "refresh-token", token.RefreshToken)
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
 	mockPermissionRepo.AssertExpectations(t)
 	mockTokenManager.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
 }
 
 func TestAuthUsecase_Login_WithPermissionsIncludingActions(t *testing.T) {
@@ -445,8 +489,11 @@ func TestAuthUsecase_Login_WithPermissionsIncludingActions(t *testing.T) {
 	mockPermissionRepo := new(MockPermissionRepository)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
 
 	email := "test@example.com"
 	password := "password123"
@@ -463,16 +510,16 @@ func TestAuthUsecase_Login_WithPermissionsIncludingActions(t *testing.T) {
 		UpdatedAt: time.Now(),
 	}
 
-	// Create permissions with both modules and actions
+	// Create permissions with grants on several modules and actions
 	permission1 := &permissionEntity.Permission{}
 	permission1.UserID = "user-123"
-	permission1.Modules = []string{"admin", "user"}
-	permission1.Actions = []string{"create", "read"}
+	permission1.Grants = []string{"admin:create", "admin:read", "user:read"}
+	permission1.Level = "admin"
 
 	permission2 := &permissionEntity.Permission{}
 	permission2.UserID = "user-123"
-	permission2.Modules = []string{"reports"}
-	permission2.Actions = []string{"update", "delete", "export"}
+	permission2.Grants = []string{"reports:update", "reports:delete", "reports:export"}
+	permission2.Level = "admin"
 
 	permissions := []*permissionEntity.Permission{permission1, permission2}
 
@@ -480,27 +527,514 @@ func TestAuthUsecase_Login_WithPermissionsIncludingActions(t *testing.T) {
 
 	mockRepo.On("FindByEmail", mock.Anything, email).Return(user, nil)
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(true, nil)
+	mockRefreshTokens.On("Generate").Return("refresh-token", nil)
+	mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
 	mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return(permissions, nil)
-	mockTokenManager.On("Sign", mock.MatchedBy(func(data map[string]interface{}) bool {
-		modules, modulesOk := data["modules"].([]string)
-		actions, actionsOk := data["actions"].([]string)
-		if !modulesOk || !actionsOk {
-			return false
-		}
-		// Should have all modules from both permissions (3 total)
-		// Should have all actions from both permissions (5 total)
-		return len(modules) == 3 &&
-			len(actions) == 5 &&
-			data["email"] == email &&
-			data["user_id"] == "user-123"
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims authEntity.Claims) bool {
+		// Should have all grants from both permissions (6 total),
+		// without combining actions across modules
+		return len(claims.Grants) == 6 &&
+			claims.Allows("reports", "delete") &&
+			!claims.Allows("admin", "delete") &&
+			!claims.Allows("user", "create") &&
+			claims.Email == email &&
+			claims.UserID == "user-123"
 	})).Return(expectedToken, nil)
+	mockRefreshRepo.On("Save", mock.Anything, mock.MatchedBy(func(rt *authEntity.RefreshToken) bool {
+		return rt.UserID == "user-123" && rt.TokenHash == "refresh-hash"
+	})).Return(nil, nil)
 
-	token, err := usecase.Login(context.Background(), email, password)
+	token, err := usecase.Login(context.Background(), email, password, "127.0.0.1")
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, expectedToken, token.AccessToken)
+	assert.Equal(t, "refresh-token", token.RefreshToken)
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
 	mockPermissionRepo.AssertExpectations(t)
 	mockTokenManager.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Refresh_RotatesToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	user := &userEntity.User{ID: "user-123", Name: "John", Email: "test@example.com"}
+	current := authEntity.NewRefreshToken("user-123", "family-1", "old-hash", time.Hour)
+
+	mockRefreshTokens.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByHash", mock.Anything, "old-hash").Return(current, nil)
+	mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+	mockRefreshTokens.On("Generate").Return("new-token", nil)
+	mockRefreshTokens.On("Hash", "new-token").Return("new-hash")
+	mockRefreshRepo.On("Revoke", mock.Anything, current.ID, mock.AnythingOfType("string")).Return(nil)
+	mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return([]*permissionEntity.Permission{}, nil)
+	mockTokenManager.On("Sign", mock.Anything).Return("new.access.token", nil)
+	mockRefreshRepo.On("Save", mock.Anything, mock.MatchedBy(func(rt *authEntity.RefreshToken) bool {
+		return rt.FamilyID == "family-1" && rt.TokenHash == "new-hash"
+	})).Return(nil, nil)
+
+	pair, err := usecase.Refresh(context.Background(), "old-token")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new.access.token", pair.AccessToken)
+	assert.Equal(t, "new-token", pair.RefreshToken)
+	mockRefreshRepo.AssertExpectations(t)
+	mockTokenManager.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Refresh_FailedSaveKeepsTheCurrentToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	tx := &recordingTransactor{}
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), mockTokenManager, new(MockBcrypt), mockRefreshRepo, mockRefreshTokens, time.Hour, tx, auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	current := authEntity.NewRefreshToken("user-123", "family-1", "old-hash", time.Hour)
+
+	mockRefreshTokens.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByHash", mock.Anything, "old-hash").Return(current, nil)
+	mockRepo.On("FindByID", mock.Anything, "user-123").Return(&userEntity.User{ID: "user-123"}, nil)
+	mockRefreshTokens.On("Generate").Return("new-token", nil)
+	mockRefreshTokens.On("Hash", "new-token").Return("new-hash")
+	mockRefreshRepo.On("Revoke", mock.Anything, current.ID, mock.AnythingOfType("string")).Return(nil)
+	mockRefreshRepo.On("Save", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	pair, err := usecase.Refresh(context.Background(), "old-token")
+
+	assert.Nil(t, pair)
+	assert.ErrorContains(t, err, "failed to save refresh token")
+	// The revocation shares the failed transaction, so it is rolled back.
+	assert.ErrorContains(t, tx.err, "db error")
+	mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+}
+
+func TestAuthUsecase_Refresh_UnknownToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	mockRefreshTokens.On("Hash", "unknown").Return("unknown-hash")
+	mockRefreshRepo.On("FindByHash", mock.Anything, "unknown-hash").Return(nil, errors.New("not found"))
+
+	pair, err := usecase.Refresh(context.Background(), "unknown")
+
+	assert.Nil(t, pair)
+	assert.ErrorIs(t, err, authEntity.ErrInvalidRefreshToken)
+}
+
+func TestAuthUsecase_Refresh_ExpiredToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	expired := authEntity.NewRefreshToken("user-123", "family-1", "old-hash", -time.Minute)
+
+	mockRefreshTokens.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByHash", mock.Anything, "old-hash").Return(expired, nil)
+
+	pair, err := usecase.Refresh(context.Background(), "old-token")
+
+	assert.Nil(t, pair)
+	assert.ErrorIs(t, err, authEntity.ErrInvalidRefreshToken)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Refresh_ReuseRevokesFamily(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	rotated := authEntity.NewRefreshToken("user-123", "family-1", "old-hash", time.Hour)
+	revokedAt := time.Now()
+	rotated.RevokedAt = &revokedAt
+
+	mockRefreshTokens.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByHash", mock.Anything, "old-hash").Return(rotated, nil)
+	mockRefreshRepo.On("RevokeFamily", mock.Anything, "family-1").Return(nil)
+
+	pair, err := usecase.Refresh(context.Background(), "old-token")
+
+	assert.Nil(t, pair)
+	assert.ErrorIs(t, err, authEntity.ErrRefreshTokenReused)
+	mockRefreshRepo.AssertExpectations(t)
+	mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+}
+
+func TestAuthUsecase_Refresh_ConcurrentRotationRevokesFamily(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com"}
+	current := authEntity.NewRefreshToken("user-123", "family-1", "old-hash", time.Hour)
+
+	mockRefreshTokens.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByHash", mock.Anything, "old-hash").Return(current, nil)
+	mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+	mockRefreshTokens.On("Generate").Return("new-token", nil)
+	mockRefreshTokens.On("Hash", "new-token").Return("new-hash")
+	mockRefreshRepo.On("Revoke", mock.Anything, current.ID, mock.AnythingOfType("string")).Return(port_auth_repository.ErrRefreshTokenRevoked)
+	mockRefreshRepo.On("RevokeFamily", mock.Anything, "family-1").Return(nil)
+
+	pair, err := usecase.Refresh(context.Background(), "old-token")
+
+	assert.Nil(t, pair)
+	assert.ErrorIs(t, err, authEntity.ErrRefreshTokenReused)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Refresh_RejectsTokenIssuedBeforeUserRevocation(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	current := authEntity.NewRefreshToken("user-123", "family-1", "old-hash", time.Hour)
+	current.CreatedAt = time.Now().Add(-time.Minute)
+	_ = revocations.RevokeAllForUser(context.Background(), "user-123")
+
+	mockRefreshTokens.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByHash", mock.Anything, "old-hash").Return(current, nil)
+
+	pair, err := usecase.Refresh(context.Background(), "old-token")
+
+	assert.Nil(t, pair)
+	assert.ErrorIs(t, err, authEntity.ErrInvalidRefreshToken)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Logout_RevokesAccessTokenAndRefreshFamily(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	current := authEntity.NewRefreshToken("user-123", "family-1", "refresh-hash", time.Hour)
+
+	mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
+	mockRefreshRepo.On("FindByHash", mock.Anything, "refresh-hash").Return(current, nil)
+	mockRefreshRepo.On("RevokeFamily", mock.Anything, "family-1").Return(nil)
+
+	err := usecase.Logout(context.Background(), "user-123", "jti-123", time.Now().Add(time.Hour), "refresh-token")
+
+	assert.NoError(t, err)
+	revoked, _ := revocations.IsRevoked(context.Background(), "jti-123", "user-123", time.Now())
+	assert.True(t, revoked)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Logout_IgnoresRefreshTokenOfAnotherUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	other := authEntity.NewRefreshToken("user-999", "family-9", "refresh-hash", time.Hour)
+
+	mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
+	mockRefreshRepo.On("FindByHash", mock.Anything, "refresh-hash").Return(other, nil)
+
+	err := usecase.Logout(context.Background(), "user-123", "jti-123", time.Now().Add(time.Hour), "refresh-token")
+
+	assert.NoError(t, err)
+	mockRefreshRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Login_ThrottlesAfterRepeatedFailures(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}
+	mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "wrongpassword", "hashed").Return(false, errors.New("password mismatch"))
+
+	for i := 0; i < authEntity.DefaultAccountLockoutPolicy.FreeAttempts+1; i++ {
+		_, err := usecase.Login(context.Background(), "test@example.com", "wrongpassword", "10.0.0.1")
+		assert.ErrorIs(t, err, authEntity.ErrInvalidCredentials)
+	}
+
+	_, err := usecase.Login(context.Background(), "test@example.com", "wrongpassword", "10.0.0.2")
+
+	var throttled *authEntity.LoginThrottledError
+	assert.ErrorAs(t, err, &throttled)
+	assert.ErrorIs(t, err, authEntity.ErrLoginThrottled)
+	assert.Greater(t, throttled.RetryAfter, time.Duration(0))
+	mockBcrypt.AssertNumberOfCalls(t, "HashComparer", authEntity.DefaultAccountLockoutPolicy.FreeAttempts+1)
+}
+
+func TestAuthUsecase_Login_UnknownEmailsAreThrottledToo(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+	attempts := auth_memory.NewLoginAttemptMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, attempts, noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	mockRepo.On("FindByEmail", mock.Anything, "ghost@example.com").Return(nil, port_user_repository.ErrUserNotFound)
+	mockBcrypt.On("HashComparer", "password123", dummyPasswordHash).Return(false, errors.New("password mismatch"))
+
+	_, err := usecase.Login(context.Background(), "ghost@example.com", "password123", "10.0.0.1")
+	assert.ErrorIs(t, err, authEntity.ErrInvalidCredentials)
+
+	attempt, _ := attempts.Find(context.Background(), "account:ghost@example.com")
+	assert.Equal(t, 1, attempt.Failures)
+	attempt, _ = attempts.Find(context.Background(), "ip:10.0.0.1")
+	assert.Equal(t, 1, attempt.Failures)
+}
+
+func TestAuthUsecase_Login_ThrottlesByIP(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+	attempts := auth_memory.NewLoginAttemptMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, attempts, noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	for i := 0; i < authEntity.DefaultIPLockoutPolicy.LockoutThreshold; i++ {
+		_, _ = attempts.RegisterFailure(context.Background(), "ip:10.0.0.1", time.Now(), time.Hour)
+	}
+
+	_, err := usecase.Login(context.Background(), "other@example.com", "password123", "10.0.0.1")
+
+	assert.ErrorIs(t, err, authEntity.ErrLoginThrottled)
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Login_SuccessResetsAccountFailures(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+	attempts := auth_memory.NewLoginAttemptMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, attempts, noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	_, _ = attempts.RegisterFailure(context.Background(), accountAttemptKey("Test@Example.com "), time.Now(), time.Hour)
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}
+	mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockRefreshTokens.On("Generate").Return("refresh-token", nil)
+	mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
+	mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return([]*permissionEntity.Permission{}, nil)
+	mockTokenManager.On("Sign", mock.Anything).Return("jwt.token.here", nil)
+	mockRefreshRepo.On("Save", mock.Anything, mock.Anything).Return(nil, nil)
+
+	_, err := usecase.Login(context.Background(), "test@example.com", "password123", "10.0.0.1")
+	assert.NoError(t, err)
+
+	attempt, _ := attempts.Find(context.Background(), "account:test@example.com")
+	assert.Equal(t, 0, attempt.Failures)
+}
+
+func TestAuthUsecase_Unlock(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+	attempts := auth_memory.NewLoginAttemptMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, attempts, noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	for i := 0; i < authEntity.DefaultAccountLockoutPolicy.LockoutThreshold; i++ {
+		_, _ = attempts.RegisterFailure(context.Background(), "account:test@example.com", time.Now(), time.Hour)
+	}
+	mockRepo.On("FindByID", mock.Anything, "user-123").Return(&userEntity.User{ID: "user-123", Email: "test@example.com"}, nil)
+
+	err := usecase.Unlock(context.Background(), "user-123")
+
+	assert.NoError(t, err)
+	attempt, _ := attempts.Find(context.Background(), "account:test@example.com")
+	assert.False(t, attempt.IsLocked(time.Now(), authEntity.DefaultAccountLockoutPolicy))
+}
+
+func TestAuthUsecase_Unlock_UserNotFound(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, revocations, auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	mockRepo.On("FindByID", mock.Anything, "missing").Return(nil, port_user_repository.ErrUserNotFound)
+
+	err := usecase.Unlock(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, port_user_repository.ErrUserNotFound)
+}
+
+func TestAuthUsecase_Login_UnverifiedEmailRefusedWhenRequired(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), new(MockTokenManager), mockBcrypt, mockRefreshRepo, new(MockOpaqueTokenGenerator), time.Hour, stubTransactor{}, auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, true)
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hash"}
+	mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hash").Return(true, nil)
+
+	token, err := usecase.Login(context.Background(), "test@example.com", "password123", "127.0.0.1")
+
+	assert.ErrorIs(t, err, authEntity.ErrEmailNotVerified)
+	assert.Nil(t, token)
+	mockRefreshRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Login_UnverifiedEmailWithWrongPasswordIsInvalidCredentials(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), new(MockTokenManager), mockBcrypt, new(MockRefreshTokenRepository), new(MockOpaqueTokenGenerator), time.Hour, stubTransactor{}, auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, true)
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hash"}
+	mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "wrong", "hash").Return(false, nil)
+
+	_, err := usecase.Login(context.Background(), "test@example.com", "wrong", "127.0.0.1")
+
+	assert.ErrorIs(t, err, authEntity.ErrInvalidCredentials)
+}
+
+func TestAuthUsecase_Login_MergesRoleGrants(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockRoleRepo := new(MockRoleRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+
+	usecase := NewAuthUsecase(mockRepo, NewGrantResolver(mockPermissionRepo, mockRoleRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	user := &userEntity.User{ID: "user-123", Name: "John", Email: "test@example.com", Password: "hashed"}
+
+	mockRepo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockRefreshTokens.On("Generate").Return("refresh-token", nil)
+	mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
+	mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return([]*permissionEntity.Permission{
+		{Grants: []string{"users:read"}, Level: "admin"},
+	}, nil)
+	mockRoleRepo.On("FindByUserID", mock.Anything, "user-123").Return([]*roleEntity.Role{
+		{Name: "teacher", Grants: []string{"students:read", "students:update"}},
+	}, nil)
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims authEntity.Claims) bool {
+		return assert.ObjectsAreEqual([]string{"users:read", "students:read", "students:update"}, claims.Grants)
+	})).Return("jwt.token.here", nil)
+	mockRefreshRepo.On("Save", mock.Anything, mock.Anything).Return(nil, nil)
+
+	token, err := usecase.Login(context.Background(), user.Email, "password123", "127.0.0.1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt.token.here", token.AccessToken)
+	mockRoleRepo.AssertExpectations(t)
+	mockTokenManager.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Login_CarriesSchoolGrants(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRefreshTokens := new(MockOpaqueTokenGenerator)
+
+	usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{}, auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), noMFA(), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+	user := &userEntity.User{ID: "user-123", Name: "John", Email: "test@example.com", Password: "hashed"}
+
+	mockRepo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockRefreshTokens.On("Generate").Return("refresh-token", nil)
+	mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
+	mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return([]*permissionEntity.Permission{
+		{Grants: []string{"schools:read"}, Level: "admin"},
+		{Grants: []string{"students:read"}, SchoolIDs: []string{"school-a"}, Level: "admin"},
+	}, nil)
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims authEntity.Claims) bool {
+		return assert.ObjectsAreEqual([]string{"schools:read"}, claims.Grants) &&
+			assert.ObjectsAreEqual(map[string][]string{"school-a": {"students:read"}}, claims.SchoolGrants)
+	})).Return("jwt.token.here", nil)
+	mockRefreshRepo.On("Save", mock.Anything, mock.Anything).Return(nil, nil)
+
+	_, err := usecase.Login(context.Background(), user.Email, "password123", "127.0.0.1")
+
+	assert.NoError(t, err)
+	mockTokenManager.AssertExpectations(t)
 }
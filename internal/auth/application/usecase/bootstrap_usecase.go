@@ -0,0 +1,128 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+var adminGrants = []string{"users:*", "permissions:*", "schools:*", "students:*", "audit:*", "webhooks:*", "events:*"}
+
+type BootstrapUsecase struct {
+	repo           port_user_repository.UserRepository
+	permissionRepo port_permission_repository.PermissionRepository
+	roleRepo       port_role_repository.RoleRepository
+	passwordHasher port_cryptography.Bcrypt
+}
+
+var _ port_auth_usecase.BootstrapUsecase = &BootstrapUsecase{}
+
+func NewBootstrapUsecase(
+	repo port_user_repository.UserRepository,
+	permissionRepo port_permission_repository.PermissionRepository,
+	roleRepo port_role_repository.RoleRepository,
+	passwordHasher port_cryptography.Bcrypt,
+) *BootstrapUsecase {
+	return &BootstrapUsecase{
+		repo:           repo,
+		permissionRepo: permissionRepo,
+		roleRepo:       roleRepo,
+		passwordHasher: passwordHasher,
+	}
+}
+
+// Run provisions the first administrator. It only does anything while no
+// permission has been granted and no role defined yet, which is the one
+// state in which nobody could create users or grant permissions through the
+// API. An account left
+// without a grant by an interrupted run is reused rather than duplicated.
+func (b *BootstrapUsecase) Run(ctx context.Context, name, email, password string) (*user_entity.User, error) {
+	permissions, err := b.permissionRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing permissions: %w", err)
+	}
+	if len(permissions) > 0 {
+		return nil, auth_entity.ErrAlreadyBootstrapped
+	}
+
+	roles, err := b.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing roles: %w", err)
+	}
+	if len(roles) > 0 {
+		return nil, auth_entity.ErrAlreadyBootstrapped
+	}
+
+	email = strings.TrimSpace(email)
+
+	user, err := b.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, port_user_repository.ErrUserNotFound) {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+
+		user, err = b.createAdmin(ctx, name, email, password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	permission, err := permission_entity.NewPermission(&permission_entity.Permission{
+		ID:          uuid.New().String(),
+		UserID:      user.ID,
+		Grants:      adminGrants,
+		Level:       string(permission_entity.LevelAdmin),
+		Description: "bootstrap administrator",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create permission: %w", err)
+	}
+
+	if _, err := b.permissionRepo.Save(ctx, permission); err != nil {
+		return nil, fmt.Errorf("failed to save permission: %w", err)
+	}
+
+	return user, nil
+}
+
+func (b *BootstrapUsecase) createAdmin(ctx context.Context, name, email, password string) (*user_entity.User, error) {
+	hash, err := b.passwordHasher.Hash(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// The operator chose this address, so there is nothing to verify.
+	verifiedAt := time.Now()
+	candidate := &user_entity.User{
+		ID:              uuid.New().String(),
+		Name:            name,
+		Surname:         name,
+		Nickname:        name,
+		Email:           email,
+		Password:        hash,
+		EmailVerifiedAt: &verifiedAt,
+	}
+
+	if _, err := user_entity.ValidationUser(candidate); err != nil {
+		return nil, err
+	}
+
+	user, err := b.repo.Save(ctx, candidate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return user, nil
+}
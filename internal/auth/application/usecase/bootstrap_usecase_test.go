@@ -0,0 +1,141 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authEntity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	roleEntity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+func isAdminGrant(userID string) func(p *permissionEntity.Permission) bool {
+	return func(p *permissionEntity.Permission) bool {
+		return p.UserID == userID &&
+			assert.ObjectsAreEqual(adminGrants, p.Grants)
+	}
+}
+
+func TestBootstrapUsecase_Run(t *testing.T) {
+	t.Run("should create the administrator", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockRoleRepo := new(MockRoleRepository)
+		mockBcrypt := new(MockBcrypt)
+		usecase := NewBootstrapUsecase(mockRepo, mockPermissionRepo, mockRoleRepo, mockBcrypt)
+
+		mockPermissionRepo.On("FindAll", mock.Anything).Return([]*permissionEntity.Permission{}, nil)
+		mockRoleRepo.On("FindAll", mock.Anything).Return([]*roleEntity.Role{}, nil)
+		mockRepo.On("FindByEmail", mock.Anything, "admin@example.com").Return(nil, port_user_repository.ErrUserNotFound)
+		mockBcrypt.On("Hash", "strongPassword123").Return("hash", nil)
+		mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(u *userEntity.User) bool {
+			return u.Email == "admin@example.com" && u.Password == "hash" && u.IsEmailVerified()
+		})).Return(&userEntity.User{ID: "admin-1", Email: "admin@example.com"}, nil)
+		mockPermissionRepo.On("Save", mock.Anything, mock.MatchedBy(isAdminGrant("admin-1"))).Return(&permissionEntity.Permission{}, nil)
+
+		user, err := usecase.Run(context.Background(), "Admin", " admin@example.com ", "strongPassword123")
+
+		require.NoError(t, err)
+		assert.Equal(t, "admin-1", user.ID)
+		mockRepo.AssertExpectations(t)
+		mockPermissionRepo.AssertExpectations(t)
+	})
+
+	t.Run("should skip when permissions already exist", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		usecase := NewBootstrapUsecase(mockRepo, mockPermissionRepo, new(MockRoleRepository), new(MockBcrypt))
+
+		mockPermissionRepo.On("FindAll", mock.Anything).Return([]*permissionEntity.Permission{{ID: "p-1"}}, nil)
+
+		user, err := usecase.Run(context.Background(), "Admin", "admin@example.com", "strongPassword123")
+
+		assert.ErrorIs(t, err, authEntity.ErrAlreadyBootstrapped)
+		assert.Nil(t, user)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should skip when roles already exist", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockRoleRepo := new(MockRoleRepository)
+		usecase := NewBootstrapUsecase(mockRepo, mockPermissionRepo, mockRoleRepo, new(MockBcrypt))
+
+		mockPermissionRepo.On("FindAll", mock.Anything).Return([]*permissionEntity.Permission{}, nil)
+		mockRoleRepo.On("FindAll", mock.Anything).Return([]*roleEntity.Role{{ID: "r-1"}}, nil)
+
+		user, err := usecase.Run(context.Background(), "Admin", "admin@example.com", "strongPassword123")
+
+		assert.ErrorIs(t, err, authEntity.ErrAlreadyBootstrapped)
+		assert.Nil(t, user)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reuse the account left by an interrupted run", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockRoleRepo := new(MockRoleRepository)
+		mockBcrypt := new(MockBcrypt)
+		usecase := NewBootstrapUsecase(mockRepo, mockPermissionRepo, mockRoleRepo, mockBcrypt)
+
+		existing := &userEntity.User{ID: "admin-1", Email: "admin@example.com"}
+
+		mockPermissionRepo.On("FindAll", mock.Anything).Return([]*permissionEntity.Permission{}, nil)
+		mockRoleRepo.On("FindAll", mock.Anything).Return([]*roleEntity.Role{}, nil)
+		mockRepo.On("FindByEmail", mock.Anything, "admin@example.com").Return(existing, nil)
+		mockPermissionRepo.On("Save", mock.Anything, mock.MatchedBy(isAdminGrant("admin-1"))).Return(&permissionEntity.Permission{}, nil)
+
+		user, err := usecase.Run(context.Background(), "Admin", "admin@example.com", "strongPassword123")
+
+		require.NoError(t, err)
+		assert.Equal(t, existing, user)
+		mockBcrypt.AssertNotCalled(t, "Hash", mock.Anything)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return validation error on invalid input", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockRoleRepo := new(MockRoleRepository)
+		mockBcrypt := new(MockBcrypt)
+		usecase := NewBootstrapUsecase(mockRepo, mockPermissionRepo, mockRoleRepo, mockBcrypt)
+
+		mockPermissionRepo.On("FindAll", mock.Anything).Return([]*permissionEntity.Permission{}, nil)
+		mockRoleRepo.On("FindAll", mock.Anything).Return([]*roleEntity.Role{}, nil)
+		mockRepo.On("FindByEmail", mock.Anything, "not-an-email").Return(nil, port_user_repository.ErrUserNotFound)
+		mockBcrypt.On("Hash", "strongPassword123").Return("hash", nil)
+
+		_, err := usecase.Run(context.Background(), "Admin", "not-an-email", "strongPassword123")
+
+		var validationErr *userEntity.ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		mockPermissionRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+// The bootstrap administrator is the only account that can grant anything,
+// so a module it cannot reach stays out of reach for everybody.
+func TestBootstrapUsecase_AdminGrantsCoverRoutedModules(t *testing.T) {
+	routers, err := filepath.Glob("../../../*/presentation/router/*.go")
+	require.NoError(t, err)
+	require.NotEmpty(t, routers)
+
+	pattern := regexp.MustCompile(`ModuleAccessMiddleware\(\[\]string\{"([a-z_]+)"`)
+	for _, router := range routers {
+		source, err := os.ReadFile(router)
+		require.NoError(t, err)
+
+		for _, match := range pattern.FindAllStringSubmatch(string(source), -1) {
+			assert.Contains(t, adminGrants, match[1]+":*", "module routed in %s", filepath.Base(router))
+		}
+	}
+}
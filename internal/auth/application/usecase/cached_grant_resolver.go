@@ -0,0 +1,80 @@
+package auth_usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+// CachedGrantResolver keeps each user's resolved grants for a short TTL.
+// Permission and role events call Invalidate so changes show up before it
+// expires. The cache is local to the process: an event is delivered by one
+// instance's relay only, so other instances see the change once their entry
+// expires.
+type CachedGrantResolver struct {
+	next    port_auth_usecase.GrantResolver
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cachedGrants
+	// version changes on every invalidation so a lookup that raced with one
+	// does not store what it read before the change.
+	version uint64
+}
+
+type cachedGrants struct {
+	grants    permission_entity.ScopedGrants
+	expiresAt time.Time
+}
+
+var _ port_auth_usecase.GrantResolver = &CachedGrantResolver{}
+
+func NewCachedGrantResolver(next port_auth_usecase.GrantResolver, ttl time.Duration) *CachedGrantResolver {
+	return &CachedGrantResolver{
+		next:    next,
+		ttl:     ttl,
+		entries: make(map[string]cachedGrants),
+	}
+}
+
+func (c *CachedGrantResolver) Resolve(ctx context.Context, userID string) (permission_entity.ScopedGrants, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	version := c.version
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.grants, nil
+	}
+
+	grants, err := c.next.Resolve(ctx, userID)
+	if err != nil {
+		return permission_entity.ScopedGrants{}, err
+	}
+
+	c.mu.Lock()
+	if c.version == version {
+		c.entries[userID] = cachedGrants{grants: grants, expiresAt: time.Now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+
+	return grants, nil
+}
+
+func (c *CachedGrantResolver) Invalidate(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+	c.version++
+}
+
+// InvalidateAll drops every entry, for changes such as editing a role that
+// reach users the event does not name.
+func (c *CachedGrantResolver) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	c.version++
+}
@@ -0,0 +1,78 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+func TestCachedGrantResolver_ServesFromCacheUntilInvalidated(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	cache := NewCachedGrantResolver(withoutRoles(permissions), time.Minute)
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{
+		{Grants: []string{"students:read", "students:delete"}, Level: "admin"},
+	}, nil).Once()
+
+	first, err := cache.Resolve(context.Background(), "user-1")
+	assert.NoError(t, err)
+	second, err := cache.Resolve(context.Background(), "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+	permissions.AssertNumberOfCalls(t, "FindPermissionByUserID", 1)
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{
+		{Grants: []string{"students:read"}, Level: "admin"},
+	}, nil).Once()
+	cache.Invalidate("user-1")
+
+	revoked, err := cache.Resolve(context.Background(), "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"students:read"}, revoked.Grants)
+}
+
+func TestCachedGrantResolver_InvalidateAll(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	cache := NewCachedGrantResolver(withoutRoles(permissions), time.Minute)
+
+	permissions.On("FindPermissionByUserID", mock.Anything, mock.Anything).Return([]*permissionEntity.Permission{}, nil)
+
+	_, _ = cache.Resolve(context.Background(), "user-1")
+	_, _ = cache.Resolve(context.Background(), "user-2")
+	cache.InvalidateAll()
+	_, _ = cache.Resolve(context.Background(), "user-1")
+	_, _ = cache.Resolve(context.Background(), "user-2")
+
+	permissions.AssertNumberOfCalls(t, "FindPermissionByUserID", 4)
+}
+
+func TestCachedGrantResolver_Expires(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	cache := NewCachedGrantResolver(withoutRoles(permissions), time.Millisecond)
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{}, nil)
+
+	_, _ = cache.Resolve(context.Background(), "user-1")
+	time.Sleep(5 * time.Millisecond)
+	_, _ = cache.Resolve(context.Background(), "user-1")
+
+	permissions.AssertNumberOfCalls(t, "FindPermissionByUserID", 2)
+}
+
+func TestCachedGrantResolver_DoesNotCacheErrors(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	cache := NewCachedGrantResolver(withoutRoles(permissions), time.Minute)
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return(nil, errors.New("db down")).Once()
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{}, nil).Once()
+
+	_, err := cache.Resolve(context.Background(), "user-1")
+	assert.Error(t, err)
+	_, err = cache.Resolve(context.Background(), "user-1")
+	assert.NoError(t, err)
+}
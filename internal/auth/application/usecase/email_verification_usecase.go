@@ -0,0 +1,156 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_email_notifier "github.com/williamkoller/system-education/internal/user/port/email"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type EmailVerificationUsecase struct {
+	repo         port_user_repository.UserRepository
+	verifyRepo   port_auth_repository.EmailVerificationTokenRepository
+	verifyTokens port_auth_cryptography.OpaqueTokenGenerator
+	notifier     port_email_notifier.EmailNotifier
+	verifyURL    string
+	expiresIn    time.Duration
+}
+
+var _ port_auth_usecase.EmailVerificationUsecase = &EmailVerificationUsecase{}
+
+func NewEmailVerificationUsecase(
+	repo port_user_repository.UserRepository,
+	verifyRepo port_auth_repository.EmailVerificationTokenRepository,
+	verifyTokens port_auth_cryptography.OpaqueTokenGenerator,
+	notifier port_email_notifier.EmailNotifier,
+	verifyURL string,
+	expiresIn time.Duration,
+) *EmailVerificationUsecase {
+	return &EmailVerificationUsecase{
+		repo:         repo,
+		verifyRepo:   verifyRepo,
+		verifyTokens: verifyTokens,
+		notifier:     notifier,
+		verifyURL:    verifyURL,
+		expiresIn:    expiresIn,
+	}
+}
+
+// Send mails a fresh verification link, invalidating any link sent before.
+func (e *EmailVerificationUsecase) Send(ctx context.Context, userID string) error {
+	user, err := e.repo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	link, err := e.issue(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return e.notifier.SendEmailVerificationEmail(user.Name, user.Email, link)
+}
+
+func (e *EmailVerificationUsecase) Verify(ctx context.Context, token string) error {
+	current, err := e.verifyRepo.FindByHash(ctx, e.verifyTokens.Hash(token))
+	if err != nil {
+		if errors.Is(err, port_auth_repository.ErrEmailVerificationTokenNotFound) {
+			return auth_entity.ErrInvalidEmailVerificationToken
+		}
+		return fmt.Errorf("failed to find verification token: %w", err)
+	}
+
+	if current.IsUsed() || current.IsExpired(time.Now()) {
+		return auth_entity.ErrInvalidEmailVerificationToken
+	}
+
+	if err := e.verifyRepo.MarkUsed(ctx, current.ID); err != nil {
+		if errors.Is(err, port_auth_repository.ErrEmailVerificationTokenUsed) {
+			return auth_entity.ErrInvalidEmailVerificationToken
+		}
+		return fmt.Errorf("failed to consume verification token: %w", err)
+	}
+
+	if err := e.repo.MarkEmailVerified(ctx, current.UserID, time.Now()); err != nil {
+		if errors.Is(err, port_user_repository.ErrUserNotFound) {
+			return auth_entity.ErrInvalidEmailVerificationToken
+		}
+		return fmt.Errorf("failed to verify e-mail: %w", err)
+	}
+
+	return nil
+}
+
+// Resend answers the same way for unknown and already verified addresses so
+// the endpoint cannot be used to find out which addresses have an account.
+func (e *EmailVerificationUsecase) Resend(ctx context.Context, email string) error {
+	user, err := e.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, port_user_repository.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	link, err := e.issue(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	// A delivery failure is only logged: answering differently here would
+	// reveal that the address belongs to an unverified account.
+	if err := e.notifier.SendEmailVerificationEmail(user.Name, user.Email, link); err != nil {
+		log.Printf("Falha ao reenviar e‑mail de verificação: %v", err)
+	}
+
+	return nil
+}
+
+func (e *EmailVerificationUsecase) issue(ctx context.Context, user *user_entity.User) (string, error) {
+	if err := e.verifyRepo.InvalidateAllForUser(ctx, user.ID); err != nil {
+		return "", fmt.Errorf("failed to invalidate previous verification tokens: %w", err)
+	}
+
+	plain, err := e.verifyTokens.Generate()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	token := auth_entity.NewEmailVerificationToken(user.ID, e.verifyTokens.Hash(plain), e.expiresIn)
+	if _, err := e.verifyRepo.Save(ctx, token); err != nil {
+		return "", fmt.Errorf("failed to save verification token: %w", err)
+	}
+
+	return e.verifyLink(plain)
+}
+
+func (e *EmailVerificationUsecase) verifyLink(token string) (string, error) {
+	link, err := url.Parse(e.verifyURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid e-mail verification url: %w", err)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
@@ -0,0 +1,193 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authEntity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type MockEmailVerificationTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationTokenRepository) Save(ctx context.Context, t *authEntity.EmailVerificationToken) (*authEntity.EmailVerificationToken, error) {
+	args := m.Called(ctx, t)
+	return t, args.Error(0)
+}
+
+func (m *MockEmailVerificationTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*authEntity.EmailVerificationToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authEntity.EmailVerificationToken), args.Error(1)
+}
+
+func (m *MockEmailVerificationTokenRepository) MarkUsed(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationTokenRepository) InvalidateAllForUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+const verifyURL = "https://systemeducation.com/verify-email"
+
+func expectVerificationLink(verifyRepo *MockEmailVerificationTokenRepository, tokens *MockOpaqueTokenGenerator) {
+	verifyRepo.On("InvalidateAllForUser", mock.Anything, "user-123").Return(nil)
+	tokens.On("Generate").Return("verify-token", nil)
+	tokens.On("Hash", "verify-token").Return("verify-hash")
+	verifyRepo.On("Save", mock.Anything, mock.MatchedBy(func(t *authEntity.EmailVerificationToken) bool {
+		return t.UserID == "user-123" && t.TokenHash == "verify-hash"
+	})).Return(nil)
+}
+
+func TestEmailVerificationUsecase_Send(t *testing.T) {
+	t.Run("should mail a verification link", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockVerifyRepo := new(MockEmailVerificationTokenRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		mockNotifier := new(MockEmailNotifier)
+		usecase := NewEmailVerificationUsecase(mockRepo, mockVerifyRepo, mockTokens, mockNotifier, verifyURL, 24*time.Hour)
+
+		user := &userEntity.User{ID: "user-123", Name: "John", Email: "john@example.com"}
+
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+		expectVerificationLink(mockVerifyRepo, mockTokens)
+		mockNotifier.On("SendEmailVerificationEmail", "John", "john@example.com", verifyURL+"?token=verify-token").Return(nil)
+
+		err := usecase.Send(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		mockVerifyRepo.AssertExpectations(t)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("should do nothing when the e-mail is already verified", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		usecase := NewEmailVerificationUsecase(mockRepo, new(MockEmailVerificationTokenRepository), mockTokens, new(MockEmailNotifier), verifyURL, 24*time.Hour)
+
+		now := time.Now()
+		user := &userEntity.User{ID: "user-123", Email: "john@example.com", EmailVerifiedAt: &now}
+
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+
+		err := usecase.Send(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		mockTokens.AssertNotCalled(t, "Generate")
+	})
+}
+
+func TestEmailVerificationUsecase_Verify(t *testing.T) {
+	t.Run("should mark the user as verified", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockVerifyRepo := new(MockEmailVerificationTokenRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		usecase := NewEmailVerificationUsecase(mockRepo, mockVerifyRepo, mockTokens, new(MockEmailNotifier), verifyURL, 24*time.Hour)
+
+		token := authEntity.NewEmailVerificationToken("user-123", "verify-hash", time.Hour)
+
+		mockTokens.On("Hash", "verify-token").Return("verify-hash")
+		mockVerifyRepo.On("FindByHash", mock.Anything, "verify-hash").Return(token, nil)
+		mockVerifyRepo.On("MarkUsed", mock.Anything, token.ID).Return(nil)
+		mockRepo.On("MarkEmailVerified", mock.Anything, "user-123", mock.Anything).Return(nil)
+
+		err := usecase.Verify(context.Background(), "verify-token")
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name  string
+		setup func(verifyRepo *MockEmailVerificationTokenRepository)
+	}{
+		{
+			name: "an unknown token",
+			setup: func(verifyRepo *MockEmailVerificationTokenRepository) {
+				verifyRepo.On("FindByHash", mock.Anything, "verify-hash").Return(nil, port_auth_repository.ErrEmailVerificationTokenNotFound)
+			},
+		},
+		{
+			name: "an expired token",
+			setup: func(verifyRepo *MockEmailVerificationTokenRepository) {
+				token := authEntity.NewEmailVerificationToken("user-123", "verify-hash", -time.Minute)
+				verifyRepo.On("FindByHash", mock.Anything, "verify-hash").Return(token, nil)
+			},
+		},
+		{
+			name: "a token consumed concurrently",
+			setup: func(verifyRepo *MockEmailVerificationTokenRepository) {
+				token := authEntity.NewEmailVerificationToken("user-123", "verify-hash", time.Hour)
+				verifyRepo.On("FindByHash", mock.Anything, "verify-hash").Return(token, nil)
+				verifyRepo.On("MarkUsed", mock.Anything, token.ID).Return(port_auth_repository.ErrEmailVerificationTokenUsed)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockVerifyRepo := new(MockEmailVerificationTokenRepository)
+			mockTokens := new(MockOpaqueTokenGenerator)
+			usecase := NewEmailVerificationUsecase(mockRepo, mockVerifyRepo, mockTokens, new(MockEmailNotifier), verifyURL, 24*time.Hour)
+
+			mockTokens.On("Hash", "verify-token").Return("verify-hash")
+			tt.setup(mockVerifyRepo)
+
+			err := usecase.Verify(context.Background(), "verify-token")
+
+			assert.ErrorIs(t, err, authEntity.ErrInvalidEmailVerificationToken)
+			mockRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEmailVerificationUsecase_Resend(t *testing.T) {
+	t.Run("should stay silent for unknown or verified addresses", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockNotifier := new(MockEmailNotifier)
+		usecase := NewEmailVerificationUsecase(mockRepo, new(MockEmailVerificationTokenRepository), new(MockOpaqueTokenGenerator), mockNotifier, verifyURL, 24*time.Hour)
+
+		now := time.Now()
+
+		mockRepo.On("FindByEmail", mock.Anything, "ghost@example.com").Return(nil, port_user_repository.ErrUserNotFound)
+		mockRepo.On("FindByEmail", mock.Anything, "done@example.com").Return(&userEntity.User{ID: "user-9", EmailVerifiedAt: &now}, nil)
+
+		assert.NoError(t, usecase.Resend(context.Background(), "ghost@example.com"))
+		assert.NoError(t, usecase.Resend(context.Background(), "done@example.com"))
+		mockNotifier.AssertNotCalled(t, "SendEmailVerificationEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should stay silent when delivery fails", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockVerifyRepo := new(MockEmailVerificationTokenRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		mockNotifier := new(MockEmailNotifier)
+		usecase := NewEmailVerificationUsecase(mockRepo, mockVerifyRepo, mockTokens, mockNotifier, verifyURL, 24*time.Hour)
+
+		user := &userEntity.User{ID: "user-123", Name: "John", Email: "john@example.com"}
+
+		mockRepo.On("FindByEmail", mock.Anything, "john@example.com").Return(user, nil)
+		expectVerificationLink(mockVerifyRepo, mockTokens)
+		mockNotifier.On("SendEmailVerificationEmail", "John", "john@example.com", mock.Anything).Return(errors.New("resend down"))
+
+		err := usecase.Resend(context.Background(), "john@example.com")
+
+		assert.NoError(t, err)
+		mockNotifier.AssertExpectations(t)
+	})
+}
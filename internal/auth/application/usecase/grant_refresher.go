@@ -0,0 +1,44 @@
+package auth_usecase
+
+import (
+	"context"
+	"slices"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+)
+
+// GrantRefresher replaces the grants carried in a token with the user's
+// current ones, filtered by the MFA policy the same way login does.
+type GrantRefresher struct {
+	grants    port_auth_usecase.GrantResolver
+	mfaPolicy auth_entity.MFAPolicy
+}
+
+var _ port_auth_usecase.GrantRefresher = &GrantRefresher{}
+
+func NewGrantRefresher(grants port_auth_usecase.GrantResolver, mfaPolicy auth_entity.MFAPolicy) *GrantRefresher {
+	return &GrantRefresher{grants: grants, mfaPolicy: mfaPolicy}
+}
+
+// Refresh returns a copy of claims with current grants. API key principals
+// are returned as they are: Authenticate already resolved them for this
+// request and narrowed them to the key's scope.
+func (r *GrantRefresher) Refresh(ctx context.Context, claims *auth_entity.Claims) (*auth_entity.Claims, error) {
+	if slices.Contains(claims.AuthMethods, auth_entity.AuthMethodAPIKey) {
+		return claims, nil
+	}
+
+	held, err := r.grants.Resolve(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	held = held.Map(func(g []string) []string {
+		return r.mfaPolicy.Filter(g, claims.MFAVerified())
+	})
+
+	refreshed := *claims
+	refreshed.Grants = held.Grants
+	refreshed.SchoolGrants = held.Schools
+	return &refreshed, nil
+}
@@ -0,0 +1,69 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	authEntity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+func TestGrantRefresher_ReplacesTokenGrants(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	refresher := NewGrantRefresher(withoutRoles(permissions), authEntity.MFAPolicy{})
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{
+		{Grants: []string{"students:read"}, Level: "admin"},
+		{Grants: []string{"schools:read"}, SchoolIDs: []string{"school-a"}, Level: "admin"},
+	}, nil)
+
+	claims := &authEntity.Claims{UserID: "user-1", Grants: []string{"students:read", "students:delete"}, AuthMethods: []string{authEntity.AuthMethodPassword}}
+	refreshed, err := refresher.Refresh(context.Background(), claims)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"students:read"}, refreshed.Grants)
+	assert.Equal(t, map[string][]string{"school-a": {"schools:read"}}, refreshed.SchoolGrants)
+	assert.Equal(t, []string{"students:read", "students:delete"}, claims.Grants, "token claims are left untouched")
+}
+
+func TestGrantRefresher_AppliesMFAPolicy(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	refresher := NewGrantRefresher(withoutRoles(permissions), authEntity.MFAPolicy{RequiredModules: []string{"users"}})
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{
+		{Grants: []string{"users:read", "students:read"}, Level: "admin"},
+	}, nil)
+
+	withoutMFA, err := refresher.Refresh(context.Background(), &authEntity.Claims{UserID: "user-1", AuthMethods: []string{authEntity.AuthMethodPassword}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"students:read"}, withoutMFA.Grants)
+
+	withMFA, err := refresher.Refresh(context.Background(), &authEntity.Claims{UserID: "user-1", AuthMethods: []string{authEntity.AuthMethodPassword, authEntity.AuthMethodMFA}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"users:read", "students:read"}, withMFA.Grants)
+}
+
+func TestGrantRefresher_KeepsAPIKeyScope(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	refresher := NewGrantRefresher(withoutRoles(permissions), authEntity.MFAPolicy{})
+
+	claims := &authEntity.Claims{UserID: "user-1", Grants: []string{"students:read"}, AuthMethods: []string{authEntity.AuthMethodAPIKey}}
+	refreshed, err := refresher.Refresh(context.Background(), claims)
+
+	assert.NoError(t, err)
+	assert.Same(t, claims, refreshed)
+	permissions.AssertNotCalled(t, "FindPermissionByUserID", mock.Anything, mock.Anything)
+}
+
+func TestGrantRefresher_ReturnsResolverErrors(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	refresher := NewGrantRefresher(withoutRoles(permissions), authEntity.MFAPolicy{})
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return(nil, errors.New("db down"))
+
+	_, err := refresher.Refresh(context.Background(), &authEntity.Claims{UserID: "user-1"})
+	assert.Error(t, err)
+}
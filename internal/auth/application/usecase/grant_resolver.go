@@ -0,0 +1,63 @@
+package auth_usecase
+
+import (
+	"context"
+	"fmt"
+
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+)
+
+// GrantResolver computes a user's effective grants: the union of their
+// direct permissions and every role assigned to them. Permissions limited to
+// schools stay limited to them; roles apply network-wide.
+type GrantResolver struct {
+	permissionRepo port_permission_repository.PermissionRepository
+	roleRepo       port_role_repository.RoleRepository
+}
+
+func NewGrantResolver(permissionRepo port_permission_repository.PermissionRepository, roleRepo port_role_repository.RoleRepository) *GrantResolver {
+	return &GrantResolver{permissionRepo: permissionRepo, roleRepo: roleRepo}
+}
+
+var (
+	_ port_auth_usecase.GrantResolver = &GrantResolver{}
+	_ port_auth_usecase.GrantSources  = &GrantResolver{}
+)
+
+func (g *GrantResolver) Resolve(ctx context.Context, userID string) (permission_entity.ScopedGrants, error) {
+	permissions, roles, err := g.Sources(ctx, userID)
+	if err != nil {
+		return permission_entity.ScopedGrants{}, err
+	}
+	return mergeSources(permissions, roles), nil
+}
+
+// Sources returns the permissions and roles Resolve merges.
+func (g *GrantResolver) Sources(ctx context.Context, userID string) ([]*permission_entity.Permission, []*role_entity.Role, error) {
+	permissions, err := g.permissionRepo.FindPermissionByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+
+	roles, err := g.roleRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+
+	return permissions, roles, nil
+}
+
+func mergeSources(permissions []*permission_entity.Permission, roles []*role_entity.Role) permission_entity.ScopedGrants {
+	grants := permission_entity.ScopedGrants{Grants: []string{}}
+	for _, permission := range permissions {
+		grants.Add(permission.GetSchoolIDs(), permission.EffectiveGrants())
+	}
+	for _, role := range roles {
+		grants.Add(nil, role.Grants)
+	}
+	return grants
+}
@@ -0,0 +1,163 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	roleEntity "github.com/williamkoller/system-education/internal/role/domain/entity"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) Save(ctx context.Context, r *roleEntity.Role) (*roleEntity.Role, error) {
+	args := m.Called(ctx, r)
+	result, _ := args.Get(0).(*roleEntity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) Update(ctx context.Context, id string, r *roleEntity.Role) (*roleEntity.Role, error) {
+	args := m.Called(ctx, id, r)
+	result, _ := args.Get(0).(*roleEntity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) FindAll(ctx context.Context) ([]*roleEntity.Role, error) {
+	args := m.Called(ctx)
+	result, _ := args.Get(0).([]*roleEntity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByID(ctx context.Context, id string) (*roleEntity.Role, error) {
+	args := m.Called(ctx, id)
+	result, _ := args.Get(0).(*roleEntity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByName(ctx context.Context, name string) (*roleEntity.Role, error) {
+	args := m.Called(ctx, name)
+	result, _ := args.Get(0).(*roleEntity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByUserID(ctx context.Context, userID string) ([]*roleEntity.Role, error) {
+	args := m.Called(ctx, userID)
+	result, _ := args.Get(0).([]*roleEntity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) AssignToUser(ctx context.Context, roleID, userID string) error {
+	args := m.Called(ctx, roleID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) UnassignFromUser(ctx context.Context, roleID, userID string) error {
+	args := m.Called(ctx, roleID, userID)
+	return args.Error(0)
+}
+
+// withoutRoles resolves grants from direct permissions only, which is what
+// the tests written before roles existed expect.
+func withoutRoles(permissions port_permission_repository.PermissionRepository) *GrantResolver {
+	roles := new(MockRoleRepository)
+	roles.On("FindByUserID", mock.Anything, mock.Anything).Return([]*roleEntity.Role{}, nil).Maybe()
+	return NewGrantResolver(permissions, roles)
+}
+
+func TestGrantResolver_MergesPermissionsAndRoles(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	roles := new(MockRoleRepository)
+	resolver := NewGrantResolver(permissions, roles)
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{
+		{Grants: []string{"users:read"}, Level: "admin"},
+	}, nil)
+	roles.On("FindByUserID", mock.Anything, "user-1").Return([]*roleEntity.Role{
+		{Name: "teacher", Grants: []string{"students:read", "students:update", "users:read", "users:update"}},
+		{Name: "librarian", Grants: []string{"schools:read"}},
+	}, nil)
+
+	grants, err := resolver.Resolve(context.Background(), "user-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"users:read", "students:read", "students:update", "users:update", "schools:read"}, grants.Grants)
+}
+
+func TestGrantResolver_RolesOnly(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	roles := new(MockRoleRepository)
+	resolver := NewGrantResolver(permissions, roles)
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{}, nil)
+	roles.On("FindByUserID", mock.Anything, "user-1").Return([]*roleEntity.Role{
+		{Name: "teacher", Grants: []string{"students:read"}},
+	}, nil)
+
+	grants, err := resolver.Resolve(context.Background(), "user-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"students:read"}, grants.Grants)
+}
+
+func TestGrantResolver_RoleLookupError(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	roles := new(MockRoleRepository)
+	resolver := NewGrantResolver(permissions, roles)
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{}, nil)
+	roles.On("FindByUserID", mock.Anything, "user-1").Return(nil, errors.New("db down"))
+
+	grants, err := resolver.Resolve(context.Background(), "user-1")
+
+	assert.Error(t, err)
+	assert.Empty(t, grants.Grants)
+}
+
+func TestGrantResolver_KeepsSchoolScope(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	roles := new(MockRoleRepository)
+	resolver := NewGrantResolver(permissions, roles)
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{
+		{Grants: []string{"students:read"}, SchoolIDs: []string{"school-a"}, Level: "admin"},
+		{Grants: []string{"students:update"}, SchoolIDs: []string{"school-a", "school-b"}, Level: "admin"},
+	}, nil)
+	roles.On("FindByUserID", mock.Anything, "user-1").Return([]*roleEntity.Role{
+		{Name: "reader", Grants: []string{"schools:read"}},
+	}, nil)
+
+	grants, err := resolver.Resolve(context.Background(), "user-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"schools:read"}, grants.Grants)
+	assert.Equal(t, map[string][]string{
+		"school-a": {"students:read", "students:update"},
+		"school-b": {"students:update"},
+	}, grants.Schools)
+}
+
+func TestGrantResolver_CapsPermissionsByLevel(t *testing.T) {
+	permissions := new(MockPermissionRepository)
+	resolver := withoutRoles(permissions)
+
+	permissions.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{
+		{Grants: []string{"students:*"}, Level: "editor"},
+		{Grants: []string{"users:read", "users:delete"}, Level: "viewer"},
+	}, nil)
+
+	grants, err := resolver.Resolve(context.Background(), "user-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"students:read", "students:create", "students:update", "users:read"}, grants.Grants)
+}
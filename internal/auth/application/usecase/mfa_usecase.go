@@ -0,0 +1,218 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+const recoveryCodeCount = 10
+
+type MFAUsecase struct {
+	repo          port_user_repository.UserRepository
+	mfa           port_auth_repository.MFARepository
+	totp          port_auth_cryptography.TOTP
+	recoveryCodes port_auth_cryptography.OpaqueTokenGenerator
+	revocations   port_auth_repository.TokenRevocationStore
+}
+
+var _ port_auth_usecase.MFAUsecase = &MFAUsecase{}
+
+func NewMFAUsecase(
+	repo port_user_repository.UserRepository,
+	mfa port_auth_repository.MFARepository,
+	totp port_auth_cryptography.TOTP,
+	recoveryCodes port_auth_cryptography.OpaqueTokenGenerator,
+	revocations port_auth_repository.TokenRevocationStore,
+) *MFAUsecase {
+	return &MFAUsecase{
+		repo:          repo,
+		mfa:           mfa,
+		totp:          totp,
+		recoveryCodes: recoveryCodes,
+		revocations:   revocations,
+	}
+}
+
+// Enroll starts (or restarts) an enrollment. The secret only protects logins
+// after Confirm proves the authenticator app produces valid codes.
+func (m *MFAUsecase) Enroll(ctx context.Context, userID string) (*auth_entity.MFASetup, error) {
+	user, err := m.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := findConfirmedEnrollment(ctx, m.mfa, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment != nil {
+		return nil, auth_entity.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := m.totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate mfa secret: %w", err)
+	}
+
+	if err := m.mfa.SaveEnrollment(ctx, auth_entity.NewMFAEnrollment(userID, secret)); err != nil {
+		return nil, fmt.Errorf("failed to save mfa enrollment: %w", err)
+	}
+
+	return &auth_entity.MFASetup{
+		Secret:          secret,
+		ProvisioningURI: m.totp.ProvisioningURI(secret, user.Email),
+	}, nil
+}
+
+func (m *MFAUsecase) Confirm(ctx context.Context, userID, code string) ([]string, error) {
+	enrollment, err := m.mfa.FindEnrollment(ctx, userID)
+	if err != nil {
+		if errors.Is(err, port_auth_repository.ErrMFAEnrollmentNotFound) {
+			return nil, auth_entity.ErrMFANotEnabled
+		}
+		return nil, fmt.Errorf("failed to find mfa enrollment: %w", err)
+	}
+
+	if enrollment.IsConfirmed() {
+		return nil, auth_entity.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := m.totp.Validate(enrollment.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, auth_entity.ErrInvalidMFACode
+	}
+
+	if _, err := m.mfa.ConsumeStep(ctx, userID, step); err != nil {
+		return nil, fmt.Errorf("failed to record mfa code: %w", err)
+	}
+
+	if err := m.mfa.ConfirmEnrollment(ctx, userID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to confirm mfa enrollment: %w", err)
+	}
+
+	return m.issueRecoveryCodes(ctx, userID)
+}
+
+// Disable removes the second factor and ends every session of the user, since
+// existing tokens may still carry modules that were granted because of MFA.
+func (m *MFAUsecase) Disable(ctx context.Context, userID, code string) error {
+	if _, err := m.verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	if err := m.mfa.DeleteEnrollment(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete mfa enrollment: %w", err)
+	}
+
+	if err := m.revocations.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (m *MFAUsecase) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	if _, err := m.verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	return m.issueRecoveryCodes(ctx, userID)
+}
+
+func (m *MFAUsecase) verify(ctx context.Context, userID, code string) (*auth_entity.MFAEnrollment, error) {
+	enrollment, err := findConfirmedEnrollment(ctx, m.mfa, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, auth_entity.ErrMFANotEnabled
+	}
+
+	if err := verifySecondFactor(ctx, m.mfa, m.totp, m.recoveryCodes, enrollment, code, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+func (m *MFAUsecase) issueRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	plain := make([]string, 0, recoveryCodeCount)
+	codes := make([]*auth_entity.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := m.recoveryCodes.Generate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		plain = append(plain, code)
+		codes = append(codes, auth_entity.NewRecoveryCode(userID, m.recoveryCodes.Hash(code)))
+	}
+
+	if err := m.mfa.ReplaceRecoveryCodes(ctx, userID, codes); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+
+	return plain, nil
+}
+
+// findConfirmedEnrollment returns nil when the user has no second factor yet.
+func findConfirmedEnrollment(ctx context.Context, mfa port_auth_repository.MFARepository, userID string) (*auth_entity.MFAEnrollment, error) {
+	enrollment, err := mfa.FindEnrollment(ctx, userID)
+	if err != nil {
+		if errors.Is(err, port_auth_repository.ErrMFAEnrollmentNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find mfa enrollment: %w", err)
+	}
+
+	if !enrollment.IsConfirmed() {
+		return nil, nil
+	}
+
+	return enrollment, nil
+}
+
+func verifySecondFactor(
+	ctx context.Context,
+	mfa port_auth_repository.MFARepository,
+	totp port_auth_cryptography.TOTP,
+	recoveryCodes port_auth_cryptography.OpaqueTokenGenerator,
+	enrollment *auth_entity.MFAEnrollment,
+	code string,
+	now time.Time,
+) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return auth_entity.ErrInvalidMFACode
+	}
+
+	if step, ok := totp.Validate(enrollment.Secret, code, now); ok {
+		consumed, err := mfa.ConsumeStep(ctx, enrollment.UserID, step)
+		if err != nil {
+			return fmt.Errorf("failed to record mfa code: %w", err)
+		}
+		if !consumed {
+			return auth_entity.ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	used, err := mfa.ConsumeRecoveryCode(ctx, enrollment.UserID, recoveryCodes.Hash(code))
+	if err != nil {
+		return fmt.Errorf("failed to check recovery code: %w", err)
+	}
+	if !used {
+		return auth_entity.ErrInvalidMFACode
+	}
+
+	return nil
+}
@@ -0,0 +1,536 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authEntity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_memory "github.com/williamkoller/system-education/internal/auth/infra/memory"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+)
+
+type MockMFARepository struct {
+	mock.Mock
+}
+
+func (m *MockMFARepository) FindEnrollment(ctx context.Context, userID string) (*authEntity.MFAEnrollment, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authEntity.MFAEnrollment), args.Error(1)
+}
+
+func (m *MockMFARepository) SaveEnrollment(ctx context.Context, e *authEntity.MFAEnrollment) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) ConfirmEnrollment(ctx context.Context, userID string, confirmedAt time.Time) error {
+	args := m.Called(ctx, userID, confirmedAt)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) DeleteEnrollment(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) ConsumeStep(ctx context.Context, userID string, step int64) (bool, error) {
+	args := m.Called(ctx, userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*authEntity.RecoveryCode) error {
+	args := m.Called(ctx, userID, codes)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) SaveChallenge(ctx context.Context, c *authEntity.MFAChallenge) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) FindChallengeByHash(ctx context.Context, tokenHash string) (*authEntity.MFAChallenge, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authEntity.MFAChallenge), args.Error(1)
+}
+
+func (m *MockMFARepository) MarkChallengeUsed(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockTOTP struct {
+	mock.Mock
+}
+
+func (m *MockTOTP) GenerateSecret() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTOTP) ProvisioningURI(secret, accountName string) string {
+	args := m.Called(secret, accountName)
+	return args.String(0)
+}
+
+func (m *MockTOTP) Validate(secret, code string, at time.Time) (int64, bool) {
+	args := m.Called(secret, code, at)
+	return args.Get(0).(int64), args.Bool(1)
+}
+
+// noMFA is the MFA repository of a user who never enrolled.
+func noMFA() *MockMFARepository {
+	repo := new(MockMFARepository)
+	repo.On("FindEnrollment", mock.Anything, mock.Anything).Return(nil, port_auth_repository.ErrMFAEnrollmentNotFound).Maybe()
+	return repo
+}
+
+func confirmedEnrollment(userID string) *authEntity.MFAEnrollment {
+	enrollment := authEntity.NewMFAEnrollment(userID, "SECRET")
+	now := time.Now()
+	enrollment.ConfirmedAt = &now
+	return enrollment
+}
+
+func TestMFAUsecase_Enroll(t *testing.T) {
+	t.Run("should store an unconfirmed secret and return its URI", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockMFA := new(MockMFARepository)
+		mockTOTP := new(MockTOTP)
+		usecase := NewMFAUsecase(mockRepo, mockMFA, mockTOTP, new(MockOpaqueTokenGenerator), auth_memory.NewTokenRevocationMemoryStore())
+
+		user := &userEntity.User{ID: "user-1", Email: "john@example.com"}
+
+		mockRepo.On("FindByID", mock.Anything, "user-1").Return(user, nil)
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(nil, port_auth_repository.ErrMFAEnrollmentNotFound)
+		mockTOTP.On("GenerateSecret").Return("SECRET", nil)
+		mockMFA.On("SaveEnrollment", mock.Anything, mock.MatchedBy(func(e *authEntity.MFAEnrollment) bool {
+			return e.UserID == "user-1" && e.Secret == "SECRET" && !e.IsConfirmed()
+		})).Return(nil)
+		mockTOTP.On("ProvisioningURI", "SECRET", "john@example.com").Return("otpauth://totp/x")
+
+		setup, err := usecase.Enroll(context.Background(), "user-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "SECRET", setup.Secret)
+		assert.Equal(t, "otpauth://totp/x", setup.ProvisioningURI)
+		mockMFA.AssertExpectations(t)
+	})
+
+	t.Run("should return error when MFA is already enabled", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockMFA := new(MockMFARepository)
+		usecase := NewMFAUsecase(mockRepo, mockMFA, new(MockTOTP), new(MockOpaqueTokenGenerator), auth_memory.NewTokenRevocationMemoryStore())
+
+		mockRepo.On("FindByID", mock.Anything, "user-1").Return(&userEntity.User{ID: "user-1"}, nil)
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(confirmedEnrollment("user-1"), nil)
+
+		_, err := usecase.Enroll(context.Background(), "user-1")
+
+		assert.ErrorIs(t, err, authEntity.ErrMFAAlreadyEnabled)
+		mockMFA.AssertNotCalled(t, "SaveEnrollment", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMFAUsecase_Confirm(t *testing.T) {
+	t.Run("should confirm the enrollment and issue recovery codes", func(t *testing.T) {
+		mockMFA := new(MockMFARepository)
+		mockTOTP := new(MockTOTP)
+		mockRecoveryCodes := new(MockOpaqueTokenGenerator)
+		usecase := NewMFAUsecase(new(MockUserRepository), mockMFA, mockTOTP, mockRecoveryCodes, auth_memory.NewTokenRevocationMemoryStore())
+
+		enrollment := authEntity.NewMFAEnrollment("user-1", "SECRET")
+
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(enrollment, nil)
+		mockTOTP.On("Validate", "SECRET", "123456", mock.Anything).Return(int64(42), true)
+		mockMFA.On("ConsumeStep", mock.Anything, "user-1", int64(42)).Return(true, nil)
+		mockMFA.On("ConfirmEnrollment", mock.Anything, "user-1", mock.Anything).Return(nil)
+		mockRecoveryCodes.On("Generate").Return("AAAA-BBBB-CCCC-DDDD", nil)
+		mockRecoveryCodes.On("Hash", "AAAA-BBBB-CCCC-DDDD").Return("code-hash")
+		mockMFA.On("ReplaceRecoveryCodes", mock.Anything, "user-1", mock.MatchedBy(func(codes []*authEntity.RecoveryCode) bool {
+			return len(codes) == recoveryCodeCount && codes[0].CodeHash == "code-hash"
+		})).Return(nil)
+
+		codes, err := usecase.Confirm(context.Background(), "user-1", " 123456 ")
+
+		require.NoError(t, err)
+		assert.Len(t, codes, recoveryCodeCount)
+		mockMFA.AssertExpectations(t)
+	})
+
+	t.Run("should reject an invalid code", func(t *testing.T) {
+		mockMFA := new(MockMFARepository)
+		mockTOTP := new(MockTOTP)
+		usecase := NewMFAUsecase(new(MockUserRepository), mockMFA, mockTOTP, new(MockOpaqueTokenGenerator), auth_memory.NewTokenRevocationMemoryStore())
+
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(authEntity.NewMFAEnrollment("user-1", "SECRET"), nil)
+		mockTOTP.On("Validate", "SECRET", "000000", mock.Anything).Return(int64(0), false)
+
+		_, err := usecase.Confirm(context.Background(), "user-1", "000000")
+
+		assert.ErrorIs(t, err, authEntity.ErrInvalidMFACode)
+		mockMFA.AssertNotCalled(t, "ConfirmEnrollment", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error when the user never enrolled", func(t *testing.T) {
+		mockMFA := new(MockMFARepository)
+		usecase := NewMFAUsecase(new(MockUserRepository), mockMFA, new(MockTOTP), new(MockOpaqueTokenGenerator), auth_memory.NewTokenRevocationMemoryStore())
+
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(nil, port_auth_repository.ErrMFAEnrollmentNotFound)
+
+		_, err := usecase.Confirm(context.Background(), "user-1", "123456")
+
+		assert.ErrorIs(t, err, authEntity.ErrMFANotEnabled)
+	})
+}
+
+func TestMFAUsecase_Disable(t *testing.T) {
+	t.Run("should delete the enrollment and revoke sessions", func(t *testing.T) {
+		mockMFA := new(MockMFARepository)
+		mockTOTP := new(MockTOTP)
+		revocations := auth_memory.NewTokenRevocationMemoryStore()
+		usecase := NewMFAUsecase(new(MockUserRepository), mockMFA, mockTOTP, new(MockOpaqueTokenGenerator), revocations)
+
+		issuedAt := time.Now().Add(-time.Minute)
+
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(confirmedEnrollment("user-1"), nil)
+		mockTOTP.On("Validate", "SECRET", "123456", mock.Anything).Return(int64(42), true)
+		mockMFA.On("ConsumeStep", mock.Anything, "user-1", int64(42)).Return(true, nil)
+		mockMFA.On("DeleteEnrollment", mock.Anything, "user-1").Return(nil)
+
+		err := usecase.Disable(context.Background(), "user-1", "123456")
+
+		require.NoError(t, err)
+		revoked, err := revocations.IsRevoked(context.Background(), "jti", "user-1", issuedAt)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+		mockMFA.AssertExpectations(t)
+	})
+
+	t.Run("should accept a recovery code", func(t *testing.T) {
+		mockMFA := new(MockMFARepository)
+		mockTOTP := new(MockTOTP)
+		mockRecoveryCodes := new(MockOpaqueTokenGenerator)
+		usecase := NewMFAUsecase(new(MockUserRepository), mockMFA, mockTOTP, mockRecoveryCodes, auth_memory.NewTokenRevocationMemoryStore())
+
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(confirmedEnrollment("user-1"), nil)
+		mockTOTP.On("Validate", "SECRET", "AAAA-BBBB-CCCC-DDDD", mock.Anything).Return(int64(0), false)
+		mockRecoveryCodes.On("Hash", "AAAA-BBBB-CCCC-DDDD").Return("code-hash")
+		mockMFA.On("ConsumeRecoveryCode", mock.Anything, "user-1", "code-hash").Return(true, nil)
+		mockMFA.On("DeleteEnrollment", mock.Anything, "user-1").Return(nil)
+
+		err := usecase.Disable(context.Background(), "user-1", "AAAA-BBBB-CCCC-DDDD")
+
+		assert.NoError(t, err)
+		mockMFA.AssertExpectations(t)
+	})
+
+	t.Run("should reject a replayed code", func(t *testing.T) {
+		mockMFA := new(MockMFARepository)
+		mockTOTP := new(MockTOTP)
+		usecase := NewMFAUsecase(new(MockUserRepository), mockMFA, mockTOTP, new(MockOpaqueTokenGenerator), auth_memory.NewTokenRevocationMemoryStore())
+
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(confirmedEnrollment("user-1"), nil)
+		mockTOTP.On("Validate", "SECRET", "123456", mock.Anything).Return(int64(42), true)
+		mockMFA.On("ConsumeStep", mock.Anything, "user-1", int64(42)).Return(false, nil)
+
+		err := usecase.Disable(context.Background(), "user-1", "123456")
+
+		assert.ErrorIs(t, err, authEntity.ErrInvalidMFACode)
+		mockMFA.AssertNotCalled(t, "DeleteEnrollment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error when the enrollment is not confirmed", func(t *testing.T) {
+		mockMFA := new(MockMFARepository)
+		usecase := NewMFAUsecase(new(MockUserRepository), mockMFA, new(MockTOTP), new(MockOpaqueTokenGenerator), auth_memory.NewTokenRevocationMemoryStore())
+
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(authEntity.NewMFAEnrollment("user-1", "SECRET"), nil)
+
+		err := usecase.Disable(context.Background(), "user-1", "123456")
+
+		assert.ErrorIs(t, err, authEntity.ErrMFANotEnabled)
+	})
+}
+
+func TestMFAUsecase_RegenerateRecoveryCodes(t *testing.T) {
+	t.Run("should replace the recovery codes", func(t *testing.T) {
+		mockMFA := new(MockMFARepository)
+		mockTOTP := new(MockTOTP)
+		mockRecoveryCodes := new(MockOpaqueTokenGenerator)
+		usecase := NewMFAUsecase(new(MockUserRepository), mockMFA, mockTOTP, mockRecoveryCodes, auth_memory.NewTokenRevocationMemoryStore())
+
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(confirmedEnrollment("user-1"), nil)
+		mockTOTP.On("Validate", "SECRET", "123456", mock.Anything).Return(int64(42), true)
+		mockMFA.On("ConsumeStep", mock.Anything, "user-1", int64(42)).Return(true, nil)
+		mockRecoveryCodes.On("Generate").Return("AAAA-BBBB-CCCC-DDDD", nil)
+		mockRecoveryCodes.On("Hash", "AAAA-BBBB-CCCC-DDDD").Return("code-hash")
+		mockMFA.On("ReplaceRecoveryCodes", mock.Anything, "user-1", mock.Anything).Return(nil)
+
+		codes, err := usecase.RegenerateRecoveryCodes(context.Background(), "user-1", "123456")
+
+		require.NoError(t, err)
+		assert.Len(t, codes, recoveryCodeCount)
+	})
+}
+
+func TestAuthUsecase_Login_WithMFA(t *testing.T) {
+	t.Run("should return a challenge instead of tokens when MFA is enabled", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenManager := new(MockTokenManager)
+		mockBcrypt := new(MockBcrypt)
+		mockRefreshRepo := new(MockRefreshTokenRepository)
+		mockRefreshTokens := new(MockOpaqueTokenGenerator)
+		mockMFA := new(MockMFARepository)
+		usecase := NewAuthUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{},
+			auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), mockMFA, new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+		user := &userEntity.User{ID: "user-1", Email: "john@example.com", Password: "hash"}
+
+		mockRepo.On("FindByEmail", mock.Anything, "john@example.com").Return(user, nil)
+		mockBcrypt.On("HashComparer", "password123", "hash").Return(true, nil)
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(confirmedEnrollment("user-1"), nil)
+		mockRefreshTokens.On("Generate").Return("mfa-token", nil)
+		mockRefreshTokens.On("Hash", "mfa-token").Return("mfa-hash")
+		mockMFA.On("SaveChallenge", mock.Anything, mock.MatchedBy(func(c *authEntity.MFAChallenge) bool {
+			return c.UserID == "user-1" && c.TokenHash == "mfa-hash"
+		})).Return(nil)
+
+		pair, err := usecase.Login(context.Background(), "john@example.com", "password123", "127.0.0.1")
+
+		assert.Nil(t, pair)
+		var challenge *authEntity.MFARequiredError
+		require.ErrorAs(t, err, &challenge)
+		assert.Equal(t, "mfa-token", challenge.Token)
+		assert.WithinDuration(t, time.Now().Add(mfaChallengeExpiresIn), challenge.ExpiresAt, time.Second)
+		mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+		mockRefreshRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should withhold policy modules from a user without MFA", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockTokenManager := new(MockTokenManager)
+		mockBcrypt := new(MockBcrypt)
+		mockRefreshRepo := new(MockRefreshTokenRepository)
+		mockRefreshTokens := new(MockOpaqueTokenGenerator)
+		mockMFA := new(MockMFARepository)
+		usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, mockBcrypt, mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{},
+			auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), mockMFA, new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{RequiredModules: []string{"students"}}, false)
+
+		user := &userEntity.User{ID: "user-1", Email: "john@example.com", Password: "hash"}
+		permissions := []*permissionEntity.Permission{{UserID: "user-1", Grants: []string{"schools:read", "students:read"}, Level: "admin"}}
+
+		mockRepo.On("FindByEmail", mock.Anything, "john@example.com").Return(user, nil)
+		mockBcrypt.On("HashComparer", "password123", "hash").Return(true, nil)
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(nil, port_auth_repository.ErrMFAEnrollmentNotFound)
+		mockRefreshTokens.On("Generate").Return("refresh-token", nil)
+		mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-1").Return(permissions, nil)
+		mockTokenManager.On("Sign", mock.MatchedBy(func(claims authEntity.Claims) bool {
+			return assert.ObjectsAreEqual([]string{"schools:read"}, claims.Grants) && !claims.MFAVerified()
+		})).Return("access", nil)
+		mockRefreshRepo.On("Save", mock.Anything, mock.MatchedBy(func(rt *authEntity.RefreshToken) bool {
+			return !rt.MFAVerified
+		})).Return(nil, nil)
+
+		pair, err := usecase.Login(context.Background(), "john@example.com", "password123", "127.0.0.1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "access", pair.AccessToken)
+		mockTokenManager.AssertExpectations(t)
+	})
+
+	t.Run("should return error when the enrollment lookup fails", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenManager := new(MockTokenManager)
+		mockBcrypt := new(MockBcrypt)
+		mockMFA := new(MockMFARepository)
+		usecase := NewAuthUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), mockTokenManager, mockBcrypt, new(MockRefreshTokenRepository), new(MockOpaqueTokenGenerator), time.Hour, stubTransactor{},
+			auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), mockMFA, new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+		user := &userEntity.User{ID: "user-1", Email: "john@example.com", Password: "hash"}
+
+		mockRepo.On("FindByEmail", mock.Anything, "john@example.com").Return(user, nil)
+		mockBcrypt.On("HashComparer", "password123", "hash").Return(true, nil)
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(nil, errors.New("db error"))
+
+		_, err := usecase.Login(context.Background(), "john@example.com", "password123", "")
+
+		assert.Error(t, err)
+		mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+	})
+}
+
+func TestAuthUsecase_VerifyMFA(t *testing.T) {
+	t.Run("should issue MFA verified tokens", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockTokenManager := new(MockTokenManager)
+		mockRefreshRepo := new(MockRefreshTokenRepository)
+		mockRefreshTokens := new(MockOpaqueTokenGenerator)
+		mockMFA := new(MockMFARepository)
+		mockTOTP := new(MockTOTP)
+		usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, new(MockBcrypt), mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{},
+			auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), mockMFA, mockTOTP, new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{RequiredModules: []string{"students"}}, false)
+
+		user := &userEntity.User{ID: "user-1", Email: "john@example.com"}
+		challenge := authEntity.NewMFAChallenge("user-1", "mfa-hash", time.Minute)
+		permissions := []*permissionEntity.Permission{{UserID: "user-1", Grants: []string{"schools:read", "students:read"}, Level: "admin"}}
+
+		mockRefreshTokens.On("Hash", "mfa-token").Return("mfa-hash")
+		mockMFA.On("FindChallengeByHash", mock.Anything, "mfa-hash").Return(challenge, nil)
+		mockRepo.On("FindByID", mock.Anything, "user-1").Return(user, nil)
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(confirmedEnrollment("user-1"), nil)
+		mockTOTP.On("Validate", "SECRET", "123456", mock.Anything).Return(int64(42), true)
+		mockMFA.On("ConsumeStep", mock.Anything, "user-1", int64(42)).Return(true, nil)
+		mockMFA.On("MarkChallengeUsed", mock.Anything, challenge.ID).Return(true, nil)
+		mockRefreshTokens.On("Generate").Return("refresh-token", nil)
+		mockRefreshTokens.On("Hash", "refresh-token").Return("refresh-hash")
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-1").Return(permissions, nil)
+		mockTokenManager.On("Sign", mock.MatchedBy(func(claims authEntity.Claims) bool {
+			return assert.ObjectsAreEqual([]string{"schools:read", "students:read"}, claims.Grants) && claims.MFAVerified()
+		})).Return("access", nil)
+		mockRefreshRepo.On("Save", mock.Anything, mock.MatchedBy(func(rt *authEntity.RefreshToken) bool {
+			return rt.MFAVerified
+		})).Return(nil, nil)
+
+		pair, err := usecase.VerifyMFA(context.Background(), "mfa-token", "123456", "127.0.0.1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "access", pair.AccessToken)
+		assert.Equal(t, "refresh-token", pair.RefreshToken)
+		mockMFA.AssertExpectations(t)
+		mockTokenManager.AssertExpectations(t)
+	})
+
+	t.Run("should count an invalid code as a failed login", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRefreshTokens := new(MockOpaqueTokenGenerator)
+		mockMFA := new(MockMFARepository)
+		mockTOTP := new(MockTOTP)
+		mockRecoveryCodes := new(MockOpaqueTokenGenerator)
+		attempts := auth_memory.NewLoginAttemptMemoryStore()
+		usecase := NewAuthUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), new(MockTokenManager), new(MockBcrypt), new(MockRefreshTokenRepository), mockRefreshTokens, time.Hour, stubTransactor{},
+			auth_memory.NewTokenRevocationMemoryStore(), attempts, mockMFA, mockTOTP, mockRecoveryCodes, authEntity.MFAPolicy{}, false)
+
+		user := &userEntity.User{ID: "user-1", Email: "john@example.com"}
+		challenge := authEntity.NewMFAChallenge("user-1", "mfa-hash", time.Minute)
+
+		mockRefreshTokens.On("Hash", "mfa-token").Return("mfa-hash")
+		mockMFA.On("FindChallengeByHash", mock.Anything, "mfa-hash").Return(challenge, nil)
+		mockRepo.On("FindByID", mock.Anything, "user-1").Return(user, nil)
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(confirmedEnrollment("user-1"), nil)
+		mockTOTP.On("Validate", "SECRET", "000000", mock.Anything).Return(int64(0), false)
+		mockRecoveryCodes.On("Hash", "000000").Return("wrong-hash")
+		mockMFA.On("ConsumeRecoveryCode", mock.Anything, "user-1", "wrong-hash").Return(false, nil)
+
+		pair, err := usecase.VerifyMFA(context.Background(), "mfa-token", "000000", "127.0.0.1")
+
+		assert.Nil(t, pair)
+		assert.ErrorIs(t, err, authEntity.ErrInvalidMFACode)
+		attempt, err := attempts.Find(context.Background(), accountAttemptKey("john@example.com"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempt.Failures)
+		mockMFA.AssertNotCalled(t, "MarkChallengeUsed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject an expired or unknown challenge", func(t *testing.T) {
+		mockRefreshTokens := new(MockOpaqueTokenGenerator)
+		mockMFA := new(MockMFARepository)
+		usecase := NewAuthUsecase(new(MockUserRepository), withoutRoles(new(MockPermissionRepository)), new(MockTokenManager), new(MockBcrypt), new(MockRefreshTokenRepository), mockRefreshTokens, time.Hour, stubTransactor{},
+			auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), mockMFA, new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+		expired := authEntity.NewMFAChallenge("user-1", "expired-hash", -time.Minute)
+
+		mockRefreshTokens.On("Hash", "expired").Return("expired-hash")
+		mockRefreshTokens.On("Hash", "unknown").Return("unknown-hash")
+		mockMFA.On("FindChallengeByHash", mock.Anything, "expired-hash").Return(expired, nil)
+		mockMFA.On("FindChallengeByHash", mock.Anything, "unknown-hash").Return(nil, port_auth_repository.ErrMFAChallengeNotFound)
+
+		_, err := usecase.VerifyMFA(context.Background(), "expired", "123456", "")
+		assert.ErrorIs(t, err, authEntity.ErrInvalidMFAToken)
+
+		_, err = usecase.VerifyMFA(context.Background(), "unknown", "123456", "")
+		assert.ErrorIs(t, err, authEntity.ErrInvalidMFAToken)
+	})
+
+	t.Run("should reject a challenge already used", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenManager := new(MockTokenManager)
+		mockRefreshTokens := new(MockOpaqueTokenGenerator)
+		mockMFA := new(MockMFARepository)
+		mockTOTP := new(MockTOTP)
+		usecase := NewAuthUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), mockTokenManager, new(MockBcrypt), new(MockRefreshTokenRepository), mockRefreshTokens, time.Hour, stubTransactor{},
+			auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), mockMFA, mockTOTP, new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{}, false)
+
+		challenge := authEntity.NewMFAChallenge("user-1", "mfa-hash", time.Minute)
+
+		mockRefreshTokens.On("Hash", "mfa-token").Return("mfa-hash")
+		mockMFA.On("FindChallengeByHash", mock.Anything, "mfa-hash").Return(challenge, nil)
+		mockRepo.On("FindByID", mock.Anything, "user-1").Return(&userEntity.User{ID: "user-1", Email: "john@example.com"}, nil)
+		mockMFA.On("FindEnrollment", mock.Anything, "user-1").Return(confirmedEnrollment("user-1"), nil)
+		mockTOTP.On("Validate", "SECRET", "123456", mock.Anything).Return(int64(42), true)
+		mockMFA.On("ConsumeStep", mock.Anything, "user-1", int64(42)).Return(true, nil)
+		mockMFA.On("MarkChallengeUsed", mock.Anything, challenge.ID).Return(false, nil)
+
+		_, err := usecase.VerifyMFA(context.Background(), "mfa-token", "123456", "")
+
+		assert.ErrorIs(t, err, authEntity.ErrInvalidMFAToken)
+		mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+	})
+}
+
+func TestAuthUsecase_Refresh_WithMFA(t *testing.T) {
+	t.Run("should keep the MFA verification of the rotated token", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockTokenManager := new(MockTokenManager)
+		mockRefreshRepo := new(MockRefreshTokenRepository)
+		mockRefreshTokens := new(MockOpaqueTokenGenerator)
+		usecase := NewAuthUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockTokenManager, new(MockBcrypt), mockRefreshRepo, mockRefreshTokens, time.Hour, stubTransactor{},
+			auth_memory.NewTokenRevocationMemoryStore(), auth_memory.NewLoginAttemptMemoryStore(), new(MockMFARepository), new(MockTOTP), new(MockOpaqueTokenGenerator), authEntity.MFAPolicy{RequiredModules: []string{"students"}}, false)
+
+		user := &userEntity.User{ID: "user-1", Email: "john@example.com"}
+		current := authEntity.NewRefreshToken("user-1", "family-1", "old-hash", time.Hour)
+		current.MFAVerified = true
+
+		mockRefreshTokens.On("Hash", "old-token").Return("old-hash")
+		mockRefreshRepo.On("FindByHash", mock.Anything, "old-hash").Return(current, nil)
+		mockRepo.On("FindByID", mock.Anything, "user-1").Return(user, nil)
+		mockRefreshTokens.On("Generate").Return("new-token", nil)
+		mockRefreshTokens.On("Hash", "new-token").Return("new-hash")
+		mockRefreshRepo.On("Revoke", mock.Anything, current.ID, mock.AnythingOfType("string")).Return(nil)
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-1").Return([]*permissionEntity.Permission{
+			{UserID: "user-1", Grants: []string{"students:read"}, Level: "admin"},
+		}, nil)
+		mockTokenManager.On("Sign", mock.MatchedBy(func(claims authEntity.Claims) bool {
+			return claims.HasModule("students") && claims.MFAVerified()
+		})).Return("access", nil)
+		mockRefreshRepo.On("Save", mock.Anything, mock.MatchedBy(func(rt *authEntity.RefreshToken) bool {
+			return rt.MFAVerified && rt.FamilyID == "family-1"
+		})).Return(nil, nil)
+
+		_, err := usecase.Refresh(context.Background(), "old-token")
+
+		require.NoError(t, err)
+		mockTokenManager.AssertExpectations(t)
+		mockRefreshRepo.AssertExpectations(t)
+	})
+}
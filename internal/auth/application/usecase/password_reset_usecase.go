@@ -0,0 +1,166 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+	port_email_notifier "github.com/williamkoller/system-education/internal/user/port/email"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	port_transaction "github.com/williamkoller/system-education/shared/port/transaction"
+)
+
+type PasswordResetUsecase struct {
+	repo           port_user_repository.UserRepository
+	resetRepo      port_auth_repository.PasswordResetTokenRepository
+	resetTokens    port_auth_cryptography.OpaqueTokenGenerator
+	passwordHasher port_cryptography.Bcrypt
+	notifier       port_email_notifier.EmailNotifier
+	revocations    port_auth_repository.TokenRevocationStore
+	tx             port_transaction.Transactor
+	audit          port_audit_usecase.Recorder
+	resetURL       string
+	expiresIn      time.Duration
+}
+
+var _ port_auth_usecase.PasswordResetUsecase = &PasswordResetUsecase{}
+
+func NewPasswordResetUsecase(
+	repo port_user_repository.UserRepository,
+	resetRepo port_auth_repository.PasswordResetTokenRepository,
+	resetTokens port_auth_cryptography.OpaqueTokenGenerator,
+	passwordHasher port_cryptography.Bcrypt,
+	notifier port_email_notifier.EmailNotifier,
+	revocations port_auth_repository.TokenRevocationStore,
+	tx port_transaction.Transactor,
+	audit port_audit_usecase.Recorder,
+	resetURL string,
+	expiresIn time.Duration,
+) *PasswordResetUsecase {
+	return &PasswordResetUsecase{
+		repo:           repo,
+		resetRepo:      resetRepo,
+		resetTokens:    resetTokens,
+		passwordHasher: passwordHasher,
+		notifier:       notifier,
+		revocations:    revocations,
+		tx:             tx,
+		audit:          audit,
+		resetURL:       resetURL,
+		expiresIn:      expiresIn,
+	}
+}
+
+// Forgot succeeds silently for unknown e-mails so the endpoint cannot be used
+// to find out which addresses have an account.
+func (p *PasswordResetUsecase) Forgot(ctx context.Context, email string) error {
+	user, err := p.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, port_user_repository.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := p.resetRepo.InvalidateAllForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to invalidate previous reset tokens: %w", err)
+	}
+
+	plain, err := p.resetTokens.Generate()
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	token := auth_entity.NewPasswordResetToken(user.ID, p.resetTokens.Hash(plain), p.expiresIn)
+	if _, err := p.resetRepo.Save(ctx, token); err != nil {
+		return fmt.Errorf("failed to save reset token: %w", err)
+	}
+
+	link, err := p.resetLink(plain)
+	if err != nil {
+		return err
+	}
+
+	// A delivery failure is only logged: answering differently here would
+	// reveal that the address belongs to an account.
+	if err := p.notifier.SendPasswordResetEmail(user.Name, user.Email, link); err != nil {
+		log.Printf("Falha ao enviar e‑mail de redefinição de senha: %v", err)
+	}
+
+	return nil
+}
+
+func (p *PasswordResetUsecase) Reset(ctx context.Context, token, newPassword string) error {
+	current, err := p.resetRepo.FindByHash(ctx, p.resetTokens.Hash(token))
+	if err != nil {
+		if errors.Is(err, port_auth_repository.ErrPasswordResetTokenNotFound) {
+			return auth_entity.ErrInvalidPasswordResetToken
+		}
+		return fmt.Errorf("failed to find reset token: %w", err)
+	}
+
+	if current.IsUsed() || current.IsExpired(time.Now()) {
+		return auth_entity.ErrInvalidPasswordResetToken
+	}
+
+	user, err := p.repo.FindByID(ctx, current.UserID)
+	if err != nil {
+		return auth_entity.ErrInvalidPasswordResetToken
+	}
+
+	hash, err := p.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// The token is only spent together with the password change, so a failed
+	// save leaves the link usable.
+	before := audit_entity.Snapshot(user)
+	user.Password = hash
+	err = p.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := p.resetRepo.MarkUsed(ctx, current.ID); err != nil {
+			return fmt.Errorf("failed to consume reset token: %w", err)
+		}
+		if _, err := p.repo.Update(ctx, user.ID, user); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+		// Nobody is signed in; the holder of the reset link acts for the account.
+		actor := auth_entity.WithPrincipal(ctx, &auth_entity.Claims{UserID: user.ID})
+		return p.audit.Record(actor, audit_entity.ActionUpdate, auditUserEntityType, user.ID, before, audit_entity.Snapshot(user))
+	})
+	if errors.Is(err, port_auth_repository.ErrPasswordResetTokenUsed) {
+		return auth_entity.ErrInvalidPasswordResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := p.revocations.RevokeAllForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PasswordResetUsecase) resetLink(token string) (string, error) {
+	link, err := url.Parse(p.resetURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid password reset url: %w", err)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
@@ -0,0 +1,350 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	audit_usecase "github.com/williamkoller/system-education/internal/audit/application/usecase"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	audit_model "github.com/williamkoller/system-education/internal/audit/infra/db/model"
+	audit_repository "github.com/williamkoller/system-education/internal/audit/infra/db/repository"
+	authEntity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_memory "github.com/williamkoller/system-education/internal/auth/infra/memory"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type MockPasswordResetTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetTokenRepository) Save(ctx context.Context, t *authEntity.PasswordResetToken) (*authEntity.PasswordResetToken, error) {
+	args := m.Called(ctx, t)
+	return t, args.Error(0)
+}
+
+func (m *MockPasswordResetTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*authEntity.PasswordResetToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authEntity.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetTokenRepository) MarkUsed(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetTokenRepository) InvalidateAllForUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type MockEmailNotifier struct {
+	mock.Mock
+}
+
+func (m *MockEmailNotifier) SendWelcomeEmail(name, email string) error {
+	args := m.Called(name, email)
+	return args.Error(0)
+}
+
+func (m *MockEmailNotifier) SendPasswordResetEmail(name, email, resetURL string) error {
+	args := m.Called(name, email, resetURL)
+	return args.Error(0)
+}
+
+func (m *MockEmailNotifier) SendEmailVerificationEmail(name, email, verifyURL string) error {
+	args := m.Called(name, email, verifyURL)
+	return args.Error(0)
+}
+
+type stubTransactor struct{}
+
+func (stubTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// recordingTransactor keeps the error the transaction ended with, which a
+// real transactor would roll back on.
+type recordingTransactor struct {
+	err error
+}
+
+func (r *recordingTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.err = fn(ctx)
+	return r.err
+}
+
+type MockAuditRecorder struct {
+	mock.Mock
+}
+
+func (m *MockAuditRecorder) Record(ctx context.Context, action audit_entity.Action, entityType, entityID string, before, after map[string]any) error {
+	args := m.Called(ctx, action, entityType, entityID, before, after)
+	return args.Error(0)
+}
+
+func ignoreAudit() *MockAuditRecorder {
+	audit := new(MockAuditRecorder)
+	audit.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return audit
+}
+
+// auditLog is a real audit trail over an in-memory audit_events table.
+func auditLog(t *testing.T) (*audit_usecase.AuditUsecase, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&audit_model.AuditEvent{}))
+	return audit_usecase.NewAuditUsecase(audit_repository.NewAuditGormRepository(db)), db
+}
+
+func auditRows(t *testing.T, db *gorm.DB) []audit_model.AuditEvent {
+	var rows []audit_model.AuditEvent
+	require.NoError(t, db.Order("occurred_at").Find(&rows).Error)
+	return rows
+}
+
+const resetURL = "https://systemeducation.com/reset-password"
+
+func TestPasswordResetUsecase_Forgot(t *testing.T) {
+	t.Run("should send a reset link", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockResetRepo := new(MockPasswordResetTokenRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		mockNotifier := new(MockEmailNotifier)
+		usecase := NewPasswordResetUsecase(mockRepo, mockResetRepo, mockTokens, new(MockBcrypt), mockNotifier, auth_memory.NewTokenRevocationMemoryStore(), stubTransactor{}, ignoreAudit(), resetURL, 30*time.Minute)
+
+		user := &userEntity.User{ID: "user-123", Name: "John", Email: "john@example.com"}
+
+		mockRepo.On("FindByEmail", mock.Anything, "john@example.com").Return(user, nil)
+		mockResetRepo.On("InvalidateAllForUser", mock.Anything, "user-123").Return(nil)
+		mockTokens.On("Generate").Return("reset-token", nil)
+		mockTokens.On("Hash", "reset-token").Return("reset-hash")
+		mockResetRepo.On("Save", mock.Anything, mock.MatchedBy(func(t *authEntity.PasswordResetToken) bool {
+			return t.UserID == "user-123" && t.TokenHash == "reset-hash"
+		})).Return(nil)
+		mockNotifier.On("SendPasswordResetEmail", "John", "john@example.com", resetURL+"?token=reset-token").Return(nil)
+
+		err := usecase.Forgot(context.Background(), "john@example.com")
+
+		assert.NoError(t, err)
+		mockResetRepo.AssertExpectations(t)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("should succeed silently for an unknown e-mail", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockNotifier := new(MockEmailNotifier)
+		usecase := NewPasswordResetUsecase(mockRepo, new(MockPasswordResetTokenRepository), new(MockOpaqueTokenGenerator), new(MockBcrypt), mockNotifier, auth_memory.NewTokenRevocationMemoryStore(), stubTransactor{}, ignoreAudit(), resetURL, 30*time.Minute)
+
+		mockRepo.On("FindByEmail", mock.Anything, "ghost@example.com").Return(nil, port_user_repository.ErrUserNotFound)
+
+		err := usecase.Forgot(context.Background(), "ghost@example.com")
+
+		assert.NoError(t, err)
+		mockNotifier.AssertNotCalled(t, "SendPasswordResetEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not report an e-mail delivery failure", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockResetRepo := new(MockPasswordResetTokenRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		mockNotifier := new(MockEmailNotifier)
+		usecase := NewPasswordResetUsecase(mockRepo, mockResetRepo, mockTokens, new(MockBcrypt), mockNotifier, auth_memory.NewTokenRevocationMemoryStore(), stubTransactor{}, ignoreAudit(), resetURL, 30*time.Minute)
+
+		user := &userEntity.User{ID: "user-123", Name: "John", Email: "john@example.com"}
+
+		mockRepo.On("FindByEmail", mock.Anything, "john@example.com").Return(user, nil)
+		mockResetRepo.On("InvalidateAllForUser", mock.Anything, "user-123").Return(nil)
+		mockTokens.On("Generate").Return("reset-token", nil)
+		mockTokens.On("Hash", "reset-token").Return("reset-hash")
+		mockResetRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+		mockNotifier.On("SendPasswordResetEmail", "John", "john@example.com", mock.Anything).Return(errors.New("resend API error"))
+
+		err := usecase.Forgot(context.Background(), "john@example.com")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestPasswordResetUsecase_Reset(t *testing.T) {
+	t.Run("should update the password and revoke sessions", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockResetRepo := new(MockPasswordResetTokenRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		mockHasher := new(MockBcrypt)
+		revocations := auth_memory.NewTokenRevocationMemoryStore()
+		audit, db := auditLog(t)
+		usecase := NewPasswordResetUsecase(mockRepo, mockResetRepo, mockTokens, mockHasher, new(MockEmailNotifier), revocations, stubTransactor{}, audit, resetURL, 30*time.Minute)
+
+		token := authEntity.NewPasswordResetToken("user-123", "reset-hash", time.Hour)
+		user := &userEntity.User{ID: "user-123", Email: "john@example.com", Password: "old-hash"}
+
+		mockTokens.On("Hash", "reset-token").Return("reset-hash")
+		mockResetRepo.On("FindByHash", mock.Anything, "reset-hash").Return(token, nil)
+		mockResetRepo.On("MarkUsed", mock.Anything, token.ID).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+		mockHasher.On("Hash", "newPassword123").Return("new-hash", nil)
+		mockRepo.On("Update", mock.Anything, "user-123", mock.MatchedBy(func(u *userEntity.User) bool {
+			return u.Password == "new-hash"
+		})).Return(user, nil)
+
+		err := usecase.Reset(context.Background(), "reset-token", "newPassword123")
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		revoked, _ := revocations.IsRevoked(context.Background(), "", "user-123", time.Now().Add(-time.Minute))
+		assert.True(t, revoked)
+
+		rows := auditRows(t, db)
+		require.Len(t, rows, 1)
+		assert.Equal(t, string(audit_entity.ActionUpdate), rows[0].Action)
+		assert.Equal(t, "user", rows[0].EntityType)
+		assert.Equal(t, "user-123", rows[0].EntityID)
+		assert.Equal(t, "user-123", *rows[0].ActorID)
+		assert.Equal(t, audit_entity.Change{From: audit_entity.Redacted, To: audit_entity.Redacted}, rows[0].Changes["Password"])
+	})
+
+	t.Run("should keep the password when the audit fails", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockResetRepo := new(MockPasswordResetTokenRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		mockHasher := new(MockBcrypt)
+		revocations := auth_memory.NewTokenRevocationMemoryStore()
+		audit := new(MockAuditRecorder)
+		tx := &recordingTransactor{}
+		usecase := NewPasswordResetUsecase(mockRepo, mockResetRepo, mockTokens, mockHasher, new(MockEmailNotifier), revocations, tx, audit, resetURL, 30*time.Minute)
+
+		token := authEntity.NewPasswordResetToken("user-123", "reset-hash", time.Hour)
+
+		mockTokens.On("Hash", "reset-token").Return("reset-hash")
+		mockResetRepo.On("FindByHash", mock.Anything, "reset-hash").Return(token, nil)
+		mockResetRepo.On("MarkUsed", mock.Anything, token.ID).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(&userEntity.User{ID: "user-123"}, nil)
+		mockHasher.On("Hash", "newPassword123").Return("new-hash", nil)
+		mockRepo.On("Update", mock.Anything, "user-123", mock.Anything).Return(&userEntity.User{ID: "user-123"}, nil)
+		audit.On("Record", mock.Anything, audit_entity.ActionUpdate, "user", "user-123", mock.Anything, mock.Anything).Return(errors.New("audit unavailable"))
+
+		err := usecase.Reset(context.Background(), "reset-token", "newPassword123")
+
+		assert.EqualError(t, err, "audit unavailable")
+		assert.EqualError(t, tx.err, "audit unavailable")
+		revoked, _ := revocations.IsRevoked(context.Background(), "", "user-123", time.Now().Add(-time.Minute))
+		assert.False(t, revoked)
+	})
+
+	used := authEntity.NewPasswordResetToken("user-123", "reset-hash", time.Hour)
+	now := time.Now()
+	used.UsedAt = &now
+
+	expired := authEntity.NewPasswordResetToken("user-123", "reset-hash", time.Hour)
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	cases := map[string]struct {
+		token *authEntity.PasswordResetToken
+		err   error
+	}{
+		"unknown": {nil, port_auth_repository.ErrPasswordResetTokenNotFound},
+		"used":    {used, nil},
+		"expired": {expired, nil},
+	}
+
+	for name, tc := range cases {
+		t.Run("should reject an "+name+" token", func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockResetRepo := new(MockPasswordResetTokenRepository)
+			mockTokens := new(MockOpaqueTokenGenerator)
+			usecase := NewPasswordResetUsecase(mockRepo, mockResetRepo, mockTokens, new(MockBcrypt), new(MockEmailNotifier), auth_memory.NewTokenRevocationMemoryStore(), stubTransactor{}, ignoreAudit(), resetURL, 30*time.Minute)
+
+			mockTokens.On("Hash", "reset-token").Return("reset-hash")
+			if tc.token == nil {
+				mockResetRepo.On("FindByHash", mock.Anything, "reset-hash").Return(nil, tc.err)
+			} else {
+				mockResetRepo.On("FindByHash", mock.Anything, "reset-hash").Return(tc.token, nil)
+			}
+
+			err := usecase.Reset(context.Background(), "reset-token", "newPassword123")
+
+			assert.ErrorIs(t, err, authEntity.ErrInvalidPasswordResetToken)
+			mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("should reject a token consumed by a concurrent reset", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockResetRepo := new(MockPasswordResetTokenRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		mockHasher := new(MockBcrypt)
+		usecase := NewPasswordResetUsecase(mockRepo, mockResetRepo, mockTokens, mockHasher, new(MockEmailNotifier), auth_memory.NewTokenRevocationMemoryStore(), stubTransactor{}, ignoreAudit(), resetURL, 30*time.Minute)
+
+		token := authEntity.NewPasswordResetToken("user-123", "reset-hash", time.Hour)
+
+		mockTokens.On("Hash", "reset-token").Return("reset-hash")
+		mockResetRepo.On("FindByHash", mock.Anything, "reset-hash").Return(token, nil)
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(&userEntity.User{ID: "user-123"}, nil)
+		mockHasher.On("Hash", "newPassword123").Return("new-hash", nil)
+		mockResetRepo.On("MarkUsed", mock.Anything, token.ID).Return(port_auth_repository.ErrPasswordResetTokenUsed)
+
+		err := usecase.Reset(context.Background(), "reset-token", "newPassword123")
+
+		assert.ErrorIs(t, err, authEntity.ErrInvalidPasswordResetToken)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should keep the token when hashing fails", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockResetRepo := new(MockPasswordResetTokenRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		mockHasher := new(MockBcrypt)
+		usecase := NewPasswordResetUsecase(mockRepo, mockResetRepo, mockTokens, mockHasher, new(MockEmailNotifier), auth_memory.NewTokenRevocationMemoryStore(), stubTransactor{}, ignoreAudit(), resetURL, 30*time.Minute)
+
+		token := authEntity.NewPasswordResetToken("user-123", "reset-hash", time.Hour)
+
+		mockTokens.On("Hash", "reset-token").Return("reset-hash")
+		mockResetRepo.On("FindByHash", mock.Anything, "reset-hash").Return(token, nil)
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(&userEntity.User{ID: "user-123"}, nil)
+		mockHasher.On("Hash", "newPassword123").Return("", errors.New("hash error"))
+
+		err := usecase.Reset(context.Background(), "reset-token", "newPassword123")
+
+		assert.ErrorContains(t, err, "failed to hash password")
+		mockResetRepo.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should consume the token in the transaction that fails to save", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockResetRepo := new(MockPasswordResetTokenRepository)
+		mockTokens := new(MockOpaqueTokenGenerator)
+		mockHasher := new(MockBcrypt)
+		revocations := auth_memory.NewTokenRevocationMemoryStore()
+		tx := &recordingTransactor{}
+		usecase := NewPasswordResetUsecase(mockRepo, mockResetRepo, mockTokens, mockHasher, new(MockEmailNotifier), revocations, tx, ignoreAudit(), resetURL, 30*time.Minute)
+
+		token := authEntity.NewPasswordResetToken("user-123", "reset-hash", time.Hour)
+
+		mockTokens.On("Hash", "reset-token").Return("reset-hash")
+		mockResetRepo.On("FindByHash", mock.Anything, "reset-hash").Return(token, nil)
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(&userEntity.User{ID: "user-123"}, nil)
+		mockHasher.On("Hash", "newPassword123").Return("new-hash", nil)
+		mockResetRepo.On("MarkUsed", mock.Anything, token.ID).Return(nil)
+		mockRepo.On("Update", mock.Anything, "user-123", mock.Anything).Return(nil, errors.New("db error"))
+
+		err := usecase.Reset(context.Background(), "reset-token", "newPassword123")
+
+		assert.ErrorContains(t, err, "failed to update password")
+		assert.ErrorContains(t, tx.err, "db error")
+		mockResetRepo.AssertCalled(t, "MarkUsed", mock.Anything, token.ID)
+		revoked, _ := revocations.IsRevoked(context.Background(), "", "user-123", time.Now().Add(-time.Minute))
+		assert.False(t, revoked)
+	})
+}
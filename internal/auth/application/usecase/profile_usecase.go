@@ -0,0 +1,120 @@
+package auth_usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	school_entity "github.com/williamkoller/system-education/internal/school/domain/entity"
+	port_school_repository "github.com/williamkoller/system-education/internal/school/port/repository"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	port_transaction "github.com/williamkoller/system-education/shared/port/transaction"
+)
+
+// auditUserEntityType matches the user module's, so self-service edits sit
+// in the same trail as the ones made through /users.
+const auditUserEntityType = "user"
+
+type ProfileUsecase struct {
+	repo       port_user_repository.UserRepository
+	grants     port_auth_usecase.GrantResolver
+	schoolRepo port_school_repository.SchoolRepository
+	tx         port_transaction.Transactor
+	audit      port_audit_usecase.Recorder
+}
+
+var _ port_auth_usecase.ProfileUsecase = &ProfileUsecase{}
+
+func NewProfileUsecase(
+	repo port_user_repository.UserRepository,
+	grants port_auth_usecase.GrantResolver,
+	schoolRepo port_school_repository.SchoolRepository,
+	tx port_transaction.Transactor,
+	audit port_audit_usecase.Recorder,
+) *ProfileUsecase {
+	return &ProfileUsecase{
+		repo:       repo,
+		grants:     grants,
+		schoolRepo: schoolRepo,
+		tx:         tx,
+		audit:      audit,
+	}
+}
+
+func (p *ProfileUsecase) Me(ctx context.Context, userID string) (*auth_entity.Profile, error) {
+	user, err := p.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.buildProfile(ctx, user)
+}
+
+func (p *ProfileUsecase) UpdateMe(ctx context.Context, userID string, name, nickname *string) (*auth_entity.Profile, error) {
+	var errs []string
+	if name != nil && strings.TrimSpace(*name) == "" {
+		errs = append(errs, "name cannot be empty")
+	}
+	if nickname != nil && strings.TrimSpace(*nickname) == "" {
+		errs = append(errs, "nickname cannot be empty")
+	}
+	if len(errs) > 0 {
+		return nil, &user_entity.ValidationError{Errors: errs}
+	}
+
+	user, err := p.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := audit_entity.Snapshot(user)
+	if _, err := user.UpdateUser(name, nickname, nil, nil, nil); err != nil {
+		return nil, err
+	}
+
+	var updated *user_entity.User
+	err = p.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		saved, err := p.repo.Update(ctx, user.ID, user)
+		if err != nil {
+			return fmt.Errorf("failed to update profile: %w", err)
+		}
+		updated = saved
+		return p.audit.Record(ctx, audit_entity.ActionUpdate, auditUserEntityType, saved.ID, before, audit_entity.Snapshot(saved))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.buildProfile(ctx, updated)
+}
+
+func (p *ProfileUsecase) buildProfile(ctx context.Context, user *user_entity.User) (*auth_entity.Profile, error) {
+	grants, err := p.grants.Resolve(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &auth_entity.Profile{
+		User:         user,
+		Grants:       grants.Grants,
+		SchoolGrants: grants.Schools,
+		Schools:      []*school_entity.School{},
+	}
+
+	// Only the schools the user may read are listed.
+	if scope := grants.Scope("schools", "read"); !scope.IsEmpty() {
+		schools, err := p.schoolRepo.FindAll(permission_entity.WithSchoolScope(ctx, scope))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load schools: %w", err)
+		}
+		profile.Schools = schools
+	}
+
+	return profile, nil
+}
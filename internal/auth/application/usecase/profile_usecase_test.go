@@ -0,0 +1,259 @@
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	authEntity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	schoolEntity "github.com/williamkoller/system-education/internal/school/domain/entity"
+	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type MockSchoolRepository struct {
+	mock.Mock
+}
+
+func (m *MockSchoolRepository) Save(ctx context.Context, s *schoolEntity.School) (*schoolEntity.School, error) {
+	args := m.Called(ctx, s)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schoolEntity.School), args.Error(1)
+}
+
+func (m *MockSchoolRepository) Update(ctx context.Context, id string, s *schoolEntity.School) (*schoolEntity.School, error) {
+	args := m.Called(ctx, id, s)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schoolEntity.School), args.Error(1)
+}
+
+func (m *MockSchoolRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSchoolRepository) FindAll(ctx context.Context) ([]*schoolEntity.School, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*schoolEntity.School), args.Error(1)
+}
+
+func (m *MockSchoolRepository) FindById(ctx context.Context, id string) (*schoolEntity.School, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schoolEntity.School), args.Error(1)
+}
+
+func TestProfileUsecase_Me(t *testing.T) {
+	t.Run("should return the user with grants and readable schools", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockSchoolRepo := new(MockSchoolRepository)
+		usecase := NewProfileUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockSchoolRepo, stubTransactor{}, ignoreAudit())
+
+		user := &userEntity.User{ID: "user-123", Name: "John", Email: "john@example.com", Password: "hash"}
+		permissions := []*permissionEntity.Permission{
+			{UserID: "user-123", Grants: []string{"schools:read", "students:read"}, Level: "admin"},
+			{UserID: "user-123", Grants: []string{"students:read", "students:update"}, Level: "admin"},
+		}
+		schools := []*schoolEntity.School{{ID: "school-1", Name: "Escola 1", Code: "E1"}}
+
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return(permissions, nil)
+		mockSchoolRepo.On("FindAll", mock.Anything).Return(schools, nil)
+
+		profile, err := usecase.Me(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, user, profile.User)
+		assert.Equal(t, []string{"schools:read", "students:read", "students:update"}, profile.Grants)
+		assert.Equal(t, schools, profile.Schools)
+		mockRepo.AssertExpectations(t)
+		mockPermissionRepo.AssertExpectations(t)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not list schools without schools:read", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockSchoolRepo := new(MockSchoolRepository)
+		usecase := NewProfileUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockSchoolRepo, stubTransactor{}, ignoreAudit())
+
+		user := &userEntity.User{ID: "user-123", Name: "John"}
+		permissions := []*permissionEntity.Permission{
+			{UserID: "user-123", Grants: []string{"students:read"}, Level: "admin"},
+		}
+
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return(permissions, nil)
+
+		profile, err := usecase.Me(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		assert.Empty(t, profile.Schools)
+		mockSchoolRepo.AssertNotCalled(t, "FindAll", mock.Anything)
+	})
+
+	t.Run("should list only the schools a scoped grant reaches", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockSchoolRepo := new(MockSchoolRepository)
+		usecase := NewProfileUsecase(mockRepo, withoutRoles(mockPermissionRepo), mockSchoolRepo, stubTransactor{}, ignoreAudit())
+
+		user := &userEntity.User{ID: "user-123", Name: "John"}
+		permissions := []*permissionEntity.Permission{
+			{UserID: "user-123", Grants: []string{"schools:read", "students:read"}, SchoolIDs: []string{"school-1"}, Level: "admin"},
+		}
+		schools := []*schoolEntity.School{{ID: "school-1", Name: "Escola 1", Code: "E1"}}
+
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return(permissions, nil)
+		mockSchoolRepo.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool {
+			return assert.ObjectsAreEqual(permissionEntity.SchoolScope{SchoolIDs: []string{"school-1"}}, permissionEntity.SchoolScopeFrom(ctx))
+		})).Return(schools, nil)
+
+		profile, err := usecase.Me(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		assert.Empty(t, profile.Grants)
+		assert.Equal(t, map[string][]string{"school-1": {"schools:read", "students:read"}}, profile.SchoolGrants)
+		assert.Equal(t, schools, profile.Schools)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error when the user does not exist", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		usecase := NewProfileUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), new(MockSchoolRepository), stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindByID", mock.Anything, "missing").Return(nil, port_user_repository.ErrUserNotFound)
+
+		profile, err := usecase.Me(context.Background(), "missing")
+
+		assert.ErrorIs(t, err, port_user_repository.ErrUserNotFound)
+		assert.Nil(t, profile)
+	})
+
+	t.Run("should return error when permissions fail to load", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		usecase := NewProfileUsecase(mockRepo, withoutRoles(mockPermissionRepo), new(MockSchoolRepository), stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(&userEntity.User{ID: "user-123"}, nil)
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return(nil, errors.New("db error"))
+
+		profile, err := usecase.Me(context.Background(), "user-123")
+
+		assert.Error(t, err)
+		assert.Nil(t, profile)
+	})
+}
+
+func TestProfileUsecase_UpdateMe(t *testing.T) {
+	t.Run("should update only the given fields", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		usecase := NewProfileUsecase(mockRepo, withoutRoles(mockPermissionRepo), new(MockSchoolRepository), stubTransactor{}, ignoreAudit())
+
+		user := &userEntity.User{ID: "user-123", Name: "John", Nickname: "johnd", Email: "john@example.com", Password: "hash", Age: 30}
+		name := "Johnny"
+
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+		mockRepo.On("Update", mock.Anything, "user-123", mock.MatchedBy(func(u *userEntity.User) bool {
+			return u.Name == "Johnny" && u.Nickname == "johnd" && u.Email == "john@example.com" && u.Password == "hash"
+		})).Return(user, nil)
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return([]*permissionEntity.Permission{}, nil)
+
+		profile, err := usecase.UpdateMe(context.Background(), "user-123", &name, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Johnny", profile.User.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should audit the change under the user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		audit, db := auditLog(t)
+		usecase := NewProfileUsecase(mockRepo, withoutRoles(mockPermissionRepo), new(MockSchoolRepository), stubTransactor{}, audit)
+		ctx := authEntity.WithPrincipal(context.Background(), &authEntity.Claims{UserID: "user-123"})
+
+		user := &userEntity.User{ID: "user-123", Name: "John", Nickname: "johnd", Email: "john@example.com"}
+		nickname := "jj"
+
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+		mockRepo.On("Update", mock.Anything, "user-123", user).Return(user, nil)
+		mockPermissionRepo.On("FindPermissionByUserID", mock.Anything, "user-123").Return([]*permissionEntity.Permission{}, nil)
+
+		_, err := usecase.UpdateMe(ctx, "user-123", nil, &nickname)
+		require.NoError(t, err)
+
+		rows := auditRows(t, db)
+		require.Len(t, rows, 1)
+		assert.Equal(t, string(audit_entity.ActionUpdate), rows[0].Action)
+		assert.Equal(t, "user", rows[0].EntityType)
+		assert.Equal(t, "user-123", rows[0].EntityID)
+		assert.Equal(t, "user-123", *rows[0].ActorID)
+		assert.Equal(t, audit_entity.Change{From: "johnd", To: "jj"}, rows[0].Changes["Nickname"])
+	})
+
+	t.Run("should fail the update when the audit fails", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewProfileUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), new(MockSchoolRepository), stubTransactor{}, audit)
+
+		user := &userEntity.User{ID: "user-123", Name: "John", Email: "john@example.com"}
+		nickname := "jj"
+
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+		mockRepo.On("Update", mock.Anything, "user-123", user).Return(user, nil)
+		audit.On("Record", mock.Anything, audit_entity.ActionUpdate, "user", "user-123", mock.Anything, mock.Anything).Return(errors.New("audit unavailable"))
+
+		profile, err := usecase.UpdateMe(context.Background(), "user-123", nil, &nickname)
+
+		assert.EqualError(t, err, "audit unavailable")
+		assert.Nil(t, profile)
+	})
+
+	t.Run("should reject a blank name", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		usecase := NewProfileUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), new(MockSchoolRepository), stubTransactor{}, ignoreAudit())
+
+		name := "   "
+
+		profile, err := usecase.UpdateMe(context.Background(), "user-123", &name, nil)
+
+		var validationErr *userEntity.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Nil(t, profile)
+		mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error when repository fails", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		usecase := NewProfileUsecase(mockRepo, withoutRoles(new(MockPermissionRepository)), new(MockSchoolRepository), stubTransactor{}, ignoreAudit())
+
+		user := &userEntity.User{ID: "user-123", Name: "John", Email: "john@example.com"}
+		nickname := "jj"
+
+		mockRepo.On("FindByID", mock.Anything, "user-123").Return(user, nil)
+		mockRepo.On("Update", mock.Anything, "user-123", user).Return(nil, errors.New("db error"))
+
+		profile, err := usecase.UpdateMe(context.Background(), "user-123", nil, &nickname)
+
+		assert.Error(t, err)
+		assert.Nil(t, profile)
+	})
+}
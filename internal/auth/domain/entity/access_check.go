@@ -0,0 +1,36 @@
+package auth_entity
+
+import (
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+)
+
+// Resource types an access check can be narrowed to. Both resolve to the
+// school whose scope decides the outcome.
+const (
+	ResourceSchool  = "school"
+	ResourceStudent = "student"
+)
+
+type Resource struct {
+	Type     string
+	ID       string
+	SchoolID string
+}
+
+// AccessCheck is a decision for one user, module and action together with
+// what contributed to it: the matching grants and the permissions and roles
+// they came from.
+type AccessCheck struct {
+	UserID       string
+	Module       string
+	Action       string
+	Resource     *Resource
+	Allowed      bool
+	Reason       string
+	MFARequired  bool
+	Grants       []string
+	SchoolGrants map[string][]string
+	Permissions  []*permission_entity.Permission
+	Roles        []*role_entity.Role
+}
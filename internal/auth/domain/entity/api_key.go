@@ -0,0 +1,60 @@
+package auth_entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+const AuthMethodAPIKey = "apikey"
+
+// APIKey is a long-lived credential a user issues for an integration. Only
+// the hash of the key is kept; Prefix is stored in clear to find the key and
+// to let the owner tell their keys apart.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Grants     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func NewAPIKey(userID, name, prefix, keyHash string, grants []string, expiresAt *time.Time) *APIKey {
+	return &APIKey{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Grants:    grants,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+func (k *APIKey) IsActive(now time.Time) bool {
+	return !k.IsRevoked() && !k.IsExpired(now)
+}
+
+// Scope narrows the key's grants to what the owner still holds, so removing a
+// permission from the user also removes it from their keys. School limits on
+// the owner's grants carry over to the key.
+func (k *APIKey) Scope(held permission_entity.ScopedGrants) permission_entity.ScopedGrants {
+	return held.Map(func(grants []string) []string {
+		return permission_entity.Intersect(k.Grants, grants)
+	})
+}
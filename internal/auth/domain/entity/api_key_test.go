@@ -0,0 +1,49 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key := NewAPIKey("user-1", "sis sync", "abcd1234", "hash", []string{"students:read"}, nil)
+
+	assert.NotEmpty(t, key.ID)
+	assert.Equal(t, "user-1", key.UserID)
+	assert.Equal(t, "abcd1234", key.Prefix)
+	assert.True(t, key.IsActive(time.Now()))
+}
+
+func TestAPIKey_IsActive(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	expired := NewAPIKey("user-1", "old", "p1", "h1", nil, &past)
+	assert.True(t, expired.IsExpired(time.Now()))
+	assert.False(t, expired.IsActive(time.Now()))
+
+	valid := NewAPIKey("user-1", "new", "p2", "h2", nil, &future)
+	assert.True(t, valid.IsActive(time.Now()))
+
+	now := time.Now()
+	valid.RevokedAt = &now
+	assert.True(t, valid.IsRevoked())
+	assert.False(t, valid.IsActive(time.Now()))
+}
+
+func TestAPIKey_Scope(t *testing.T) {
+	key := NewAPIKey("user-1", "sync", "p", "h", []string{"students:read", "schools:*"}, nil)
+
+	held := permission_entity.ScopedGrants{
+		Grants:  []string{"students:*", "schools:read", "users:read"},
+		Schools: map[string][]string{"school-a": {"schools:update", "users:*"}, "school-b": {"schools:read"}},
+	}
+
+	scoped := key.Scope(held)
+
+	assert.Equal(t, []string{"students:read", "schools:read"}, scoped.Grants)
+	assert.Equal(t, map[string][]string{"school-a": {"schools:update"}, "school-b": {"schools:read"}}, scoped.Schools)
+}
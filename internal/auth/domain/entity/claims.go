@@ -0,0 +1,80 @@
+package auth_entity
+
+import (
+	"context"
+	"time"
+
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+// Authentication methods reported in the amr claim (RFC 8176).
+const (
+	AuthMethodPassword = "pwd"
+	AuthMethodMFA      = "mfa"
+)
+
+// Claims is the typed payload of an access token. Registered claims (iss,
+// aud, jti, iat, nbf, exp) are filled in by the TokenManager when signing.
+// Grants apply to every school; SchoolGrants only to the school they are
+// keyed by.
+type Claims struct {
+	UserID       string
+	Name         string
+	Nickname     string
+	Email        string
+	Grants       []string
+	SchoolGrants map[string][]string
+	AuthMethods  []string
+	Issuer       string
+	Audience     []string
+	ID           string
+	IssuedAt     time.Time
+	NotBefore    time.Time
+	ExpiresAt    time.Time
+}
+
+func (c *Claims) Scoped() permission_entity.ScopedGrants {
+	return permission_entity.ScopedGrants{Grants: c.Grants, Schools: c.SchoolGrants}
+}
+
+// HasModule reports whether the token grants anything on module, in at
+// least one school.
+func (c *Claims) HasModule(module string) bool {
+	return !c.Scoped().Scope(module, "").IsEmpty()
+}
+
+// Allows reports whether the token carries a grant for action on module, in
+// at least one school. Use SchoolScope to learn which.
+func (c *Claims) Allows(module, action string) bool {
+	return !c.SchoolScope(module, action).IsEmpty()
+}
+
+func (c *Claims) SchoolScope(module, action string) permission_entity.SchoolScope {
+	return c.Scoped().Scope(module, action)
+}
+
+type principalKey struct{}
+
+// WithPrincipal attaches the authenticated caller for code that has no gin
+// context, such as use cases recording who made a change.
+func WithPrincipal(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, principalKey{}, claims)
+}
+
+func PrincipalFrom(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(principalKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+func (c *Claims) MFAVerified() bool {
+	return contains(c.AuthMethods, AuthMethodMFA)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
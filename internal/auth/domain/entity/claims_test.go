@@ -0,0 +1,30 @@
+package auth_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaims_HasModuleAndAllows(t *testing.T) {
+	claims := &Claims{Grants: []string{"users:read", "schools:read"}}
+
+	assert.True(t, claims.HasModule("schools"))
+	assert.False(t, claims.HasModule("Schools"))
+	assert.True(t, claims.Allows("users", "read"))
+	assert.False(t, claims.Allows("users", "delete"))
+	assert.False(t, claims.Allows("students", "read"))
+}
+
+func TestClaims_SchoolScope(t *testing.T) {
+	claims := &Claims{
+		Grants:       []string{"schools:read"},
+		SchoolGrants: map[string][]string{"school-a": {"students:read"}},
+	}
+
+	assert.True(t, claims.HasModule("students"))
+	assert.True(t, claims.Allows("students", "read"))
+	assert.False(t, claims.Allows("students", "update"))
+	assert.Equal(t, []string{"school-a"}, claims.SchoolScope("students", "read").SchoolIDs)
+	assert.True(t, claims.SchoolScope("schools", "read").All)
+}
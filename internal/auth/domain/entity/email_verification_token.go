@@ -0,0 +1,36 @@
+package auth_entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmailVerificationToken struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewEmailVerificationToken(userID, tokenHash string, expiresIn time.Duration) *EmailVerificationToken {
+	now := time.Now()
+
+	return &EmailVerificationToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(expiresIn),
+		CreatedAt: now,
+	}
+}
+
+func (e *EmailVerificationToken) IsUsed() bool {
+	return e.UsedAt != nil
+}
+
+func (e *EmailVerificationToken) IsExpired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
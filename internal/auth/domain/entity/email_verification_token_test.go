@@ -0,0 +1,25 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEmailVerificationToken(t *testing.T) {
+	token := NewEmailVerificationToken("user-1", "hash", 24*time.Hour)
+
+	assert.NotEmpty(t, token.ID)
+	assert.Equal(t, "user-1", token.UserID)
+	assert.Equal(t, "hash", token.TokenHash)
+	assert.False(t, token.IsUsed())
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), token.ExpiresAt, time.Second)
+}
+
+func TestEmailVerificationToken_IsExpired(t *testing.T) {
+	token := NewEmailVerificationToken("user-1", "hash", time.Hour)
+
+	assert.False(t, token.IsExpired(time.Now()))
+	assert.True(t, token.IsExpired(time.Now().Add(2*time.Hour)))
+}
@@ -0,0 +1,63 @@
+package auth_entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+
+	ErrInvalidPasswordResetToken = errors.New("invalid or expired password reset token")
+
+	ErrInvalidEmailVerificationToken = errors.New("invalid or expired e-mail verification token")
+	ErrEmailNotVerified              = errors.New("e-mail address not verified")
+
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrLoginThrottled     = errors.New("too many login attempts, try again later")
+
+	ErrMFARequired       = errors.New("second factor required")
+	ErrInvalidMFAToken   = errors.New("invalid or expired mfa token")
+	ErrInvalidMFACode    = errors.New("invalid mfa code")
+	ErrMFANotEnabled     = errors.New("mfa is not enabled")
+	ErrMFAAlreadyEnabled = errors.New("mfa is already enabled")
+
+	ErrAlreadyBootstrapped = errors.New("an administrator has already been provisioned")
+
+	ErrInvalidAPIKey      = errors.New("invalid api key")
+	ErrAPIKeyScopeDenied  = errors.New("api key scope exceeds the caller's permissions")
+	ErrAPIKeyNotAllowed   = errors.New("api keys cannot manage api keys")
+	ErrInvalidAPIKeyScope = errors.New("api key must grant at least one module:action pair")
+
+	ErrAccessCheckDenied   = errors.New("checking another user's access requires permissions:read")
+	ErrUnknownResourceType = errors.New("resource type must be school or student")
+	ErrResourceNotFound    = errors.New("resource not found")
+)
+
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrLoginThrottled.Error()
+}
+
+func (e *LoginThrottledError) Is(target error) bool {
+	return target == ErrLoginThrottled
+}
+
+// MFARequiredError is returned by a login whose password was accepted but that
+// still has to present a second factor together with Token.
+type MFARequiredError struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (e *MFARequiredError) Error() string {
+	return ErrMFARequired.Error()
+}
+
+func (e *MFARequiredError) Is(target error) bool {
+	return target == ErrMFARequired
+}
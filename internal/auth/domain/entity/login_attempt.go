@@ -0,0 +1,76 @@
+package auth_entity
+
+import "time"
+
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// LockoutPolicy describes how failed logins slow down further attempts:
+// after FreeAttempts failures each new attempt waits BaseDelay, doubling up to
+// MaxDelay, and after LockoutThreshold failures the key is locked for
+// LockoutDuration. Failures older than Window are forgotten.
+type LockoutPolicy struct {
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	Window           time.Duration
+}
+
+var (
+	DefaultAccountLockoutPolicy = LockoutPolicy{
+		FreeAttempts:     3,
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: 10,
+		LockoutDuration:  15 * time.Minute,
+		Window:           time.Hour,
+	}
+
+	DefaultIPLockoutPolicy = LockoutPolicy{
+		FreeAttempts:     10,
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: 50,
+		LockoutDuration:  time.Hour,
+		Window:           time.Hour,
+	}
+)
+
+func (a *LoginAttempt) IsStale(now time.Time, policy LockoutPolicy) bool {
+	return a == nil || a.Failures == 0 || now.Sub(a.LastFailureAt) > policy.Window
+}
+
+// BlockedUntil returns the moment from which a new attempt is accepted again.
+// A zero time means the key is not blocked.
+func (a *LoginAttempt) BlockedUntil(now time.Time, policy LockoutPolicy) time.Time {
+	if a.IsStale(now, policy) {
+		return time.Time{}
+	}
+
+	if policy.LockoutThreshold > 0 && a.Failures >= policy.LockoutThreshold {
+		return a.LastFailureAt.Add(policy.LockoutDuration)
+	}
+
+	if a.Failures <= policy.FreeAttempts {
+		return time.Time{}
+	}
+
+	delay := policy.BaseDelay
+	for i := policy.FreeAttempts + 1; i < a.Failures && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	return a.LastFailureAt.Add(delay)
+}
+
+func (a *LoginAttempt) IsLocked(now time.Time, policy LockoutPolicy) bool {
+	return now.Before(a.BlockedUntil(now, policy))
+}
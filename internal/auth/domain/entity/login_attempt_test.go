@@ -0,0 +1,62 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttempt_BlockedUntil(t *testing.T) {
+	now := time.Now()
+	policy := LockoutPolicy{
+		FreeAttempts:     2,
+		BaseDelay:        time.Second,
+		MaxDelay:         4 * time.Second,
+		LockoutThreshold: 6,
+		LockoutDuration:  time.Minute,
+		Window:           time.Hour,
+	}
+
+	cases := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{5, 4 * time.Second},
+		{6, time.Minute},
+	}
+
+	for _, tc := range cases {
+		attempt := &LoginAttempt{Key: "k", Failures: tc.failures, LastFailureAt: now}
+		blockedUntil := attempt.BlockedUntil(now, policy)
+
+		if tc.want == 0 {
+			assert.True(t, blockedUntil.IsZero(), "failures=%d", tc.failures)
+			continue
+		}
+		assert.Equal(t, now.Add(tc.want), blockedUntil, "failures=%d", tc.failures)
+	}
+}
+
+func TestLoginAttempt_IsLocked(t *testing.T) {
+	now := time.Now()
+	attempt := &LoginAttempt{Key: "k", Failures: 10, LastFailureAt: now.Add(-time.Minute)}
+
+	assert.True(t, attempt.IsLocked(now, DefaultAccountLockoutPolicy))
+	assert.False(t, attempt.IsLocked(now.Add(DefaultAccountLockoutPolicy.LockoutDuration), DefaultAccountLockoutPolicy))
+}
+
+func TestLoginAttempt_StaleFailuresAreForgotten(t *testing.T) {
+	now := time.Now()
+	attempt := &LoginAttempt{Key: "k", Failures: 10, LastFailureAt: now.Add(-2 * time.Hour)}
+
+	assert.True(t, attempt.IsStale(now, DefaultAccountLockoutPolicy))
+	assert.False(t, attempt.IsLocked(now, DefaultAccountLockoutPolicy))
+
+	var missing *LoginAttempt
+	assert.True(t, missing.BlockedUntil(now, DefaultAccountLockoutPolicy).IsZero())
+}
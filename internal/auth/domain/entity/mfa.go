@@ -0,0 +1,118 @@
+package auth_entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+// MFAEnrollment holds a user's TOTP secret. It only protects logins once
+// ConfirmedAt is set, i.e. after the user proved their authenticator works.
+type MFAEnrollment struct {
+	UserID       string
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func NewMFAEnrollment(userID, secret string) *MFAEnrollment {
+	now := time.Now()
+
+	return &MFAEnrollment{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (m *MFAEnrollment) IsConfirmed() bool {
+	return m != nil && m.ConfirmedAt != nil
+}
+
+type MFASetup struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+type RecoveryCode struct {
+	ID        string
+	UserID    string
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewRecoveryCode(userID, codeHash string) *RecoveryCode {
+	return &RecoveryCode{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		CodeHash:  codeHash,
+		CreatedAt: time.Now(),
+	}
+}
+
+// MFAChallenge is the "mfa pending" step of a login: the password was
+// correct and the holder of the token may now present a second factor.
+type MFAChallenge struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewMFAChallenge(userID, tokenHash string, expiresIn time.Duration) *MFAChallenge {
+	now := time.Now()
+
+	return &MFAChallenge{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(expiresIn),
+		CreatedAt: now,
+	}
+}
+
+func (c *MFAChallenge) IsUsed() bool {
+	return c.UsedAt != nil
+}
+
+func (c *MFAChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// MFAPolicy lists the modules that are only granted to sessions that passed
+// a second factor.
+type MFAPolicy struct {
+	RequiredModules []string
+}
+
+func (p MFAPolicy) Requires(modules []string) bool {
+	for _, module := range modules {
+		if contains(p.RequiredModules, module) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter drops the grants on protected modules from a session that did not
+// pass MFA.
+func (p MFAPolicy) Filter(grants []string, mfaVerified bool) []string {
+	if mfaVerified || len(p.RequiredModules) == 0 {
+		return grants
+	}
+
+	allowed := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		if !contains(p.RequiredModules, permission_entity.GrantModule(grant)) {
+			allowed = append(allowed, grant)
+		}
+	}
+	return allowed
+}
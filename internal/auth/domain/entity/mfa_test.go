@@ -0,0 +1,53 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFAEnrollment_IsConfirmed(t *testing.T) {
+	enrollment := NewMFAEnrollment("user-1", "SECRET")
+	assert.False(t, enrollment.IsConfirmed())
+
+	now := time.Now()
+	enrollment.ConfirmedAt = &now
+	assert.True(t, enrollment.IsConfirmed())
+
+	var missing *MFAEnrollment
+	assert.False(t, missing.IsConfirmed())
+}
+
+func TestMFAChallenge(t *testing.T) {
+	challenge := NewMFAChallenge("user-1", "hash", 5*time.Minute)
+
+	assert.NotEmpty(t, challenge.ID)
+	assert.False(t, challenge.IsUsed())
+	assert.False(t, challenge.IsExpired(time.Now()))
+	assert.True(t, challenge.IsExpired(time.Now().Add(10*time.Minute)))
+}
+
+func TestMFAPolicy_Requires(t *testing.T) {
+	policy := MFAPolicy{RequiredModules: []string{"students", "permissions"}}
+
+	assert.True(t, policy.Requires([]string{"schools", "students"}))
+	assert.False(t, policy.Requires([]string{"schools"}))
+	assert.False(t, MFAPolicy{}.Requires([]string{"students"}))
+}
+
+func TestMFAPolicy_Filter(t *testing.T) {
+	policy := MFAPolicy{RequiredModules: []string{"students"}}
+	grants := []string{"schools:read", "students:*"}
+
+	assert.Equal(t, []string{"schools:read"}, policy.Filter(grants, false))
+	assert.Equal(t, grants, policy.Filter(grants, true))
+	assert.Equal(t, grants, MFAPolicy{}.Filter(grants, false))
+}
+
+func TestMFARequiredError(t *testing.T) {
+	var err error = &MFARequiredError{Token: "token"}
+
+	assert.ErrorIs(t, err, ErrMFARequired)
+	assert.Equal(t, ErrMFARequired.Error(), err.Error())
+}
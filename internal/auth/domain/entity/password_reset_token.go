@@ -0,0 +1,36 @@
+package auth_entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasswordResetToken struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewPasswordResetToken(userID, tokenHash string, expiresIn time.Duration) *PasswordResetToken {
+	now := time.Now()
+
+	return &PasswordResetToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(expiresIn),
+		CreatedAt: now,
+	}
+}
+
+func (p *PasswordResetToken) IsUsed() bool {
+	return p.UsedAt != nil
+}
+
+func (p *PasswordResetToken) IsExpired(now time.Time) bool {
+	return !now.Before(p.ExpiresAt)
+}
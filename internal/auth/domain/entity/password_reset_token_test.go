@@ -0,0 +1,33 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPasswordResetToken(t *testing.T) {
+	token := NewPasswordResetToken("user-1", "hash", 30*time.Minute)
+
+	assert.NotEmpty(t, token.ID)
+	assert.Equal(t, "user-1", token.UserID)
+	assert.Equal(t, "hash", token.TokenHash)
+	assert.False(t, token.IsUsed())
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), token.ExpiresAt, time.Second)
+}
+
+func TestPasswordResetToken_IsExpired(t *testing.T) {
+	token := NewPasswordResetToken("user-1", "hash", time.Hour)
+
+	assert.False(t, token.IsExpired(time.Now()))
+	assert.True(t, token.IsExpired(time.Now().Add(2*time.Hour)))
+}
+
+func TestPasswordResetToken_IsUsed(t *testing.T) {
+	token := NewPasswordResetToken("user-1", "hash", time.Hour)
+	now := time.Now()
+	token.UsedAt = &now
+
+	assert.True(t, token.IsUsed())
+}
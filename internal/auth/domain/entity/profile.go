@@ -0,0 +1,15 @@
+package auth_entity
+
+import (
+	school_entity "github.com/williamkoller/system-education/internal/school/domain/entity"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+)
+
+// Profile is the authenticated user as seen by themselves: the account data
+// plus the grants and schools their permissions currently give them.
+type Profile struct {
+	User         *user_entity.User
+	Grants       []string
+	SchoolGrants map[string][]string
+	Schools      []*school_entity.School
+}
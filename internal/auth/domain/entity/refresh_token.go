@@ -0,0 +1,48 @@
+package auth_entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefreshToken struct {
+	ID           string
+	UserID       string
+	FamilyID     string
+	TokenHash    string
+	ExpiresAt    time.Time
+	RevokedAt    *time.Time
+	ReplacedByID string
+	// MFAVerified is carried over on rotation so a refreshed session keeps
+	// the modules that require a second factor only if the login passed one.
+	MFAVerified bool
+	CreatedAt   time.Time
+}
+
+// NewRefreshToken starts a new token family when familyID is empty, otherwise
+// the token is issued as the next member of an existing rotation chain.
+func NewRefreshToken(userID, familyID, tokenHash string, expiresIn time.Duration) *RefreshToken {
+	if familyID == "" {
+		familyID = uuid.New().String()
+	}
+
+	now := time.Now()
+
+	return &RefreshToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(expiresIn),
+		CreatedAt: now,
+	}
+}
+
+func (r *RefreshToken) IsRevoked() bool {
+	return r.RevokedAt != nil
+}
+
+func (r *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
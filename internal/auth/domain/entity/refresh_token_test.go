@@ -0,0 +1,42 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRefreshToken(t *testing.T) {
+	t.Run("should start a new family when none is given", func(t *testing.T) {
+		token := NewRefreshToken("user-1", "", "hash", time.Hour)
+
+		assert.NotEmpty(t, token.ID)
+		assert.NotEmpty(t, token.FamilyID)
+		assert.Equal(t, "user-1", token.UserID)
+		assert.Equal(t, "hash", token.TokenHash)
+		assert.False(t, token.IsRevoked())
+		assert.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresAt, time.Second)
+	})
+
+	t.Run("should keep the given family", func(t *testing.T) {
+		token := NewRefreshToken("user-1", "family-1", "hash", time.Hour)
+
+		assert.Equal(t, "family-1", token.FamilyID)
+	})
+}
+
+func TestRefreshToken_IsExpired(t *testing.T) {
+	token := NewRefreshToken("user-1", "", "hash", time.Hour)
+
+	assert.False(t, token.IsExpired(time.Now()))
+	assert.True(t, token.IsExpired(time.Now().Add(2*time.Hour)))
+}
+
+func TestRefreshToken_IsRevoked(t *testing.T) {
+	token := NewRefreshToken("user-1", "", "hash", time.Hour)
+	now := time.Now()
+	token.RevokedAt = &now
+
+	assert.True(t, token.IsRevoked())
+}
@@ -0,0 +1,53 @@
+package infra_cryptography
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	port_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+)
+
+const apiKeyScheme = "se"
+
+// SecureAPIKeyGenerator issues keys shaped like se_<prefix>_<secret>. The
+// prefix identifies the key in listings and lookups; only the secret part
+// has to stay private.
+type SecureAPIKeyGenerator struct{}
+
+var _ port_cryptography.APIKeyGenerator = &SecureAPIKeyGenerator{}
+
+func NewSecureAPIKeyGenerator() *SecureAPIKeyGenerator {
+	return &SecureAPIKeyGenerator{}
+}
+
+func (g *SecureAPIKeyGenerator) Generate() (string, string, error) {
+	prefixBytes := make([]byte, 6)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	prefix := hex.EncodeToString(prefixBytes)
+	key := apiKeyScheme + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	return key, prefix, nil
+}
+
+func (g *SecureAPIKeyGenerator) Prefix(key string) (string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyScheme || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func (g *SecureAPIKeyGenerator) Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,47 @@
+package infra_cryptography
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecureAPIKeyGenerator_Generate(t *testing.T) {
+	generator := NewSecureAPIKeyGenerator()
+
+	key, prefix, err := generator.Generate()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, "se_"+prefix+"_"))
+	assert.Len(t, prefix, 12)
+
+	other, otherPrefix, err := generator.Generate()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	assert.NotEqual(t, prefix, otherPrefix)
+}
+
+func TestSecureAPIKeyGenerator_Prefix(t *testing.T) {
+	generator := NewSecureAPIKeyGenerator()
+	key, prefix, err := generator.Generate()
+	require.NoError(t, err)
+
+	parsed, ok := generator.Prefix(key)
+	assert.True(t, ok)
+	assert.Equal(t, prefix, parsed)
+
+	for _, invalid := range []string{"", "se_", "se_abc", "xx_abc_secret", "se__secret", "se_abc_"} {
+		_, ok := generator.Prefix(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestSecureAPIKeyGenerator_Hash(t *testing.T) {
+	generator := NewSecureAPIKeyGenerator()
+
+	assert.Equal(t, generator.Hash("se_a_b"), generator.Hash("se_a_b"))
+	assert.NotEqual(t, generator.Hash("se_a_b"), generator.Hash("se_a_c"))
+	assert.Len(t, generator.Hash("se_a_b"), 64)
+}
@@ -2,54 +2,214 @@ package infra_cryptography
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 	port_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
 )
 
 type JWTTokenManager struct {
 	secretKey string
 	expiresIn time.Duration
+	issuer    string
+	audience  string
+	activeKey *SigningKey
+	keys      map[string]*SigningKey
 }
 
-var _ port_cryptography.TokenManager = &JWTTokenManager{}
+type jwtClaims struct {
+	UserID       string              `json:"user_id"`
+	Name         string              `json:"name,omitempty"`
+	Nickname     string              `json:"nick_name,omitempty"`
+	Email        string              `json:"email"`
+	Grants       []string            `json:"grants"`
+	SchoolGrants map[string][]string `json:"school_grants,omitempty"`
+	AMR          []string            `json:"amr,omitempty"`
+	jwt.RegisteredClaims
+}
+
+var (
+	_ port_cryptography.TokenManager   = &JWTTokenManager{}
+	_ port_cryptography.KeySetProvider = &JWTTokenManager{}
+)
 
 func NewJWTTokenManager(secret string, expiresIn time.Duration) *JWTTokenManager {
-	return &JWTTokenManager{secretKey: secret, expiresIn: expiresIn}
+	key := NewHMACSigningKey("", []byte(secret))
+
+	return &JWTTokenManager{
+		secretKey: secret,
+		expiresIn: expiresIn,
+		activeKey: key,
+		keys:      map[string]*SigningKey{key.ID: key},
+	}
 }
 
-func (j *JWTTokenManager) Verify(tokenStr string) (map[string]interface{}, error) {
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
+// NewJWTTokenManagerWithKeys signs with the key identified by activeKID and
+// accepts tokens signed by any of the given keys.
+func NewJWTTokenManagerWithKeys(keys []*SigningKey, activeKID string, expiresIn time.Duration) (*JWTTokenManager, error) {
+	manager := &JWTTokenManager{expiresIn: expiresIn, keys: make(map[string]*SigningKey, len(keys))}
+
+	for _, key := range keys {
+		if _, exists := manager.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key id %q", key.ID)
 		}
-		return []byte(j.secretKey), nil
-	})
+		manager.keys[key.ID] = key
+	}
+
+	active, ok := manager.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q not found", activeKID)
+	}
+	if !active.CanSign() {
+		return nil, fmt.Errorf("active signing key %q has no private key", activeKID)
+	}
+	manager.activeKey = active
+
+	return manager, nil
+}
+
+// LoadJWTTokenManager builds the manager from configuration. Without key files
+// tokens are signed with HS256 and the shared secret; with key files the
+// secret, when set, is only kept to verify HS256 tokens issued before the
+// switch. A non-empty issuer or audience is stamped on every token and
+// required by Verify.
+func LoadJWTTokenManager(secret string, keyFiles map[string]string, activeKID string, issuer string, audience string, expiresIn time.Duration) (*JWTTokenManager, error) {
+	manager, err := loadJWTTokenManager(secret, keyFiles, activeKID, expiresIn)
+	if err != nil {
+		return nil, err
+	}
+
+	manager.issuer = issuer
+	manager.audience = audience
+
+	return manager, nil
+}
+
+func loadJWTTokenManager(secret string, keyFiles map[string]string, activeKID string, expiresIn time.Duration) (*JWTTokenManager, error) {
+	if len(keyFiles) == 0 {
+		return NewJWTTokenManager(secret, expiresIn), nil
+	}
+
+	keys := make([]*SigningKey, 0, len(keyFiles)+1)
+	for kid, path := range keyFiles {
+		key, err := LoadSigningKeyFromFile(kid, path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if secret != "" {
+		keys = append(keys, &SigningKey{ID: "", Method: jwt.SigningMethodHS256, verifyKey: []byte(secret)})
+	}
+
+	return NewJWTTokenManagerWithKeys(keys, activeKID, expiresIn)
+}
+
+func (j *JWTTokenManager) Verify(tokenStr string) (*auth_entity.Claims, error) {
+	var options []jwt.ParserOption
+	if j.issuer != "" {
+		options = append(options, jwt.WithIssuer(j.issuer))
+	}
+	if j.audience != "" {
+		options = append(options, jwt.WithAudience(j.audience))
+	}
+
+	claims := &jwtClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, j.keyFunc, options...)
 
 	if err != nil || !token.Valid {
 		return nil, errors.New("invalid token")
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok {
-		result := make(map[string]interface{})
-		for k, v := range claims {
-			result[k] = v
-		}
-		return result, nil
+	return claims.toEntity(), nil
+}
+
+func (j *JWTTokenManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+
+	return key.verifyKey, nil
+}
+
+func (j *JWTTokenManager) Sign(claims auth_entity.Claims) (string, error) {
+	now := time.Now()
+
+	registered := jwt.RegisteredClaims{
+		ID:        uuid.New().String(),
+		Issuer:    j.issuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(j.expiresIn)),
+	}
+	if j.audience != "" {
+		registered.Audience = jwt.ClaimStrings{j.audience}
 	}
 
-	return nil, errors.New("could not parse claims")
+	token := jwt.NewWithClaims(j.activeKey.Method, &jwtClaims{
+		UserID:           claims.UserID,
+		Name:             claims.Name,
+		Nickname:         claims.Nickname,
+		Email:            claims.Email,
+		Grants:           claims.Grants,
+		SchoolGrants:     claims.SchoolGrants,
+		AMR:              claims.AuthMethods,
+		RegisteredClaims: registered,
+	})
+	if j.activeKey.ID != "" {
+		token.Header["kid"] = j.activeKey.ID
+	}
+	return token.SignedString(j.activeKey.signKey)
 }
 
-func (j *JWTTokenManager) Sign(data map[string]interface{}) (string, error) {
-	claims := jwt.MapClaims{}
+func (j *JWTTokenManager) PublicKeys() []auth_entity.JSONWebKey {
+	keys := make([]auth_entity.JSONWebKey, 0, len(j.keys))
+	for _, key := range j.keys {
+		jwk, err := key.JWK()
+		if err != nil {
+			continue
+		}
+		keys = append(keys, jwk)
+	}
+
+	sort.Slice(keys, func(a, b int) bool { return keys[a].Kid < keys[b].Kid })
+
+	return keys
+}
 
-	for k, v := range data {
-		claims[k] = v
+func (c *jwtClaims) toEntity() *auth_entity.Claims {
+	return &auth_entity.Claims{
+		UserID:       c.UserID,
+		Name:         c.Name,
+		Nickname:     c.Nickname,
+		Email:        c.Email,
+		Grants:       c.Grants,
+		SchoolGrants: c.SchoolGrants,
+		AuthMethods:  c.AMR,
+		Issuer:       c.Issuer,
+		Audience:     c.Audience,
+		ID:           c.ID,
+		IssuedAt:     numericTime(c.IssuedAt),
+		NotBefore:    numericTime(c.NotBefore),
+		ExpiresAt:    numericTime(c.ExpiresAt),
 	}
+}
 
-	claims["exp"] = time.Now().Add(j.expiresIn).Unix()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secretKey))
+func numericTime(date *jwt.NumericDate) time.Time {
+	if date == nil {
+		return time.Time{}
+	}
+	return date.Time
 }
@@ -1,33 +1,34 @@
 package infra_cryptography
 
 import (
+	"crypto/x509"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 )
 
 func TestJWTTokenManager(t *testing.T) {
 	manager := NewJWTTokenManager("secret", time.Hour)
 
-	data := map[string]interface{}{"user_id": 123}
-	token, err := manager.Sign(data)
+	token, err := manager.Sign(auth_entity.Claims{UserID: "user-123"})
 	require.NoError(t, err)
 
 	parsedData, err := manager.Verify(token)
 	require.NoError(t, err)
-	require.Equal(t, float64(123), parsedData["user_id"])
+	require.Equal(t, "user-123", parsedData.UserID)
 }
 
 func TestJWTTokenManager_Sign_Success(t *testing.T) {
 	manager := NewJWTTokenManager("secret-key", time.Hour)
 
-	data := map[string]interface{}{
-		"user_id": "user-123",
-		"email":   "test@example.com",
-		"modules": []string{"admin", "user"},
+	data := auth_entity.Claims{
+		UserID: "user-123",
+		Email:  "test@example.com",
+		Grants: []string{"admin:read", "user:*"},
 	}
 
 	token, err := manager.Sign(data)
@@ -38,8 +39,9 @@ func TestJWTTokenManager_Sign_Success(t *testing.T) {
 	// Verify the token can be parsed
 	parsedData, err := manager.Verify(token)
 	assert.NoError(t, err)
-	assert.Equal(t, "user-123", parsedData["user_id"])
-	assert.Equal(t, "test@example.com", parsedData["email"])
+	assert.Equal(t, "user-123", parsedData.UserID)
+	assert.Equal(t, "test@example.com", parsedData.Email)
+	assert.Equal(t, []string{"admin:read", "user:*"}, parsedData.Grants)
 }
 
 func TestJWTTokenManager_Verify_InvalidToken(t *testing.T) {
@@ -57,8 +59,7 @@ func TestJWTTokenManager_Verify_InvalidToken(t *testing.T) {
 func TestJWTTokenManager_Verify_ExpiredToken(t *testing.T) {
 	manager := NewJWTTokenManager("secret-key", 1*time.Millisecond)
 
-	data := map[string]interface{}{"user_id": "user-123"}
-	token, err := manager.Sign(data)
+	token, err := manager.Sign(auth_entity.Claims{UserID: "user-123"})
 	require.NoError(t, err)
 
 	// Wait for token to expire
@@ -75,8 +76,7 @@ func TestJWTTokenManager_Verify_WrongSecret(t *testing.T) {
 	manager1 := NewJWTTokenManager("secret-key-1", time.Hour)
 	manager2 := NewJWTTokenManager("secret-key-2", time.Hour)
 
-	data := map[string]interface{}{"user_id": "user-123"}
-	token, err := manager1.Sign(data)
+	token, err := manager1.Sign(auth_entity.Claims{UserID: "user-123"})
 	require.NoError(t, err)
 
 	// Try to verify with different secret
@@ -134,13 +134,13 @@ func TestJWTTokenManager_Verify_UnexpectedSigningMethod(t *testing.T) {
 func TestJWTTokenManager_Sign_WithMultipleFields(t *testing.T) {
 	manager := NewJWTTokenManager("secret-key", time.Hour)
 
-	data := map[string]interface{}{
-		"user_id":    "user-123",
-		"email":      "test@example.com",
-		"name":       "John Doe",
-		"modules":    []string{"admin", "user"},
-		"actions":    []string{"read", "write"},
-		"created_at": time.Now().Unix(),
+	data := auth_entity.Claims{
+		UserID:       "user-123",
+		Email:        "test@example.com",
+		Name:         "John Doe",
+		Nickname:     "johnd",
+		Grants:       []string{"admin:read", "admin:write", "user:read", "user:write"},
+		SchoolGrants: map[string][]string{"school-1": {"students:read"}},
 	}
 
 	token, err := manager.Sign(data)
@@ -151,12 +151,14 @@ func TestJWTTokenManager_Sign_WithMultipleFields(t *testing.T) {
 	// Verify all fields are preserved
 	parsedData, err := manager.Verify(token)
 	assert.NoError(t, err)
-	assert.Equal(t, "user-123", parsedData["user_id"])
-	assert.Equal(t, "test@example.com", parsedData["email"])
-	assert.Equal(t, "John Doe", parsedData["name"])
-	assert.NotNil(t, parsedData["modules"])
-	assert.NotNil(t, parsedData["actions"])
-	assert.NotNil(t, parsedData["exp"]) // exp should be added automatically
+	assert.Equal(t, "user-123", parsedData.UserID)
+	assert.Equal(t, "test@example.com", parsedData.Email)
+	assert.Equal(t, "John Doe", parsedData.Name)
+	assert.Equal(t, "johnd", parsedData.Nickname)
+	assert.Equal(t, []string{"admin:read", "admin:write", "user:read", "user:write"}, parsedData.Grants)
+	assert.Equal(t, map[string][]string{"school-1": {"students:read"}}, parsedData.SchoolGrants)
+	assert.False(t, parsedData.ExpiresAt.IsZero()) // exp should be added automatically
+	assert.False(t, parsedData.NotBefore.IsZero())
 }
 
 func TestNewJWTTokenManager(t *testing.T) {
@@ -169,3 +171,138 @@ func TestNewJWTTokenManager(t *testing.T) {
 	assert.Equal(t, secret, manager.secretKey)
 	assert.Equal(t, expiresIn, manager.expiresIn)
 }
+
+func TestJWTTokenManager_Sign_AddsUniqueJTI(t *testing.T) {
+	manager := NewJWTTokenManager("secret-key", time.Hour)
+
+	first, err := manager.Sign(auth_entity.Claims{UserID: "user-123"})
+	require.NoError(t, err)
+	second, err := manager.Sign(auth_entity.Claims{UserID: "user-123"})
+	require.NoError(t, err)
+
+	firstClaims, err := manager.Verify(first)
+	require.NoError(t, err)
+	secondClaims, err := manager.Verify(second)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, firstClaims.ID)
+	assert.NotEqual(t, firstClaims.ID, secondClaims.ID)
+	assert.False(t, firstClaims.IssuedAt.IsZero())
+}
+
+func TestJWTTokenManager_AsymmetricKeys(t *testing.T) {
+	rsaPath, _ := newRSAKeyFile(t)
+	edPath, _ := newEd25519KeyFile(t)
+
+	for kid, path := range map[string]string{"rsa-1": rsaPath, "ed-1": edPath} {
+		t.Run(kid, func(t *testing.T) {
+			manager, err := LoadJWTTokenManager("", map[string]string{kid: path}, kid, "", "", time.Hour)
+			require.NoError(t, err)
+
+			token, err := manager.Sign(auth_entity.Claims{UserID: "user-123"})
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, kid, parsed.Header["kid"])
+
+			claims, err := manager.Verify(token)
+			require.NoError(t, err)
+			assert.Equal(t, "user-123", claims.UserID)
+		})
+	}
+}
+
+func TestJWTTokenManager_KeyRotation(t *testing.T) {
+	oldPath, _ := newEd25519KeyFile(t)
+	newPath, _ := newEd25519KeyFile(t)
+
+	oldManager, err := LoadJWTTokenManager("", map[string]string{"2024": oldPath}, "2024", "", "", time.Hour)
+	require.NoError(t, err)
+	oldToken, err := oldManager.Sign(auth_entity.Claims{UserID: "user-123"})
+	require.NoError(t, err)
+
+	rotated, err := LoadJWTTokenManager("", map[string]string{"2024": oldPath, "2025": newPath}, "2025", "", "", time.Hour)
+	require.NoError(t, err)
+
+	_, err = rotated.Verify(oldToken)
+	assert.NoError(t, err, "tokens signed by a previous key must stay valid")
+
+	newToken, err := rotated.Sign(auth_entity.Claims{UserID: "user-123"})
+	require.NoError(t, err)
+	_, err = oldManager.Verify(newToken)
+	assert.Error(t, err, "unknown kid must be rejected")
+
+	assert.Len(t, rotated.PublicKeys(), 2)
+}
+
+func TestJWTTokenManager_KeepsLegacyHS256TokensValid(t *testing.T) {
+	legacy := NewJWTTokenManager("secret-key", time.Hour)
+	legacyToken, err := legacy.Sign(auth_entity.Claims{UserID: "user-123"})
+	require.NoError(t, err)
+
+	edPath, _ := newEd25519KeyFile(t)
+	manager, err := LoadJWTTokenManager("secret-key", map[string]string{"ed-1": edPath}, "ed-1", "", "", time.Hour)
+	require.NoError(t, err)
+
+	_, err = manager.Verify(legacyToken)
+	assert.NoError(t, err)
+	assert.Len(t, manager.PublicKeys(), 1, "the shared secret must never be published")
+}
+
+func TestJWTTokenManager_RejectsAlgorithmConfusion(t *testing.T) {
+	rsaPath, key := newRSAKeyFile(t)
+	manager, err := LoadJWTTokenManager("", map[string]string{"rsa-1": rsaPath}, "rsa-1", "", "", time.Hour)
+	require.NoError(t, err)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": "attacker",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	forged.Header["kid"] = "rsa-1"
+	tokenString, err := forged.SignedString(publicDER)
+	require.NoError(t, err)
+
+	_, err = manager.Verify(tokenString)
+	assert.Error(t, err)
+}
+
+func TestNewJWTTokenManagerWithKeys_Errors(t *testing.T) {
+	_, err := NewJWTTokenManagerWithKeys([]*SigningKey{NewHMACSigningKey("a", []byte("s"))}, "b", time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewJWTTokenManagerWithKeys([]*SigningKey{
+		NewHMACSigningKey("a", []byte("s")),
+		NewHMACSigningKey("a", []byte("t")),
+	}, "a", time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewJWTTokenManagerWithKeys([]*SigningKey{{ID: "pub", Method: jwt.SigningMethodEdDSA}}, "pub", time.Hour)
+	assert.Error(t, err)
+}
+
+func TestJWTTokenManager_IssuerAndAudience(t *testing.T) {
+	manager, err := LoadJWTTokenManager("secret-key", nil, "", "system-education", "system-education-api", time.Hour)
+	require.NoError(t, err)
+
+	token, err := manager.Sign(auth_entity.Claims{UserID: "user-123"})
+	require.NoError(t, err)
+
+	claims, err := manager.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "system-education", claims.Issuer)
+	assert.Equal(t, []string{"system-education-api"}, claims.Audience)
+
+	otherIssuer, err := LoadJWTTokenManager("secret-key", nil, "", "someone-else", "system-education-api", time.Hour)
+	require.NoError(t, err)
+	_, err = otherIssuer.Verify(token)
+	assert.Error(t, err, "tokens from another issuer must be rejected")
+
+	otherAudience, err := LoadJWTTokenManager("secret-key", nil, "", "system-education", "another-api", time.Hour)
+	require.NoError(t, err)
+	_, err = otherAudience.Verify(token)
+	assert.Error(t, err, "tokens for another audience must be rejected")
+}
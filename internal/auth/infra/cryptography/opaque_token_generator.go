@@ -0,0 +1,38 @@
+package infra_cryptography
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	port_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+)
+
+type SecureOpaqueTokenGenerator struct {
+	size int
+}
+
+var _ port_cryptography.OpaqueTokenGenerator = &SecureOpaqueTokenGenerator{}
+
+func NewSecureOpaqueTokenGenerator(size int) *SecureOpaqueTokenGenerator {
+	if size <= 0 {
+		size = 32
+	}
+	return &SecureOpaqueTokenGenerator{size: size}
+}
+
+func (g *SecureOpaqueTokenGenerator) Generate() (string, error) {
+	buf := make([]byte, g.size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Hash uses a plain SHA-256 digest: the tokens already carry enough entropy
+// that a slow password hash would only add latency to every lookup.
+func (g *SecureOpaqueTokenGenerator) Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,37 @@
+package infra_cryptography
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecureOpaqueTokenGenerator_Generate(t *testing.T) {
+	generator := NewSecureOpaqueTokenGenerator(32)
+
+	first, err := generator.Generate()
+	require.NoError(t, err)
+	second, err := generator.Generate()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 43)
+	assert.NotEqual(t, first, second)
+}
+
+func TestSecureOpaqueTokenGenerator_DefaultSize(t *testing.T) {
+	generator := NewSecureOpaqueTokenGenerator(0)
+
+	token, err := generator.Generate()
+
+	require.NoError(t, err)
+	assert.Len(t, token, 43)
+}
+
+func TestSecureOpaqueTokenGenerator_Hash(t *testing.T) {
+	generator := NewSecureOpaqueTokenGenerator(32)
+
+	assert.Equal(t, generator.Hash("token"), generator.Hash("token"))
+	assert.NotEqual(t, generator.Hash("token"), generator.Hash("other"))
+	assert.Len(t, generator.Hash("token"), 64)
+}
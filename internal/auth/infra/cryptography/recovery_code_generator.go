@@ -0,0 +1,43 @@
+package infra_cryptography
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+
+	port_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+)
+
+// RecoveryCodeGenerator issues MFA recovery codes meant to be typed by a
+// person: upper case base32 in groups of four, e.g. ABCD-EFGH-IJKL-MNOP.
+type RecoveryCodeGenerator struct{}
+
+var _ port_cryptography.OpaqueTokenGenerator = &RecoveryCodeGenerator{}
+
+func NewRecoveryCodeGenerator() *RecoveryCodeGenerator {
+	return &RecoveryCodeGenerator{}
+}
+
+func (g *RecoveryCodeGenerator) Generate() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	raw := base32.StdEncoding.EncodeToString(buf)
+	groups := make([]string, 0, len(raw)/4)
+	for i := 0; i < len(raw); i += 4 {
+		groups = append(groups, raw[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// Hash ignores case, dashes and spaces so the code is accepted however the
+// user copies it back.
+func (g *RecoveryCodeGenerator) Hash(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
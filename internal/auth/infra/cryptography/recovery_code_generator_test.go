@@ -0,0 +1,30 @@
+package infra_cryptography
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryCodeGenerator_Generate(t *testing.T) {
+	generator := NewRecoveryCodeGenerator()
+
+	first, err := generator.Generate()
+	require.NoError(t, err)
+	second, err := generator.Generate()
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^[A-Z2-7]{4}(-[A-Z2-7]{4}){3}$`, first)
+	assert.NotEqual(t, first, second)
+}
+
+func TestRecoveryCodeGenerator_HashNormalizesInput(t *testing.T) {
+	generator := NewRecoveryCodeGenerator()
+
+	expected := generator.Hash("ABCD-EFGH-IJKL-MNOP")
+
+	assert.Equal(t, expected, generator.Hash("abcd efgh ijkl mnop"))
+	assert.Equal(t, expected, generator.Hash("ABCDEFGHIJKLMNOP"))
+	assert.NotEqual(t, expected, generator.Hash("ABCD-EFGH-IJKL-MNOQ"))
+}
@@ -0,0 +1,114 @@
+package infra_cryptography
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+// SigningKey is a key identified by kid. Keys loaded from a public PEM can
+// only verify tokens, which lets a retired key stay valid until its tokens
+// expire.
+type SigningKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+func NewHMACSigningKey(kid string, secret []byte) *SigningKey {
+	return &SigningKey{ID: kid, Method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}
+}
+
+func (k *SigningKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+func LoadSigningKeyFromFile(kid, path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key %s: %w", kid, err)
+	}
+	return ParseSigningKeyPEM(kid, data)
+}
+
+func ParseSigningKeyPEM(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", kid)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key %s: %w", kid, err)
+		}
+		return newPrivateSigningKey(kid, key)
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key %s: %w", kid, err)
+		}
+		return newPrivateSigningKey(kid, key)
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %w", kid, err)
+		}
+		return newPublicSigningKey(kid, key)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q for signing key %s", block.Type, kid)
+	}
+}
+
+func newPrivateSigningKey(kid string, key interface{}) (*SigningKey, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, signKey: k, verifyKey: &k.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, signKey: k, verifyKey: k.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T for signing key %s", key, kid)
+	}
+}
+
+func newPublicSigningKey(kid string, key interface{}) (*SigningKey, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, verifyKey: k}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, verifyKey: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T for signing key %s", key, kid)
+	}
+}
+
+// JWK returns the public JSON Web Key. Symmetric keys are never published.
+func (k *SigningKey) JWK() (auth_entity.JSONWebKey, error) {
+	jwk := auth_entity.JSONWebKey{Kid: k.ID, Use: "sig", Alg: k.Method.Alg()}
+
+	switch pub := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return auth_entity.JSONWebKey{}, errors.New("symmetric keys cannot be published")
+	}
+
+	return jwk, nil
+}
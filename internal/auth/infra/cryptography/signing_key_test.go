@@ -0,0 +1,97 @@
+package infra_cryptography
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func newRSAKeyFile(t *testing.T) (string, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)), key
+}
+
+func newEd25519KeyFile(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return writePEM(t, "PRIVATE KEY", der), key
+}
+
+func TestLoadSigningKeyFromFile_RSA(t *testing.T) {
+	path, _ := newRSAKeyFile(t)
+
+	key, err := LoadSigningKeyFromFile("rsa-1", path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", key.Method.Alg())
+	assert.True(t, key.CanSign())
+
+	jwk, err := key.JWK()
+	require.NoError(t, err)
+	assert.Equal(t, "RSA", jwk.Kty)
+	assert.Equal(t, "rsa-1", jwk.Kid)
+	assert.Equal(t, "AQAB", jwk.E)
+	assert.NotEmpty(t, jwk.N)
+}
+
+func TestLoadSigningKeyFromFile_Ed25519(t *testing.T) {
+	path, _ := newEd25519KeyFile(t)
+
+	key, err := LoadSigningKeyFromFile("ed-1", path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "EdDSA", key.Method.Alg())
+
+	jwk, err := key.JWK()
+	require.NoError(t, err)
+	assert.Equal(t, "OKP", jwk.Kty)
+	assert.Equal(t, "Ed25519", jwk.Crv)
+	assert.NotEmpty(t, jwk.X)
+}
+
+func TestLoadSigningKeyFromFile_PublicKeyIsVerifyOnly(t *testing.T) {
+	_, private := newEd25519KeyFile(t)
+	der, err := x509.MarshalPKIXPublicKey(private.Public())
+	require.NoError(t, err)
+
+	key, err := LoadSigningKeyFromFile("ed-old", writePEM(t, "PUBLIC KEY", der))
+
+	require.NoError(t, err)
+	assert.False(t, key.CanSign())
+}
+
+func TestLoadSigningKeyFromFile_Errors(t *testing.T) {
+	_, err := LoadSigningKeyFromFile("missing", filepath.Join(t.TempDir(), "missing.pem"))
+	assert.Error(t, err)
+
+	_, err = ParseSigningKeyPEM("garbage", []byte("not a pem"))
+	assert.Error(t, err)
+
+	_, err = ParseSigningKeyPEM("cert", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1}}))
+	assert.Error(t, err)
+}
+
+func TestSigningKey_JWK_HMACIsNotPublished(t *testing.T) {
+	_, err := NewHMACSigningKey("hs", []byte("secret")).JWK()
+	assert.Error(t, err)
+}
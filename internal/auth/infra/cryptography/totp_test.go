@@ -0,0 +1,78 @@
+package infra_cryptography
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA1 seed from RFC 6238 Appendix B ("12345678901234567890").
+var rfc6238Secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPGenerator_RFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	// Appendix B lists 8 digit codes; the last 6 digits are the 6 digit code.
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		assert.Equal(t, expected, hotp(key, unix/totpPeriod), "time %d", unix)
+	}
+}
+
+func TestTOTPGenerator_Validate(t *testing.T) {
+	generator := NewTOTPGenerator("System Education")
+	at := time.Unix(1111111111, 0)
+
+	step, ok := generator.Validate(rfc6238Secret, "050471", at)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1111111111/totpPeriod), step)
+
+	_, ok = generator.Validate(rfc6238Secret, "050471", at.Add(totpPeriod*time.Second))
+	assert.True(t, ok, "previous step is accepted to tolerate drift")
+
+	_, ok = generator.Validate(rfc6238Secret, "050471", at.Add(5*totpPeriod*time.Second))
+	assert.False(t, ok)
+
+	_, ok = generator.Validate(rfc6238Secret, "000000", at)
+	assert.False(t, ok)
+
+	_, ok = generator.Validate("not base32!", "050471", at)
+	assert.False(t, ok)
+}
+
+func TestTOTPGenerator_GenerateSecret(t *testing.T) {
+	generator := NewTOTPGenerator("System Education")
+
+	first, err := generator.GenerateSecret()
+	require.NoError(t, err)
+	second, err := generator.GenerateSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+}
+
+func TestTOTPGenerator_ProvisioningURI(t *testing.T) {
+	generator := NewTOTPGenerator("System Education")
+
+	uri := generator.ProvisioningURI("SECRET", "john@example.com")
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/System Education:john@example.com", parsed.Path)
+	assert.Equal(t, "SECRET", parsed.Query().Get("secret"))
+	assert.Equal(t, "System Education", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+}
@@ -0,0 +1,50 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type EmailVerificationToken struct {
+	ID        string `gorm:"primaryKey;type:uuid"`
+	UserID    string `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (EmailVerificationToken) TableName() string {
+	return "email_verification_tokens"
+}
+
+func FromEmailVerificationTokenEntity(t *auth_entity.EmailVerificationToken) *EmailVerificationToken {
+	if t == nil {
+		return nil
+	}
+
+	return &EmailVerificationToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		TokenHash: t.TokenHash,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func ToEmailVerificationTokenEntity(t *EmailVerificationToken) *auth_entity.EmailVerificationToken {
+	if t == nil {
+		return nil
+	}
+
+	return &auth_entity.EmailVerificationToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		TokenHash: t.TokenHash,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type LoginAttempt struct {
+	AttemptKey    string `gorm:"primaryKey"`
+	Failures      int
+	LastFailureAt time.Time
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+func ToLoginAttemptEntity(a *LoginAttempt) *auth_entity.LoginAttempt {
+	if a == nil {
+		return nil
+	}
+
+	return &auth_entity.LoginAttempt{
+		Key:           a.AttemptKey,
+		Failures:      a.Failures,
+		LastFailureAt: a.LastFailureAt,
+	}
+}
@@ -0,0 +1,119 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type MFAEnrollment struct {
+	UserID       string `gorm:"primaryKey;type:uuid"`
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (MFAEnrollment) TableName() string {
+	return "mfa_enrollments"
+}
+
+func FromMFAEnrollmentEntity(e *auth_entity.MFAEnrollment) *MFAEnrollment {
+	if e == nil {
+		return nil
+	}
+
+	return &MFAEnrollment{
+		UserID:       e.UserID,
+		Secret:       e.Secret,
+		ConfirmedAt:  e.ConfirmedAt,
+		LastUsedStep: e.LastUsedStep,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
+
+func ToMFAEnrollmentEntity(e *MFAEnrollment) *auth_entity.MFAEnrollment {
+	if e == nil {
+		return nil
+	}
+
+	return &auth_entity.MFAEnrollment{
+		UserID:       e.UserID,
+		Secret:       e.Secret,
+		ConfirmedAt:  e.ConfirmedAt,
+		LastUsedStep: e.LastUsedStep,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
+
+type MFARecoveryCode struct {
+	ID        string `gorm:"primaryKey;type:uuid"`
+	UserID    string `gorm:"index"`
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (MFARecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+func FromRecoveryCodeEntity(c *auth_entity.RecoveryCode) *MFARecoveryCode {
+	if c == nil {
+		return nil
+	}
+
+	return &MFARecoveryCode{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		CodeHash:  c.CodeHash,
+		UsedAt:    c.UsedAt,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+type MFAChallenge struct {
+	ID        string `gorm:"primaryKey;type:uuid"`
+	UserID    string `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (MFAChallenge) TableName() string {
+	return "mfa_challenges"
+}
+
+func FromMFAChallengeEntity(c *auth_entity.MFAChallenge) *MFAChallenge {
+	if c == nil {
+		return nil
+	}
+
+	return &MFAChallenge{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		TokenHash: c.TokenHash,
+		ExpiresAt: c.ExpiresAt,
+		UsedAt:    c.UsedAt,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func ToMFAChallengeEntity(c *MFAChallenge) *auth_entity.MFAChallenge {
+	if c == nil {
+		return nil
+	}
+
+	return &auth_entity.MFAChallenge{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		TokenHash: c.TokenHash,
+		ExpiresAt: c.ExpiresAt,
+		UsedAt:    c.UsedAt,
+		CreatedAt: c.CreatedAt,
+	}
+}
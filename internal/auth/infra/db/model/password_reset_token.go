@@ -0,0 +1,50 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type PasswordResetToken struct {
+	ID        string `gorm:"primaryKey;type:uuid"`
+	UserID    string `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
+func FromPasswordResetTokenEntity(t *auth_entity.PasswordResetToken) *PasswordResetToken {
+	if t == nil {
+		return nil
+	}
+
+	return &PasswordResetToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		TokenHash: t.TokenHash,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func ToPasswordResetTokenEntity(t *PasswordResetToken) *auth_entity.PasswordResetToken {
+	if t == nil {
+		return nil
+	}
+
+	return &auth_entity.PasswordResetToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		TokenHash: t.TokenHash,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
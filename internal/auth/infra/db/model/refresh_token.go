@@ -0,0 +1,69 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type RefreshToken struct {
+	ID           string `gorm:"primaryKey;type:uuid"`
+	UserID       string `gorm:"index"`
+	FamilyID     string `gorm:"index"`
+	TokenHash    string `gorm:"uniqueIndex"`
+	ExpiresAt    time.Time
+	RevokedAt    *time.Time
+	ReplacedByID *string
+	MFAVerified  bool
+	CreatedAt    time.Time
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+func FromRefreshTokenEntity(t *auth_entity.RefreshToken) *RefreshToken {
+	if t == nil {
+		return nil
+	}
+
+	var replacedByID *string
+	if t.ReplacedByID != "" {
+		replacedByID = &t.ReplacedByID
+	}
+
+	return &RefreshToken{
+		ID:           t.ID,
+		UserID:       t.UserID,
+		FamilyID:     t.FamilyID,
+		TokenHash:    t.TokenHash,
+		ExpiresAt:    t.ExpiresAt,
+		RevokedAt:    t.RevokedAt,
+		ReplacedByID: replacedByID,
+		MFAVerified:  t.MFAVerified,
+		CreatedAt:    t.CreatedAt,
+	}
+}
+
+func ToRefreshTokenEntity(t *RefreshToken) *auth_entity.RefreshToken {
+	if t == nil {
+		return nil
+	}
+
+	var replacedByID string
+	if t.ReplacedByID != nil {
+		replacedByID = *t.ReplacedByID
+	}
+
+	return &auth_entity.RefreshToken{
+		ID:           t.ID,
+		UserID:       t.UserID,
+		FamilyID:     t.FamilyID,
+		TokenHash:    t.TokenHash,
+		ExpiresAt:    t.ExpiresAt,
+		RevokedAt:    t.RevokedAt,
+		ReplacedByID: replacedByID,
+		MFAVerified:  t.MFAVerified,
+		CreatedAt:    t.CreatedAt,
+	}
+}
@@ -0,0 +1,23 @@
+package auth_model
+
+import "time"
+
+type RevokedToken struct {
+	JTI       string `gorm:"column:jti;primaryKey"`
+	UserID    string
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+type UserTokenRevocation struct {
+	UserID    string `gorm:"primaryKey"`
+	RevokedAt time.Time
+}
+
+func (UserTokenRevocation) TableName() string {
+	return "user_token_revocations"
+}
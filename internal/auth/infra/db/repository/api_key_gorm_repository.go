@@ -0,0 +1,82 @@
+package auth_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+)
+
+// lastUsedResolution limits how often authenticating with a key writes to the
+// database; last-used tracking does not need to be more precise than this.
+const lastUsedResolution = time.Minute
+
+type APIKeyGormRepository struct {
+	db *gorm.DB
+}
+
+var _ port_auth_repository.APIKeyRepository = &APIKeyGormRepository{}
+
+func NewAPIKeyGormRepository(db *gorm.DB) *APIKeyGormRepository {
+	return &APIKeyGormRepository{db: db}
+}
+
+func (r *APIKeyGormRepository) Save(ctx context.Context, k *auth_entity.APIKey) (*auth_entity.APIKey, error) {
+	model := auth_model.FromAPIKeyEntity(k)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToAPIKeyEntity(model), nil
+}
+
+func (r *APIKeyGormRepository) FindByPrefix(ctx context.Context, prefix string) (*auth_entity.APIKey, error) {
+	var model auth_model.APIKey
+	if err := r.db.WithContext(ctx).First(&model, "prefix = ?", prefix).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, port_auth_repository.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToAPIKeyEntity(&model), nil
+}
+
+func (r *APIKeyGormRepository) FindByUserID(ctx context.Context, userID string) ([]*auth_entity.APIKey, error) {
+	var models []*auth_model.APIKey
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	keys := make([]*auth_entity.APIKey, 0, len(models))
+	for _, model := range models {
+		keys = append(keys, auth_model.ToAPIKeyEntity(model))
+	}
+	return keys, nil
+}
+
+// Revoke is scoped to the owner so a user cannot revoke someone else's key by
+// guessing its id.
+func (r *APIKeyGormRepository) Revoke(ctx context.Context, id, userID string) error {
+	result := r.db.WithContext(ctx).Model(&auth_model.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return port_auth_repository.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeyGormRepository) TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&auth_model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-lastUsedResolution)).
+		Update("last_used_at", usedAt).Error
+}
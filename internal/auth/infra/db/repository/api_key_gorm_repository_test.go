@@ -0,0 +1,98 @@
+package auth_repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+)
+
+func setupAPIKeyRepository(t *testing.T) *auth_repository.APIKeyGormRepository {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&auth_model.APIKey{}))
+	return auth_repository.NewAPIKeyGormRepository(db)
+}
+
+func TestAPIKeyGormRepository_SaveAndFindByPrefix(t *testing.T) {
+	repo := setupAPIKeyRepository(t)
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	key := auth_entity.NewAPIKey("user-1", "sync", "prefix-1", "hash-1", []string{"students:read"}, &expiresAt)
+
+	_, err := repo.Save(context.Background(), key)
+	require.NoError(t, err)
+
+	found, err := repo.FindByPrefix(context.Background(), "prefix-1")
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, found.ID)
+	assert.Equal(t, "hash-1", found.KeyHash)
+	assert.Equal(t, []string{"students:read"}, found.Grants)
+	require.NotNil(t, found.ExpiresAt)
+	assert.True(t, expiresAt.Equal(*found.ExpiresAt))
+
+	_, err = repo.FindByPrefix(context.Background(), "missing")
+	assert.ErrorIs(t, err, port_auth_repository.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyGormRepository_FindByUserID(t *testing.T) {
+	repo := setupAPIKeyRepository(t)
+	for _, key := range []*auth_entity.APIKey{
+		auth_entity.NewAPIKey("user-1", "a", "p1", "h1", []string{"students:read"}, nil),
+		auth_entity.NewAPIKey("user-1", "b", "p2", "h2", []string{"students:read"}, nil),
+		auth_entity.NewAPIKey("user-2", "c", "p3", "h3", []string{"students:read"}, nil),
+	} {
+		_, err := repo.Save(context.Background(), key)
+		require.NoError(t, err)
+	}
+
+	keys, err := repo.FindByUserID(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+}
+
+func TestAPIKeyGormRepository_Revoke(t *testing.T) {
+	repo := setupAPIKeyRepository(t)
+	key := auth_entity.NewAPIKey("user-1", "sync", "prefix-1", "hash-1", []string{"students:read"}, nil)
+	_, err := repo.Save(context.Background(), key)
+	require.NoError(t, err)
+
+	err = repo.Revoke(context.Background(), key.ID, "user-2")
+	assert.ErrorIs(t, err, port_auth_repository.ErrAPIKeyNotFound)
+
+	require.NoError(t, repo.Revoke(context.Background(), key.ID, "user-1"))
+
+	found, err := repo.FindByPrefix(context.Background(), "prefix-1")
+	require.NoError(t, err)
+	assert.True(t, found.IsRevoked())
+
+	err = repo.Revoke(context.Background(), key.ID, "user-1")
+	assert.ErrorIs(t, err, port_auth_repository.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyGormRepository_TouchLastUsed(t *testing.T) {
+	repo := setupAPIKeyRepository(t)
+	key := auth_entity.NewAPIKey("user-1", "sync", "prefix-1", "hash-1", []string{"students:read"}, nil)
+	_, err := repo.Save(context.Background(), key)
+	require.NoError(t, err)
+
+	first := time.Now().Truncate(time.Second)
+	require.NoError(t, repo.TouchLastUsed(context.Background(), key.ID, first))
+	require.NoError(t, repo.TouchLastUsed(context.Background(), key.ID, first.Add(10*time.Second)))
+
+	found, err := repo.FindByPrefix(context.Background(), "prefix-1")
+	require.NoError(t, err)
+	require.NotNil(t, found.LastUsedAt)
+	assert.True(t, first.Equal(*found.LastUsedAt), "updates within a minute are skipped")
+
+	later := first.Add(2 * time.Minute)
+	require.NoError(t, repo.TouchLastUsed(context.Background(), key.ID, later))
+
+	found, err = repo.FindByPrefix(context.Background(), "prefix-1")
+	require.NoError(t, err)
+	assert.True(t, later.Equal(*found.LastUsedAt))
+}
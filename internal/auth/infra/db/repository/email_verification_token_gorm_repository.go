@@ -0,0 +1,63 @@
+package auth_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+)
+
+type EmailVerificationTokenGormRepository struct {
+	db *gorm.DB
+}
+
+var _ port_auth_repository.EmailVerificationTokenRepository = &EmailVerificationTokenGormRepository{}
+
+func NewEmailVerificationTokenGormRepository(db *gorm.DB) *EmailVerificationTokenGormRepository {
+	return &EmailVerificationTokenGormRepository{db: db}
+}
+
+func (r *EmailVerificationTokenGormRepository) Save(ctx context.Context, t *auth_entity.EmailVerificationToken) (*auth_entity.EmailVerificationToken, error) {
+	model := auth_model.FromEmailVerificationTokenEntity(t)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToEmailVerificationTokenEntity(model), nil
+}
+
+func (r *EmailVerificationTokenGormRepository) FindByHash(ctx context.Context, tokenHash string) (*auth_entity.EmailVerificationToken, error) {
+	var model auth_model.EmailVerificationToken
+	if err := r.db.WithContext(ctx).First(&model, "token_hash = ?", tokenHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, port_auth_repository.ErrEmailVerificationTokenNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToEmailVerificationTokenEntity(&model), nil
+}
+
+func (r *EmailVerificationTokenGormRepository) MarkUsed(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Model(&auth_model.EmailVerificationToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return port_auth_repository.ErrEmailVerificationTokenUsed
+	}
+
+	return nil
+}
+
+func (r *EmailVerificationTokenGormRepository) InvalidateAllForUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Model(&auth_model.EmailVerificationToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,71 @@
+package auth_repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+)
+
+func setupEmailVerificationRepository(t *testing.T) *auth_repository.EmailVerificationTokenGormRepository {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&auth_model.EmailVerificationToken{}))
+	return auth_repository.NewEmailVerificationTokenGormRepository(db)
+}
+
+func TestEmailVerificationTokenGormRepository_SaveAndFindByHash(t *testing.T) {
+	repo := setupEmailVerificationRepository(t)
+	token := auth_entity.NewEmailVerificationToken("user-1", "hash-1", time.Hour)
+
+	_, err := repo.Save(context.Background(), token)
+	require.NoError(t, err)
+
+	found, err := repo.FindByHash(context.Background(), "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, found.ID)
+	assert.False(t, found.IsUsed())
+
+	_, err = repo.FindByHash(context.Background(), "missing")
+	assert.ErrorIs(t, err, port_auth_repository.ErrEmailVerificationTokenNotFound)
+}
+
+func TestEmailVerificationTokenGormRepository_MarkUsed(t *testing.T) {
+	repo := setupEmailVerificationRepository(t)
+	token := auth_entity.NewEmailVerificationToken("user-1", "hash-1", time.Hour)
+	_, err := repo.Save(context.Background(), token)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.MarkUsed(context.Background(), token.ID))
+
+	found, err := repo.FindByHash(context.Background(), "hash-1")
+	require.NoError(t, err)
+	assert.True(t, found.IsUsed())
+
+	err = repo.MarkUsed(context.Background(), token.ID)
+	assert.ErrorIs(t, err, port_auth_repository.ErrEmailVerificationTokenUsed)
+}
+
+func TestEmailVerificationTokenGormRepository_InvalidateAllForUser(t *testing.T) {
+	repo := setupEmailVerificationRepository(t)
+	first := auth_entity.NewEmailVerificationToken("user-1", "hash-1", time.Hour)
+	second := auth_entity.NewEmailVerificationToken("user-1", "hash-2", time.Hour)
+	other := auth_entity.NewEmailVerificationToken("user-2", "hash-3", time.Hour)
+	for _, token := range []*auth_entity.EmailVerificationToken{first, second, other} {
+		_, err := repo.Save(context.Background(), token)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, repo.InvalidateAllForUser(context.Background(), "user-1"))
+
+	for hash, used := range map[string]bool{"hash-1": true, "hash-2": true, "hash-3": false} {
+		found, err := repo.FindByHash(context.Background(), hash)
+		require.NoError(t, err)
+		assert.Equal(t, used, found.IsUsed(), hash)
+	}
+}
@@ -0,0 +1,58 @@
+package auth_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginAttemptGormRepository struct {
+	db *gorm.DB
+}
+
+var _ port_auth_repository.LoginAttemptStore = &LoginAttemptGormRepository{}
+
+func NewLoginAttemptGormRepository(db *gorm.DB) *LoginAttemptGormRepository {
+	return &LoginAttemptGormRepository{db: db}
+}
+
+func (r *LoginAttemptGormRepository) Find(ctx context.Context, key string) (*auth_entity.LoginAttempt, error) {
+	var model auth_model.LoginAttempt
+	if err := r.db.WithContext(ctx).First(&model, "attempt_key = ?", key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &auth_entity.LoginAttempt{Key: key}, nil
+		}
+		return nil, err
+	}
+	return auth_model.ToLoginAttemptEntity(&model), nil
+}
+
+func (r *LoginAttemptGormRepository) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*auth_entity.LoginAttempt, error) {
+	model := &auth_model.LoginAttempt{AttemptKey: key, Failures: 1, LastFailureAt: now}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "attempt_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures": gorm.Expr(
+				"CASE WHEN login_attempts.last_failure_at < ? THEN 1 ELSE login_attempts.failures + 1 END",
+				now.Add(-window),
+			),
+			"last_failure_at": now,
+		}),
+	}).Create(model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Find(ctx, key)
+}
+
+func (r *LoginAttemptGormRepository) Reset(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Delete(&auth_model.LoginAttempt{}, "attempt_key = ?", key).Error
+}
@@ -0,0 +1,59 @@
+package auth_repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+)
+
+func setupLoginAttemptRepository(t *testing.T) *auth_repository.LoginAttemptGormRepository {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&auth_model.LoginAttempt{}))
+	return auth_repository.NewLoginAttemptGormRepository(db)
+}
+
+func TestLoginAttemptGormRepository_Find_Unknown(t *testing.T) {
+	repo := setupLoginAttemptRepository(t)
+
+	attempt, err := repo.Find(context.Background(), "account:ghost@example.com")
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, attempt.Failures)
+}
+
+func TestLoginAttemptGormRepository_RegisterFailure(t *testing.T) {
+	repo := setupLoginAttemptRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	attempt, err := repo.RegisterFailure(ctx, "ip:10.0.0.1", now, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+
+	attempt, err = repo.RegisterFailure(ctx, "ip:10.0.0.1", now.Add(time.Second), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempt.Failures)
+
+	attempt, err = repo.RegisterFailure(ctx, "ip:10.0.0.1", now.Add(2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures, "stale failures should restart the count")
+}
+
+func TestLoginAttemptGormRepository_Reset(t *testing.T) {
+	repo := setupLoginAttemptRepository(t)
+	ctx := context.Background()
+
+	_, err := repo.RegisterFailure(ctx, "account:john@example.com", time.Now().UTC(), time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Reset(ctx, "account:john@example.com"))
+
+	attempt, err := repo.Find(ctx, "account:john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 0, attempt.Failures)
+}
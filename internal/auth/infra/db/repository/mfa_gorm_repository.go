@@ -0,0 +1,140 @@
+package auth_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MFAGormRepository struct {
+	db *gorm.DB
+}
+
+var _ port_auth_repository.MFARepository = &MFAGormRepository{}
+
+func NewMFAGormRepository(db *gorm.DB) *MFAGormRepository {
+	return &MFAGormRepository{db: db}
+}
+
+func (r *MFAGormRepository) FindEnrollment(ctx context.Context, userID string) (*auth_entity.MFAEnrollment, error) {
+	var model auth_model.MFAEnrollment
+	if err := r.db.WithContext(ctx).First(&model, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, port_auth_repository.ErrMFAEnrollmentNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToMFAEnrollmentEntity(&model), nil
+}
+
+// SaveEnrollment replaces any previous secret of the user, which is how an
+// unfinished enrollment is restarted.
+func (r *MFAGormRepository) SaveEnrollment(ctx context.Context, e *auth_entity.MFAEnrollment) error {
+	model := auth_model.FromMFAEnrollmentEntity(e)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "confirmed_at", "last_used_step", "updated_at"}),
+	}).Create(model).Error
+}
+
+func (r *MFAGormRepository) ConfirmEnrollment(ctx context.Context, userID string, confirmedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&auth_model.MFAEnrollment{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{"confirmed_at": confirmedAt, "updated_at": time.Now()})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return port_auth_repository.ErrMFAEnrollmentNotFound
+	}
+
+	return nil
+}
+
+func (r *MFAGormRepository) DeleteEnrollment(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&auth_model.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&auth_model.MFAEnrollment{}).Error
+	})
+}
+
+// ConsumeStep records the time step of an accepted code and fails when that
+// step, or a later one, was already used, so a code cannot be replayed.
+func (r *MFAGormRepository) ConsumeStep(ctx context.Context, userID string, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&auth_model.MFAEnrollment{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Updates(map[string]interface{}{"last_used_step": step, "updated_at": time.Now()})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *MFAGormRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*auth_entity.RecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&auth_model.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if len(codes) == 0 {
+			return nil
+		}
+
+		models := make([]*auth_model.MFARecoveryCode, 0, len(codes))
+		for _, code := range codes {
+			models = append(models, auth_model.FromRecoveryCodeEntity(code))
+		}
+		return tx.Create(&models).Error
+	})
+}
+
+func (r *MFAGormRepository) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&auth_model.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *MFAGormRepository) SaveChallenge(ctx context.Context, c *auth_entity.MFAChallenge) error {
+	return r.db.WithContext(ctx).Create(auth_model.FromMFAChallengeEntity(c)).Error
+}
+
+func (r *MFAGormRepository) FindChallengeByHash(ctx context.Context, tokenHash string) (*auth_entity.MFAChallenge, error) {
+	var model auth_model.MFAChallenge
+	if err := r.db.WithContext(ctx).First(&model, "token_hash = ?", tokenHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, port_auth_repository.ErrMFAChallengeNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToMFAChallengeEntity(&model), nil
+}
+
+func (r *MFAGormRepository) MarkChallengeUsed(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&auth_model.MFAChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,137 @@
+package auth_repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+)
+
+func setupMFARepository(t *testing.T) *auth_repository.MFAGormRepository {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&auth_model.MFAEnrollment{}, &auth_model.MFARecoveryCode{}, &auth_model.MFAChallenge{}))
+	return auth_repository.NewMFAGormRepository(db)
+}
+
+func TestMFAGormRepository_SaveAndConfirmEnrollment(t *testing.T) {
+	repo := setupMFARepository(t)
+	ctx := context.Background()
+
+	_, err := repo.FindEnrollment(ctx, "user-1")
+	assert.ErrorIs(t, err, port_auth_repository.ErrMFAEnrollmentNotFound)
+
+	require.NoError(t, repo.SaveEnrollment(ctx, auth_entity.NewMFAEnrollment("user-1", "FIRST")))
+	require.NoError(t, repo.SaveEnrollment(ctx, auth_entity.NewMFAEnrollment("user-1", "SECOND")))
+
+	found, err := repo.FindEnrollment(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "SECOND", found.Secret)
+	assert.False(t, found.IsConfirmed())
+
+	require.NoError(t, repo.ConfirmEnrollment(ctx, "user-1", time.Now()))
+
+	found, err = repo.FindEnrollment(ctx, "user-1")
+	require.NoError(t, err)
+	assert.True(t, found.IsConfirmed())
+
+	err = repo.ConfirmEnrollment(ctx, "user-2", time.Now())
+	assert.ErrorIs(t, err, port_auth_repository.ErrMFAEnrollmentNotFound)
+}
+
+func TestMFAGormRepository_ConsumeStep(t *testing.T) {
+	repo := setupMFARepository(t)
+	ctx := context.Background()
+	require.NoError(t, repo.SaveEnrollment(ctx, auth_entity.NewMFAEnrollment("user-1", "SECRET")))
+
+	ok, err := repo.ConsumeStep(ctx, "user-1", 100)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.ConsumeStep(ctx, "user-1", 100)
+	require.NoError(t, err)
+	assert.False(t, ok, "same step cannot be used twice")
+
+	ok, err = repo.ConsumeStep(ctx, "user-1", 99)
+	require.NoError(t, err)
+	assert.False(t, ok, "older step cannot be used after a newer one")
+
+	ok, err = repo.ConsumeStep(ctx, "user-1", 101)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestMFAGormRepository_RecoveryCodes(t *testing.T) {
+	repo := setupMFARepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, "user-1", []*auth_entity.RecoveryCode{
+		auth_entity.NewRecoveryCode("user-1", "old"),
+	}))
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, "user-1", []*auth_entity.RecoveryCode{
+		auth_entity.NewRecoveryCode("user-1", "hash-1"),
+		auth_entity.NewRecoveryCode("user-1", "hash-2"),
+	}))
+
+	ok, err := repo.ConsumeRecoveryCode(ctx, "user-1", "old")
+	require.NoError(t, err)
+	assert.False(t, ok, "replaced codes are no longer valid")
+
+	ok, err = repo.ConsumeRecoveryCode(ctx, "user-2", "hash-1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = repo.ConsumeRecoveryCode(ctx, "user-1", "hash-1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.ConsumeRecoveryCode(ctx, "user-1", "hash-1")
+	require.NoError(t, err)
+	assert.False(t, ok, "codes are single use")
+}
+
+func TestMFAGormRepository_DeleteEnrollment(t *testing.T) {
+	repo := setupMFARepository(t)
+	ctx := context.Background()
+	require.NoError(t, repo.SaveEnrollment(ctx, auth_entity.NewMFAEnrollment("user-1", "SECRET")))
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, "user-1", []*auth_entity.RecoveryCode{
+		auth_entity.NewRecoveryCode("user-1", "hash-1"),
+	}))
+
+	require.NoError(t, repo.DeleteEnrollment(ctx, "user-1"))
+
+	_, err := repo.FindEnrollment(ctx, "user-1")
+	assert.ErrorIs(t, err, port_auth_repository.ErrMFAEnrollmentNotFound)
+	ok, err := repo.ConsumeRecoveryCode(ctx, "user-1", "hash-1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestMFAGormRepository_Challenges(t *testing.T) {
+	repo := setupMFARepository(t)
+	ctx := context.Background()
+	challenge := auth_entity.NewMFAChallenge("user-1", "challenge-hash", 5*time.Minute)
+
+	require.NoError(t, repo.SaveChallenge(ctx, challenge))
+
+	found, err := repo.FindChallengeByHash(ctx, "challenge-hash")
+	require.NoError(t, err)
+	assert.Equal(t, challenge.ID, found.ID)
+	assert.False(t, found.IsUsed())
+
+	_, err = repo.FindChallengeByHash(ctx, "missing")
+	assert.ErrorIs(t, err, port_auth_repository.ErrMFAChallengeNotFound)
+
+	ok, err := repo.MarkChallengeUsed(ctx, challenge.ID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.MarkChallengeUsed(ctx, challenge.ID)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,66 @@
+package auth_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	"gorm.io/gorm"
+)
+
+type PasswordResetTokenGormRepository struct {
+	db *gorm.DB
+}
+
+var _ port_auth_repository.PasswordResetTokenRepository = &PasswordResetTokenGormRepository{}
+
+func NewPasswordResetTokenGormRepository(db *gorm.DB) *PasswordResetTokenGormRepository {
+	return &PasswordResetTokenGormRepository{db: db}
+}
+
+func (r *PasswordResetTokenGormRepository) Save(ctx context.Context, t *auth_entity.PasswordResetToken) (*auth_entity.PasswordResetToken, error) {
+	model := auth_model.FromPasswordResetTokenEntity(t)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToPasswordResetTokenEntity(model), nil
+}
+
+func (r *PasswordResetTokenGormRepository) FindByHash(ctx context.Context, tokenHash string) (*auth_entity.PasswordResetToken, error) {
+	var model auth_model.PasswordResetToken
+	if err := r.db.WithContext(ctx).First(&model, "token_hash = ?", tokenHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, port_auth_repository.ErrPasswordResetTokenNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToPasswordResetTokenEntity(&model), nil
+}
+
+// MarkUsed only succeeds for a token that has not been consumed yet, so the
+// same link cannot reset the password twice even under concurrent requests.
+func (r *PasswordResetTokenGormRepository) MarkUsed(ctx context.Context, id string) error {
+	result := shared_database.Conn(ctx, r.db).Model(&auth_model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return port_auth_repository.ErrPasswordResetTokenUsed
+	}
+
+	return nil
+}
+
+func (r *PasswordResetTokenGormRepository) InvalidateAllForUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Model(&auth_model.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
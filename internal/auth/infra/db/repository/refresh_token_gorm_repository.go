@@ -0,0 +1,71 @@
+package auth_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenGormRepository struct {
+	db *gorm.DB
+}
+
+var _ port_auth_repository.RefreshTokenRepository = &RefreshTokenGormRepository{}
+
+func NewRefreshTokenGormRepository(db *gorm.DB) *RefreshTokenGormRepository {
+	return &RefreshTokenGormRepository{db: db}
+}
+
+func (r *RefreshTokenGormRepository) Save(ctx context.Context, t *auth_entity.RefreshToken) (*auth_entity.RefreshToken, error) {
+	model := auth_model.FromRefreshTokenEntity(t)
+	if err := shared_database.Conn(ctx, r.db).Create(model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToRefreshTokenEntity(model), nil
+}
+
+func (r *RefreshTokenGormRepository) FindByHash(ctx context.Context, tokenHash string) (*auth_entity.RefreshToken, error) {
+	var model auth_model.RefreshToken
+	if err := r.db.WithContext(ctx).First(&model, "token_hash = ?", tokenHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, port_auth_repository.ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToRefreshTokenEntity(&model), nil
+}
+
+// Revoke only succeeds for a token that is still active, so two concurrent
+// refreshes presenting the same token cannot both rotate it.
+func (r *RefreshTokenGormRepository) Revoke(ctx context.Context, id string, replacedByID string) error {
+	updates := map[string]interface{}{"revoked_at": time.Now()}
+	if replacedByID != "" {
+		updates["replaced_by_id"] = replacedByID
+	}
+
+	result := shared_database.Conn(ctx, r.db).Model(&auth_model.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(updates)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return port_auth_repository.ErrRefreshTokenRevoked
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenGormRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Model(&auth_model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,89 @@
+package auth_repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&auth_model.RefreshToken{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestRefreshTokenGormRepository_SaveAndFindByHash(t *testing.T) {
+	repo := auth_repository.NewRefreshTokenGormRepository(setupTestDB(t))
+	token := auth_entity.NewRefreshToken("user-1", "", "hash-1", time.Hour)
+
+	saved, err := repo.Save(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, saved.ID)
+
+	found, err := repo.FindByHash(context.Background(), "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, found.ID)
+	assert.Equal(t, token.FamilyID, found.FamilyID)
+	assert.False(t, found.IsRevoked())
+}
+
+func TestRefreshTokenGormRepository_FindByHash_NotFound(t *testing.T) {
+	repo := auth_repository.NewRefreshTokenGormRepository(setupTestDB(t))
+
+	found, err := repo.FindByHash(context.Background(), "unknown")
+
+	assert.Nil(t, found)
+	assert.ErrorIs(t, err, port_auth_repository.ErrRefreshTokenNotFound)
+}
+
+func TestRefreshTokenGormRepository_Revoke(t *testing.T) {
+	repo := auth_repository.NewRefreshTokenGormRepository(setupTestDB(t))
+	token := auth_entity.NewRefreshToken("user-1", "", "hash-1", time.Hour)
+	_, err := repo.Save(context.Background(), token)
+	require.NoError(t, err)
+
+	err = repo.Revoke(context.Background(), token.ID, "next-id")
+	require.NoError(t, err)
+
+	found, err := repo.FindByHash(context.Background(), "hash-1")
+	require.NoError(t, err)
+	assert.True(t, found.IsRevoked())
+	assert.Equal(t, "next-id", found.ReplacedByID)
+
+	err = repo.Revoke(context.Background(), token.ID, "other-id")
+	assert.ErrorIs(t, err, port_auth_repository.ErrRefreshTokenRevoked)
+}
+
+func TestRefreshTokenGormRepository_RevokeFamily(t *testing.T) {
+	repo := auth_repository.NewRefreshTokenGormRepository(setupTestDB(t))
+	first := auth_entity.NewRefreshToken("user-1", "", "hash-1", time.Hour)
+	second := auth_entity.NewRefreshToken("user-1", first.FamilyID, "hash-2", time.Hour)
+	other := auth_entity.NewRefreshToken("user-1", "", "hash-3", time.Hour)
+
+	for _, token := range []*auth_entity.RefreshToken{first, second, other} {
+		_, err := repo.Save(context.Background(), token)
+		require.NoError(t, err)
+	}
+
+	err := repo.RevokeFamily(context.Background(), first.FamilyID)
+	require.NoError(t, err)
+
+	for hash, revoked := range map[string]bool{"hash-1": true, "hash-2": true, "hash-3": false} {
+		found, err := repo.FindByHash(context.Background(), hash)
+		require.NoError(t, err)
+		assert.Equal(t, revoked, found.IsRevoked(), hash)
+	}
+}
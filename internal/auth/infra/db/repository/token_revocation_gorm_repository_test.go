@@ -0,0 +1,96 @@
+package auth_repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+)
+
+func setupRevocationRepository(t *testing.T) *auth_repository.TokenRevocationGormRepository {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&auth_model.RevokedToken{}, &auth_model.UserTokenRevocation{}))
+	return auth_repository.NewTokenRevocationGormRepository(db)
+}
+
+func TestTokenRevocationGormRepository_Revoke(t *testing.T) {
+	repo := setupRevocationRepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Revoke(ctx, "jti-1", "user-1", time.Now().Add(time.Hour)))
+	require.NoError(t, repo.Revoke(ctx, "jti-1", "user-1", time.Now().Add(time.Hour)))
+
+	revoked, err := repo.IsRevoked(ctx, "jti-1", "user-1", time.Now())
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsRevoked(ctx, "jti-2", "user-1", time.Now())
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenRevocationGormRepository_RevokeAllForUser(t *testing.T) {
+	repo := setupRevocationRepository(t)
+	ctx := context.Background()
+	issuedBefore := time.Now().Add(-time.Minute)
+
+	require.NoError(t, repo.RevokeAllForUser(ctx, "user-1"))
+	require.NoError(t, repo.RevokeAllForUser(ctx, "user-1"))
+
+	revoked, err := repo.IsRevoked(ctx, "jti-1", "user-1", issuedBefore)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsRevoked(ctx, "", "user-1", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = repo.IsRevoked(ctx, "jti-1", "user-2", issuedBefore)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenRevocationGormRepository_RevokeAllForUserWithinTheSecond(t *testing.T) {
+	repo := setupRevocationRepository(t)
+	ctx := context.Background()
+
+	// A token signed in the same second as the revocation carries the same
+	// iat as one signed just before it. Starting on a second boundary keeps
+	// both in one second.
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	require.NoError(t, repo.RevokeAllForUser(ctx, "user-1"))
+	issuedAt := time.Now().Truncate(time.Second)
+
+	revoked, err := repo.IsRevoked(ctx, "", "user-1", issuedAt)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsRevoked(ctx, "", "user-1", issuedAt.Add(time.Second))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenRevocationGormRepository_PurgeExpired(t *testing.T) {
+	repo := setupRevocationRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, repo.Revoke(ctx, "expired", "user-1", now.Add(-time.Minute)))
+	require.NoError(t, repo.Revoke(ctx, "live", "user-1", now.Add(time.Hour)))
+
+	purged, err := repo.PurgeExpired(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	revoked, err := repo.IsRevoked(ctx, "live", "user-2", now)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsRevoked(ctx, "expired", "user-2", now)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
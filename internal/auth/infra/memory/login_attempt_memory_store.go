@@ -0,0 +1,54 @@
+package auth_memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+)
+
+type LoginAttemptMemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]auth_entity.LoginAttempt
+}
+
+var _ port_auth_repository.LoginAttemptStore = &LoginAttemptMemoryStore{}
+
+func NewLoginAttemptMemoryStore() *LoginAttemptMemoryStore {
+	return &LoginAttemptMemoryStore{attempts: make(map[string]auth_entity.LoginAttempt)}
+}
+
+func (s *LoginAttemptMemoryStore) Find(_ context.Context, key string) (*auth_entity.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		return &auth_entity.LoginAttempt{Key: key}, nil
+	}
+	return &attempt, nil
+}
+
+func (s *LoginAttemptMemoryStore) RegisterFailure(_ context.Context, key string, now time.Time, window time.Duration) (*auth_entity.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok || attempt.LastFailureAt.Before(now.Add(-window)) {
+		attempt = auth_entity.LoginAttempt{Key: key}
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	s.attempts[key] = attempt
+
+	return &attempt, nil
+}
+
+func (s *LoginAttemptMemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.attempts, key)
+	return nil
+}
@@ -0,0 +1,41 @@
+package auth_memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginAttemptMemoryStore_RegisterFailure(t *testing.T) {
+	store := NewLoginAttemptMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	attempt, err := store.RegisterFailure(ctx, "ip:10.0.0.1", now, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+
+	attempt, err = store.RegisterFailure(ctx, "ip:10.0.0.1", now.Add(time.Second), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempt.Failures)
+
+	attempt, err = store.RegisterFailure(ctx, "ip:10.0.0.1", now.Add(2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+}
+
+func TestLoginAttemptMemoryStore_Reset(t *testing.T) {
+	store := NewLoginAttemptMemoryStore()
+	ctx := context.Background()
+
+	_, err := store.RegisterFailure(ctx, "account:john@example.com", time.Now(), time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, store.Reset(ctx, "account:john@example.com"))
+
+	attempt, err := store.Find(ctx, "account:john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 0, attempt.Failures)
+}
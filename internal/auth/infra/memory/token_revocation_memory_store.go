@@ -0,0 +1,67 @@
+package auth_memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+)
+
+type TokenRevocationMemoryStore struct {
+	mu          sync.RWMutex
+	revoked     map[string]time.Time
+	userRevoked map[string]time.Time
+}
+
+var _ port_auth_repository.TokenRevocationStore = &TokenRevocationMemoryStore{}
+var _ port_auth_repository.TokenRevocationPurger = &TokenRevocationMemoryStore{}
+
+func NewTokenRevocationMemoryStore() *TokenRevocationMemoryStore {
+	return &TokenRevocationMemoryStore{
+		revoked:     make(map[string]time.Time),
+		userRevoked: make(map[string]time.Time),
+	}
+}
+
+func (s *TokenRevocationMemoryStore) Revoke(_ context.Context, jti string, _ string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[jti] = expiresAt
+	return nil
+}
+
+func (s *TokenRevocationMemoryStore) RevokeAllForUser(_ context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.userRevoked[userID] = time.Now()
+	return nil
+}
+
+func (s *TokenRevocationMemoryStore) IsRevoked(_ context.Context, jti string, userID string, issuedAt time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.revoked[jti]; ok && jti != "" {
+		return true, nil
+	}
+
+	// iat only has second precision, so a token from the same second as the
+	// revocation is treated as revoked.
+	revokedAt, ok := s.userRevoked[userID]
+	return ok && !issuedAt.Truncate(time.Second).After(revokedAt.Truncate(time.Second)), nil
+}
+
+func (s *TokenRevocationMemoryStore) PurgeExpired(_ context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for jti, expiresAt := range s.revoked {
+		if !expiresAt.After(now) {
+			delete(s.revoked, jti)
+			purged++
+		}
+	}
+	return purged, nil
+}
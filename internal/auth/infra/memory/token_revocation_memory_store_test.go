@@ -0,0 +1,86 @@
+package auth_memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRevocationMemoryStore_Revoke(t *testing.T) {
+	store := NewTokenRevocationMemoryStore()
+	ctx := context.Background()
+
+	require.NoError(t, store.Revoke(ctx, "jti-1", "user-1", time.Now().Add(time.Hour)))
+
+	revoked, err := store.IsRevoked(ctx, "jti-1", "user-1", time.Now())
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = store.IsRevoked(ctx, "jti-2", "user-1", time.Now())
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenRevocationMemoryStore_RevokeAllForUser(t *testing.T) {
+	store := NewTokenRevocationMemoryStore()
+	ctx := context.Background()
+	issuedBefore := time.Now().Add(-time.Minute)
+
+	require.NoError(t, store.RevokeAllForUser(ctx, "user-1"))
+
+	revoked, err := store.IsRevoked(ctx, "jti-1", "user-1", issuedBefore)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = store.IsRevoked(ctx, "", "user-1", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = store.IsRevoked(ctx, "jti-1", "user-2", issuedBefore)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenRevocationMemoryStore_RevokeAllForUserWithinTheSecond(t *testing.T) {
+	store := NewTokenRevocationMemoryStore()
+	ctx := context.Background()
+
+	// A token signed in the same second as the revocation carries the same
+	// iat as one signed just before it. Starting on a second boundary keeps
+	// both in one second.
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	require.NoError(t, store.RevokeAllForUser(ctx, "user-1"))
+	issuedAt := time.Now().Truncate(time.Second)
+
+	revoked, err := store.IsRevoked(ctx, "", "user-1", issuedAt)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = store.IsRevoked(ctx, "", "user-1", issuedAt.Add(time.Second))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenRevocationMemoryStore_PurgeExpired(t *testing.T) {
+	store := NewTokenRevocationMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, store.Revoke(ctx, "expired", "user-1", now.Add(-time.Minute)))
+	require.NoError(t, store.Revoke(ctx, "live", "user-1", now.Add(time.Hour)))
+
+	purged, err := store.PurgeExpired(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	revoked, err := store.IsRevoked(ctx, "live", "user-2", now)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = store.IsRevoked(ctx, "expired", "user-2", now)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
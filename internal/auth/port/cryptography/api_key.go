@@ -0,0 +1,8 @@
+package port_auth_cryptography
+
+type APIKeyGenerator interface {
+	// Generate returns the full key handed to the user and its public prefix.
+	Generate() (key string, prefix string, err error)
+	Prefix(key string) (string, bool)
+	Hash(key string) string
+}
@@ -0,0 +1,6 @@
+package port_auth_cryptography
+
+type OpaqueTokenGenerator interface {
+	Generate() (string, error)
+	Hash(token string) string
+}
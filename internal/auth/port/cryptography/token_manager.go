@@ -1,6 +1,8 @@
 package port_auth_cryptography
 
+import auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+
 type TokenManager interface {
-	Sign(data map[string]interface{}) (string, error)
-	Verify(tokenStr string) (map[string]interface{}, error)
+	Sign(claims auth_entity.Claims) (string, error)
+	Verify(tokenStr string) (*auth_entity.Claims, error)
 }
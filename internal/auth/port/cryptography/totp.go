@@ -0,0 +1,11 @@
+package port_auth_cryptography
+
+import "time"
+
+type TOTP interface {
+	GenerateSecret() (string, error)
+	ProvisioningURI(secret, accountName string) string
+	// Validate returns the time step the code belongs to so callers can
+	// refuse to accept the same code twice.
+	Validate(secret, code string, at time.Time) (int64, bool)
+}
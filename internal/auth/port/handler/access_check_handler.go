@@ -0,0 +1,7 @@
+package port_auth_handler
+
+import "github.com/gin-gonic/gin"
+
+type AccessCheckHandler interface {
+	Check(c *gin.Context)
+}
@@ -4,5 +4,8 @@ import "github.com/gin-gonic/gin"
 
 type AuthHandler interface {
 	Login(c *gin.Context)
-	Profile( c *gin.Context)
+	VerifyMFA(c *gin.Context)
+	Refresh(c *gin.Context)
+	Logout(c *gin.Context)
+	Unlock(c *gin.Context)
 }
@@ -0,0 +1,8 @@
+package port_auth_handler
+
+import "github.com/gin-gonic/gin"
+
+type EmailVerificationHandler interface {
+	Verify(c *gin.Context)
+	Resend(c *gin.Context)
+}
@@ -0,0 +1,7 @@
+package port_auth_handler
+
+import "github.com/gin-gonic/gin"
+
+type JWKSHandler interface {
+	JWKS(c *gin.Context)
+}
@@ -0,0 +1,10 @@
+package port_auth_handler
+
+import "github.com/gin-gonic/gin"
+
+type MFAHandler interface {
+	Enroll(c *gin.Context)
+	Confirm(c *gin.Context)
+	Disable(c *gin.Context)
+	RegenerateRecoveryCodes(c *gin.Context)
+}
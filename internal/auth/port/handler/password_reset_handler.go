@@ -0,0 +1,8 @@
+package port_auth_handler
+
+import "github.com/gin-gonic/gin"
+
+type PasswordResetHandler interface {
+	Forgot(c *gin.Context)
+	Reset(c *gin.Context)
+}
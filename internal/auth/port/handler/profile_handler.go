@@ -0,0 +1,8 @@
+package port_auth_handler
+
+import "github.com/gin-gonic/gin"
+
+type ProfileHandler interface {
+	Me(c *gin.Context)
+	UpdateMe(c *gin.Context)
+}
@@ -0,0 +1,19 @@
+package port_auth_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type APIKeyRepository interface {
+	Save(ctx context.Context, k *auth_entity.APIKey) (*auth_entity.APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*auth_entity.APIKey, error)
+	FindByUserID(ctx context.Context, userID string) ([]*auth_entity.APIKey, error)
+	Revoke(ctx context.Context, id, userID string) error
+	TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error
+}
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
@@ -0,0 +1,20 @@
+package port_auth_repository
+
+import (
+	"context"
+	"errors"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type EmailVerificationTokenRepository interface {
+	Save(ctx context.Context, t *auth_entity.EmailVerificationToken) (*auth_entity.EmailVerificationToken, error)
+	FindByHash(ctx context.Context, tokenHash string) (*auth_entity.EmailVerificationToken, error)
+	MarkUsed(ctx context.Context, id string) error
+	InvalidateAllForUser(ctx context.Context, userID string) error
+}
+
+var (
+	ErrEmailVerificationTokenNotFound = errors.New("e-mail verification token not found")
+	ErrEmailVerificationTokenUsed     = errors.New("e-mail verification token already used")
+)
@@ -0,0 +1,17 @@
+package port_auth_repository
+
+import (
+	"context"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type LoginAttemptStore interface {
+	// Find returns an attempt with no failures when the key is unknown.
+	Find(ctx context.Context, key string) (*auth_entity.LoginAttempt, error)
+	// RegisterFailure atomically records a failed login, restarting the count
+	// when the previous failure is older than window.
+	RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*auth_entity.LoginAttempt, error)
+	Reset(ctx context.Context, key string) error
+}
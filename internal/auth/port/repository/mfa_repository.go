@@ -0,0 +1,29 @@
+package port_auth_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type MFARepository interface {
+	FindEnrollment(ctx context.Context, userID string) (*auth_entity.MFAEnrollment, error)
+	SaveEnrollment(ctx context.Context, e *auth_entity.MFAEnrollment) error
+	ConfirmEnrollment(ctx context.Context, userID string, confirmedAt time.Time) error
+	DeleteEnrollment(ctx context.Context, userID string) error
+	ConsumeStep(ctx context.Context, userID string, step int64) (bool, error)
+
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*auth_entity.RecoveryCode) error
+	ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+
+	SaveChallenge(ctx context.Context, c *auth_entity.MFAChallenge) error
+	FindChallengeByHash(ctx context.Context, tokenHash string) (*auth_entity.MFAChallenge, error)
+	MarkChallengeUsed(ctx context.Context, id string) (bool, error)
+}
+
+var (
+	ErrMFAEnrollmentNotFound = errors.New("mfa enrollment not found")
+	ErrMFAChallengeNotFound  = errors.New("mfa challenge not found")
+)
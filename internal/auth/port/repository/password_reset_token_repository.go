@@ -0,0 +1,20 @@
+package port_auth_repository
+
+import (
+	"context"
+	"errors"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type PasswordResetTokenRepository interface {
+	Save(ctx context.Context, t *auth_entity.PasswordResetToken) (*auth_entity.PasswordResetToken, error)
+	FindByHash(ctx context.Context, tokenHash string) (*auth_entity.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id string) error
+	InvalidateAllForUser(ctx context.Context, userID string) error
+}
+
+var (
+	ErrPasswordResetTokenNotFound = errors.New("password reset token not found")
+	ErrPasswordResetTokenUsed     = errors.New("password reset token already used")
+)
@@ -0,0 +1,20 @@
+package port_auth_repository
+
+import (
+	"context"
+	"errors"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type RefreshTokenRepository interface {
+	Save(ctx context.Context, t *auth_entity.RefreshToken) (*auth_entity.RefreshToken, error)
+	FindByHash(ctx context.Context, tokenHash string) (*auth_entity.RefreshToken, error)
+	Revoke(ctx context.Context, id string, replacedByID string) error
+	RevokeFamily(ctx context.Context, familyID string) error
+}
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenRevoked  = errors.New("refresh token already revoked")
+)
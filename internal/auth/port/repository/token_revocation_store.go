@@ -0,0 +1,22 @@
+package port_auth_repository
+
+import (
+	"context"
+	"time"
+)
+
+type TokenRevocationStore interface {
+	Revoke(ctx context.Context, jti string, userID string, expiresAt time.Time) error
+	RevokeAllForUser(ctx context.Context, userID string) error
+	// IsRevoked reports whether the token identified by jti was revoked, or
+	// whether every session of userID was revoked after issuedAt. Tokens
+	// issued in the same second as the revocation count as revoked.
+	// An empty jti only checks the user-wide revocation.
+	IsRevoked(ctx context.Context, jti string, userID string, issuedAt time.Time) (bool, error)
+}
+
+// TokenRevocationPurger drops revoked tokens that have expired, which are
+// rejected on expiry alone.
+type TokenRevocationPurger interface {
+	PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,11 @@
+package port_auth_usecase
+
+import (
+	"context"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type AccessCheckUsecase interface {
+	Check(ctx context.Context, principal *auth_entity.Claims, userID, module, action string, resource *auth_entity.Resource) (*auth_entity.AccessCheck, error)
+}
@@ -0,0 +1,18 @@
+package port_auth_usecase
+
+import (
+	"context"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type APIKeyUsecase interface {
+	Create(ctx context.Context, principal *auth_entity.Claims, name string, grants []string, expiresAt *time.Time) (*auth_entity.APIKey, string, error)
+	List(ctx context.Context, userID string) ([]*auth_entity.APIKey, error)
+	Revoke(ctx context.Context, userID, id string) error
+}
+
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*auth_entity.Claims, error)
+}
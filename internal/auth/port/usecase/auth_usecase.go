@@ -1,8 +1,16 @@
 package port_auth_usecase
 
-import "context"
+import (
+	"context"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
 
 type AuthUsecase interface {
-    Login(ctx context.Context, email, password string) (string, error)
-    Profile(ctx context.Context, email string) (string, error)
+	Login(ctx context.Context, email, password, clientIP string) (*auth_entity.TokenPair, error)
+	VerifyMFA(ctx context.Context, mfaToken, code, clientIP string) (*auth_entity.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*auth_entity.TokenPair, error)
+	Logout(ctx context.Context, userID, jti string, expiresAt time.Time, refreshToken string) error
+	Unlock(ctx context.Context, userID string) error
 }
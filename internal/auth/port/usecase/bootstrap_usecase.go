@@ -0,0 +1,11 @@
+package port_auth_usecase
+
+import (
+	"context"
+
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+)
+
+type BootstrapUsecase interface {
+	Run(ctx context.Context, name, email, password string) (*user_entity.User, error)
+}
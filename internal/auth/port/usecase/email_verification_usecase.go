@@ -0,0 +1,9 @@
+package port_auth_usecase
+
+import "context"
+
+type EmailVerificationUsecase interface {
+	Send(ctx context.Context, userID string) error
+	Verify(ctx context.Context, token string) error
+	Resend(ctx context.Context, email string) error
+}
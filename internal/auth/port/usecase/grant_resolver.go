@@ -0,0 +1,24 @@
+package port_auth_usecase
+
+import (
+	"context"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+)
+
+type GrantResolver interface {
+	Resolve(ctx context.Context, userID string) (permission_entity.ScopedGrants, error)
+}
+
+// GrantSources lists where a user's grants come from.
+type GrantSources interface {
+	Sources(ctx context.Context, userID string) ([]*permission_entity.Permission, []*role_entity.Role, error)
+}
+
+// GrantRefresher re-reads a principal's grants while serving a request, so
+// permission changes apply without a new login.
+type GrantRefresher interface {
+	Refresh(ctx context.Context, claims *auth_entity.Claims) (*auth_entity.Claims, error)
+}
@@ -0,0 +1,14 @@
+package port_auth_usecase
+
+import (
+	"context"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type MFAUsecase interface {
+	Enroll(ctx context.Context, userID string) (*auth_entity.MFASetup, error)
+	Confirm(ctx context.Context, userID, code string) ([]string, error)
+	Disable(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+}
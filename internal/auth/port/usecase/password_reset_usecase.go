@@ -0,0 +1,8 @@
+package port_auth_usecase
+
+import "context"
+
+type PasswordResetUsecase interface {
+	Forgot(ctx context.Context, email string) error
+	Reset(ctx context.Context, token, newPassword string) error
+}
@@ -0,0 +1,12 @@
+package port_auth_usecase
+
+import (
+	"context"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type ProfileUsecase interface {
+	Me(ctx context.Context, userID string) (*auth_entity.Profile, error)
+	UpdateMe(ctx context.Context, userID string, name, nickname *string) (*auth_entity.Profile, error)
+}
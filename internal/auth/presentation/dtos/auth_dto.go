@@ -1,6 +1,63 @@
 package auth_dtos
 
+import "time"
+
 type AuthDto struct {
 	Email    string `json:"email" binding:"required" validate:"required,email" example:"john.doe@example.com"`
 	Password string `json:"password" binding:"required" validate:"required,min=8" example:"strongPassword123"`
 }
+
+type RefreshTokenDto struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutDto struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ForgotPasswordDto struct {
+	Email string `json:"email" binding:"required,email" example:"john.doe@example.com"`
+}
+
+type ResetPasswordDto struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8" example:"strongPassword123"`
+}
+
+type ResendVerificationDto struct {
+	Email string `json:"email" binding:"required,email" example:"john.doe@example.com"`
+}
+
+type UpdateProfileDto struct {
+	Name     *string `json:"name" binding:"omitempty,min=2,max=100" example:"John"`
+	Nickname *string `json:"nickname" binding:"omitempty,min=2,max=50" example:"johnd"`
+}
+
+type VerifyMFADto struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+type MFACodeDto struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+type CreateAPIKeyDto struct {
+	Name      string     `json:"name" binding:"required,min=2,max=100" example:"nightly sync"`
+	Grants    []string   `json:"grants" binding:"required,min=1" example:"students:read"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-12-31T23:59:59Z"`
+}
+
+// AccessCheckDto asks whether a user may perform action on module. Without
+// user_id the caller's own token is checked.
+type AccessCheckDto struct {
+	UserID   string                  `json:"user_id"`
+	Module   string                  `json:"module" binding:"required" example:"students"`
+	Action   string                  `json:"action" binding:"required" example:"delete"`
+	Resource *AccessCheckResourceDto `json:"resource"`
+}
+
+type AccessCheckResourceDto struct {
+	Type string `json:"type" binding:"required,oneof=school student" example:"student"`
+	ID   string `json:"id" binding:"required"`
+}
@@ -0,0 +1,63 @@
+package auth_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_mapper "github.com/williamkoller/system-education/internal/auth/application/mapper"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+)
+
+type AccessCheckHandler struct {
+	usecase port_auth_usecase.AccessCheckUsecase
+}
+
+func NewAccessCheckHandler(usecase port_auth_usecase.AccessCheckUsecase) *AccessCheckHandler {
+	return &AccessCheckHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.AccessCheckHandler = &AccessCheckHandler{}
+
+func (h *AccessCheckHandler) Check(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	var input auth_dtos.AccessCheckDto
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	var resource *auth_entity.Resource
+	if input.Resource != nil {
+		resource = &auth_entity.Resource{Type: input.Resource.Type, ID: input.Resource.ID}
+	}
+
+	check, err := h.usecase.Check(c.Request.Context(), claims, input.UserID, input.Module, input.Action, resource)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth_entity.ErrAccessCheckDenied):
+			c.Status(http.StatusForbidden)
+		case errors.Is(err, auth_entity.ErrResourceNotFound):
+			c.Status(http.StatusNotFound)
+		case errors.Is(err, auth_entity.ErrUnknownResourceType):
+			c.Status(http.StatusBadRequest)
+		default:
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToAccessCheckResponse(check))
+}
@@ -0,0 +1,98 @@
+package auth_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_mapper "github.com/williamkoller/system-education/internal/auth/application/mapper"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+)
+
+type APIKeyHandler struct {
+	usecase port_auth_usecase.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(usecase port_auth_usecase.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.APIKeyHandler = &APIKeyHandler{}
+
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var input auth_dtos.CreateAPIKeyDto
+
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	apiKey, key, err := h.usecase.Create(c.Request.Context(), claims, input.Name, input.Grants, input.ExpiresAt)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, auth_mapper.ToCreatedAPIKeyResponse(apiKey, key))
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	keys, err := h.usecase.List(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToAPIKeyResponses(keys))
+}
+
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.Revoke(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *APIKeyHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, port_auth_repository.ErrAPIKeyNotFound):
+		c.Status(http.StatusNotFound)
+	case errors.Is(err, auth_entity.ErrInvalidAPIKeyScope):
+		c.Status(http.StatusBadRequest)
+	case errors.Is(err, auth_entity.ErrAPIKeyScopeDenied),
+		errors.Is(err, auth_entity.ErrAPIKeyNotAllowed):
+		c.Status(http.StatusForbidden)
+	default:
+		c.Status(http.StatusInternalServerError)
+	}
+	c.Error(err).SetType(gin.ErrorTypePublic)
+}
@@ -1,12 +1,19 @@
 package auth_handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	auth_mapper "github.com/williamkoller/system-education/internal/auth/application/mapper"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
 	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
 )
 
 type AuthHandler struct {
@@ -28,28 +35,114 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.usecase.Login(c.Request.Context(), input.Email, input.Password)
+	pair, err := h.usecase.Login(c.Request.Context(), input.Email, input.Password, c.ClientIP())
 
 	if err != nil {
-		c.Status(http.StatusUnauthorized)
+		var challenge *auth_entity.MFARequiredError
+		if errors.As(err, &challenge) {
+			c.JSON(http.StatusOK, auth_mapper.ToMFAChallengeResponse(challenge))
+			return
+		}
+		h.loginError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToTokenResponse(pair))
+}
+
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var input auth_dtos.VerifyMFADto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	pair, err := h.usecase.VerifyMFA(c.Request.Context(), input.MFAToken, input.Code, c.ClientIP())
+	if err != nil {
+		h.loginError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToTokenResponse(pair))
+}
+
+func (h *AuthHandler) loginError(c *gin.Context, err error) {
+	var throttled *auth_entity.LoginThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		c.Status(http.StatusTooManyRequests)
+	case errors.Is(err, auth_entity.ErrInvalidCredentials),
+		errors.Is(err, auth_entity.ErrInvalidMFAToken),
+		errors.Is(err, auth_entity.ErrInvalidMFACode):
+		c.Status(http.StatusUnauthorized)
+	case errors.Is(err, auth_entity.ErrEmailNotVerified):
+		c.Status(http.StatusForbidden)
+	default:
+		c.Status(http.StatusInternalServerError)
+	}
+	c.Error(err).SetType(gin.ErrorTypePublic)
 }
 
-func (h *AuthHandler) Profile(c *gin.Context) {
-	userEmail, _ := c.Get("userEmail")
-	email, err := h.usecase.Profile(c.Request.Context(), userEmail.(string))
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var input auth_dtos.RefreshTokenDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	pair, err := h.usecase.Refresh(c.Request.Context(), input.RefreshToken)
 
 	if err != nil {
-		c.Status(http.StatusNotFound)
+		c.Status(http.StatusUnauthorized)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToTokenResponse(pair))
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var input auth_dtos.LogoutDto
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.Status(http.StatusBadRequest)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
+	}
+
+	claims, ok := auth_middleware.Principal(c)
+	if !ok || claims.ID == "" {
+		c.Status(http.StatusBadRequest)
+		c.Error(errors.New("token does not support logout")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.Logout(c.Request.Context(), claims.UserID, claims.ID, claims.ExpiresAt, input.RefreshToken); err != nil {
+		c.Status(http.StatusInternalServerError)
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Welcome to your profile",
-		"email":   email,
-	})
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) Unlock(c *gin.Context) {
+	if err := h.usecase.Unlock(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, port_user_repository.ErrUserNotFound) {
+			c.Status(http.StatusNotFound)
+		} else {
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,88 @@
+package auth_handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type MockAuthUsecase struct {
+	mock.Mock
+}
+
+func (m *MockAuthUsecase) Login(ctx context.Context, email, password, clientIP string) (*auth_entity.TokenPair, error) {
+	args := m.Called(ctx, email, password, clientIP)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthUsecase) VerifyMFA(ctx context.Context, mfaToken, code, clientIP string) (*auth_entity.TokenPair, error) {
+	args := m.Called(ctx, mfaToken, code, clientIP)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthUsecase) Refresh(ctx context.Context, refreshToken string) (*auth_entity.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthUsecase) Logout(ctx context.Context, userID, jti string, expiresAt time.Time, refreshToken string) error {
+	args := m.Called(ctx, userID, jti, expiresAt, refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockAuthUsecase) Unlock(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func TestAuthHandler_Login_ClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	login := func(t *testing.T, trustedProxies []string, forwardedFor string) *MockAuthUsecase {
+		usecase := new(MockAuthUsecase)
+		usecase.On("Login", mock.Anything, "john@example.com", "secret", mock.Anything).Return(&auth_entity.TokenPair{}, nil)
+
+		g := gin.New()
+		require.NoError(t, g.SetTrustedProxies(trustedProxies))
+		g.POST("/auth/login", NewAuthHandler(usecase).Login)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"email":"john@example.com","password":"secret"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		req.RemoteAddr = "203.0.113.7:51234"
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		return usecase
+	}
+
+	t.Run("ignores a spoofed X-Forwarded-For from an untrusted peer", func(t *testing.T) {
+		usecase := login(t, nil, "198.51.100.1")
+
+		usecase.AssertCalled(t, "Login", mock.Anything, "john@example.com", "secret", "203.0.113.7")
+	})
+
+	t.Run("uses X-Forwarded-For from a trusted proxy", func(t *testing.T) {
+		usecase := login(t, []string{"203.0.113.0/24"}, "198.51.100.1")
+
+		usecase.AssertCalled(t, "Login", mock.Anything, "john@example.com", "secret", "198.51.100.1")
+	})
+}
@@ -0,0 +1,65 @@
+package auth_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+)
+
+type EmailVerificationHandler struct {
+	usecase port_auth_usecase.EmailVerificationUsecase
+}
+
+func NewEmailVerificationHandler(usecase port_auth_usecase.EmailVerificationUsecase) *EmailVerificationHandler {
+	return &EmailVerificationHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.EmailVerificationHandler = &EmailVerificationHandler{}
+
+func (h *EmailVerificationHandler) Verify(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.Status(http.StatusBadRequest)
+		c.Error(errors.New("token is required")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.Verify(c.Request.Context(), token); err != nil {
+		if errors.Is(err, auth_entity.ErrInvalidEmailVerificationToken) {
+			c.Status(http.StatusBadRequest)
+		} else {
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "e-mail verified",
+	})
+}
+
+func (h *EmailVerificationHandler) Resend(c *gin.Context) {
+	var input auth_dtos.ResendVerificationDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.Resend(c.Request.Context(), input.Email); err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "if the e-mail is registered and not yet verified, a verification link has been sent",
+	})
+}
@@ -0,0 +1,24 @@
+package auth_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+)
+
+type JWKSHandler struct {
+	keys port_auth_cryptography.KeySetProvider
+}
+
+func NewJWKSHandler(keys port_auth_cryptography.KeySetProvider) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+var _ port_auth_handler.JWKSHandler = &JWKSHandler{}
+
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": h.keys.PublicKeys()})
+}
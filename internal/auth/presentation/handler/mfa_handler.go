@@ -0,0 +1,120 @@
+package auth_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_mapper "github.com/williamkoller/system-education/internal/auth/application/mapper"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type MFAHandler struct {
+	usecase port_auth_usecase.MFAUsecase
+}
+
+func NewMFAHandler(usecase port_auth_usecase.MFAUsecase) *MFAHandler {
+	return &MFAHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.MFAHandler = &MFAHandler{}
+
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	setup, err := h.usecase.Enroll(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToMFASetupResponse(setup))
+}
+
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	claims, input, ok := h.bind(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.usecase.Confirm(c.Request.Context(), claims.UserID, input.Code)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &auth_mapper.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *MFAHandler) Disable(c *gin.Context) {
+	claims, input, ok := h.bind(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Disable(c.Request.Context(), claims.UserID, input.Code); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	claims, input, ok := h.bind(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.usecase.RegenerateRecoveryCodes(c.Request.Context(), claims.UserID, input.Code)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &auth_mapper.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *MFAHandler) bind(c *gin.Context) (*auth_entity.Claims, auth_dtos.MFACodeDto, bool) {
+	var input auth_dtos.MFACodeDto
+
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return nil, input, false
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return nil, input, false
+	}
+
+	return claims, input, true
+}
+
+func (h *MFAHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, port_user_repository.ErrUserNotFound):
+		c.Status(http.StatusNotFound)
+	case errors.Is(err, auth_entity.ErrInvalidMFACode):
+		c.Status(http.StatusUnauthorized)
+	case errors.Is(err, auth_entity.ErrMFANotEnabled),
+		errors.Is(err, auth_entity.ErrMFAAlreadyEnabled):
+		c.Status(http.StatusConflict)
+	default:
+		c.Status(http.StatusInternalServerError)
+	}
+	c.Error(err).SetType(gin.ErrorTypePublic)
+}
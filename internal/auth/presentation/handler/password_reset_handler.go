@@ -0,0 +1,64 @@
+package auth_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+)
+
+type PasswordResetHandler struct {
+	usecase port_auth_usecase.PasswordResetUsecase
+}
+
+func NewPasswordResetHandler(usecase port_auth_usecase.PasswordResetUsecase) *PasswordResetHandler {
+	return &PasswordResetHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.PasswordResetHandler = &PasswordResetHandler{}
+
+func (h *PasswordResetHandler) Forgot(c *gin.Context) {
+	var input auth_dtos.ForgotPasswordDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.Forgot(c.Request.Context(), input.Email); err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "if the e-mail is registered, a reset link has been sent",
+	})
+}
+
+func (h *PasswordResetHandler) Reset(c *gin.Context) {
+	var input auth_dtos.ResetPasswordDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.Reset(c.Request.Context(), input.Token, input.Password); err != nil {
+		if errors.Is(err, auth_entity.ErrInvalidPasswordResetToken) {
+			c.Status(http.StatusBadRequest)
+		} else {
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package auth_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_mapper "github.com/williamkoller/system-education/internal/auth/application/mapper"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type ProfileHandler struct {
+	usecase port_auth_usecase.ProfileUsecase
+}
+
+func NewProfileHandler(usecase port_auth_usecase.ProfileUsecase) *ProfileHandler {
+	return &ProfileHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.ProfileHandler = &ProfileHandler{}
+
+func (h *ProfileHandler) Me(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	profile, err := h.usecase.Me(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToProfileResponse(profile, claims))
+}
+
+func (h *ProfileHandler) UpdateMe(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	var input auth_dtos.UpdateProfileDto
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	profile, err := h.usecase.UpdateMe(c.Request.Context(), claims.UserID, input.Name, input.Nickname)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToProfileResponse(profile, claims))
+}
+
+func (h *ProfileHandler) handleError(c *gin.Context, err error) {
+	var validationErr *user_entity.ValidationError
+
+	switch {
+	case errors.Is(err, port_user_repository.ErrUserNotFound):
+		c.Status(http.StatusNotFound)
+	case errors.As(err, &validationErr):
+		c.Status(http.StatusBadRequest)
+	default:
+		c.Status(http.StatusInternalServerError)
+	}
+	c.Error(err).SetType(gin.ErrorTypePublic)
+}
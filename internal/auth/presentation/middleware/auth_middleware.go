@@ -1,39 +1,64 @@
 package auth_middleware
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 )
 
-func AuthMiddleware(jwt port_auth_cryptography.TokenManager) gin.HandlerFunc {
+const (
+	bearerScheme = "Bearer "
+	apiKeyScheme = "ApiKey "
+)
+
+func AuthMiddleware(jwt port_auth_cryptography.TokenManager, revocations port_auth_repository.TokenRevocationStore, apiKeys port_auth_usecase.APIKeyAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
-		if len(authHeader) < 7 || authHeader[:7] != "Bearer " {
+
+		if strings.HasPrefix(authHeader, apiKeyScheme) {
+			claims, err := apiKeys.Authenticate(c.Request.Context(), authHeader[len(apiKeyScheme):])
+			if err != nil {
+				if errors.Is(err, auth_entity.ErrInvalidAPIKey) {
+					c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+					return
+				}
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not validate api key"})
+				return
+			}
+
+			SetPrincipal(c, claims)
+			c.Next()
+			return
+		}
+
+		if !strings.HasPrefix(authHeader, bearerScheme) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid token"})
 			return
 		}
-		tokenStr := authHeader[7:]
+		tokenStr := authHeader[len(bearerScheme):]
 		claims, err := jwt.Verify(tokenStr)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			return
 		}
 
-		c.Set("userEmail", claims["email"])
-
-		if userID, ok := claims["user_id"]; ok {
-			c.Set("userID", userID)
+		revoked, err := revocations.IsRevoked(c.Request.Context(), claims.ID, claims.UserID, claims.IssuedAt)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not validate token"})
+			return
 		}
-
-		if modules, ok := claims["modules"]; ok {
-			c.Set("modules", modules)
+		if revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+			return
 		}
 
-		if actions, ok := claims["actions"]; ok {
-			c.Set("actions", actions)
-		}
+		SetPrincipal(c, claims)
 
 		c.Next()
 	}
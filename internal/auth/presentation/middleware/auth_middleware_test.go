@@ -1,31 +1,48 @@
 package auth_middleware
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_memory "github.com/williamkoller/system-education/internal/auth/infra/memory"
 )
 
 type MockTokenManager struct {
 	mock.Mock
 }
 
-func (m *MockTokenManager) Sign(data map[string]interface{}) (string, error) {
-	args := m.Called(data)
+func (m *MockTokenManager) Sign(claims auth_entity.Claims) (string, error) {
+	args := m.Called(claims)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockTokenManager) Verify(token string) (map[string]interface{}, error) {
+func (m *MockTokenManager) Verify(token string) (*auth_entity.Claims, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(map[string]interface{}), args.Error(1)
+	return args.Get(0).(*auth_entity.Claims), args.Error(1)
+}
+
+type MockAPIKeyAuthenticator struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyAuthenticator) Authenticate(ctx context.Context, key string) (*auth_entity.Claims, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.Claims), args.Error(1)
 }
 
 func TestAuthMiddleware_Success_WithAllClaims(t *testing.T) {
@@ -34,25 +51,23 @@ func TestAuthMiddleware_Success_WithAllClaims(t *testing.T) {
 	mockJWT := new(MockTokenManager)
 	token := "valid.jwt.token"
 
-	claims := map[string]interface{}{
-		"email":   "user@example.com",
-		"user_id": "user-123",
-		"modules": []interface{}{"admin", "user"},
+	claims := &auth_entity.Claims{
+		Email:  "user@example.com",
+		UserID: "user-123",
+		Grants: []string{"admin:*", "user:read"},
 	}
 
 	mockJWT.On("Verify", token).Return(claims, nil)
 
 	router := gin.New()
-	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
-		// Verify all claims were set in context
-		email, _ := c.Get("userEmail")
-		userID, _ := c.Get("userID")
-		modules, _ := c.Get("modules")
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
+		// Verify the principal was set in context
+		principal, _ := Principal(c)
 
 		c.JSON(http.StatusOK, gin.H{
-			"email":   email,
-			"user_id": userID,
-			"modules": modules,
+			"email":   principal.Email,
+			"user_id": principal.UserID,
+			"grants":  principal.Grants,
 		})
 	})
 
@@ -75,23 +90,21 @@ func TestAuthMiddleware_Success_WithoutOptionalClaims(t *testing.T) {
 	token := "valid.jwt.token"
 
 	// Only email claim (minimal valid token)
-	claims := map[string]interface{}{
-		"email": "user@example.com",
+	claims := &auth_entity.Claims{
+		Email: "user@example.com",
 	}
 
 	mockJWT.On("Verify", token).Return(claims, nil)
 
 	router := gin.New()
-	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
-		email, emailExists := c.Get("userEmail")
-		_, userIDExists := c.Get("userID")
-		_, modulesExist := c.Get("modules")
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
+		principal, ok := Principal(c)
 
 		c.JSON(http.StatusOK, gin.H{
-			"email":         email,
-			"email_exists":  emailExists,
-			"userid_exists": userIDExists,
-			"modules_exist": modulesExist,
+			"email":         principal.Email,
+			"email_exists":  ok,
+			"userid_exists": principal.UserID != "",
+			"grants_exist":  len(principal.Grants) > 0,
 		})
 	})
 
@@ -105,7 +118,7 @@ func TestAuthMiddleware_Success_WithoutOptionalClaims(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "user@example.com")
 	assert.Contains(t, w.Body.String(), `"email_exists":true`)
 	assert.Contains(t, w.Body.String(), `"userid_exists":false`)
-	assert.Contains(t, w.Body.String(), `"modules_exist":false`)
+	assert.Contains(t, w.Body.String(), `"grants_exist":false`)
 	mockJWT.AssertExpectations(t)
 }
 
@@ -115,7 +128,7 @@ func TestAuthMiddleware_MissingAuthorizationHeader(t *testing.T) {
 	mockJWT := new(MockTokenManager)
 
 	router := gin.New()
-	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
@@ -136,7 +149,7 @@ func TestAuthMiddleware_InvalidAuthorizationHeader_TooShort(t *testing.T) {
 	mockJWT := new(MockTokenManager)
 
 	router := gin.New()
-	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
@@ -157,7 +170,7 @@ func TestAuthMiddleware_InvalidAuthorizationHeader_WrongPrefix(t *testing.T) {
 	mockJWT := new(MockTokenManager)
 
 	router := gin.New()
-	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
@@ -181,7 +194,7 @@ func TestAuthMiddleware_TokenVerificationFails(t *testing.T) {
 	mockJWT.On("Verify", token).Return(nil, errors.New("invalid signature"))
 
 	router := gin.New()
-	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
@@ -205,7 +218,7 @@ func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 	mockJWT.On("Verify", token).Return(nil, errors.New("token expired"))
 
 	router := gin.New()
-	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
@@ -229,7 +242,7 @@ func TestAuthMiddleware_EmptyToken(t *testing.T) {
 	mockJWT.On("Verify", "").Return(nil, errors.New("empty token"))
 
 	router := gin.New()
-	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
@@ -250,23 +263,26 @@ func TestAuthMiddleware_ContextPropagation(t *testing.T) {
 	mockJWT := new(MockTokenManager)
 	token := "valid.jwt.token"
 
-	claims := map[string]interface{}{
-		"email":   "test@example.com",
-		"user_id": "user-456",
-		"modules": []interface{}{"reports", "analytics"},
+	claims := &auth_entity.Claims{
+		Email:  "test@example.com",
+		UserID: "user-456",
+		Grants: []string{"reports:read", "analytics:read"},
 	}
 
 	mockJWT.On("Verify", token).Return(claims, nil)
 
-	var capturedEmail interface{}
-	var capturedUserID interface{}
-	var capturedModules interface{}
+	var capturedEmail string
+	var capturedUserID string
+	var capturedGrants []string
+	var capturedFromContext *auth_entity.Claims
 
 	router := gin.New()
-	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
-		capturedEmail, _ = c.Get("userEmail")
-		capturedUserID, _ = c.Get("userID")
-		capturedModules, _ = c.Get("modules")
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
+		principal, _ := Principal(c)
+		capturedEmail = principal.Email
+		capturedUserID = principal.UserID
+		capturedGrants = principal.Grants
+		capturedFromContext, _ = auth_entity.PrincipalFrom(c.Request.Context())
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
@@ -279,39 +295,35 @@ func TestAuthMiddleware_ContextPropagation(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "test@example.com", capturedEmail)
 	assert.Equal(t, "user-456", capturedUserID)
-	assert.NotNil(t, capturedModules)
+	assert.NotNil(t, capturedGrants)
+	assert.Same(t, claims, capturedFromContext)
 	mockJWT.AssertExpectations(t)
 }
 
-func TestAuthMiddleware_WithActionsInClaims(t *testing.T) {
+func TestAuthMiddleware_WithGrantsInClaims(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockJWT := new(MockTokenManager)
 	token := "valid.jwt.token"
 
-	claims := map[string]interface{}{
-		"email":   "user@example.com",
-		"user_id": "user-123",
-		"modules": []interface{}{"admin", "user"},
-		"actions": []interface{}{"read", "delete", "update"},
+	claims := &auth_entity.Claims{
+		Email:  "user@example.com",
+		UserID: "user-123",
+		Grants: []string{"admin:read", "admin:delete", "admin:update", "user:read", "user:delete", "user:update"},
 	}
 
 	mockJWT.On("Verify", token).Return(claims, nil)
 
 	router := gin.New()
-	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
 		// Verify all claims were set in context
-		email, _ := c.Get("userEmail")
-		userID, _ := c.Get("userID")
-		modules, _ := c.Get("modules")
-		actions, actionsExist := c.Get("actions")
+		principal, _ := Principal(c)
 
 		c.JSON(http.StatusOK, gin.H{
-			"email":         email,
-			"user_id":       userID,
-			"modules":       modules,
-			"actions":       actions,
-			"actions_exist": actionsExist,
+			"email":        principal.Email,
+			"user_id":      principal.UserID,
+			"grants":       principal.Grants,
+			"grants_exist": len(principal.Grants) > 0,
 		})
 	})
 
@@ -324,9 +336,200 @@ func TestAuthMiddleware_WithActionsInClaims(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), "user@example.com")
 	assert.Contains(t, w.Body.String(), "user-123")
-	assert.Contains(t, w.Body.String(), `"actions_exist":true`)
+	assert.Contains(t, w.Body.String(), `"grants_exist":true`)
 	assert.Contains(t, w.Body.String(), "read")
 	assert.Contains(t, w.Body.String(), "delete")
 	assert.Contains(t, w.Body.String(), "update")
 	mockJWT.AssertExpectations(t)
 }
+
+func TestAuthMiddleware_RevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+	token := "revoked.jwt.token"
+
+	claims := &auth_entity.Claims{
+		Email:    "user@example.com",
+		UserID:   "user-123",
+		ID:       "jti-123",
+		IssuedAt: time.Now(),
+	}
+
+	mockJWT.On("Verify", token).Return(claims, nil)
+	require.NoError(t, revocations.Revoke(context.Background(), "jti-123", "user-123", time.Now().Add(time.Hour)))
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT, revocations, new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "token revoked")
+}
+
+func TestAuthMiddleware_AllSessionsRevokedForUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	revocations := auth_memory.NewTokenRevocationMemoryStore()
+	token := "old.jwt.token"
+
+	claims := &auth_entity.Claims{
+		Email:    "user@example.com",
+		UserID:   "user-123",
+		ID:       "jti-123",
+		IssuedAt: time.Now().Add(-time.Minute),
+	}
+
+	mockJWT.On("Verify", token).Return(claims, nil)
+	require.NoError(t, revocations.RevokeAllForUser(context.Background(), "user-123"))
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT, revocations, new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "token revoked")
+}
+
+func TestAuthMiddleware_SetsTokenMetadata(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	token := "valid.jwt.token"
+	expiresAt := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+
+	claims := &auth_entity.Claims{
+		Email:     "user@example.com",
+		UserID:    "user-123",
+		ID:        "jti-123",
+		IssuedAt:  time.Now(),
+		ExpiresAt: expiresAt,
+	}
+
+	mockJWT.On("Verify", token).Return(claims, nil)
+
+	var captured *auth_entity.Claims
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), new(MockAPIKeyAuthenticator)), func(c *gin.Context) {
+		captured, _ = Principal(c)
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, captured)
+	assert.Equal(t, "jti-123", captured.ID)
+	assert.Equal(t, expiresAt, captured.ExpiresAt)
+}
+
+func TestAuthMiddleware_APIKey_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	apiKeys := new(MockAPIKeyAuthenticator)
+	key := "se_0123456789ab_secret"
+
+	claims := &auth_entity.Claims{
+		UserID:      "user-123",
+		Grants:      []string{"students:read"},
+		AuthMethods: []string{auth_entity.AuthMethodAPIKey},
+	}
+	apiKeys.On("Authenticate", mock.Anything, key).Return(claims, nil)
+
+	var captured *auth_entity.Claims
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT, auth_memory.NewTokenRevocationMemoryStore(), apiKeys), func(c *gin.Context) {
+		captured, _ = Principal(c)
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "ApiKey "+key)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, captured)
+	assert.Equal(t, []string{"students:read"}, captured.Grants)
+	mockJWT.AssertNotCalled(t, "Verify", mock.Anything)
+	apiKeys.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_APIKey_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	apiKeys := new(MockAPIKeyAuthenticator)
+	apiKeys.On("Authenticate", mock.Anything, "bad").Return(nil, auth_entity.ErrInvalidAPIKey)
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(new(MockTokenManager), auth_memory.NewTokenRevocationMemoryStore(), apiKeys), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "ApiKey bad")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "unauthorized")
+}
+
+func TestAuthMiddleware_APIKey_LookupError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	apiKeys := new(MockAPIKeyAuthenticator)
+	apiKeys.On("Authenticate", mock.Anything, "key").Return(nil, errors.New("db down"))
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(new(MockTokenManager), auth_memory.NewTokenRevocationMemoryStore(), apiKeys), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "ApiKey key")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestPrincipal_NotAuthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	principal, ok := Principal(c)
+
+	assert.False(t, ok)
+	assert.Nil(t, principal)
+
+	c.Set(principalKey, "not-a-principal")
+	_, ok = Principal(c)
+	assert.False(t, ok)
+}
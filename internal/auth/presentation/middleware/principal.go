@@ -0,0 +1,29 @@
+package auth_middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+const principalKey = "principal"
+
+// SetPrincipal stores the authenticated claims on the gin context and on the
+// request's context.Context.
+func SetPrincipal(c *gin.Context, claims *auth_entity.Claims) {
+	c.Set(principalKey, claims)
+	if c.Request != nil {
+		c.Request = c.Request.WithContext(auth_entity.WithPrincipal(c.Request.Context(), claims))
+	}
+}
+
+// Principal returns the claims of the authenticated caller, or false when the
+// route is not behind AuthMiddleware.
+func Principal(c *gin.Context) (*auth_entity.Claims, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return nil, false
+	}
+
+	claims, ok := value.(*auth_entity.Claims)
+	return claims, ok && claims != nil
+}
@@ -1,30 +1,195 @@
 package auth_router
 
 import (
+	"context"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	audit_router "github.com/williamkoller/system-education/internal/audit/presentation/router"
 	auth_usecase "github.com/williamkoller/system-education/internal/auth/application/usecase"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 	infra_cryptography "github.com/williamkoller/system-education/internal/auth/infra/cryptography"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 	auth_handler "github.com/williamkoller/system-education/internal/auth/presentation/handler"
 	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	permission_event "github.com/williamkoller/system-education/internal/permission/domain/event"
 	permission_repository "github.com/williamkoller/system-education/internal/permission/infra/db/repository"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	role_event "github.com/williamkoller/system-education/internal/role/domain/event"
+	role_repository "github.com/williamkoller/system-education/internal/role/infra/db/repository"
+	school_repository "github.com/williamkoller/system-education/internal/school/infra/db/repository"
+	student_repository "github.com/williamkoller/system-education/internal/student/infra/db/repository"
 	user_cryptography "github.com/williamkoller/system-education/internal/user/infra/cryptography"
 	user_repository "github.com/williamkoller/system-education/internal/user/infra/db/repository"
+	infra_email "github.com/williamkoller/system-education/internal/user/infra/email"
+	port_email_notifier "github.com/williamkoller/system-education/internal/user/port/email"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	"github.com/williamkoller/system-education/shared/infra/email"
 	"gorm.io/gorm"
 )
 
-func AuthRouter(r *gin.Engine, db *gorm.DB, secret string, expiresIn time.Duration) {
+func AuthRouter(r *gin.Engine, db *gorm.DB, jwt port_auth_cryptography.TokenManager, keys port_auth_cryptography.KeySetProvider, refreshExpiresIn time.Duration, apiKey string, fromAddress string, resetURL string, resetExpiresIn time.Duration, verifyURL string, verifyExpiresIn time.Duration, requireVerifiedEmail bool, mfaIssuer string, mfaRequiredModules []string, middleware port_permission_middleware.PermissionMiddleware, refresher port_auth_usecase.GrantRefresher) {
 	repository := user_repository.NewUserGormRepository(db)
-	permissionRepo := permission_repository.NewPermissionGormRepository(db)
-	jwt := infra_cryptography.NewJWTTokenManager(secret, expiresIn)
+	grants := NewGrantResolver(db)
+	revocations := auth_repository.NewTokenRevocationGormRepository(db)
 	crypto := user_cryptography.NewBcryptHasher(12)
+	refreshRepo := auth_repository.NewRefreshTokenGormRepository(db)
+	refreshTokens := infra_cryptography.NewSecureOpaqueTokenGenerator(32)
+	attempts := auth_repository.NewLoginAttemptGormRepository(db)
+	mfaRepo := auth_repository.NewMFAGormRepository(db)
+	totp := infra_cryptography.NewTOTPGenerator(mfaIssuer)
+	recoveryCodes := infra_cryptography.NewRecoveryCodeGenerator()
+	mfaPolicy := auth_entity.MFAPolicy{RequiredModules: mfaRequiredModules}
 
-	usecase := auth_usecase.NewAuthUsecase(repository, permissionRepo, jwt, crypto)
+	usecase := auth_usecase.NewAuthUsecase(repository, grants, jwt, crypto, refreshRepo, refreshTokens, refreshExpiresIn, shared_database.NewGormTransactor(db), revocations, attempts, mfaRepo, totp, recoveryCodes, mfaPolicy, requireVerifiedEmail)
 	handler := auth_handler.NewAuthHandler(usecase)
+
+	mfaUsecase := auth_usecase.NewMFAUsecase(repository, mfaRepo, totp, recoveryCodes, revocations)
+	mfaHandler := auth_handler.NewMFAHandler(mfaUsecase)
+
+	client := email.NewResendClient(apiKey, fromAddress)
+	notifier := infra_email.NewResendEmailNotifier(client)
+	resetRepo := auth_repository.NewPasswordResetTokenGormRepository(db)
+	resetTokens := infra_cryptography.NewSecureOpaqueTokenGenerator(32)
+	resetUsecase := auth_usecase.NewPasswordResetUsecase(repository, resetRepo, resetTokens, crypto, notifier, revocations, shared_database.NewGormTransactor(db), audit_router.NewAuditUsecase(db), resetURL, resetExpiresIn)
+	resetHandler := auth_handler.NewPasswordResetHandler(resetUsecase)
+
+	verifyUsecase := NewEmailVerificationUsecase(db, notifier, verifyURL, verifyExpiresIn)
+	verifyHandler := auth_handler.NewEmailVerificationHandler(verifyUsecase)
+
+	schoolRepo := school_repository.NewSchoolGormRepository(db)
+	profileUsecase := auth_usecase.NewProfileUsecase(repository, grants, schoolRepo, shared_database.NewGormTransactor(db), audit_router.NewAuditUsecase(db))
+	profileHandler := auth_handler.NewProfileHandler(profileUsecase)
+
+	apiKeyUsecase := NewAPIKeyAuthenticator(db, mfaRequiredModules)
+	apiKeyHandler := auth_handler.NewAPIKeyHandler(apiKeyUsecase)
+
+	accessCheckUsecase := auth_usecase.NewAccessCheckUsecase(grants, refresher, schoolRepo, student_repository.NewStudentGormRepository(db), mfaPolicy)
+	accessCheckHandler := auth_handler.NewAccessCheckHandler(accessCheckUsecase)
+
+	jwksHandler := auth_handler.NewJWKSHandler(keys)
+	r.GET("/.well-known/jwks.json", jwksHandler.JWKS)
+
 	auth := r.Group("auth")
 	{
 		auth.POST("login", handler.Login)
-		auth.POST("profile", auth_middleware.AuthMiddleware(jwt), handler.Profile)
+		auth.POST("mfa/verify", handler.VerifyMFA)
+		auth.POST("refresh", handler.Refresh)
+		auth.POST("logout", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), handler.Logout)
+		auth.POST("password/forgot", resetHandler.Forgot)
+		auth.POST("password/reset", resetHandler.Reset)
+		auth.GET("verify-email", verifyHandler.Verify)
+		auth.POST("verify-email/resend", verifyHandler.Resend)
+		auth.POST("users/:id/unlock",
+			auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase),
+			middleware.ModuleAccessMiddleware([]string{"users"}, []string{"update"}),
+			handler.Unlock,
+		)
+		auth.POST("mfa/enroll", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), mfaHandler.Enroll)
+		auth.POST("mfa/confirm", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), mfaHandler.Confirm)
+		auth.POST("mfa/disable", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), mfaHandler.Disable)
+		auth.POST("mfa/recovery-codes", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), mfaHandler.RegenerateRecoveryCodes)
+		auth.GET("me", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), profileHandler.Me)
+		auth.PATCH("me", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), profileHandler.UpdateMe)
+		auth.POST("api-keys", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), apiKeyHandler.Create)
+		auth.GET("api-keys", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), apiKeyHandler.List)
+		auth.DELETE("api-keys/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), apiKeyHandler.Revoke)
 	}
+
+	r.POST("/authz/check", auth_middleware.AuthMiddleware(jwt, revocations, apiKeyUsecase), accessCheckHandler.Check)
+}
+
+// NewGrantResolver merges direct permissions with role grants for every
+// place that turns a user into modules and actions.
+func NewGrantResolver(db *gorm.DB) *auth_usecase.GrantResolver {
+	return auth_usecase.NewGrantResolver(
+		permission_repository.NewPermissionGormRepository(db),
+		role_repository.NewRoleGormRepository(db),
+	)
+}
+
+// NewGrantRefresher is what lets route checks see permission changes before
+// the token expires: in live mode grants are re-read on each request through
+// a cache the permission and role events invalidate as the outbox relay
+// delivers them. Each event reaches the relay of a single instance, so the
+// other instances keep serving what they cached for up to cacheTTL. It
+// returns nil in token mode.
+func NewGrantRefresher(db *gorm.DB, events shared_event.Subscriber, live bool, cacheTTL time.Duration, mfaRequiredModules []string) port_auth_usecase.GrantRefresher {
+	if !live {
+		return nil
+	}
+
+	cache := auth_usecase.NewCachedGrantResolver(NewGrantResolver(db), cacheTTL)
+	invalidate := func(_ context.Context, e shared_event.Event) error {
+		switch evt := e.(type) {
+		case *permission_event.PermissionCreatedEvent:
+			cache.Invalidate(evt.UserID)
+		case *permission_event.PermissionUpdatedEvent:
+			cache.Invalidate(evt.UserID)
+		case *permission_event.PermissionDeletedEvent:
+			cache.Invalidate(evt.UserID)
+		case *role_event.RoleAssignedEvent:
+			cache.Invalidate(evt.UserID)
+		case *role_event.RoleUnassignedEvent:
+			cache.Invalidate(evt.UserID)
+		case *role_event.RoleUpdatedEvent, *role_event.RoleDeletedEvent:
+			// Every holder of the role is affected and the event does not
+			// list them.
+			cache.InvalidateAll()
+		}
+		return nil
+	}
+	events.Subscribe("grant-cache", &permission_event.PermissionCreatedEvent{}, invalidate)
+	events.Subscribe("grant-cache", &permission_event.PermissionUpdatedEvent{}, invalidate)
+	events.Subscribe("grant-cache", &permission_event.PermissionDeletedEvent{}, invalidate)
+	// A new role has no holders yet, so role.created changes nobody's grants.
+	events.Subscribe("grant-cache", &role_event.RoleUpdatedEvent{}, invalidate)
+	events.Subscribe("grant-cache", &role_event.RoleDeletedEvent{}, invalidate)
+	events.Subscribe("grant-cache", &role_event.RoleAssignedEvent{}, invalidate)
+	events.Subscribe("grant-cache", &role_event.RoleUnassignedEvent{}, invalidate)
+
+	return auth_usecase.NewGrantRefresher(cache, auth_entity.MFAPolicy{RequiredModules: mfaRequiredModules})
+}
+
+// NewAPIKeyAuthenticator builds the usecase behind "Authorization: ApiKey"
+// so every router authenticates keys the same way.
+func NewAPIKeyAuthenticator(db *gorm.DB, mfaRequiredModules []string) *auth_usecase.APIKeyUsecase {
+	return auth_usecase.NewAPIKeyUsecase(
+		user_repository.NewUserGormRepository(db),
+		NewGrantResolver(db),
+		auth_repository.NewAPIKeyGormRepository(db),
+		infra_cryptography.NewSecureAPIKeyGenerator(),
+		auth_entity.MFAPolicy{RequiredModules: mfaRequiredModules},
+	)
+}
+
+// NewEmailVerificationUsecase is shared with the user router, which mails the
+// first link when an account is created.
+func NewEmailVerificationUsecase(db *gorm.DB, notifier port_email_notifier.EmailNotifier, verifyURL string, expiresIn time.Duration) *auth_usecase.EmailVerificationUsecase {
+	return auth_usecase.NewEmailVerificationUsecase(
+		user_repository.NewUserGormRepository(db),
+		auth_repository.NewEmailVerificationTokenGormRepository(db),
+		infra_cryptography.NewSecureOpaqueTokenGenerator(32),
+		notifier,
+		verifyURL,
+		expiresIn,
+	)
+}
+
+// NewTokenRevocationPurger is used by main to drop expired revocations.
+func NewTokenRevocationPurger(db *gorm.DB) port_auth_repository.TokenRevocationPurger {
+	return auth_repository.NewTokenRevocationGormRepository(db)
+}
+
+func NewBootstrapUsecase(db *gorm.DB) *auth_usecase.BootstrapUsecase {
+	return auth_usecase.NewBootstrapUsecase(
+		user_repository.NewUserGormRepository(db),
+		permission_repository.NewPermissionGormRepository(db),
+		role_repository.NewRoleGormRepository(db),
+		user_cryptography.NewBcryptHasher(12),
+	)
 }
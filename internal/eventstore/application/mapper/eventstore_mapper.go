@@ -0,0 +1,52 @@
+package eventstore_mapper
+
+import (
+	"encoding/json"
+	"time"
+
+	eventstore_dtos "github.com/williamkoller/system-education/internal/eventstore/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type EventResponse struct {
+	Position      int64           `json:"position"`
+	ID            string          `json:"id"`
+	Event         string          `json:"event"`
+	AggregateID   string          `json:"aggregateId,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredOn    time.Time       `json:"occurredOn"`
+	SchemaVersion int             `json:"schemaVersion"`
+	RecordedAt    time.Time       `json:"recordedAt"`
+}
+
+func ToEventResponse(e *shared_event.StoredEvent) *EventResponse {
+	return &EventResponse{
+		Position:      e.Position,
+		ID:            e.ID,
+		Event:         e.EventName,
+		AggregateID:   e.AggregateID,
+		Payload:       json.RawMessage(e.Payload),
+		OccurredOn:    e.OccurredOn,
+		SchemaVersion: e.SchemaVersion,
+		RecordedAt:    e.RecordedAt,
+	}
+}
+
+func ToEventResponses(es []*shared_event.StoredEvent) []*EventResponse {
+	responses := make([]*EventResponse, 0, len(es))
+	for _, e := range es {
+		responses = append(responses, ToEventResponse(e))
+	}
+	return responses
+}
+
+func ToFilter(input eventstore_dtos.FindEventsDto) shared_event.EventFilter {
+	return shared_event.EventFilter{
+		AggregateID:   input.AggregateID,
+		EventName:     input.Event,
+		From:          input.From,
+		To:            input.To,
+		AfterPosition: input.After,
+		Limit:         input.Limit,
+	}
+}
@@ -0,0 +1,40 @@
+package eventstore_mapper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	eventstore_dtos "github.com/williamkoller/system-education/internal/eventstore/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+func TestToEventResponses(t *testing.T) {
+	now := time.Now()
+	events := []*shared_event.StoredEvent{
+		{Position: 7, ID: "evt-1", EventName: "student.enrolled", AggregateID: "student-1", Payload: `{"StudentID":"student-1"}`, OccurredOn: now, SchemaVersion: 1, RecordedAt: now},
+	}
+
+	responses := ToEventResponses(events)
+
+	assert.Len(t, responses, 1)
+	assert.Equal(t, &EventResponse{
+		Position:      7,
+		ID:            "evt-1",
+		Event:         "student.enrolled",
+		AggregateID:   "student-1",
+		Payload:       json.RawMessage(`{"StudentID":"student-1"}`),
+		OccurredOn:    now,
+		SchemaVersion: 1,
+		RecordedAt:    now,
+	}, responses[0])
+}
+
+func TestToFilter(t *testing.T) {
+	from := time.Now()
+
+	filter := ToFilter(eventstore_dtos.FindEventsDto{AggregateID: "student-1", Event: "student.updated", From: &from, After: 40, Limit: 10})
+
+	assert.Equal(t, shared_event.EventFilter{AggregateID: "student-1", EventName: "student.updated", From: &from, AfterPosition: 40, Limit: 10}, filter)
+}
@@ -0,0 +1,76 @@
+package eventstore_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+
+	port_eventstore_usecase "github.com/williamkoller/system-education/internal/eventstore/port/usecase"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+const replayBatchSize = 500
+
+type EventStoreUsecase struct {
+	store shared_event.EventStore
+}
+
+func NewEventStoreUsecase(store shared_event.EventStore) *EventStoreUsecase {
+	return &EventStoreUsecase{store: store}
+}
+
+var _ port_eventstore_usecase.EventStoreUsecase = &EventStoreUsecase{}
+
+func (u *EventStoreUsecase) Find(ctx context.Context, filter shared_event.EventFilter) ([]*shared_event.StoredEvent, error) {
+	return u.store.Find(ctx, filter.Normalized())
+}
+
+// Replay hands every matching event, in the order they were stored, to
+// handler and returns how many it replayed. filter.Limit is ignored. Events
+// are decoded into the prototype of the same name; those without one are
+// skipped. The first failure stops the replay and says which position to
+// resume after.
+func (u *EventStoreUsecase) Replay(ctx context.Context, filter shared_event.EventFilter, prototypes []shared_event.Event, handler shared_event.DeliveryHandler) (int, error) {
+	types := make(map[string]reflect.Type, len(prototypes))
+	for _, prototype := range prototypes {
+		types[prototype.EventName()] = reflect.TypeOf(prototype).Elem()
+	}
+
+	filter.Limit = replayBatchSize
+	replayed := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return replayed, err
+		}
+
+		batch, err := u.store.Find(ctx, filter)
+		if err != nil {
+			return replayed, fmt.Errorf("failed to read events after position %d: %w", filter.AfterPosition, err)
+		}
+
+		for _, stored := range batch {
+			eventType, ok := types[stored.EventName]
+			if !ok {
+				log.Printf("event replay: skipping %s %s, no handler for it", stored.EventName, stored.ID)
+				filter.AfterPosition = stored.Position
+				continue
+			}
+
+			event := reflect.New(eventType).Interface().(shared_event.Event)
+			if err := json.Unmarshal([]byte(stored.Payload), event); err != nil {
+				return replayed, fmt.Errorf("failed to decode %s at position %d, resume after %d: %w", stored.EventName, stored.Position, filter.AfterPosition, err)
+			}
+			if err := handler(shared_event.WithEventID(ctx, stored.ID), event); err != nil {
+				return replayed, fmt.Errorf("replay stopped at %s %s (position %d), resume after %d: %w", stored.EventName, stored.ID, stored.Position, filter.AfterPosition, err)
+			}
+			replayed++
+			filter.AfterPosition = stored.Position
+		}
+
+		if len(batch) < filter.Limit {
+			return replayed, nil
+		}
+	}
+}
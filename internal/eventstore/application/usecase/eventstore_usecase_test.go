@@ -0,0 +1,134 @@
+package eventstore_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type MockEventStore struct {
+	mock.Mock
+}
+
+func (m *MockEventStore) Append(ctx context.Context, events ...*shared_event.StoredEvent) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+func (m *MockEventStore) Find(ctx context.Context, filter shared_event.EventFilter) ([]*shared_event.StoredEvent, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*shared_event.StoredEvent), args.Error(1)
+}
+
+type schoolCreated struct {
+	SchoolID string
+	Date     time.Time
+}
+
+func (e *schoolCreated) EventName() string     { return "school.created" }
+func (e *schoolCreated) OccurredOn() time.Time { return e.Date }
+
+func stored(position int64, name, payload string) *shared_event.StoredEvent {
+	return &shared_event.StoredEvent{
+		Position:  position,
+		ID:        fmt.Sprintf("evt-%d", position),
+		EventName: name,
+		Payload:   payload,
+	}
+}
+
+func afterPosition(position int64) interface{} {
+	return mock.MatchedBy(func(f shared_event.EventFilter) bool {
+		return f.AfterPosition == position && f.Limit == replayBatchSize
+	})
+}
+
+func TestEventStoreUsecase_Find(t *testing.T) {
+	store := new(MockEventStore)
+	usecase := NewEventStoreUsecase(store)
+
+	store.On("Find", mock.Anything, shared_event.EventFilter{AggregateID: "school-1", Limit: shared_event.MaxStoreLimit}).
+		Return([]*shared_event.StoredEvent{stored(1, "school.created", `{}`)}, nil)
+
+	events, err := usecase.Find(context.Background(), shared_event.EventFilter{AggregateID: "school-1", Limit: 5000})
+
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestEventStoreUsecase_Replay(t *testing.T) {
+	prototypes := []shared_event.Event{&schoolCreated{}}
+
+	t.Run("decodes the stream and passes each event's id", func(t *testing.T) {
+		store := new(MockEventStore)
+		usecase := NewEventStoreUsecase(store)
+		store.On("Find", mock.Anything, afterPosition(0)).Return([]*shared_event.StoredEvent{
+			stored(1, "school.created", `{"SchoolID": "school-1"}`),
+			stored(2, "user.created", `{"UserID": "user-1"}`),
+			stored(3, "school.created", `{"SchoolID": "school-2"}`),
+		}, nil)
+
+		var schools, ids []string
+		replayed, err := usecase.Replay(context.Background(), shared_event.EventFilter{}, prototypes, func(ctx context.Context, event shared_event.Event) error {
+			schools = append(schools, event.(*schoolCreated).SchoolID)
+			ids = append(ids, shared_event.EventIDFrom(ctx))
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, replayed)
+		assert.Equal(t, []string{"school-1", "school-2"}, schools)
+		assert.Equal(t, []string{"evt-1", "evt-3"}, ids)
+	})
+
+	t.Run("reads past the first batch", func(t *testing.T) {
+		store := new(MockEventStore)
+		usecase := NewEventStoreUsecase(store)
+		full := make([]*shared_event.StoredEvent, 0, replayBatchSize)
+		for i := range replayBatchSize {
+			full = append(full, stored(int64(i+1), "school.created", `{}`))
+		}
+		store.On("Find", mock.Anything, afterPosition(0)).Return(full, nil).Once()
+		store.On("Find", mock.Anything, afterPosition(replayBatchSize)).Return([]*shared_event.StoredEvent{}, nil).Once()
+
+		replayed, err := usecase.Replay(context.Background(), shared_event.EventFilter{}, prototypes, func(context.Context, shared_event.Event) error {
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, replayBatchSize, replayed)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("stops at the first handler error", func(t *testing.T) {
+		store := new(MockEventStore)
+		usecase := NewEventStoreUsecase(store)
+		store.On("Find", mock.Anything, afterPosition(4)).Return([]*shared_event.StoredEvent{
+			stored(5, "school.created", `{"SchoolID": "school-1"}`),
+			stored(6, "school.created", `{"SchoolID": "school-2"}`),
+		}, nil)
+
+		calls := 0
+		replayed, err := usecase.Replay(context.Background(), shared_event.EventFilter{AfterPosition: 4}, prototypes, func(_ context.Context, event shared_event.Event) error {
+			calls++
+			if event.(*schoolCreated).SchoolID == "school-2" {
+				return errors.New("lms unavailable")
+			}
+			return nil
+		})
+
+		assert.Equal(t, 1, replayed)
+		assert.Equal(t, 2, calls)
+		assert.EqualError(t, err, "replay stopped at school.created evt-6 (position 6), resume after 5: lms unavailable")
+	})
+}
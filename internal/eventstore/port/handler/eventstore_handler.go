@@ -0,0 +1,7 @@
+package port_eventstore_handler
+
+import "github.com/gin-gonic/gin"
+
+type EventStoreHandler interface {
+	FindEvents(c *gin.Context)
+}
@@ -0,0 +1,12 @@
+package port_eventstore_usecase
+
+import (
+	"context"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type EventStoreUsecase interface {
+	Find(ctx context.Context, filter shared_event.EventFilter) ([]*shared_event.StoredEvent, error)
+	Replay(ctx context.Context, filter shared_event.EventFilter, prototypes []shared_event.Event, handler shared_event.DeliveryHandler) (int, error)
+}
@@ -0,0 +1,15 @@
+package eventstore_dtos
+
+import "time"
+
+// FindEventsDto is bound from the query string of GET /events. from and to
+// are RFC 3339 timestamps; after is the position of the last event already
+// read.
+type FindEventsDto struct {
+	AggregateID string     `form:"aggregate_id"`
+	Event       string     `form:"event" example:"student.enrolled"`
+	From        *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To          *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	After       int64      `form:"after" binding:"omitempty,min=0"`
+	Limit       int        `form:"limit" binding:"omitempty,min=1"`
+}
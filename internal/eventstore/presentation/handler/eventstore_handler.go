@@ -0,0 +1,38 @@
+package eventstore_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	eventstore_mapper "github.com/williamkoller/system-education/internal/eventstore/application/mapper"
+	port_eventstore_handler "github.com/williamkoller/system-education/internal/eventstore/port/handler"
+	port_eventstore_usecase "github.com/williamkoller/system-education/internal/eventstore/port/usecase"
+	eventstore_dtos "github.com/williamkoller/system-education/internal/eventstore/presentation/dtos"
+)
+
+type EventStoreHandler struct {
+	usecase port_eventstore_usecase.EventStoreUsecase
+}
+
+var _ port_eventstore_handler.EventStoreHandler = &EventStoreHandler{}
+
+func NewEventStoreHandler(usecase port_eventstore_usecase.EventStoreUsecase) *EventStoreHandler {
+	return &EventStoreHandler{usecase: usecase}
+}
+
+func (h *EventStoreHandler) FindEvents(c *gin.Context) {
+	var input eventstore_dtos.FindEventsDto
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	events, err := h.usecase.Find(c.Request.Context(), eventstore_mapper.ToFilter(input))
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+	c.JSON(http.StatusOK, eventstore_mapper.ToEventResponses(events))
+}
@@ -0,0 +1,26 @@
+package eventstore_router
+
+import (
+	"github.com/gin-gonic/gin"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	eventstore_usecase "github.com/williamkoller/system-education/internal/eventstore/application/usecase"
+	eventstore_handler "github.com/williamkoller/system-education/internal/eventstore/presentation/handler"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	shared_eventstore "github.com/williamkoller/system-education/shared/infra/eventstore"
+	"gorm.io/gorm"
+)
+
+func EventStoreRouter(g *gin.Engine, db *gorm.DB, jwt port_auth_cryptography.TokenManager, apiKeys port_auth_usecase.APIKeyAuthenticator, middleware port_permission_middleware.PermissionMiddleware) {
+	handler := eventstore_handler.NewEventStoreHandler(NewEventStoreUsecase(db))
+	revocations := auth_repository.NewTokenRevocationGormRepository(db)
+
+	g.GET("/events", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"events"}, []string{"read"}), handler.FindEvents)
+}
+
+// NewEventStoreUsecase is shared with the replay command.
+func NewEventStoreUsecase(db *gorm.DB) *eventstore_usecase.EventStoreUsecase {
+	return eventstore_usecase.NewEventStoreUsecase(shared_eventstore.NewGormEventStore(db))
+}
@@ -9,8 +9,8 @@ import (
 type PermissionResponse struct {
 	ID          string    `json:"id"`
 	UserID      string    `json:"userId"`
-	Modules     []string  `json:"modules"`
-	Actions     []string  `json:"actions"`
+	Grants      []string  `json:"grants"`
+	SchoolIDs   []string  `json:"schoolIds"`
 	Level       string    `json:"level"`
 	Description string    `json:"description"`
 	CreatedAt   time.Time `json:"createdAt"`
@@ -21,8 +21,8 @@ func ToPermission(p *permission_entity.Permission) *PermissionResponse {
 	return &PermissionResponse{
 		ID:          p.ID,
 		UserID:      p.UserID,
-		Modules:     p.Modules,
-		Actions:     p.Actions,
+		Grants:      p.Grants,
+		SchoolIDs:   p.SchoolIDs,
 		Level:       p.Level,
 		Description: p.Description,
 		CreatedAt:   p.CreatedAt,
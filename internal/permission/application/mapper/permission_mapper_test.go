@@ -13,8 +13,7 @@ func TestToPermission(t *testing.T) {
 	permission := &permission_entity.Permission{
 		ID:          "123",
 		UserID:      "user-1",
-		Modules:     []string{"module1"},
-		Actions:     []string{"read"},
+		Grants:      []string{"module1:read"},
 		Level:       "admin",
 		Description: "test description",
 		CreatedAt:   now,
@@ -25,8 +24,7 @@ func TestToPermission(t *testing.T) {
 
 	assert.Equal(t, permission.ID, response.ID)
 	assert.Equal(t, permission.UserID, response.UserID)
-	assert.Equal(t, permission.Modules, response.Modules)
-	assert.Equal(t, permission.Actions, response.Actions)
+	assert.Equal(t, permission.Grants, response.Grants)
 	assert.Equal(t, permission.Level, response.Level)
 	assert.Equal(t, permission.Description, response.Description)
 	assert.Equal(t, permission.CreatedAt, response.CreatedAt)
@@ -39,8 +37,7 @@ func TestToPermissions(t *testing.T) {
 		{
 			ID:          "123",
 			UserID:      "user-1",
-			Modules:     []string{"module1"},
-			Actions:     []string{"read"},
+			Grants:      []string{"module1:read"},
 			Level:       "admin",
 			Description: "test description 1",
 			CreatedAt:   now,
@@ -49,9 +46,8 @@ func TestToPermissions(t *testing.T) {
 		{
 			ID:          "456",
 			UserID:      "user-2",
-			Modules:     []string{"module2"},
-			Actions:     []string{"write"},
-			Level:       "user",
+			Grants:      []string{"module2:write"},
+			Level:       "viewer",
 			Description: "test description 2",
 			CreatedAt:   now,
 			UpdatedAt:   now,
@@ -5,30 +5,43 @@ import (
     "fmt"
 
     "github.com/google/uuid"
+    audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+    port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+    auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
     permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
     port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
     port_permission_usecase "github.com/williamkoller/system-education/internal/permission/port/usecase"
     permission_dtos "github.com/williamkoller/system-education/internal/permission/presentation/dtos"
+    shared_event "github.com/williamkoller/system-education/shared/domain/event"
+    port_transaction "github.com/williamkoller/system-education/shared/port/transaction"
 )
 
+const auditEntityType = "permission"
+
 type PermissionUsecase struct {
 	permissionRepository port_permission_repository.PermissionRepository
+	outbox               shared_event.Outbox
+	tx                   port_transaction.Transactor
+	audit                port_audit_usecase.Recorder
 }
 
-func NewPermissionUsecase(permissionRepository port_permission_repository.PermissionRepository) *PermissionUsecase {
+func NewPermissionUsecase(permissionRepository port_permission_repository.PermissionRepository, outbox shared_event.Outbox, tx port_transaction.Transactor, audit port_audit_usecase.Recorder) *PermissionUsecase {
 	return &PermissionUsecase{
 		permissionRepository: permissionRepository,
+		outbox:               outbox,
+		tx:                   tx,
+		audit:                audit,
 	}
 }
 
 var _ port_permission_usecase.PermissionUsecase = &PermissionUsecase{}
 
-func (p *PermissionUsecase) Create(ctx context.Context, input permission_dtos.AddPermissionDto) (*permission_entity.Permission, error) {
+func (p *PermissionUsecase) Create(ctx context.Context, principal *auth_entity.Claims, input permission_dtos.AddPermissionDto) (*permission_entity.Permission, error) {
 	newPermission, err := permission_entity.NewPermission(&permission_entity.Permission{
 		ID:          uuid.New().String(),
 		UserID:      input.UserID,
-		Modules:     input.Modules,
-		Actions:     input.Actions,
+		Grants:      input.Grants,
+		SchoolIDs:   input.SchoolIDs,
 		Level:       input.Level,
 		Description: input.Description,
 	})
@@ -37,9 +50,24 @@ func (p *PermissionUsecase) Create(ctx context.Context, input permission_dtos.Ad
 		return nil, fmt.Errorf("failed to create permission: %w", err)
 	}
 
-    permission, err := p.permissionRepository.Save(ctx, newPermission)
+	if err := authorizeGrant(principal, newPermission); err != nil {
+		return nil, err
+	}
+
+	var permission *permission_entity.Permission
+	err = p.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		saved, err := p.permissionRepository.Save(ctx, newPermission)
+		if err != nil {
+			return fmt.Errorf("failed to save permission: %w", err)
+		}
+		permission = saved
+		if err := p.record(ctx, newPermission); err != nil {
+			return err
+		}
+		return p.audit.Record(ctx, audit_entity.ActionCreate, auditEntityType, saved.ID, nil, audit_entity.Snapshot(saved))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to save permission: %w", err)
+		return nil, err
 	}
 
 	return permission, nil
@@ -61,30 +89,65 @@ func (p *PermissionUsecase) FindById(ctx context.Context, id string) (*permissio
 	return permission, nil
 }
 
-func (p *PermissionUsecase) Update(ctx context.Context, id string, input permission_dtos.UpdatePermissionDto) (*permission_entity.Permission, error) {
+func (p *PermissionUsecase) Update(ctx context.Context, principal *auth_entity.Claims, id string, input permission_dtos.UpdatePermissionDto) (*permission_entity.Permission, error) {
     permission, err := p.permissionRepository.FindByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find permission by id: %w", err)
 	}
 
-	permission, err = permission.UpdatePermission(input.Modules, input.Actions, input.Level, input.Description)
+	if err := authorizeGrant(principal, permission); err != nil {
+		return nil, err
+	}
+	before := audit_entity.Snapshot(permission)
+
+	permission, err = permission.UpdatePermission(input.Grants, input.SchoolIDs, input.Level, input.Description)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update permission: %w", err)
 	}
 
-    permission, err = p.permissionRepository.Update(ctx, id, permission)
+	if err := authorizeGrant(principal, permission); err != nil {
+		return nil, err
+	}
+
+	var updated *permission_entity.Permission
+	err = p.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		saved, err := p.permissionRepository.Update(ctx, id, permission)
+		if err != nil {
+			return fmt.Errorf("failed to update permission: %w", err)
+		}
+		updated = saved
+		if err := p.record(ctx, permission); err != nil {
+			return err
+		}
+		return p.audit.Record(ctx, audit_entity.ActionUpdate, auditEntityType, id, before, audit_entity.Snapshot(saved))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update permission: %w", err)
+		return nil, err
 	}
-	return permission, nil
+
+	return updated, nil
 }
 
-func (p *PermissionUsecase) Delete(ctx context.Context, id string) error {
-    user, err := p.permissionRepository.FindByID(ctx, id)
+func (p *PermissionUsecase) Delete(ctx context.Context, principal *auth_entity.Claims, id string) error {
+    permission, err := p.permissionRepository.FindByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to find permission by id: %w", err)
 	}
-    return p.permissionRepository.Delete(ctx, user.ID)
+
+	if err := authorizeGrant(principal, permission); err != nil {
+		return err
+	}
+
+	permission.Delete()
+	return p.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := p.permissionRepository.Delete(ctx, permission.ID); err != nil {
+			return err
+		}
+		if err := p.record(ctx, permission); err != nil {
+			return err
+		}
+		return p.audit.Record(ctx, audit_entity.ActionDelete, auditEntityType, permission.ID, audit_entity.Snapshot(permission), nil)
+	})
 }
 
 func (p *PermissionUsecase) FindPermissionByUserID(ctx context.Context, userID string) ([]*permission_entity.Permission, error) {
@@ -94,3 +157,26 @@ func (p *PermissionUsecase) FindPermissionByUserID(ctx context.Context, userID s
 	}
 	return permissions, nil
 }
+
+// record adds the permission's pending events to the outbox, inside the
+// transaction that persists it.
+func (p *PermissionUsecase) record(ctx context.Context, permission *permission_entity.Permission) error {
+	if err := p.outbox.Add(ctx, permission.PullDomainEvents()...); err != nil {
+		return fmt.Errorf("failed to record permission events: %w", err)
+	}
+	return nil
+}
+
+// authorizeGrant requires the principal to hold the permission's level and
+// each of its grants, in every school it is scoped to.
+func authorizeGrant(principal *auth_entity.Claims, permission *permission_entity.Permission) error {
+	held := principal.Scoped()
+	for _, grant := range permission.Grants {
+		module := permission_entity.GrantModule(grant)
+		if !held.Level(module, permission.SchoolIDs).AtLeast(permission_entity.Level(permission.Level)) ||
+			!held.CoversIn(grant, permission.SchoolIDs) {
+			return permission_entity.ErrLevelAboveGrantor
+		}
+	}
+	return nil
+}
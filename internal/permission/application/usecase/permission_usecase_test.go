@@ -7,8 +7,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	permission_event "github.com/williamkoller/system-education/internal/permission/domain/event"
 	permission_dtos "github.com/williamkoller/system-education/internal/permission/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
 )
 
 type MockPermissionRepository struct {
@@ -60,15 +64,63 @@ func (m *MockPermissionRepository) FindByID(ctx context.Context, id string) (*pe
 	return args.Get(0).(*permission_entity.Permission), args.Error(1)
 }
 
+type MockOutbox struct {
+	mock.Mock
+}
+
+func (m *MockOutbox) Add(ctx context.Context, events ...shared_event.Event) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+type stubTransactor struct{}
+
+func (stubTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditRecorder struct {
+	mock.Mock
+}
+
+func (m *MockAuditRecorder) Record(ctx context.Context, action audit_entity.Action, entityType, entityID string, before, after map[string]any) error {
+	args := m.Called(ctx, action, entityType, entityID, before, after)
+	return args.Error(0)
+}
+
+func ignoreAudit() *MockAuditRecorder {
+	audit := new(MockAuditRecorder)
+	audit.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return audit
+}
+
+func ignoreEvents() *MockOutbox {
+	events := new(MockOutbox)
+	events.On("Add", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return events
+}
+
+// added matches a single event of the given type that satisfies match.
+func added[E shared_event.Event](match func(E) bool) interface{} {
+	return mock.MatchedBy(func(events []shared_event.Event) bool {
+		if len(events) != 1 {
+			return false
+		}
+		e, ok := events[0].(E)
+		return ok && match(e)
+	})
+}
+
+var admin = &auth_entity.Claims{UserID: "admin-1", Grants: []string{"module1:*", "module2:*"}}
+
 func TestPermissionUsecase_Create(t *testing.T) {
 	t.Run("should create permission successfully", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		input := permission_dtos.AddPermissionDto{
 			UserID:      "user-1",
-			Modules:     []string{"module1"},
-			Actions:     []string{"read"},
+			Grants:      []string{"module1:read"},
 			Level:       "admin",
 			Description: "test permission",
 		}
@@ -76,13 +128,12 @@ func TestPermissionUsecase_Create(t *testing.T) {
 		mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*permission_entity.Permission")).Return(&permission_entity.Permission{
 			ID:          "123",
 			UserID:      input.UserID,
-			Modules:     input.Modules,
-			Actions:     input.Actions,
+			Grants:      input.Grants,
 			Level:       input.Level,
 			Description: input.Description,
 		}, nil)
 
-		permission, err := usecase.Create(context.Background(), input)
+		permission, err := usecase.Create(context.Background(), admin, input)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, permission)
@@ -92,13 +143,13 @@ func TestPermissionUsecase_Create(t *testing.T) {
 
 	t.Run("should return error when validation fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		input := permission_dtos.AddPermissionDto{
 			UserID: "", // Invalid
 		}
 
-		permission, err := usecase.Create(context.Background(), input)
+		permission, err := usecase.Create(context.Background(), admin, input)
 
 		assert.Error(t, err)
 		assert.Nil(t, permission)
@@ -107,30 +158,85 @@ func TestPermissionUsecase_Create(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		input := permission_dtos.AddPermissionDto{
 			UserID:      "user-1",
-			Modules:     []string{"module1"},
-			Actions:     []string{"read"},
+			Grants:      []string{"module1:read"},
 			Level:       "admin",
 			Description: "test permission",
 		}
 
 		mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*permission_entity.Permission")).Return(nil, errors.New("db error"))
 
-		permission, err := usecase.Create(context.Background(), input)
+		permission, err := usecase.Create(context.Background(), admin, input)
 
 		assert.Error(t, err)
 		assert.Nil(t, permission)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("should reject a level above the grantor's own", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		editor := &auth_entity.Claims{UserID: "editor-1", Grants: []string{"module1:read", "module1:create", "module1:update"}}
+		input := permission_dtos.AddPermissionDto{
+			UserID: "user-1",
+			Grants: []string{"module1:read"},
+			Level:  "manager",
+		}
+
+		permission, err := usecase.Create(context.Background(), editor, input)
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		assert.Nil(t, permission)
+		mockRepo.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("should reject a grant the grantor does not hold", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		viewer := &auth_entity.Claims{UserID: "viewer-1", Grants: []string{"module1:read"}}
+		input := permission_dtos.AddPermissionDto{
+			UserID: "user-1",
+			Grants: []string{"module1:delete"},
+			Level:  "viewer",
+		}
+
+		_, err := usecase.Create(context.Background(), viewer, input)
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		mockRepo.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("should reject a network-wide grant from a school-scoped grantor", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		principal := &auth_entity.Claims{UserID: "admin-2", SchoolGrants: map[string][]string{"school-1": {"module1:*"}}}
+		input := permission_dtos.AddPermissionDto{
+			UserID: "user-1",
+			Grants: []string{"module1:read"},
+			Level:  "viewer",
+		}
+
+		_, err := usecase.Create(context.Background(), principal, input)
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+
+		input.SchoolIDs = []string{"school-1"}
+		mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*permission_entity.Permission")).Return(&permission_entity.Permission{ID: "123"}, nil)
+
+		_, err = usecase.Create(context.Background(), principal, input)
+		assert.NoError(t, err)
+	})
 }
 
 func TestPermissionUsecase_FindAll(t *testing.T) {
 	t.Run("should return all permissions", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		expectedPermissions := []*permission_entity.Permission{
 			{ID: "1", UserID: "user-1"},
@@ -148,7 +254,7 @@ func TestPermissionUsecase_FindAll(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		mockRepo.On("FindAll", mock.Anything).Return(nil, errors.New("db error"))
 
@@ -163,7 +269,7 @@ func TestPermissionUsecase_FindAll(t *testing.T) {
 func TestPermissionUsecase_FindById(t *testing.T) {
 	t.Run("should return permission by id", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		expectedPermission := &permission_entity.Permission{ID: "123", UserID: "user-1"}
 
@@ -178,7 +284,7 @@ func TestPermissionUsecase_FindById(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		mockRepo.On("FindByID", mock.Anything, "123").Return(nil, errors.New("db error"))
 
@@ -193,17 +299,15 @@ func TestPermissionUsecase_FindById(t *testing.T) {
 func TestPermissionUsecase_Update(t *testing.T) {
 	t.Run("should update permission successfully", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
-		modules := []string{"module2"}
-		actions := []string{"write"}
-		level := "user"
+		grants := []string{"module2:write"}
+		level := "viewer"
 		description := "updated permission"
 
 		input := permission_dtos.UpdatePermissionDto{
-			Modules:     &modules,
-			Actions:     &actions,
+			Grants:      &grants,
 			Level:       &level,
 			Description: &description,
 		}
@@ -211,8 +315,7 @@ func TestPermissionUsecase_Update(t *testing.T) {
 		existingPermission := &permission_entity.Permission{
 			ID:          id,
 			UserID:      "user-1",
-			Modules:     []string{"module1"},
-			Actions:     []string{"read"},
+			Grants:      []string{"module1:read"},
 			Level:       "admin",
 			Description: "test permission",
 		}
@@ -221,8 +324,7 @@ func TestPermissionUsecase_Update(t *testing.T) {
 		updatedPermission := &permission_entity.Permission{
 			ID:          id,
 			UserID:      "user-1",
-			Modules:     *input.Modules,
-			Actions:     *input.Actions,
+			Grants:      *input.Grants,
 			Level:       *input.Level,
 			Description: *input.Description,
 		}
@@ -232,7 +334,7 @@ func TestPermissionUsecase_Update(t *testing.T) {
 		// but we can match the content or just use mock.AnythingOfType
 		mockRepo.On("Update", mock.Anything, id, mock.AnythingOfType("*permission_entity.Permission")).Return(updatedPermission, nil)
 
-		permission, err := usecase.Update(context.Background(), id, input)
+		permission, err := usecase.Update(context.Background(), admin, id, input)
 
 		assert.NoError(t, err)
 		assert.Equal(t, updatedPermission, permission)
@@ -241,14 +343,14 @@ func TestPermissionUsecase_Update(t *testing.T) {
 
 	t.Run("should return error when find by id fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
 		input := permission_dtos.UpdatePermissionDto{}
 
 		mockRepo.On("FindByID", mock.Anything, id).Return(nil, permission_entity.ErrNotFound)
 
-		permission, err := usecase.Update(context.Background(), id, input)
+		permission, err := usecase.Update(context.Background(), admin, id, input)
 
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, permission_entity.ErrNotFound)
@@ -258,24 +360,24 @@ func TestPermissionUsecase_Update(t *testing.T) {
 
 	t.Run("should return error when update fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
-		modules := []string{"module2"}
+		grants := []string{"module2:read"}
 		input := permission_dtos.UpdatePermissionDto{
-			Modules: &modules,
+			Grants: &grants,
 		}
 
 		existingPermission := &permission_entity.Permission{
-			ID:      id,
-			Modules: []string{"module1"},
-			Level:   "user",
+			ID:     id,
+			Grants: []string{"module1:read"},
+			Level:  "viewer",
 		}
 
 		mockRepo.On("FindByID", mock.Anything, id).Return(existingPermission, nil)
 		mockRepo.On("Update", mock.Anything, id, mock.AnythingOfType("*permission_entity.Permission")).Return(nil, errors.New("db error"))
 
-		permission, err := usecase.Update(context.Background(), id, input)
+		permission, err := usecase.Update(context.Background(), admin, id, input)
 
 		assert.Error(t, err)
 		assert.Nil(t, permission)
@@ -284,7 +386,7 @@ func TestPermissionUsecase_Update(t *testing.T) {
 
 	t.Run("should return error when validation fails during update", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
 		level := ""
@@ -299,19 +401,45 @@ func TestPermissionUsecase_Update(t *testing.T) {
 
 		mockRepo.On("FindByID", mock.Anything, id).Return(existingPermission, nil)
 
-		permission, err := usecase.Update(context.Background(), id, input)
+		permission, err := usecase.Update(context.Background(), admin, id, input)
 
 		assert.Error(t, err)
 		assert.Nil(t, permission)
 		assert.Contains(t, err.Error(), "level cannot be empty")
 		mockRepo.AssertNotCalled(t, "Update")
 	})
+
+	t.Run("should not let a grantor modify a permission above their level", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		id := "123"
+		level := "viewer"
+		input := permission_dtos.UpdatePermissionDto{
+			Level: &level,
+		}
+
+		existingPermission := &permission_entity.Permission{
+			ID:     id,
+			Grants: []string{"module1:*"},
+			Level:  "admin",
+		}
+
+		mockRepo.On("FindByID", mock.Anything, id).Return(existingPermission, nil)
+
+		editor := &auth_entity.Claims{UserID: "editor-1", Grants: []string{"module1:read", "module1:create", "module1:update"}}
+		permission, err := usecase.Update(context.Background(), editor, id, input)
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		assert.Nil(t, permission)
+		mockRepo.AssertNotCalled(t, "Update")
+	})
 }
 
 func TestPermissionUsecase_Delete(t *testing.T) {
 	t.Run("should delete permission successfully", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
 
@@ -319,7 +447,7 @@ func TestPermissionUsecase_Delete(t *testing.T) {
 		mockRepo.On("FindByID", mock.Anything, id).Return(&permission_entity.Permission{ID: id}, nil)
 		mockRepo.On("Delete", mock.Anything, id).Return(nil)
 
-		err := usecase.Delete(context.Background(), id)
+		err := usecase.Delete(context.Background(), admin, id)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
@@ -327,13 +455,13 @@ func TestPermissionUsecase_Delete(t *testing.T) {
 
 	t.Run("should return error when find by id fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
 
 		mockRepo.On("FindByID", mock.Anything, id).Return(nil, permission_entity.ErrNotFound)
 
-		err := usecase.Delete(context.Background(), id)
+		err := usecase.Delete(context.Background(), admin, id)
 
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, permission_entity.ErrNotFound)
@@ -342,24 +470,52 @@ func TestPermissionUsecase_Delete(t *testing.T) {
 
 	t.Run("should return error when delete fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
 
 		mockRepo.On("FindByID", mock.Anything, id).Return(&permission_entity.Permission{ID: id}, nil)
 		mockRepo.On("Delete", mock.Anything, id).Return(errors.New("db error"))
 
-		err := usecase.Delete(context.Background(), id)
+		err := usecase.Delete(context.Background(), admin, id)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 }
 
+func TestPermissionUsecase_Delete_RequiresGrantor(t *testing.T) {
+	t.Run("should not let a grantor delete a permission above their level", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindByID", mock.Anything, "123").Return(&permission_entity.Permission{ID: "123", Grants: []string{"module1:*"}, Level: "admin"}, nil)
+
+		editor := &auth_entity.Claims{UserID: "editor-1", Grants: []string{"module1:read", "module1:create", "module1:update"}}
+		err := usecase.Delete(context.Background(), editor, "123")
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not let a grantor delete grants they do not hold", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindByID", mock.Anything, "123").Return(&permission_entity.Permission{ID: "123", Grants: []string{"module1:delete"}, Level: "viewer"}, nil)
+
+		viewer := &auth_entity.Claims{UserID: "viewer-1", Grants: []string{"module1:read"}}
+		err := usecase.Delete(context.Background(), viewer, "123")
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
 func TestPermissionUsecase_FindPermissionByUserID(t *testing.T) {
 	t.Run("should return permissions by user id", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		userID := "user-1"
 		expectedPermissions := []*permission_entity.Permission{
@@ -377,7 +533,7 @@ func TestPermissionUsecase_FindPermissionByUserID(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		userID := "user-1"
 
@@ -390,3 +546,135 @@ func TestPermissionUsecase_FindPermissionByUserID(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestPermissionUsecase_AddsChangesToOutbox(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		events := new(MockOutbox)
+		usecase := NewPermissionUsecase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*permission_entity.Permission")).Return(&permission_entity.Permission{ID: "123"}, nil)
+		events.On("Add", mock.Anything, added(func(*permission_event.PermissionCreatedEvent) bool { return true })).Return(nil).Once()
+
+		_, err := usecase.Create(context.Background(), admin, permission_dtos.AddPermissionDto{
+			UserID: "user-1",
+			Grants: []string{"module1:read"},
+			Level:  "viewer",
+		})
+
+		assert.NoError(t, err)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("updated", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		events := new(MockOutbox)
+		usecase := NewPermissionUsecase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		level := "viewer"
+		mockRepo.On("FindByID", mock.Anything, "123").Return(&permission_entity.Permission{ID: "123", UserID: "user-1", Grants: []string{"module1:read"}, Level: "admin"}, nil)
+		mockRepo.On("Update", mock.Anything, "123", mock.AnythingOfType("*permission_entity.Permission")).Return(&permission_entity.Permission{ID: "123"}, nil)
+		events.On("Add", mock.Anything, added(func(e *permission_event.PermissionUpdatedEvent) bool {
+			return e.UserID == "user-1" && e.Level == "viewer"
+		})).Return(nil).Once()
+
+		_, err := usecase.Update(context.Background(), admin, "123", permission_dtos.UpdatePermissionDto{Level: &level})
+
+		assert.NoError(t, err)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		events := new(MockOutbox)
+		usecase := NewPermissionUsecase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindByID", mock.Anything, "123").Return(&permission_entity.Permission{ID: "123", UserID: "user-1"}, nil)
+		mockRepo.On("Delete", mock.Anything, "123").Return(nil)
+		events.On("Add", mock.Anything, added(func(e *permission_event.PermissionDeletedEvent) bool {
+			return e.UserID == "user-1"
+		})).Return(nil).Once()
+
+		assert.NoError(t, usecase.Delete(context.Background(), admin, "123"))
+		events.AssertExpectations(t)
+	})
+
+	t.Run("nothing when the delete fails", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		events := new(MockOutbox)
+		usecase := NewPermissionUsecase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindByID", mock.Anything, "123").Return(&permission_entity.Permission{ID: "123", UserID: "user-1"}, nil)
+		mockRepo.On("Delete", mock.Anything, "123").Return(errors.New("db error"))
+
+		assert.Error(t, usecase.Delete(context.Background(), admin, "123"))
+		events.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
+
+	t.Run("an outbox failure fails the write", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		events := new(MockOutbox)
+		audit := new(MockAuditRecorder)
+		usecase := NewPermissionUsecase(mockRepo, events, stubTransactor{}, audit)
+
+		mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*permission_entity.Permission")).Return(&permission_entity.Permission{ID: "123"}, nil)
+		events.On("Add", mock.Anything, mock.Anything).Return(errors.New("outbox unavailable"))
+
+		_, err := usecase.Create(context.Background(), admin, permission_dtos.AddPermissionDto{
+			UserID: "user-1",
+			Grants: []string{"module1:read"},
+			Level:  "viewer",
+		})
+
+		assert.ErrorContains(t, err, "failed to record permission events")
+		audit.AssertNotCalled(t, "Record")
+	})
+}
+
+func TestPermissionUsecase_RecordsAudit(t *testing.T) {
+	t.Run("updated", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, audit)
+
+		existing := &permission_entity.Permission{ID: "123", UserID: "user-1", Grants: []string{"module1:read"}, Level: "admin"}
+		mockRepo.On("FindByID", mock.Anything, "123").Return(existing, nil)
+		mockRepo.On("Update", mock.Anything, "123", existing).Return(existing, nil)
+
+		var changes audit_entity.Changes
+		audit.On("Record", mock.Anything, audit_entity.ActionUpdate, "permission", "123", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				changes = audit_entity.Diff(args.Get(4).(map[string]any), args.Get(5).(map[string]any))
+			}).Return(nil).Once()
+
+		level := "viewer"
+		_, err := usecase.Update(context.Background(), admin, "123", permission_dtos.UpdatePermissionDto{Level: &level})
+
+		assert.NoError(t, err)
+		assert.Equal(t, audit_entity.Change{From: "admin", To: "viewer"}, changes["Level"])
+		assert.NotContains(t, changes, "Grants")
+	})
+
+	t.Run("failed delete records nothing", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, audit)
+
+		mockRepo.On("FindByID", mock.Anything, "123").Return(&permission_entity.Permission{ID: "123"}, nil)
+		mockRepo.On("Delete", mock.Anything, "123").Return(errors.New("db error"))
+
+		assert.Error(t, usecase.Delete(context.Background(), admin, "123"))
+		audit.AssertNotCalled(t, "Record")
+	})
+	t.Run("an audit failure fails the write", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewPermissionUsecase(mockRepo, ignoreEvents(), stubTransactor{}, audit)
+
+		mockRepo.On("FindByID", mock.Anything, "123").Return(&permission_entity.Permission{ID: "123"}, nil)
+		mockRepo.On("Delete", mock.Anything, "123").Return(nil)
+		audit.On("Record", mock.Anything, audit_entity.ActionDelete, "permission", "123", mock.Anything, mock.Anything).Return(errors.New("audit unavailable"))
+
+		assert.EqualError(t, usecase.Delete(context.Background(), admin, "123"), "audit unavailable")
+	})
+}
@@ -0,0 +1,112 @@
+package permission_entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Messages returned to a caller that is denied.
+const (
+	ReasonModuleDenied = "Access denied to required modules"
+	ReasonActionDenied = "Access denied to required actions"
+)
+
+// Decision is the outcome of checking grants against the modules and actions
+// a route requires. Scope lists the schools the allowing grants cover.
+type Decision struct {
+	Allowed bool
+	Scope   SchoolScope
+	Reason  string
+}
+
+// Decide allows when a grant pairs one of the modules with one of the
+// actions. Modules and actions are checked together: users:read and
+// students:delete do not add up to users:delete. No actions means any
+// action on the module.
+func Decide(held ScopedGrants, modules, actions []string) Decision {
+	hasModule := false
+	var scope SchoolScope
+	for _, module := range modules {
+		if held.Scope(module, "").IsEmpty() {
+			continue
+		}
+		hasModule = true
+
+		if len(actions) == 0 {
+			scope = scope.Union(held.Scope(module, ""))
+			continue
+		}
+		for _, action := range actions {
+			scope = scope.Union(held.Scope(module, action))
+		}
+	}
+
+	switch {
+	case !hasModule:
+		return Decision{Reason: ReasonModuleDenied}
+	case scope.IsEmpty():
+		return Decision{Reason: ReasonActionDenied}
+	}
+	return Decision{Allowed: true, Scope: scope}
+}
+
+// Matching keeps the grants that count towards action on module, in the
+// same terms as Scope.
+func (s ScopedGrants) Matching(module, action string) ScopedGrants {
+	return s.Map(func(grants []string) []string {
+		matching := []string{}
+		for _, grant := range grants {
+			m, a, ok := ParseGrant(grant)
+			if !ok || m != module {
+				continue
+			}
+			if action == "" || a == WildcardAction || a == action {
+				matching = append(matching, grant)
+				continue
+			}
+			if level, ok := ParseLevel(action); ok && level.Implies(a) {
+				matching = append(matching, grant)
+			}
+		}
+		return matching
+	})
+}
+
+// Explain spells out a decision for debugging: what was required and what
+// the held grants offer on those modules.
+func Explain(held ScopedGrants, modules, actions []string) string {
+	required := make([]string, 0, len(modules)*max(len(actions), 1))
+	offered := ScopedGrants{Grants: []string{}}
+	for _, module := range modules {
+		if len(actions) == 0 {
+			required = append(required, NewGrant(module, WildcardAction))
+		}
+		for _, action := range actions {
+			required = append(required, NewGrant(module, action))
+		}
+		onModule := held.Matching(module, "")
+		offered.Add(nil, onModule.Grants)
+		for schoolID, grants := range onModule.Schools {
+			offered.Add([]string{schoolID}, grants)
+		}
+	}
+
+	offers := make([]string, 0, len(offered.Grants)+len(offered.Schools))
+	if len(offered.Grants) > 0 {
+		offers = append(offers, fmt.Sprintf("%s in every school", strings.Join(offered.Grants, ", ")))
+	}
+	schoolIDs := make([]string, 0, len(offered.Schools))
+	for schoolID := range offered.Schools {
+		schoolIDs = append(schoolIDs, schoolID)
+	}
+	slices.Sort(schoolIDs)
+	for _, schoolID := range schoolIDs {
+		offers = append(offers, fmt.Sprintf("%s in school %s", strings.Join(offered.Schools[schoolID], ", "), schoolID))
+	}
+	if len(offers) == 0 {
+		offers = append(offers, "nothing")
+	}
+
+	return fmt.Sprintf("requires one of %s; holds %s", strings.Join(required, ", "), strings.Join(offers, "; "))
+}
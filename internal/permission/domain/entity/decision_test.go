@@ -0,0 +1,49 @@
+package permission_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecide(t *testing.T) {
+	held := ScopedGrants{
+		Grants:  []string{"users:read"},
+		Schools: map[string][]string{"school-a": {"students:delete"}},
+	}
+
+	allowed := Decide(held, []string{"students"}, []string{"delete"})
+	assert.True(t, allowed.Allowed)
+	assert.Equal(t, SchoolScope{SchoolIDs: []string{"school-a"}}, allowed.Scope)
+
+	assert.Equal(t, Decision{Reason: ReasonActionDenied}, Decide(held, []string{"users"}, []string{"delete"}))
+	assert.Equal(t, Decision{Reason: ReasonModuleDenied}, Decide(held, []string{"schools"}, []string{"read"}))
+	assert.Equal(t, AllSchools(), Decide(held, []string{"users"}, nil).Scope)
+}
+
+func TestScopedGrants_Matching(t *testing.T) {
+	held := ScopedGrants{
+		Grants:  []string{"students:read", "students:update", "users:read"},
+		Schools: map[string][]string{"school-a": {"students:*"}, "school-b": {"users:delete"}},
+	}
+
+	assert.Equal(t, ScopedGrants{
+		Grants:  []string{"students:read"},
+		Schools: map[string][]string{"school-a": {"students:*"}},
+	}, held.Matching("students", "read"))
+	assert.Equal(t, []string{"students:read", "students:update"}, held.Matching("students", "").Grants)
+	assert.Equal(t, []string{"students:read", "students:update"}, held.Matching("students", "editor").Grants)
+}
+
+func TestExplain(t *testing.T) {
+	held := ScopedGrants{
+		Grants:  []string{"students:read", "users:read"},
+		Schools: map[string][]string{"school-a": {"students:update"}},
+	}
+
+	assert.Equal(t,
+		"requires one of students:delete; holds students:read in every school; students:update in school school-a",
+		Explain(held, []string{"students"}, []string{"delete"}),
+	)
+	assert.Equal(t, "requires one of schools:*; holds nothing", Explain(held, []string{"schools"}, nil))
+}
@@ -11,8 +11,8 @@ type Permission struct {
 	shared_event.AggregateRoot
 	ID          string
 	UserID      string
-	Modules     []string
-	Actions     []string
+	Grants      []string
+	SchoolIDs   []string
 	Level       string
 	Description string
 	CreatedAt   time.Time
@@ -27,26 +27,26 @@ func NewPermission(p *Permission) (*Permission, error) {
 	permission := &Permission{
 		ID:          vp.ID,
 		UserID:      vp.UserID,
-		Modules:     vp.Modules,
-		Actions:     vp.Actions,
+		Grants:      vp.Grants,
+		SchoolIDs:   vp.SchoolIDs,
 		Level:       vp.Level,
 		Description: vp.Description,
 		CreatedAt:   vp.CreatedAt,
 		UpdatedAt:   vp.UpdatedAt,
 	}
 
-	permission.AddDomainEvent(permission_event.NewPermissionCreatedEvent(permission.ID, permission.UserID, permission.Modules, permission.Actions, permission.Level, permission.Description))
+	permission.AddDomainEvent(permission_event.NewPermissionCreatedEvent(permission.ID, permission.UserID, permission.Grants, permission.Level, permission.Description))
 
 	return permission, nil
 }
 
-func (p *Permission) UpdatePermission(modules, actions *[]string, level, description *string) (*Permission, error) {
-	if modules != nil {
-		p.Modules = *modules
+func (p *Permission) UpdatePermission(grants, schoolIDs *[]string, level, description *string) (*Permission, error) {
+	if grants != nil {
+		p.Grants = *grants
 	}
 
-	if actions != nil {
-		p.Actions = *actions
+	if schoolIDs != nil {
+		p.SchoolIDs = *schoolIDs
 	}
 
 	if level != nil {
@@ -64,9 +64,17 @@ func (p *Permission) UpdatePermission(modules, actions *[]string, level, descrip
 		return nil, err
 	}
 
+	vp.AddDomainEvent(permission_event.NewPermissionUpdatedEvent(vp.ID, vp.UserID, vp.Grants, vp.SchoolIDs, vp.Level))
+
 	return vp, nil
 }
 
+// Delete records that the permission is being removed; the repository does
+// the actual deletion.
+func (p *Permission) Delete() {
+	p.AddDomainEvent(permission_event.NewPermissionDeletedEvent(p.ID, p.UserID))
+}
+
 func (p *Permission) GetID() string {
 	if p == nil {
 		return ""
@@ -81,18 +89,28 @@ func (p *Permission) GetUserID() string {
 	return p.UserID
 }
 
-func (p *Permission) GetModules() []string {
+func (p *Permission) GetGrants() []string {
+	if p == nil {
+		return nil
+	}
+	return p.Grants
+}
+
+// GetSchoolIDs returns the schools the grants are limited to; empty means
+// every school.
+func (p *Permission) GetSchoolIDs() []string {
 	if p == nil {
 		return nil
 	}
-	return p.Modules
+	return p.SchoolIDs
 }
 
-func (p *Permission) GetActions() []string {
+// EffectiveGrants are the grants capped by the permission's level.
+func (p *Permission) EffectiveGrants() []string {
 	if p == nil {
 		return nil
 	}
-	return p.Actions
+	return CapGrants(p.Grants, Level(p.Level))
 }
 
 func (p *Permission) GetLevel() string {
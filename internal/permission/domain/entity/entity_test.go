@@ -13,8 +13,7 @@ func TestNewPermission(t *testing.T) {
 		p := &Permission{
 			ID:          "123",
 			UserID:      "user-123",
-			Modules:     []string{"module1"},
-			Actions:     []string{"read"},
+			Grants:      []string{"module1:read"},
 			Level:       "admin",
 			Description: "test permission",
 			CreatedAt:   time.Now(),
@@ -27,8 +26,7 @@ func TestNewPermission(t *testing.T) {
 		assert.NotNil(t, permission)
 		assert.Equal(t, p.ID, permission.ID)
 		assert.Equal(t, p.UserID, permission.UserID)
-		assert.Equal(t, p.Modules, permission.Modules)
-		assert.Equal(t, p.Actions, permission.Actions)
+		assert.Equal(t, p.Grants, permission.Grants)
 		assert.Equal(t, p.Level, permission.Level)
 		assert.Equal(t, p.Description, permission.Description)
 
@@ -39,10 +37,9 @@ func TestNewPermission(t *testing.T) {
 
 	t.Run("should return error when id is empty", func(t *testing.T) {
 		p := &Permission{
-			UserID:  "user-123",
-			Modules: []string{"module1"},
-			Actions: []string{"read"},
-			Level:   "admin",
+			UserID: "user-123",
+			Grants: []string{"module1:read"},
+			Level:  "admin",
 		}
 
 		permission, err := NewPermission(p)
@@ -54,10 +51,9 @@ func TestNewPermission(t *testing.T) {
 
 	t.Run("should return error when user id is empty", func(t *testing.T) {
 		p := &Permission{
-			ID:      "123",
-			Modules: []string{"module1"},
-			Actions: []string{"read"},
-			Level:   "admin",
+			ID:     "123",
+			Grants: []string{"module1:read"},
+			Level:  "admin",
 		}
 
 		permission, err := NewPermission(p)
@@ -67,42 +63,42 @@ func TestNewPermission(t *testing.T) {
 		assert.Contains(t, err.Error(), "user id is required")
 	})
 
-	t.Run("should return error when modules is empty", func(t *testing.T) {
+	t.Run("should return error when grants is empty", func(t *testing.T) {
 		p := &Permission{
-			ID:      "123",
-			UserID:  "user-123",
-			Actions: []string{"read"},
-			Level:   "admin",
+			ID:     "123",
+			UserID: "user-123",
+			Level:  "admin",
 		}
 
 		permission, err := NewPermission(p)
 
 		assert.Error(t, err)
 		assert.Nil(t, permission)
-		assert.Contains(t, err.Error(), "modules is required")
+		assert.Contains(t, err.Error(), "grants is required")
 	})
 
-	t.Run("should return error when actions is empty", func(t *testing.T) {
+	t.Run("should return error when a grant is not a module:action pair", func(t *testing.T) {
 		p := &Permission{
-			ID:      "123",
-			UserID:  "user-123",
-			Modules: []string{"module1"},
-			Level:   "admin",
+			ID:     "123",
+			UserID: "user-123",
+			Grants: []string{"module1", "*:read", "module1:"},
+			Level:  "admin",
 		}
 
 		permission, err := NewPermission(p)
 
 		assert.Error(t, err)
 		assert.Nil(t, permission)
-		assert.Contains(t, err.Error(), "actions is required")
+		assert.Contains(t, err.Error(), `grant "module1" must be module:action`)
+		assert.Contains(t, err.Error(), `grant "*:read" must be module:action`)
+		assert.Contains(t, err.Error(), `grant "module1:" must be module:action`)
 	})
 
 	t.Run("should return error when level is empty", func(t *testing.T) {
 		p := &Permission{
-			ID:      "123",
-			UserID:  "user-123",
-			Modules: []string{"module1"},
-			Actions: []string{"read"},
+			ID:     "123",
+			UserID: "user-123",
+			Grants: []string{"module1:read"},
 		}
 
 		permission, err := NewPermission(p)
@@ -126,8 +122,7 @@ func TestPermission_Getters(t *testing.T) {
 		p := &Permission{
 			ID:          "123",
 			UserID:      "user-123",
-			Modules:     []string{"module1"},
-			Actions:     []string{"read"},
+			Grants:      []string{"module1:read"},
 			Level:       "admin",
 			Description: "test permission",
 			CreatedAt:   now,
@@ -136,8 +131,7 @@ func TestPermission_Getters(t *testing.T) {
 
 		assert.Equal(t, "123", p.GetID())
 		assert.Equal(t, "user-123", p.GetUserID())
-		assert.Equal(t, []string{"module1"}, p.GetModules())
-		assert.Equal(t, []string{"read"}, p.GetActions())
+		assert.Equal(t, []string{"module1:read"}, p.GetGrants())
 		assert.Equal(t, "admin", p.GetLevel())
 		assert.Equal(t, "test permission", p.GetDescription())
 		assert.Equal(t, now, p.GetCreatedAt())
@@ -149,8 +143,7 @@ func TestPermission_Getters(t *testing.T) {
 
 		assert.Equal(t, "", p.GetID())
 		assert.Equal(t, "", p.GetUserID())
-		assert.Nil(t, p.GetModules())
-		assert.Nil(t, p.GetActions())
+		assert.Nil(t, p.GetGrants())
 		assert.Equal(t, "", p.GetLevel())
 		assert.Equal(t, "", p.GetDescription())
 		assert.True(t, p.GetCreatedAt().IsZero())
@@ -163,7 +156,7 @@ func TestPermission_PullDomainEvents(t *testing.T) {
 		p := &Permission{
 			ID: "123",
 		}
-		event := permission_event.NewPermissionCreatedEvent("123", "user-123", []string{"module1"}, []string{"read"}, "admin", "test")
+		event := permission_event.NewPermissionCreatedEvent("123", "user-123", []string{"module1:read"}, "admin", "test")
 		p.AddDomainEvent(event)
 
 		events := p.PullDomainEvents()
@@ -186,22 +179,19 @@ func TestPermission_UpdatePermission(t *testing.T) {
 	t.Run("should update permission successfully", func(t *testing.T) {
 		p := &Permission{
 			ID:          "123",
-			Modules:     []string{"module1"},
-			Actions:     []string{"read"},
+			Grants:      []string{"module1:read"},
 			Level:       "admin",
 			Description: "test permission",
 		}
 
-		modules := []string{"module2"}
-		actions := []string{"write"}
-		level := "user"
+		grants := []string{"module2:write"}
+		level := "viewer"
 		description := "updated permission"
 
-		updatedP, err := p.UpdatePermission(&modules, &actions, &level, &description)
+		updatedP, err := p.UpdatePermission(&grants, nil, &level, &description)
 
 		assert.NoError(t, err)
-		assert.Equal(t, modules, updatedP.Modules)
-		assert.Equal(t, actions, updatedP.Actions)
+		assert.Equal(t, grants, updatedP.Grants)
 		assert.Equal(t, level, updatedP.Level)
 		assert.Equal(t, description, updatedP.Description)
 		assert.False(t, updatedP.UpdatedAt.IsZero())
@@ -210,29 +200,60 @@ func TestPermission_UpdatePermission(t *testing.T) {
 	t.Run("should partially update permission", func(t *testing.T) {
 		p := &Permission{
 			ID:          "123",
-			Modules:     []string{"module1"},
-			Actions:     []string{"read"},
+			Grants:      []string{"module1:read"},
 			Level:       "admin",
 			Description: "test permission",
 		}
 
-		level := "user"
+		level := "viewer"
 
 		updatedP, err := p.UpdatePermission(nil, nil, &level, nil)
 
 		assert.NoError(t, err)
-		assert.Equal(t, []string{"module1"}, updatedP.Modules)
-		assert.Equal(t, []string{"read"}, updatedP.Actions)
+		assert.Equal(t, []string{"module1:read"}, updatedP.Grants)
 		assert.Equal(t, level, updatedP.Level)
 		assert.Equal(t, "test permission", updatedP.Description)
 		assert.False(t, updatedP.UpdatedAt.IsZero())
 	})
 
+	t.Run("should scope permission to schools", func(t *testing.T) {
+		p := &Permission{
+			ID:     "123",
+			Grants: []string{"students:read"},
+			Level:  "admin",
+		}
+
+		schoolIDs := []string{"school-1"}
+
+		updatedP, err := p.UpdatePermission(nil, &schoolIDs, nil, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, schoolIDs, updatedP.GetSchoolIDs())
+	})
+
+	t.Run("should record an updated event", func(t *testing.T) {
+		p := &Permission{
+			ID:     "123",
+			UserID: "user-123",
+			Grants: []string{"module1:read"},
+			Level:  "admin",
+		}
+
+		level := "viewer"
+
+		updatedP, err := p.UpdatePermission(nil, nil, &level, nil)
+
+		assert.NoError(t, err)
+		events := updatedP.PullDomainEvents()
+		assert.Len(t, events, 1)
+		assert.Equal(t, "permission.updated", events[0].EventName())
+		assert.Equal(t, "user-123", events[0].(*permission_event.PermissionUpdatedEvent).UserID)
+	})
+
 	t.Run("should return error when validation fails", func(t *testing.T) {
 		p := &Permission{
 			ID:          "123",
-			Modules:     []string{"module1"},
-			Actions:     []string{"read"},
+			Grants:      []string{"module1:read"},
 			Level:       "admin",
 			Description: "test permission",
 		}
@@ -246,3 +267,14 @@ func TestPermission_UpdatePermission(t *testing.T) {
 		assert.Contains(t, err.Error(), "level cannot be empty")
 	})
 }
+
+func TestPermission_Delete(t *testing.T) {
+	p := &Permission{ID: "123", UserID: "user-123"}
+
+	p.Delete()
+
+	events := p.PullDomainEvents()
+	assert.Len(t, events, 1)
+	assert.Equal(t, permission_event.NewPermissionDeletedEvent("123", "user-123").EventName(), events[0].EventName())
+	assert.Equal(t, "user-123", events[0].(*permission_event.PermissionDeletedEvent).UserID)
+}
@@ -0,0 +1,109 @@
+package permission_entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A grant is a "module:action" pair such as "students:read". The action may
+// be the wildcard "*" to grant every action on the module; modules cannot be
+// wildcarded, so a grant always names what it opens.
+const WildcardAction = "*"
+
+func NewGrant(module, action string) string {
+	return module + ":" + action
+}
+
+// ParseGrant splits a grant into its module and action, reporting whether it
+// is well formed.
+func ParseGrant(grant string) (module, action string, ok bool) {
+	module, action, ok = strings.Cut(grant, ":")
+	if !ok || module == "" || action == "" || module == WildcardAction || strings.Contains(action, ":") {
+		return "", "", false
+	}
+	return module, action, true
+}
+
+// GrantModule returns the module a grant applies to, or "" if it is malformed.
+func GrantModule(grant string) string {
+	module, _, _ := ParseGrant(grant)
+	return module
+}
+
+// Allows reports whether any of the grants permits action on module.
+func Allows(grants []string, module, action string) bool {
+	for _, grant := range grants {
+		m, a, ok := ParseGrant(grant)
+		if ok && m == module && (a == WildcardAction || a == action) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasModule reports whether the grants permit any action on module.
+func HasModule(grants []string, module string) bool {
+	for _, grant := range grants {
+		if GrantModule(grant) == module {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers reports whether grants permit everything grant does. A wildcard is
+// only covered by the same wildcard, never by a list of concrete actions.
+func Covers(grants []string, grant string) bool {
+	module, action, ok := ParseGrant(grant)
+	if !ok {
+		return false
+	}
+	if action == WildcardAction {
+		return slices.Contains(grants, NewGrant(module, WildcardAction))
+	}
+	return Allows(grants, module, action)
+}
+
+// Intersect narrows requested to what held permits. A requested wildcard the
+// holder does not have is replaced by the concrete grants they hold on that
+// module.
+func Intersect(requested, held []string) []string {
+	result := make([]string, 0, len(requested))
+	for _, grant := range requested {
+		if Covers(held, grant) {
+			result = MergeGrants(result, grant)
+			continue
+		}
+		module, action, ok := ParseGrant(grant)
+		if !ok || action != WildcardAction {
+			continue
+		}
+		for _, h := range held {
+			if GrantModule(h) == module {
+				result = MergeGrants(result, h)
+			}
+		}
+	}
+	return result
+}
+
+// MergeGrants appends grants that are not already present.
+func MergeGrants(grants []string, more ...string) []string {
+	for _, grant := range more {
+		if !slices.Contains(grants, grant) {
+			grants = append(grants, grant)
+		}
+	}
+	return grants
+}
+
+func validateGrants(grants []string) []string {
+	var errs []string
+	for _, grant := range grants {
+		if _, _, ok := ParseGrant(grant); !ok {
+			errs = append(errs, fmt.Sprintf("grant %q must be module:action", grant))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,77 @@
+package permission_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGrant(t *testing.T) {
+	tests := []struct {
+		grant  string
+		module string
+		action string
+		ok     bool
+	}{
+		{grant: "students:read", module: "students", action: "read", ok: true},
+		{grant: "students:*", module: "students", action: "*", ok: true},
+		{grant: "students"},
+		{grant: ":read"},
+		{grant: "students:"},
+		{grant: "*:read"},
+		{grant: "students:read:own"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.grant, func(t *testing.T) {
+			module, action, ok := ParseGrant(tt.grant)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.module, module)
+			assert.Equal(t, tt.action, action)
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	grants := []string{"users:read", "students:delete", "schools:*"}
+
+	assert.True(t, Allows(grants, "users", "read"))
+	assert.True(t, Allows(grants, "students", "delete"))
+	assert.True(t, Allows(grants, "schools", "update"))
+	assert.False(t, Allows(grants, "users", "delete"), "actions must not leak across modules")
+	assert.False(t, Allows(grants, "students", "read"))
+	assert.False(t, Allows(grants, "permissions", "read"))
+}
+
+func TestHasModule(t *testing.T) {
+	grants := []string{"users:read", "schools:*"}
+
+	assert.True(t, HasModule(grants, "users"))
+	assert.True(t, HasModule(grants, "schools"))
+	assert.False(t, HasModule(grants, "students"))
+}
+
+func TestCovers(t *testing.T) {
+	grants := []string{"users:read", "users:update", "schools:*"}
+
+	assert.True(t, Covers(grants, "users:read"))
+	assert.True(t, Covers(grants, "schools:delete"))
+	assert.True(t, Covers(grants, "schools:*"))
+	assert.False(t, Covers(grants, "users:*"), "concrete actions do not add up to a wildcard")
+	assert.False(t, Covers(grants, "users:delete"))
+	assert.False(t, Covers(grants, "malformed"))
+}
+
+func TestIntersect(t *testing.T) {
+	held := []string{"users:read", "users:update", "schools:*"}
+
+	assert.Equal(t, []string{"users:read", "schools:delete"}, Intersect([]string{"users:read", "users:delete", "schools:delete"}, held))
+	assert.Equal(t, []string{"users:read", "users:update"}, Intersect([]string{"users:*"}, held))
+	assert.Equal(t, []string{"schools:*"}, Intersect([]string{"schools:*"}, held))
+	assert.Empty(t, Intersect([]string{"students:*"}, held))
+}
+
+func TestMergeGrants(t *testing.T) {
+	assert.Equal(t, []string{"users:read", "schools:*"}, MergeGrants([]string{"users:read"}, "schools:*", "users:read"))
+}
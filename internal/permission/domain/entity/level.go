@@ -0,0 +1,96 @@
+package permission_entity
+
+import (
+	"errors"
+	"slices"
+)
+
+var ErrLevelAboveGrantor = errors.New("cannot grant a level above your own")
+
+// Level caps what a permission's grants allow. Each level implies the
+// actions of the ones below it:
+//
+//	viewer   read
+//	editor   read, create, update
+//	manager  read, create, update, delete
+//	admin    every action, including ones outside CRUD
+type Level string
+
+const (
+	LevelViewer  Level = "viewer"
+	LevelEditor  Level = "editor"
+	LevelManager Level = "manager"
+	LevelAdmin   Level = "admin"
+)
+
+var levels = []Level{LevelViewer, LevelEditor, LevelManager, LevelAdmin}
+
+var levelActions = map[Level][]string{
+	LevelViewer:  {"read"},
+	LevelEditor:  {"read", "create", "update"},
+	LevelManager: {"read", "create", "update", "delete"},
+	LevelAdmin:   {WildcardAction},
+}
+
+func Levels() []Level {
+	return slices.Clone(levels)
+}
+
+func ParseLevel(level string) (Level, bool) {
+	l := Level(level)
+	return l, slices.Contains(levels, l)
+}
+
+// AtLeast reports whether l ranks at or above other. An unknown level ranks
+// below every known one.
+func (l Level) AtLeast(other Level) bool {
+	return slices.Index(levels, l) >= slices.Index(levels, other)
+}
+
+func (l Level) Implies(action string) bool {
+	actions := levelActions[l]
+	return slices.Contains(actions, WildcardAction) || slices.Contains(actions, action)
+}
+
+// CapGrants drops the grants whose action the level does not imply. A
+// wildcard below admin becomes the level's own actions.
+func CapGrants(grants []string, level Level) []string {
+	capped := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		module, action, ok := ParseGrant(grant)
+		if !ok {
+			continue
+		}
+		if action != WildcardAction {
+			if level.Implies(action) {
+				capped = MergeGrants(capped, grant)
+			}
+			continue
+		}
+		for _, implied := range levelActions[level] {
+			capped = MergeGrants(capped, NewGrant(module, implied))
+		}
+	}
+	return capped
+}
+
+// ModuleLevel is the highest level whose actions grants fully allow on
+// module, or "" when they do not even allow reading it.
+func ModuleLevel(grants []string, module string) Level {
+	var held Level
+	for _, level := range levels {
+		for _, action := range levelActions[level] {
+			if action == WildcardAction {
+				if !slices.Contains(grants, NewGrant(module, WildcardAction)) {
+					return held
+				}
+				continue
+			}
+			if !Allows(grants, module, action) {
+				return held
+			}
+		}
+		held = level
+	}
+	return held
+}
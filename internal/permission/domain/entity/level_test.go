@@ -0,0 +1,50 @@
+package permission_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel(t *testing.T) {
+	level, ok := ParseLevel("editor")
+	assert.True(t, ok)
+	assert.Equal(t, LevelEditor, level)
+
+	_, ok = ParseLevel("allowed")
+	assert.False(t, ok)
+}
+
+func TestLevel_AtLeast(t *testing.T) {
+	assert.True(t, LevelAdmin.AtLeast(LevelManager))
+	assert.True(t, LevelEditor.AtLeast(LevelEditor))
+	assert.False(t, LevelViewer.AtLeast(LevelEditor))
+	assert.False(t, Level("").AtLeast(LevelViewer))
+}
+
+func TestLevel_Implies(t *testing.T) {
+	assert.True(t, LevelViewer.Implies("read"))
+	assert.False(t, LevelViewer.Implies("update"))
+	assert.True(t, LevelEditor.Implies("create"))
+	assert.False(t, LevelEditor.Implies("delete"))
+	assert.True(t, LevelManager.Implies("delete"))
+	assert.False(t, LevelManager.Implies("export"))
+	assert.True(t, LevelAdmin.Implies("export"))
+}
+
+func TestCapGrants(t *testing.T) {
+	grants := []string{"students:*", "users:delete", "reports:read"}
+
+	assert.Equal(t, []string{"students:read", "reports:read"}, CapGrants(grants, LevelViewer))
+	assert.Equal(t, []string{"students:read", "students:create", "students:update", "reports:read"}, CapGrants(grants, LevelEditor))
+	assert.Equal(t, grants, CapGrants(grants, LevelAdmin))
+	assert.Empty(t, CapGrants(grants, Level("")))
+}
+
+func TestModuleLevel(t *testing.T) {
+	assert.Equal(t, LevelAdmin, ModuleLevel([]string{"students:*"}, "students"))
+	assert.Equal(t, LevelManager, ModuleLevel([]string{"students:read", "students:create", "students:update", "students:delete", "students:export"}, "students"))
+	assert.Equal(t, LevelViewer, ModuleLevel([]string{"students:read", "students:update"}, "students"))
+	assert.Equal(t, Level(""), ModuleLevel([]string{"students:update"}, "students"))
+	assert.Equal(t, Level(""), ModuleLevel([]string{"users:*"}, "students"))
+}
@@ -0,0 +1,183 @@
+package permission_entity
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+var ErrSchoolOutOfScope = errors.New("school is outside the permitted scope")
+
+// ScopedGrants are the grants a user holds across the whole network plus
+// those limited to particular schools, keyed by school ID.
+type ScopedGrants struct {
+	Grants  []string
+	Schools map[string][]string
+}
+
+// Add records grants for the given schools, or network-wide when schoolIDs
+// is empty.
+func (s *ScopedGrants) Add(schoolIDs []string, grants []string) {
+	if len(schoolIDs) == 0 {
+		s.Grants = MergeGrants(s.Grants, grants...)
+		return
+	}
+	if s.Schools == nil {
+		s.Schools = make(map[string][]string, len(schoolIDs))
+	}
+	for _, schoolID := range schoolIDs {
+		s.Schools[schoolID] = MergeGrants(s.Schools[schoolID], grants...)
+	}
+}
+
+// Map applies f to the network-wide grants and to every school's grants,
+// dropping schools left without any.
+func (s ScopedGrants) Map(f func([]string) []string) ScopedGrants {
+	mapped := ScopedGrants{Grants: f(s.Grants)}
+	for schoolID, grants := range s.Schools {
+		if kept := f(grants); len(kept) > 0 {
+			mapped.Add([]string{schoolID}, kept)
+		}
+	}
+	return mapped
+}
+
+// Scope returns the schools in which action is allowed on module. An empty
+// action asks for any action on the module, and a level name for at least
+// that level, which a school may reach together with network-wide grants.
+func (s ScopedGrants) Scope(module, action string) SchoolScope {
+	if allowsAny(s.Grants, module, action) {
+		return AllSchools()
+	}
+	var scope SchoolScope
+	for schoolID, grants := range s.Schools {
+		if allowsAny(MergeGrants(slices.Clone(s.Grants), grants...), module, action) {
+			scope.SchoolIDs = append(scope.SchoolIDs, schoolID)
+		}
+	}
+	slices.Sort(scope.SchoolIDs)
+	return scope
+}
+
+// Level returns the level held on module in every one of schoolIDs, counting
+// network-wide grants in each. With no schools only network-wide grants
+// count.
+func (s ScopedGrants) Level(module string, schoolIDs []string) Level {
+	if len(schoolIDs) == 0 {
+		return ModuleLevel(s.Grants, module)
+	}
+	lowest := LevelAdmin
+	for _, schoolID := range schoolIDs {
+		held := ModuleLevel(MergeGrants(slices.Clone(s.Grants), s.Schools[schoolID]...), module)
+		if !held.AtLeast(lowest) {
+			lowest = held
+		}
+	}
+	return lowest
+}
+
+// Covers reports whether grant is covered network-wide or in some school.
+func (s ScopedGrants) Covers(grant string) bool {
+	if Covers(s.Grants, grant) {
+		return true
+	}
+	for _, grants := range s.Schools {
+		if Covers(grants, grant) {
+			return true
+		}
+	}
+	return false
+}
+
+// CoversIn reports whether grant is covered in every one of schoolIDs,
+// counting network-wide grants in each. With no schools only network-wide
+// grants count.
+func (s ScopedGrants) CoversIn(grant string, schoolIDs []string) bool {
+	if len(schoolIDs) == 0 {
+		return Covers(s.Grants, grant)
+	}
+	for _, schoolID := range schoolIDs {
+		if !Covers(MergeGrants(slices.Clone(s.Grants), s.Schools[schoolID]...), grant) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowsAny treats an empty action as any action and a level name as a
+// minimum level on the module.
+func allowsAny(grants []string, module, action string) bool {
+	if action == "" {
+		return HasModule(grants, module)
+	}
+	if level, ok := ParseLevel(action); ok {
+		return ModuleLevel(grants, module).AtLeast(level)
+	}
+	return Allows(grants, module, action)
+}
+
+// SchoolScope lists the schools a request may touch. All is set when the
+// grant was held network-wide.
+type SchoolScope struct {
+	All       bool
+	SchoolIDs []string
+}
+
+func AllSchools() SchoolScope {
+	return SchoolScope{All: true}
+}
+
+func (s SchoolScope) IsEmpty() bool {
+	return !s.All && len(s.SchoolIDs) == 0
+}
+
+func (s SchoolScope) Includes(schoolID string) bool {
+	return s.All || slices.Contains(s.SchoolIDs, schoolID)
+}
+
+// Union returns the schools in either scope.
+func (s SchoolScope) Union(other SchoolScope) SchoolScope {
+	if s.All || other.All {
+		return AllSchools()
+	}
+	union := SchoolScope{SchoolIDs: slices.Clone(s.SchoolIDs)}
+	for _, schoolID := range other.SchoolIDs {
+		if !slices.Contains(union.SchoolIDs, schoolID) {
+			union.SchoolIDs = append(union.SchoolIDs, schoolID)
+		}
+	}
+	return union
+}
+
+type schoolScopeKey struct{}
+
+// WithSchoolScope attaches the scope the current request was authorized
+// for. Repositories of school-owned records narrow their queries to it.
+func WithSchoolScope(ctx context.Context, scope SchoolScope) context.Context {
+	return context.WithValue(ctx, schoolScopeKey{}, scope)
+}
+
+// SchoolScopeFrom returns the request's scope. Without one no school is in
+// scope, so a caller that forgot to authorize sees nothing; internal callers
+// that must reach every school attach AllSchools themselves.
+func SchoolScopeFrom(ctx context.Context) SchoolScope {
+	if scope, ok := ctx.Value(schoolScopeKey{}).(SchoolScope); ok {
+		return scope
+	}
+	return SchoolScope{}
+}
+
+type ownerAccessKey struct{}
+
+// WithOwnerAccess marks a request let through only because the principal
+// owns the record, not because its grants cover the route. Handlers use it
+// to keep owners to the fields they may change themselves.
+func WithOwnerAccess(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ownerAccessKey{}, true)
+}
+
+// OwnerAccess reports whether the request was authorized by ownership alone.
+func OwnerAccess(ctx context.Context) bool {
+	owner, _ := ctx.Value(ownerAccessKey{}).(bool)
+	return owner
+}
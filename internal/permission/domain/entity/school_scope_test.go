@@ -0,0 +1,123 @@
+package permission_entity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopedGrants_Add(t *testing.T) {
+	var grants ScopedGrants
+
+	grants.Add(nil, []string{"users:read"})
+	grants.Add([]string{"school-a", "school-b"}, []string{"students:read"})
+	grants.Add([]string{"school-a"}, []string{"students:update", "students:read"})
+
+	assert.Equal(t, []string{"users:read"}, grants.Grants)
+	assert.Equal(t, map[string][]string{
+		"school-a": {"students:read", "students:update"},
+		"school-b": {"students:read"},
+	}, grants.Schools)
+}
+
+func TestScopedGrants_Scope(t *testing.T) {
+	grants := ScopedGrants{
+		Grants: []string{"schools:read"},
+		Schools: map[string][]string{
+			"school-b": {"students:*"},
+			"school-a": {"students:read"},
+		},
+	}
+
+	assert.Equal(t, AllSchools(), grants.Scope("schools", "read"))
+	assert.Equal(t, SchoolScope{SchoolIDs: []string{"school-a", "school-b"}}, grants.Scope("students", "read"))
+	assert.Equal(t, SchoolScope{SchoolIDs: []string{"school-b"}}, grants.Scope("students", "delete"))
+	assert.Equal(t, SchoolScope{SchoolIDs: []string{"school-a", "school-b"}}, grants.Scope("students", ""))
+	assert.True(t, grants.Scope("users", "read").IsEmpty())
+}
+
+func TestScopedGrants_Level(t *testing.T) {
+	grants := ScopedGrants{
+		Grants: []string{"students:read"},
+		Schools: map[string][]string{
+			"school-a": {"students:*"},
+			"school-b": {"students:create", "students:update"},
+		},
+	}
+
+	assert.Equal(t, LevelViewer, grants.Level("students", nil))
+	assert.Equal(t, LevelAdmin, grants.Level("students", []string{"school-a"}))
+	assert.Equal(t, LevelEditor, grants.Level("students", []string{"school-a", "school-b"}))
+	assert.Equal(t, LevelViewer, grants.Level("students", []string{"school-c"}))
+	assert.Equal(t, SchoolScope{SchoolIDs: []string{"school-a", "school-b"}}, grants.Scope("students", "editor"))
+}
+
+func TestScopedGrants_Map(t *testing.T) {
+	grants := ScopedGrants{
+		Grants:  []string{"users:read", "students:read"},
+		Schools: map[string][]string{"school-a": {"students:read"}, "school-b": {"schools:read"}},
+	}
+
+	withoutStudents := grants.Map(func(g []string) []string {
+		var kept []string
+		for _, grant := range g {
+			if GrantModule(grant) != "students" {
+				kept = append(kept, grant)
+			}
+		}
+		return kept
+	})
+
+	assert.Equal(t, []string{"users:read"}, withoutStudents.Grants)
+	assert.Equal(t, map[string][]string{"school-b": {"schools:read"}}, withoutStudents.Schools)
+}
+
+func TestScopedGrants_Covers(t *testing.T) {
+	grants := ScopedGrants{
+		Grants:  []string{"users:read"},
+		Schools: map[string][]string{"school-a": {"students:*"}},
+	}
+
+	assert.True(t, grants.Covers("users:read"))
+	assert.True(t, grants.Covers("students:*"))
+	assert.False(t, grants.Covers("schools:read"))
+}
+
+func TestScopedGrants_CoversIn(t *testing.T) {
+	grants := ScopedGrants{
+		Grants:  []string{"users:read"},
+		Schools: map[string][]string{"school-a": {"students:*"}, "school-b": {"students:read"}},
+	}
+
+	assert.True(t, grants.CoversIn("users:read", nil))
+	assert.False(t, grants.CoversIn("students:read", nil))
+	assert.True(t, grants.CoversIn("students:delete", []string{"school-a"}))
+	assert.False(t, grants.CoversIn("students:delete", []string{"school-a", "school-b"}))
+	assert.True(t, grants.CoversIn("users:read", []string{"school-b"}))
+}
+
+func TestSchoolScope(t *testing.T) {
+	scope := SchoolScope{SchoolIDs: []string{"school-a"}}
+
+	assert.True(t, scope.Includes("school-a"))
+	assert.False(t, scope.Includes("school-b"))
+	assert.True(t, AllSchools().Includes("school-b"))
+	assert.Equal(t, SchoolScope{SchoolIDs: []string{"school-a", "school-b"}}, scope.Union(SchoolScope{SchoolIDs: []string{"school-b", "school-a"}}))
+	assert.Equal(t, AllSchools(), scope.Union(AllSchools()))
+}
+
+func TestSchoolScopeFromContext(t *testing.T) {
+	assert.True(t, SchoolScopeFrom(context.Background()).IsEmpty())
+	assert.Equal(t, AllSchools(), SchoolScopeFrom(WithSchoolScope(context.Background(), AllSchools())))
+
+	scope := SchoolScope{SchoolIDs: []string{"school-a"}}
+	ctx := WithSchoolScope(context.Background(), scope)
+
+	assert.Equal(t, scope, SchoolScopeFrom(ctx))
+}
+
+func TestOwnerAccessFromContext(t *testing.T) {
+	assert.False(t, OwnerAccess(context.Background()))
+	assert.True(t, OwnerAccess(WithOwnerAccess(context.Background())))
+}
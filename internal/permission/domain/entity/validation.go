@@ -28,16 +28,17 @@ func ValidatePermission(permission *Permission) (*Permission, error) {
 		errs = append(errs, "user id is required")
 	}
 
-	if len(permission.Modules) == 0 {
-		errs = append(errs, "modules is required")
+	if len(permission.Grants) == 0 {
+		errs = append(errs, "grants is required")
 	}
 
-	if len(permission.Actions) == 0 {
-		errs = append(errs, "actions is required")
-	}
+	errs = append(errs, validateGrants(permission.Grants)...)
+	errs = append(errs, validateSchoolIDs(permission.SchoolIDs)...)
 
 	if permission.Level == "" {
 		errs = append(errs, "level is required")
+	} else {
+		errs = append(errs, validateLevel(permission.Level)...)
 	}
 
 	if len(errs) > 0 {
@@ -52,11 +53,40 @@ func ValidationUpdatePermission(permission *Permission) (*Permission, error) {
 
 	if permission.Level == "" {
 		errs = append(errs, "level cannot be empty")
+	} else {
+		errs = append(errs, validateLevel(permission.Level)...)
+	}
+
+	if len(permission.Grants) == 0 {
+		errs = append(errs, "grants cannot be empty")
 	}
 
+	errs = append(errs, validateGrants(permission.Grants)...)
+	errs = append(errs, validateSchoolIDs(permission.SchoolIDs)...)
+
 	if len(errs) > 0 {
 		return nil, &ValidationError{Errors: errs}
 	}
 
 	return permission, nil
 }
+
+func validateLevel(level string) []string {
+	if _, ok := ParseLevel(level); ok {
+		return nil
+	}
+	names := make([]string, 0, len(levels))
+	for _, l := range levels {
+		names = append(names, string(l))
+	}
+	return []string{fmt.Sprintf("level must be one of %s", strings.Join(names, ", "))}
+}
+
+func validateSchoolIDs(schoolIDs []string) []string {
+	for _, schoolID := range schoolIDs {
+		if strings.TrimSpace(schoolID) == "" {
+			return []string{"school ids cannot contain empty values"}
+		}
+	}
+	return nil
+}
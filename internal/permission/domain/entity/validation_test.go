@@ -9,11 +9,10 @@ import (
 func TestValidatePermission(t *testing.T) {
 	t.Run("should validate permission successfully", func(t *testing.T) {
 		p := &Permission{
-			ID:      "123",
-			UserID:  "user-123",
-			Modules: []string{"module1"},
-			Actions: []string{"read"},
-			Level:   "admin",
+			ID:     "123",
+			UserID: "user-123",
+			Grants: []string{"module1:read"},
+			Level:  "admin",
 		}
 
 		validatedPermission, err := ValidatePermission(p)
@@ -23,12 +22,26 @@ func TestValidatePermission(t *testing.T) {
 		assert.Equal(t, p, validatedPermission)
 	})
 
+	t.Run("should return error when level is unknown", func(t *testing.T) {
+		p := &Permission{
+			ID:     "123",
+			UserID: "user-123",
+			Grants: []string{"module1:read"},
+			Level:  "allowed",
+		}
+
+		validatedPermission, err := ValidatePermission(p)
+
+		assert.Error(t, err)
+		assert.Nil(t, validatedPermission)
+		assert.Contains(t, err.Error(), "level must be one of viewer, editor, manager, admin")
+	})
+
 	t.Run("should return error when id is empty", func(t *testing.T) {
 		p := &Permission{
-			UserID:  "user-123",
-			Modules: []string{"module1"},
-			Actions: []string{"read"},
-			Level:   "admin",
+			UserID: "user-123",
+			Grants: []string{"module1:read"},
+			Level:  "admin",
 		}
 
 		validatedPermission, err := ValidatePermission(p)
@@ -41,10 +54,9 @@ func TestValidatePermission(t *testing.T) {
 
 	t.Run("should return error when user id is empty", func(t *testing.T) {
 		p := &Permission{
-			ID:      "123",
-			Modules: []string{"module1"},
-			Actions: []string{"read"},
-			Level:   "admin",
+			ID:     "123",
+			Grants: []string{"module1:read"},
+			Level:  "admin",
 		}
 
 		validatedPermission, err := ValidatePermission(p)
@@ -55,12 +67,11 @@ func TestValidatePermission(t *testing.T) {
 		assert.Contains(t, err.Error(), "user id is required")
 	})
 
-	t.Run("should return error when modules is empty", func(t *testing.T) {
+	t.Run("should return error when grants is empty", func(t *testing.T) {
 		p := &Permission{
-			ID:      "123",
-			UserID:  "user-123",
-			Actions: []string{"read"},
-			Level:   "admin",
+			ID:     "123",
+			UserID: "user-123",
+			Level:  "admin",
 		}
 
 		validatedPermission, err := ValidatePermission(p)
@@ -68,31 +79,29 @@ func TestValidatePermission(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, validatedPermission)
 		assert.IsType(t, &ValidationError{}, err)
-		assert.Contains(t, err.Error(), "modules is required")
+		assert.Contains(t, err.Error(), "grants is required")
 	})
 
-	t.Run("should return error when actions is empty", func(t *testing.T) {
+	t.Run("should return error when a school id is empty", func(t *testing.T) {
 		p := &Permission{
-			ID:      "123",
-			UserID:  "user-123",
-			Modules: []string{"module1"},
-			Level:   "admin",
+			ID:        "123",
+			UserID:    "user-123",
+			Grants:    []string{"students:read"},
+			SchoolIDs: []string{"school-1", " "},
+			Level:     "admin",
 		}
 
 		validatedPermission, err := ValidatePermission(p)
 
-		assert.Error(t, err)
 		assert.Nil(t, validatedPermission)
-		assert.IsType(t, &ValidationError{}, err)
-		assert.Contains(t, err.Error(), "actions is required")
+		assert.EqualError(t, err, "validation failed: school ids cannot contain empty values")
 	})
 
 	t.Run("should return error when level is empty", func(t *testing.T) {
 		p := &Permission{
-			ID:      "123",
-			UserID:  "user-123",
-			Modules: []string{"module1"},
-			Actions: []string{"read"},
+			ID:     "123",
+			UserID: "user-123",
+			Grants: []string{"module1:read"},
 		}
 
 		validatedPermission, err := ValidatePermission(p)
@@ -113,8 +122,7 @@ func TestValidatePermission(t *testing.T) {
 		assert.IsType(t, &ValidationError{}, err)
 		assert.Contains(t, err.Error(), "id is required")
 		assert.Contains(t, err.Error(), "user id is required")
-		assert.Contains(t, err.Error(), "modules is required")
-		assert.Contains(t, err.Error(), "actions is required")
+		assert.Contains(t, err.Error(), "grants is required")
 		assert.Contains(t, err.Error(), "level is required")
 	})
 }
@@ -130,7 +138,8 @@ func TestValidationError_Error(t *testing.T) {
 func TestValidationUpdatePermission(t *testing.T) {
 	t.Run("should validate update permission successfully", func(t *testing.T) {
 		p := &Permission{
-			Level: "admin",
+			Grants: []string{"module1:*"},
+			Level:  "admin",
 		}
 
 		validatedP, err := ValidationUpdatePermission(p)
@@ -150,4 +159,17 @@ func TestValidationUpdatePermission(t *testing.T) {
 		assert.Nil(t, validatedP)
 		assert.Contains(t, err.Error(), "level cannot be empty")
 	})
+
+	t.Run("should return error when grants are emptied", func(t *testing.T) {
+		p := &Permission{
+			Grants: []string{},
+			Level:  "admin",
+		}
+
+		validatedP, err := ValidationUpdatePermission(p)
+
+		assert.Error(t, err)
+		assert.Nil(t, validatedP)
+		assert.Contains(t, err.Error(), "grants cannot be empty")
+	})
 }
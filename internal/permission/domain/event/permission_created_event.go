@@ -5,19 +5,17 @@ import "time"
 type PermissionCreatedEvent struct {
 	PermissionID string
 	UserID       string
-	Modules      []string
-	Actions      []string
+	Grants       []string
 	Level        string
 	Description  string
 	Date         time.Time
 }
 
-func NewPermissionCreatedEvent(permissionID string, userID string, modules []string, actions []string, level string, description string) *PermissionCreatedEvent {
+func NewPermissionCreatedEvent(permissionID string, userID string, grants []string, level string, description string) *PermissionCreatedEvent {
 	return &PermissionCreatedEvent{
 		PermissionID: permissionID,
 		UserID:       userID,
-		Modules:      modules,
-		Actions:      actions,
+		Grants:       grants,
 		Level:        level,
 		Description:  description,
 		Date:         time.Now(),
@@ -31,3 +29,7 @@ func (e *PermissionCreatedEvent) EventName() string {
 func (e *PermissionCreatedEvent) OccurredOn() time.Time {
 	return e.Date
 }
+
+func (e *PermissionCreatedEvent) AggregateID() string {
+	return e.PermissionID
+}
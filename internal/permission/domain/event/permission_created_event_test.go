@@ -10,18 +10,16 @@ import (
 func TestNewPermissionCreatedEvent(t *testing.T) {
 	permissionID := "123"
 	userID := "user-123"
-	modules := []string{"module1"}
-	actions := []string{"read"}
+	grants := []string{"module1:read"}
 	level := "admin"
 	description := "test permission"
 
-	event := NewPermissionCreatedEvent(permissionID, userID, modules, actions, level, description)
+	event := NewPermissionCreatedEvent(permissionID, userID, grants, level, description)
 
 	assert.NotNil(t, event)
 	assert.Equal(t, permissionID, event.PermissionID)
 	assert.Equal(t, userID, event.UserID)
-	assert.Equal(t, modules, event.Modules)
-	assert.Equal(t, actions, event.Actions)
+	assert.Equal(t, grants, event.Grants)
 	assert.Equal(t, level, event.Level)
 	assert.Equal(t, description, event.Description)
 	assert.WithinDuration(t, time.Now(), event.Date, time.Second)
@@ -30,6 +28,7 @@ func TestNewPermissionCreatedEvent(t *testing.T) {
 func TestPermissionCreatedEvent_EventName(t *testing.T) {
 	event := &PermissionCreatedEvent{}
 	assert.Equal(t, "permission.created", event.EventName())
+	assert.Equal(t, event.PermissionID, event.AggregateID())
 }
 
 func TestPermissionCreatedEvent_OccurredOn(t *testing.T) {
@@ -0,0 +1,29 @@
+package permission_event
+
+import "time"
+
+type PermissionDeletedEvent struct {
+	PermissionID string
+	UserID       string
+	Date         time.Time
+}
+
+func NewPermissionDeletedEvent(permissionID string, userID string) *PermissionDeletedEvent {
+	return &PermissionDeletedEvent{
+		PermissionID: permissionID,
+		UserID:       userID,
+		Date:         time.Now(),
+	}
+}
+
+func (e *PermissionDeletedEvent) EventName() string {
+	return "permission.deleted"
+}
+
+func (e *PermissionDeletedEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *PermissionDeletedEvent) AggregateID() string {
+	return e.PermissionID
+}
@@ -0,0 +1,18 @@
+package permission_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPermissionDeletedEvent(t *testing.T) {
+	event := NewPermissionDeletedEvent("123", "user-123")
+
+	assert.Equal(t, "123", event.PermissionID)
+	assert.Equal(t, "user-123", event.UserID)
+	assert.Equal(t, "permission.deleted", event.EventName())
+	assert.Equal(t, event.PermissionID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
@@ -0,0 +1,35 @@
+package permission_event
+
+import "time"
+
+type PermissionUpdatedEvent struct {
+	PermissionID string
+	UserID       string
+	Grants       []string
+	SchoolIDs    []string
+	Level        string
+	Date         time.Time
+}
+
+func NewPermissionUpdatedEvent(permissionID string, userID string, grants []string, schoolIDs []string, level string) *PermissionUpdatedEvent {
+	return &PermissionUpdatedEvent{
+		PermissionID: permissionID,
+		UserID:       userID,
+		Grants:       grants,
+		SchoolIDs:    schoolIDs,
+		Level:        level,
+		Date:         time.Now(),
+	}
+}
+
+func (e *PermissionUpdatedEvent) EventName() string {
+	return "permission.updated"
+}
+
+func (e *PermissionUpdatedEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *PermissionUpdatedEvent) AggregateID() string {
+	return e.PermissionID
+}
@@ -0,0 +1,21 @@
+package permission_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPermissionUpdatedEvent(t *testing.T) {
+	event := NewPermissionUpdatedEvent("123", "user-123", []string{"module1:read"}, []string{"school-1"}, "viewer")
+
+	assert.Equal(t, "123", event.PermissionID)
+	assert.Equal(t, "user-123", event.UserID)
+	assert.Equal(t, []string{"module1:read"}, event.Grants)
+	assert.Equal(t, []string{"school-1"}, event.SchoolIDs)
+	assert.Equal(t, "viewer", event.Level)
+	assert.Equal(t, "permission.updated", event.EventName())
+	assert.Equal(t, event.PermissionID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
@@ -13,8 +13,8 @@ type Permission struct {
 	ID          string `gorm:"primaryKey;type:uuid"`
 	UserID      string
 	User        *user_model.User `gorm:"foreignKey:UserID"`
-	Modules     pq.StringArray   `gorm:"type:text[]"`
-	Actions     pq.StringArray   `gorm:"type:text[]"`
+	Grants      pq.StringArray   `gorm:"type:text[]"`
+	SchoolIDs   pq.StringArray   `gorm:"type:text[]"`
 	Level       string
 	Description string
 	CreatedAt   time.Time
@@ -33,8 +33,8 @@ func FromEntity(p *permission_entity.Permission) *Permission {
 	return &Permission{
 		ID:          p.ID,
 		UserID:      p.UserID,
-		Modules:     pq.StringArray(p.Modules),
-		Actions:     pq.StringArray(p.Actions),
+		Grants:      pq.StringArray(p.Grants),
+		SchoolIDs:   pq.StringArray(p.SchoolIDs),
 		Level:       p.Level,
 		Description: p.Description,
 		CreatedAt:   p.CreatedAt,
@@ -57,8 +57,8 @@ func ToEntity(p *Permission) *permission_entity.Permission {
 	return &permission_entity.Permission{
 		ID:          p.ID,
 		UserID:      p.UserID,
-		Modules:     []string(p.Modules),
-		Actions:     []string(p.Actions),
+		Grants:      []string(p.Grants),
+		SchoolIDs:   []string(p.SchoolIDs),
 		Level:       p.Level,
 		Description: p.Description,
 		CreatedAt:   p.CreatedAt,
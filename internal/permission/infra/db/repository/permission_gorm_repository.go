@@ -5,6 +5,7 @@ import (
     permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
     permission_model "github.com/williamkoller/system-education/internal/permission/infra/db/model"
     port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+    shared_database "github.com/williamkoller/system-education/shared/infra/database"
     "gorm.io/gorm"
 )
 
@@ -20,7 +21,7 @@ var _ port_permission_repository.PermissionRepository = &PermissionGormRepositor
 
 func (r *PermissionGormRepository) Save(ctx context.Context, p *permission_entity.Permission) (*permission_entity.Permission, error) {
     model := permission_model.FromEntity(p)
-    if err := r.DB.WithContext(ctx).Create(&model).Error; err != nil {
+    if err := shared_database.Conn(ctx, r.DB).Create(&model).Error; err != nil {
         return nil, err
     }
     return permission_model.ToEntity(model), nil
@@ -29,7 +30,7 @@ func (r *PermissionGormRepository) Save(ctx context.Context, p *permission_entit
 func (r *PermissionGormRepository) FindByID(ctx context.Context, id string) (*permission_entity.Permission, error) {
     var permission *permission_entity.Permission
     model := permission_model.FromEntity(permission)
-    if err := r.DB.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+    if err := shared_database.Conn(ctx, r.DB).First(&model, "id = ?", id).Error; err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, permission_entity.ErrNotFound
         }
@@ -41,7 +42,7 @@ func (r *PermissionGormRepository) FindByID(ctx context.Context, id string) (*pe
 func (r *PermissionGormRepository) FindAll(ctx context.Context) ([]*permission_entity.Permission, error) {
     var permissions []*permission_entity.Permission
     model := permission_model.FromEntities(permissions)
-    if err := r.DB.WithContext(ctx).Find(&model).Error; err != nil {
+    if err := shared_database.Conn(ctx, r.DB).Find(&model).Error; err != nil {
         return nil, err
     }
     return permission_model.ToEntities(model), nil
@@ -49,7 +50,7 @@ func (r *PermissionGormRepository) FindAll(ctx context.Context) ([]*permission_e
 
 func (r *PermissionGormRepository) Update(ctx context.Context, id string, p *permission_entity.Permission) (*permission_entity.Permission, error) {
     model := permission_model.FromEntity(p)
-    result := r.DB.WithContext(ctx).Model(&permission_model.Permission{}).
+    result := shared_database.Conn(ctx, r.DB).Model(&permission_model.Permission{}).
         Where("id = ?", id).
         Updates(&model)
 
@@ -65,7 +66,7 @@ func (r *PermissionGormRepository) Update(ctx context.Context, id string, p *per
 }
 
 func (r *PermissionGormRepository) Delete(ctx context.Context, id string) error {
-    result := r.DB.WithContext(ctx).Unscoped().Delete(&permission_model.Permission{}, "id = ?", id)
+    result := shared_database.Conn(ctx, r.DB).Unscoped().Delete(&permission_model.Permission{}, "id = ?", id)
     if result.Error != nil {
         return result.Error
     }
@@ -78,7 +79,7 @@ func (r *PermissionGormRepository) Delete(ctx context.Context, id string) error
 func (r *PermissionGormRepository) FindPermissionByUserID(ctx context.Context, userID string) ([]*permission_entity.Permission, error) {
     var permissions []*permission_entity.Permission
     model := permission_model.FromEntities(permissions)
-    if err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Find(&model).Error; err != nil {
+    if err := shared_database.Conn(ctx, r.DB).Where("user_id = ?", userID).Find(&model).Error; err != nil {
         return nil, err
     }
     return permission_model.ToEntities(model), nil
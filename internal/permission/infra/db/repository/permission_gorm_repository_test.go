@@ -37,8 +37,7 @@ func (s *PermissionGormRepositorySuite) TestCreate() {
 	permission := &permission_entity.Permission{
 		ID:          "perm-1",
 		UserID:      "user-123",
-		Modules:     []string{"module1"},
-		Actions:     []string{"read"},
+		Grants:      []string{"module1:read"},
 		Level:       "admin",
 		Description: "test permission",
 	}
@@ -49,8 +48,7 @@ func (s *PermissionGormRepositorySuite) TestCreate() {
 	s.NotNil(createdPermission)
 	s.Equal(permission.ID, createdPermission.ID)
 	s.Equal(permission.UserID, createdPermission.UserID)
-	s.Equal(permission.Modules, createdPermission.Modules)
-	s.Equal(permission.Actions, createdPermission.Actions)
+	s.Equal(permission.Grants, createdPermission.Grants)
 	s.Equal(permission.Level, createdPermission.Level)
 	s.Equal(permission.Description, createdPermission.Description)
 }
@@ -59,8 +57,7 @@ func (s *PermissionGormRepositorySuite) TestCreate_Error() {
 	permission := &permission_entity.Permission{
 		ID:          "perm-2",
 		UserID:      "user-123",
-		Modules:     []string{"module1"},
-		Actions:     []string{"read"},
+		Grants:      []string{"module1:read"},
 		Level:       "admin",
 		Description: "test permission",
 	}
@@ -78,8 +75,7 @@ func (s *PermissionGormRepositorySuite) TestFindByID() {
 	permission := &permission_entity.Permission{
 		ID:          "perm-3",
 		UserID:      "user-123",
-		Modules:     []string{"module1"},
-		Actions:     []string{"read"},
+		Grants:      []string{"module1:read"},
 		Level:       "admin",
 		Description: "test permission",
 	}
@@ -138,8 +134,7 @@ func (s *PermissionGormRepositorySuite) TestUpdate() {
 	permission := &permission_entity.Permission{
 		ID:          "perm-6",
 		UserID:      "user-123",
-		Modules:     []string{"module1"},
-		Actions:     []string{"read"},
+		Grants:      []string{"module1:read"},
 		Level:       "admin",
 		Description: "test permission",
 	}
@@ -1,9 +1,20 @@
 package port_permission_middleware
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 )
 
 type PermissionMiddleware interface {
 	ModuleAccessMiddleware(requiredModules []string, requiredActions []string) gin.HandlerFunc
+	OwnerOrModuleAccessMiddleware(rule OwnershipRule, param string, requiredModules []string, requiredActions []string) gin.HandlerFunc
+}
+
+// OwnershipRule decides whether the principal owns the record identified by
+// id, and if so which schools the request may touch to reach it.
+type OwnershipRule interface {
+	Owns(ctx context.Context, principal *auth_entity.Claims, id string) (permission_entity.SchoolScope, bool, error)
 }
@@ -2,8 +2,8 @@ package permission_dtos
 
 type UpdatePermissionDto struct {
 	UserID      *string   `json:"user_id"`
-	Modules     *[]string `json:"modules"`
-	Actions     *[]string `json:"actions"`
+	Grants      *[]string `json:"grants"`
+	SchoolIDs   *[]string `json:"school_ids"`
 	Level       *string   `json:"level"`
 	Description *string   `json:"description"`
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
 	permission_mapper "github.com/williamkoller/system-education/internal/permission/application/mapper"
 	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	port_permission_handler "github.com/williamkoller/system-education/internal/permission/port/handler"
@@ -27,14 +28,26 @@ var _ port_permission_handler.PermissionHandler = &PermissionHandler{}
 func (h *PermissionHandler) CreatePermission(c *gin.Context) {
 	var input permission_dtos.AddPermissionDto
 
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.Status(http.StatusBadRequest)
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
 	}
 
-	p, err := h.usecase.Create(c.Request.Context(), input)
+	p, err := h.usecase.Create(c.Request.Context(), claims, input)
 	if err != nil {
+		if errors.Is(err, permission_entity.ErrLevelAboveGrantor) {
+			c.Status(http.StatusForbidden)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
 		var validationErr *permission_entity.ValidationError
 		if errors.As(err, &validationErr) {
 			c.Status(http.StatusBadRequest)
@@ -80,14 +93,26 @@ func (h *PermissionHandler) FindPermissionByUserID(c *gin.Context) {
 func (h *PermissionHandler) UpdatePermission(c *gin.Context) {
 	var input permission_dtos.UpdatePermissionDto
 
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.Status(http.StatusBadRequest)
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
 	}
 
-	p, err := h.usecase.Update(c.Request.Context(), c.Param("id"), input)
+	p, err := h.usecase.Update(c.Request.Context(), claims, c.Param("id"), input)
 	if err != nil {
+		if errors.Is(err, permission_entity.ErrLevelAboveGrantor) {
+			c.Status(http.StatusForbidden)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
 		if errors.Is(err, permission_entity.ErrNotFound) {
 			c.Status(http.StatusNotFound)
 			c.Error(err).SetType(gin.ErrorTypePublic)
@@ -110,7 +135,19 @@ func (h *PermissionHandler) UpdatePermission(c *gin.Context) {
 }
 
 func (h *PermissionHandler) DeletePermission(c *gin.Context) {
-	if err := h.usecase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.Delete(c.Request.Context(), claims, c.Param("id")); err != nil {
+		if errors.Is(err, permission_entity.ErrLevelAboveGrantor) {
+			c.Status(http.StatusForbidden)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
 		if errors.Is(err, permission_entity.ErrNotFound) {
 			c.Status(http.StatusNotFound)
 			c.Error(err).SetType(gin.ErrorTypePublic)
@@ -1,99 +1,118 @@
 package permission_middleware
 
 import (
+	"log"
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
 )
 
 var _ port_permission_middleware.PermissionMiddleware = &PermissionMiddleware{}
 
-type PermissionMiddleware struct{}
+// PermissionMiddleware checks the grants carried in the token, or with a
+// GrantRefresher the user's current grants, falling back to the token when
+// they cannot be loaded. With explain set, a 403 also says why.
+type PermissionMiddleware struct {
+	grants  port_auth_usecase.GrantRefresher
+	explain bool
+}
 
-func NewPermissionMiddleware() *PermissionMiddleware {
-	return &PermissionMiddleware{}
+func NewPermissionMiddleware(grants port_auth_usecase.GrantRefresher, explain bool) *PermissionMiddleware {
+	return &PermissionMiddleware{grants: grants, explain: explain}
 }
 
+// ModuleAccessMiddleware lets the request through when the principal holds a
+// grant for one of the required modules paired with one of the required
+// actions (see permission_entity.Decide). The schools those grants cover are
+// attached to the request context so repositories can narrow their queries
+// to them.
 func (m *PermissionMiddleware) ModuleAccessMiddleware(requiredModules []string, requiredActions []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Validate modules
-		modulesInterface, ok := c.Get("modules")
+		claims, ok := auth_middleware.Principal(c)
 		if !ok {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permissions not found in token"})
 			return
 		}
+		claims = m.refresh(c, claims)
 
-		modulesSlice, ok := modulesInterface.([]interface{})
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Invalid permissions format"})
+		decision := permission_entity.Decide(claims.Scoped(), requiredModules, requiredActions)
+		if !decision.Allowed {
+			m.deny(c, claims, decision, requiredModules, requiredActions)
 			return
 		}
 
-		userModules := make([]string, 0, len(modulesSlice))
-		for _, mod := range modulesSlice {
-			if modStr, ok := mod.(string); ok {
-				userModules = append(userModules, modStr)
-			}
-		}
-
-		hasModule := false
-		for _, required := range requiredModules {
-			for _, userMod := range userModules {
-				if userMod == required {
-					hasModule = true
-					break
-				}
-			}
-			if hasModule {
-				break
-			}
-		}
+		c.Request = c.Request.WithContext(permission_entity.WithSchoolScope(c.Request.Context(), decision.Scope))
+		c.Next()
+	}
+}
 
-		if !hasModule {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied to required modules"})
+// OwnerOrModuleAccessMiddleware behaves like ModuleAccessMiddleware but also
+// lets through a principal that rule says owns the record named by the path
+// parameter param, scoped to the schools the rule returns and marked with
+// permission_entity.WithOwnerAccess. API keys act only on their grants and
+// never through ownership.
+func (m *PermissionMiddleware) OwnerOrModuleAccessMiddleware(rule port_permission_middleware.OwnershipRule, param string, requiredModules []string, requiredActions []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := auth_middleware.Principal(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permissions not found in token"})
 			return
 		}
-
-		if len(requiredActions) > 0 {
-			actionsInterface, ok := c.Get("actions")
-			if !ok {
-				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Actions not found in token"})
-				return
-			}
-
-			actionsSlice, ok := actionsInterface.([]interface{})
-			if !ok {
-				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Invalid actions format"})
+		claims = m.refresh(c, claims)
+
+		decision := permission_entity.Decide(claims.Scoped(), requiredModules, requiredActions)
+		ownerOnly := false
+		if !decision.Allowed && !slices.Contains(claims.AuthMethods, auth_entity.AuthMethodAPIKey) {
+			scope, owned, err := rule.Owns(c.Request.Context(), claims, c.Param(param))
+			if err != nil {
+				log.Printf("checking ownership of %s for user %s: %v", c.Param(param), claims.UserID, err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Unable to check ownership"})
 				return
 			}
-
-			userActions := make([]string, 0, len(actionsSlice))
-			for _, act := range actionsSlice {
-				if actStr, ok := act.(string); ok {
-					userActions = append(userActions, actStr)
-				}
-			}
-
-			hasAction := false
-			for _, required := range requiredActions {
-				for _, userAct := range userActions {
-					if userAct == required {
-						hasAction = true
-						break
-					}
-				}
-				if hasAction {
-					break
-				}
-			}
-
-			if !hasAction {
-				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied to required actions"})
-				return
+			if owned {
+				decision = permission_entity.Decision{Allowed: true, Scope: scope}
+				ownerOnly = true
 			}
 		}
+		if !decision.Allowed {
+			m.deny(c, claims, decision, requiredModules, requiredActions)
+			return
+		}
 
+		ctx := permission_entity.WithSchoolScope(c.Request.Context(), decision.Scope)
+		if ownerOnly {
+			ctx = permission_entity.WithOwnerAccess(ctx)
+		}
+		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
+
+func (m *PermissionMiddleware) deny(c *gin.Context, claims *auth_entity.Claims, decision permission_entity.Decision, requiredModules []string, requiredActions []string) {
+	body := gin.H{"error": decision.Reason}
+	if m.explain {
+		body["reason"] = permission_entity.Explain(claims.Scoped(), requiredModules, requiredActions)
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, body)
+}
+
+// refresh swaps the principal for one with current grants so handlers that
+// check it further (such as the grantor rule) see the same grants.
+func (m *PermissionMiddleware) refresh(c *gin.Context, claims *auth_entity.Claims) *auth_entity.Claims {
+	if m.grants == nil {
+		return claims
+	}
+	refreshed, err := m.grants.Refresh(c.Request.Context(), claims)
+	if err != nil {
+		log.Printf("falling back to token grants for user %s: %v", claims.UserID, err)
+		return claims
+	}
+	auth_middleware.SetPrincipal(c, refreshed)
+	return refreshed
+}
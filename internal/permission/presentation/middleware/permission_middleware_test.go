@@ -1,16 +1,21 @@
 package permission_middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 )
 
 func TestNewPermissionMiddleware(t *testing.T) {
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 
 	assert.NotNil(t, middleware)
 	assert.IsType(t, &PermissionMiddleware{}, middleware)
@@ -19,15 +24,15 @@ func TestNewPermissionMiddleware(t *testing.T) {
 func TestModuleAccessMiddleware_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin", "user"}
 
 	// Create a test router
 	router := gin.New()
 
-	// Set modules in context BEFORE the route (simulating AuthMiddleware)
+	// Set principal in context BEFORE the route (simulating AuthMiddleware)
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin", "reports"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"admin:read", "reports:read"}})
 		c.Next()
 	})
 
@@ -48,7 +53,7 @@ func TestModuleAccessMiddleware_Success(t *testing.T) {
 func TestModuleAccessMiddleware_NoModulesInContext(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
 
 	router := gin.New()
@@ -65,43 +70,16 @@ func TestModuleAccessMiddleware_NoModulesInContext(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "Permissions not found in token")
 }
 
-func TestModuleAccessMiddleware_InvalidModulesFormat(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-
-	middleware := NewPermissionMiddleware()
-	requiredModules := []string{"admin"}
-
-	router := gin.New()
-
-	// Set invalid format BEFORE the route
-	router.Use(func(c *gin.Context) {
-		c.Set("modules", "invalid-format")
-		c.Next()
-	})
-
-	router.GET("/test", middleware.ModuleAccessMiddleware(requiredModules, []string{}), func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	})
-
-	req := httptest.NewRequest(http.MethodGet, "/test", nil)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusForbidden, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid permissions format")
-}
-
 func TestModuleAccessMiddleware_UserHasRequiredModule(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin", "user"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"user", "reports"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"user:read", "reports:read"}})
 		c.Next()
 	})
 
@@ -121,13 +99,13 @@ func TestModuleAccessMiddleware_UserHasRequiredModule(t *testing.T) {
 func TestModuleAccessMiddleware_UserDoesNotHaveRequiredModule(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin", "superuser"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"user", "reports"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"user:read", "reports:read"}})
 		c.Next()
 	})
 
@@ -147,13 +125,13 @@ func TestModuleAccessMiddleware_UserDoesNotHaveRequiredModule(t *testing.T) {
 func TestModuleAccessMiddleware_EmptyModulesList(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{}})
 		c.Next()
 	})
 
@@ -173,13 +151,13 @@ func TestModuleAccessMiddleware_EmptyModulesList(t *testing.T) {
 func TestModuleAccessMiddleware_MultipleModulesMatch(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin", "user", "reports"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin", "user", "reports", "analytics"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"admin:read", "user:read", "reports:read", "analytics:read"}})
 		c.Next()
 	})
 
@@ -196,44 +174,16 @@ func TestModuleAccessMiddleware_MultipleModulesMatch(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "success")
 }
 
-func TestModuleAccessMiddleware_ModulesWithNonStringValues(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-
-	middleware := NewPermissionMiddleware()
-	requiredModules := []string{"admin"}
-
-	router := gin.New()
-
-	router.Use(func(c *gin.Context) {
-		// Mix of string and non-string values
-		c.Set("modules", []interface{}{"user", 123, "reports", nil})
-		c.Next()
-	})
-
-	router.GET("/test", middleware.ModuleAccessMiddleware(requiredModules, []string{}), func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	})
-
-	req := httptest.NewRequest(http.MethodGet, "/test", nil)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	// Should fail because "admin" is not in the valid string modules
-	assert.Equal(t, http.StatusForbidden, w.Code)
-	assert.Contains(t, w.Body.String(), "Access denied to required modules")
-}
-
 func TestModuleAccessMiddleware_NilModuleValue(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", nil)
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{})
 		c.Next()
 	})
 
@@ -247,19 +197,19 @@ func TestModuleAccessMiddleware_NilModuleValue(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusForbidden, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid permissions format")
+	assert.Contains(t, w.Body.String(), "Access denied to required modules")
 }
 
 func TestModuleAccessMiddleware_SingleRequiredModuleMatch(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"reports"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"reports"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"reports:read"}})
 		c.Next()
 	})
 
@@ -279,14 +229,14 @@ func TestModuleAccessMiddleware_SingleRequiredModuleMatch(t *testing.T) {
 func TestModuleAccessMiddleware_CaseSensitiveModules(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"Admin"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
 		// User has "admin" (lowercase), but required is "Admin" (capitalized)
-		c.Set("modules", []interface{}{"admin"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"admin:read"}})
 		c.Next()
 	})
 
@@ -307,13 +257,13 @@ func TestModuleAccessMiddleware_CaseSensitiveModules(t *testing.T) {
 func TestModuleAccessMiddleware_SpecialCharactersInModules(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin-panel", "user_management"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin-panel", "reports"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"admin-panel:read", "reports:read"}})
 		c.Next()
 	})
 
@@ -333,13 +283,13 @@ func TestModuleAccessMiddleware_SpecialCharactersInModules(t *testing.T) {
 func TestModuleAccessMiddleware_EmptyRequiredModules(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{} // Empty required modules
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin", "user"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"admin:read", "user:read"}})
 		c.Next()
 	})
 
@@ -360,13 +310,13 @@ func TestModuleAccessMiddleware_EmptyRequiredModules(t *testing.T) {
 func TestModuleAccessMiddleware_NumericModuleNames(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"module1", "module2"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"module1", "module3"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"module1:read", "module3:read"}})
 		c.Next()
 	})
 
@@ -386,14 +336,14 @@ func TestModuleAccessMiddleware_NumericModuleNames(t *testing.T) {
 func TestModuleAccessMiddleware_FirstMatchWins(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin", "user", "reports"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
 		// User has "user" which is the second required module
-		c.Set("modules", []interface{}{"user"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"user:read"}})
 		c.Next()
 	})
 
@@ -415,15 +365,16 @@ func TestModuleAccessMiddleware_FirstMatchWins(t *testing.T) {
 func TestModuleAccessMiddleware_WithValidAction(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"read", "delete"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin"})
-		c.Set("actions", []interface{}{"read", "update"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{
+			Grants: []string{"admin:read", "admin:update"},
+		})
 		c.Next()
 	})
 
@@ -443,15 +394,16 @@ func TestModuleAccessMiddleware_WithValidAction(t *testing.T) {
 func TestModuleAccessMiddleware_WithoutRequiredAction(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"delete"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin"})
-		c.Set("actions", []interface{}{"read", "update"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{
+			Grants: []string{"admin:read", "admin:update"},
+		})
 		c.Next()
 	})
 
@@ -471,15 +423,14 @@ func TestModuleAccessMiddleware_WithoutRequiredAction(t *testing.T) {
 func TestModuleAccessMiddleware_NoActionsInContext(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"read"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin"})
-		// No actions set in context
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"admin:update"}})
 		c.Next()
 	})
 
@@ -493,21 +444,22 @@ func TestModuleAccessMiddleware_NoActionsInContext(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusForbidden, w.Code)
-	assert.Contains(t, w.Body.String(), "Actions not found in token")
+	assert.Contains(t, w.Body.String(), "Access denied to required actions")
 }
 
-func TestModuleAccessMiddleware_InvalidActionsFormat(t *testing.T) {
+func TestModuleAccessMiddleware_WildcardAction(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
-	requiredActions := []string{"read"}
+	requiredActions := []string{"delete"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin"})
-		c.Set("actions", "invalid-format")
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{
+			Grants: []string{"admin:*"},
+		})
 		c.Next()
 	})
 
@@ -520,22 +472,23 @@ func TestModuleAccessMiddleware_InvalidActionsFormat(t *testing.T) {
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusForbidden, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid actions format")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "success")
 }
 
-func TestModuleAccessMiddleware_EmptyActionsInContext(t *testing.T) {
+func TestModuleAccessMiddleware_ActionsDoNotLeakAcrossModules(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
-	requiredModules := []string{"admin"}
-	requiredActions := []string{"read"}
+	middleware := NewPermissionMiddleware(nil, false)
+	requiredModules := []string{"users"}
+	requiredActions := []string{"delete"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin"})
-		c.Set("actions", []interface{}{})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{
+			Grants: []string{"users:read", "students:delete"},
+		})
 		c.Next()
 	})
 
@@ -555,15 +508,16 @@ func TestModuleAccessMiddleware_EmptyActionsInContext(t *testing.T) {
 func TestModuleAccessMiddleware_MultipleActionsOneMatches(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"read", "delete", "update"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin"})
-		c.Set("actions", []interface{}{"delete"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{
+			Grants: []string{"admin:delete"},
+		})
 		c.Next()
 	})
 
@@ -580,48 +534,17 @@ func TestModuleAccessMiddleware_MultipleActionsOneMatches(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "success")
 }
 
-func TestModuleAccessMiddleware_ActionsWithNonStringValues(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-
-	middleware := NewPermissionMiddleware()
-	requiredModules := []string{"admin"}
-	requiredActions := []string{"read"}
-
-	router := gin.New()
-
-	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin"})
-		// Mix of string and non-string values
-		c.Set("actions", []interface{}{123, "delete", nil})
-		c.Next()
-	})
-
-	router.GET("/test", middleware.ModuleAccessMiddleware(requiredModules, requiredActions), func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	})
-
-	req := httptest.NewRequest(http.MethodGet, "/test", nil)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	// Should fail because "read" is not in the valid string actions
-	assert.Equal(t, http.StatusForbidden, w.Code)
-	assert.Contains(t, w.Body.String(), "Access denied to required actions")
-}
-
 func TestModuleAccessMiddleware_BackwardCompatibility_EmptyRequiredActions(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
 	requiredActions := []string{} // Empty - should skip action validation
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin"})
-		// No actions set - but should still pass because requiredActions is empty
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"admin:read"}})
 		c.Next()
 	})
 
@@ -641,16 +564,16 @@ func TestModuleAccessMiddleware_BackwardCompatibility_EmptyRequiredActions(t *te
 func TestModuleAccessMiddleware_CaseSensitiveActions(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"Read"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin"})
-		// User has "read" (lowercase), but required is "Read" (capitalized)
-		c.Set("actions", []interface{}{"read"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{
+			Grants: []string{"admin:read"},
+		})
 		c.Next()
 	})
 
@@ -671,15 +594,16 @@ func TestModuleAccessMiddleware_CaseSensitiveActions(t *testing.T) {
 func TestModuleAccessMiddleware_AllCRUDActions(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"users"}
 	requiredActions := []string{"create", "read", "update", "delete"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"users"})
-		c.Set("actions", []interface{}{"create", "read", "update", "delete"})
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{
+			Grants: []string{"users:create", "users:read", "users:update", "users:delete"},
+		})
 		c.Next()
 	})
 
@@ -699,15 +623,16 @@ func TestModuleAccessMiddleware_AllCRUDActions(t *testing.T) {
 func TestModuleAccessMiddleware_ModuleMatchButNoActionMatch(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(nil, false)
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"delete"}
 
 	router := gin.New()
 
 	router.Use(func(c *gin.Context) {
-		c.Set("modules", []interface{}{"admin"}) // Module matches
-		c.Set("actions", []interface{}{"read"})  // Action does not match
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{
+			Grants: []string{"admin:read"},
+		})
 		c.Next()
 	})
 
@@ -724,3 +649,232 @@ func TestModuleAccessMiddleware_ModuleMatchButNoActionMatch(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, w.Code)
 	assert.Contains(t, w.Body.String(), "Access denied to required actions")
 }
+
+func TestModuleAccessMiddleware_SchoolScopedGrant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	middleware := NewPermissionMiddleware(nil, false)
+
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		auth_middleware.SetPrincipal(c, &auth_entity.Claims{
+			Grants: []string{"schools:read"},
+			SchoolGrants: map[string][]string{
+				"school-a": {"students:read"},
+				"school-b": {"students:update"},
+			},
+		})
+		c.Next()
+	})
+
+	var scope permission_entity.SchoolScope
+	handler := func(c *gin.Context) {
+		scope = permission_entity.SchoolScopeFrom(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	}
+	router.GET("/students", middleware.ModuleAccessMiddleware([]string{"students"}, []string{"read"}), handler)
+	router.GET("/schools", middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"read"}), handler)
+	router.DELETE("/students", middleware.ModuleAccessMiddleware([]string{"students"}, []string{"delete"}), handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/students", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, permission_entity.SchoolScope{SchoolIDs: []string{"school-a"}}, scope)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/schools", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, permission_entity.AllSchools(), scope)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/students", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Access denied to required actions")
+}
+
+func TestModuleAccessMiddleware_RequiredLevel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	middleware := NewPermissionMiddleware(nil, false)
+
+	for name, tc := range map[string]struct {
+		grants []string
+		status int
+	}{
+		"editor meets editor":  {[]string{"students:read", "students:create", "students:update"}, http.StatusOK},
+		"manager meets editor": {[]string{"students:read", "students:create", "students:update", "students:delete"}, http.StatusOK},
+		"viewer below editor":  {[]string{"students:read", "students:update"}, http.StatusForbidden},
+	} {
+		t.Run(name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: tc.grants})
+				c.Next()
+			})
+			router.GET("/test", middleware.ModuleAccessMiddleware([]string{"students"}, []string{"editor"}), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+type stubRefresher struct {
+	grants []string
+	err    error
+}
+
+func (s stubRefresher) Refresh(_ context.Context, claims *auth_entity.Claims) (*auth_entity.Claims, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	refreshed := *claims
+	refreshed.Grants = s.grants
+	return &refreshed, nil
+}
+
+func TestModuleAccessMiddleware_LiveGrants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(refresher stubRefresher) (*httptest.ResponseRecorder, *auth_entity.Claims) {
+		var principal *auth_entity.Claims
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			auth_middleware.SetPrincipal(c, &auth_entity.Claims{UserID: "user-1", Grants: []string{"students:read", "students:delete"}})
+			c.Next()
+		})
+		router.DELETE("/test", NewPermissionMiddleware(refresher, false).ModuleAccessMiddleware([]string{"students"}, []string{"delete"}), func(c *gin.Context) {
+			principal, _ = auth_middleware.Principal(c)
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/test", nil))
+		return w, principal
+	}
+
+	t.Run("revoked grant is denied despite the token", func(t *testing.T) {
+		w, _ := serve(stubRefresher{grants: []string{"students:read"}})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("handlers see the refreshed principal", func(t *testing.T) {
+		w, principal := serve(stubRefresher{grants: []string{"students:delete"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"students:delete"}, principal.Grants)
+	})
+
+	t.Run("falls back to the token when grants cannot be loaded", func(t *testing.T) {
+		w, principal := serve(stubRefresher{err: errors.New("db down")})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"students:read", "students:delete"}, principal.Grants)
+	})
+}
+
+func TestModuleAccessMiddleware_ExplainReason(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(explain bool) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			auth_middleware.SetPrincipal(c, &auth_entity.Claims{Grants: []string{"students:read"}})
+			c.Next()
+		})
+		router.DELETE("/test", NewPermissionMiddleware(nil, explain).ModuleAccessMiddleware([]string{"students"}, []string{"delete"}), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/test", nil))
+		return w
+	}
+
+	w := serve(true)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{
+		"error": "Access denied to required actions",
+		"reason": "requires one of students:delete; holds students:read in every school"
+	}`, w.Body.String())
+
+	w = serve(false)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error": "Access denied to required actions"}`, w.Body.String())
+}
+
+type stubOwnership struct {
+	owner string
+	scope permission_entity.SchoolScope
+	err   error
+}
+
+func (s stubOwnership) Owns(_ context.Context, principal *auth_entity.Claims, id string) (permission_entity.SchoolScope, bool, error) {
+	if s.err != nil {
+		return permission_entity.SchoolScope{}, false, s.err
+	}
+	return s.scope, principal.UserID == s.owner && id == "record-1", nil
+}
+
+func TestOwnerOrModuleAccessMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var ownerOnly bool
+	serve := func(claims *auth_entity.Claims, rule stubOwnership, path string) (*httptest.ResponseRecorder, permission_entity.SchoolScope) {
+		var scope permission_entity.SchoolScope
+		ownerOnly = false
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			auth_middleware.SetPrincipal(c, claims)
+			c.Next()
+		})
+		router.GET("/records/:id", NewPermissionMiddleware(nil, false).OwnerOrModuleAccessMiddleware(rule, "id", []string{"students"}, []string{"read"}), func(c *gin.Context) {
+			scope = permission_entity.SchoolScopeFrom(c.Request.Context())
+			ownerOnly = permission_entity.OwnerAccess(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w, scope
+	}
+
+	owned := permission_entity.SchoolScope{SchoolIDs: []string{"school-a"}}
+
+	t.Run("grants are enough without ownership", func(t *testing.T) {
+		w, scope := serve(&auth_entity.Claims{UserID: "user-2", Grants: []string{"students:read"}}, stubOwnership{owner: "user-1", scope: owned}, "/records/record-1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, scope.All)
+		assert.False(t, ownerOnly)
+	})
+
+	t.Run("owner is let through scoped to the record", func(t *testing.T) {
+		w, scope := serve(&auth_entity.Claims{UserID: "user-1"}, stubOwnership{owner: "user-1", scope: owned}, "/records/record-1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, owned, scope)
+		assert.True(t, ownerOnly)
+	})
+
+	t.Run("someone else's record is denied", func(t *testing.T) {
+		w, _ := serve(&auth_entity.Claims{UserID: "user-1"}, stubOwnership{owner: "user-1", scope: owned}, "/records/record-2")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.JSONEq(t, `{"error": "Access denied to required modules"}`, w.Body.String())
+	})
+
+	t.Run("api keys do not act through ownership", func(t *testing.T) {
+		claims := &auth_entity.Claims{UserID: "user-1", AuthMethods: []string{auth_entity.AuthMethodAPIKey}}
+		w, _ := serve(claims, stubOwnership{owner: "user-1", scope: owned}, "/records/record-1")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("rule failure is a server error", func(t *testing.T) {
+		w, _ := serve(&auth_entity.Claims{UserID: "user-1"}, stubOwnership{err: errors.New("db down")}, "/records/record-1")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
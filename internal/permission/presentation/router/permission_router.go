@@ -1,37 +1,53 @@
 package permission_router
 
 import (
-	"time"
-
 	"github.com/gin-gonic/gin"
-	infra_cryptography "github.com/williamkoller/system-education/internal/auth/infra/cryptography"
+	audit_router "github.com/williamkoller/system-education/internal/audit/presentation/router"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
 	permission_usecase "github.com/williamkoller/system-education/internal/permission/application/usecase"
+	permission_event "github.com/williamkoller/system-education/internal/permission/domain/event"
 	permission_repository "github.com/williamkoller/system-education/internal/permission/infra/db/repository"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
 	permission_handler "github.com/williamkoller/system-education/internal/permission/presentation/handler"
-	permission_middleware "github.com/williamkoller/system-education/internal/permission/presentation/middleware"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_broker "github.com/williamkoller/system-education/shared/infra/broker"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	shared_outbox "github.com/williamkoller/system-education/shared/infra/outbox"
 	"gorm.io/gorm"
 )
 
-func PermissionRouter(e *gin.Engine, db *gorm.DB, secret string, expiresIn time.Duration) {
+// Events are the domain events this module writes to the outbox.
+var Events = []shared_event.Event{
+	&permission_event.PermissionCreatedEvent{},
+	&permission_event.PermissionUpdatedEvent{},
+	&permission_event.PermissionDeletedEvent{},
+}
+
+func PermissionRouter(e *gin.Engine, db *gorm.DB, jwt port_auth_cryptography.TokenManager, apiKeys port_auth_usecase.APIKeyAuthenticator, middleware port_permission_middleware.PermissionMiddleware, events shared_event.Subscriber, broker *shared_broker.EventPublisher) {
+	broker.Subscribe(events, Events)
+
 	repo := permission_repository.NewPermissionGormRepository(db)
-	jwt := infra_cryptography.NewJWTTokenManager(secret, expiresIn)
+	revocations := auth_repository.NewTokenRevocationGormRepository(db)
 
-	usecase := permission_usecase.NewPermissionUsecase(repo)
+	usecase := permission_usecase.NewPermissionUsecase(repo, shared_outbox.NewGormOutbox(db), shared_database.NewGormTransactor(db), audit_router.NewAuditUsecase(db))
 	handler := permission_handler.NewPermissionHandler(usecase)
-	middleware := permission_middleware.NewPermissionMiddleware()
 
 	p := e.Group("/permissions")
 	{
-		p.POST("", handler.CreatePermission)
-		p.GET("", handler.FindAllPermission)
-		p.GET("/user/:user_id", auth_middleware.AuthMiddleware(jwt),
+		p.POST("", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"create"}), handler.CreatePermission)
+		p.GET("", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}), handler.FindAllPermission)
+		p.GET("/user/:user_id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
 			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}), handler.FindPermissionByUserID)
-		p.PUT("/:id", auth_middleware.AuthMiddleware(jwt),
+		p.PUT("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
 			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"update"}), handler.UpdatePermission)
-		p.DELETE("/:id", auth_middleware.AuthMiddleware(jwt),
+		p.DELETE("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
 			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"delete"}), handler.DeletePermission)
-		p.GET("/:id", auth_middleware.AuthMiddleware(jwt),
+		p.GET("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
 			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}), handler.FindPermissionById)
 	}
 }
@@ -0,0 +1,35 @@
+package role_mapper
+
+import (
+	"time"
+
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+)
+
+type RoleResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Grants      []string  `json:"grants"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func ToRoleResponse(r *role_entity.Role) *RoleResponse {
+	return &RoleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Grants:      r.Grants,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func ToRoleResponses(rs []*role_entity.Role) []*RoleResponse {
+	responses := make([]*RoleResponse, 0, len(rs))
+	for _, r := range rs {
+		responses = append(responses, ToRoleResponse(r))
+	}
+	return responses
+}
@@ -0,0 +1,28 @@
+package role_mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+)
+
+func TestToRoleResponses(t *testing.T) {
+	now := time.Now()
+	roles := []*role_entity.Role{
+		{ID: "role-1", Name: "teacher", Description: "Teaching staff", Grants: []string{"students:read"}, CreatedAt: now, UpdatedAt: now},
+	}
+
+	responses := ToRoleResponses(roles)
+
+	assert.Len(t, responses, 1)
+	assert.Equal(t, &RoleResponse{
+		ID:          "role-1",
+		Name:        "teacher",
+		Description: "Teaching staff",
+		Grants:      []string{"students:read"},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, responses[0])
+}
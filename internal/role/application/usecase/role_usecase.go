@@ -0,0 +1,257 @@
+package role_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+	port_role_usecase "github.com/williamkoller/system-education/internal/role/port/usecase"
+	role_dtos "github.com/williamkoller/system-education/internal/role/presentation/dtos"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	port_transaction "github.com/williamkoller/system-education/shared/port/transaction"
+)
+
+const (
+	auditEntityType           = "role"
+	auditAssignmentEntityType = "user_role"
+)
+
+type RoleUsecase struct {
+	repo   port_role_repository.RoleRepository
+	users  port_user_repository.UserRepository
+	outbox shared_event.Outbox
+	tx     port_transaction.Transactor
+	audit  port_audit_usecase.Recorder
+}
+
+func NewRoleUsecase(repo port_role_repository.RoleRepository, users port_user_repository.UserRepository, outbox shared_event.Outbox, tx port_transaction.Transactor, audit port_audit_usecase.Recorder) *RoleUsecase {
+	return &RoleUsecase{repo: repo, users: users, outbox: outbox, tx: tx, audit: audit}
+}
+
+var _ port_role_usecase.RoleUsecase = &RoleUsecase{}
+
+func (r *RoleUsecase) Create(ctx context.Context, principal *auth_entity.Claims, input role_dtos.AddRoleDto) (*role_entity.Role, error) {
+	now := time.Now()
+	role, err := role_entity.NewRole(&role_entity.Role{
+		ID:          uuid.New().String(),
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		Grants:      input.Grants,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRole(principal, role); err != nil {
+		return nil, err
+	}
+
+	if err := r.ensureNameAvailable(ctx, role.Name, role.ID); err != nil {
+		return nil, err
+	}
+
+	var saved *role_entity.Role
+	err = r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		saved, err = r.repo.Save(ctx, role)
+		if err != nil {
+			return err
+		}
+		if err := r.record(ctx, role); err != nil {
+			return err
+		}
+		return r.audit.Record(ctx, audit_entity.ActionCreate, auditEntityType, saved.ID, nil, audit_entity.Snapshot(saved))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func (r *RoleUsecase) FindAll(ctx context.Context) ([]*role_entity.Role, error) {
+	return r.repo.FindAll(ctx)
+}
+
+func (r *RoleUsecase) FindByID(ctx context.Context, id string) (*role_entity.Role, error) {
+	return r.repo.FindByID(ctx, id)
+}
+
+func (r *RoleUsecase) FindByUserID(ctx context.Context, userID string) ([]*role_entity.Role, error) {
+	return r.repo.FindByUserID(ctx, userID)
+}
+
+func (r *RoleUsecase) Update(ctx context.Context, principal *auth_entity.Claims, id string, input role_dtos.UpdateRoleDto) (*role_entity.Role, error) {
+	role, err := r.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRole(principal, role); err != nil {
+		return nil, err
+	}
+	before := audit_entity.Snapshot(role)
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		input.Name = &name
+	}
+
+	if err := role.UpdateRole(input.Name, input.Description, input.Grants); err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRole(principal, role); err != nil {
+		return nil, err
+	}
+
+	if err := r.ensureNameAvailable(ctx, role.Name, role.ID); err != nil {
+		return nil, err
+	}
+
+	var updated *role_entity.Role
+	err = r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		updated, err = r.repo.Update(ctx, id, role)
+		if err != nil {
+			return err
+		}
+		if err := r.record(ctx, role); err != nil {
+			return err
+		}
+		return r.audit.Record(ctx, audit_entity.ActionUpdate, auditEntityType, id, before, audit_entity.Snapshot(updated))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (r *RoleUsecase) Delete(ctx context.Context, principal *auth_entity.Claims, id string) error {
+	role, err := r.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeRole(principal, role); err != nil {
+		return err
+	}
+
+	role.Delete()
+	return r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := r.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := r.record(ctx, role); err != nil {
+			return err
+		}
+		return r.audit.Record(ctx, audit_entity.ActionDelete, auditEntityType, id, audit_entity.Snapshot(role), nil)
+	})
+}
+
+func (r *RoleUsecase) AssignToUser(ctx context.Context, principal *auth_entity.Claims, roleID, userID string) error {
+	role, err := r.repo.FindByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeRole(principal, role); err != nil {
+		return err
+	}
+
+	if _, err := r.users.FindByID(ctx, userID); err != nil {
+		return err
+	}
+
+	role.AssignTo(userID)
+	return r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := r.repo.AssignToUser(ctx, roleID, userID); err != nil {
+			return err
+		}
+		if err := r.record(ctx, role); err != nil {
+			return err
+		}
+		return r.audit.Record(ctx, audit_entity.ActionCreate, auditAssignmentEntityType, userID, nil, assignment(role, userID))
+	})
+}
+
+func (r *RoleUsecase) UnassignFromUser(ctx context.Context, principal *auth_entity.Claims, roleID, userID string) error {
+	role, err := r.repo.FindByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeRole(principal, role); err != nil {
+		return err
+	}
+
+	role.UnassignFrom(userID)
+	return r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := r.repo.UnassignFromUser(ctx, roleID, userID); err != nil {
+			return err
+		}
+		if err := r.record(ctx, role); err != nil {
+			return err
+		}
+		return r.audit.Record(ctx, audit_entity.ActionDelete, auditAssignmentEntityType, userID, assignment(role, userID), nil)
+	})
+}
+
+func (r *RoleUsecase) record(ctx context.Context, role *role_entity.Role) error {
+	if err := r.outbox.Add(ctx, role.PullDomainEvents()...); err != nil {
+		return fmt.Errorf("failed to record role events: %w", err)
+	}
+	return nil
+}
+
+// assignment is what the audit trail keeps of a role held by a user, under
+// the user's id, so the trail shows who handed out which grants.
+func assignment(role *role_entity.Role, userID string) map[string]any {
+	return map[string]any{
+		"UserID":   userID,
+		"RoleID":   role.ID,
+		"RoleName": role.Name,
+		"Grants":   role.Grants,
+	}
+}
+
+func (r *RoleUsecase) ensureNameAvailable(ctx context.Context, name, id string) error {
+	existing, err := r.repo.FindByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, port_role_repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != id {
+		return port_role_repository.ErrAlreadyExists
+	}
+	return nil
+}
+
+// authorizeRole applies the grantor rule of direct permissions to a role.
+// Role grants are network-wide and not capped by a level, so the principal
+// must hold every one of them network-wide, at the level they reach on their
+// module. Only then may it define, change, hand out or take back the role.
+func authorizeRole(principal *auth_entity.Claims, role *role_entity.Role) error {
+	held := principal.Scoped()
+	for _, grant := range role.Grants {
+		module := permission_entity.GrantModule(grant)
+		if !held.Level(module, nil).AtLeast(permission_entity.ModuleLevel(role.Grants, module)) ||
+			!permission_entity.Covers(held.Grants, grant) {
+			return permission_entity.ErrLevelAboveGrantor
+		}
+	}
+	return nil
+}
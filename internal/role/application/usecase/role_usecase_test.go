@@ -0,0 +1,580 @@
+package role_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	audit_usecase "github.com/williamkoller/system-education/internal/audit/application/usecase"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	audit_model "github.com/williamkoller/system-education/internal/audit/infra/db/model"
+	audit_repository "github.com/williamkoller/system-education/internal/audit/infra/db/repository"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+	role_dtos "github.com/williamkoller/system-education/internal/role/presentation/dtos"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) Save(ctx context.Context, r *role_entity.Role) (*role_entity.Role, error) {
+	args := m.Called(ctx, r)
+	result, _ := args.Get(0).(*role_entity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) Update(ctx context.Context, id string, r *role_entity.Role) (*role_entity.Role, error) {
+	args := m.Called(ctx, id, r)
+	result, _ := args.Get(0).(*role_entity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) FindAll(ctx context.Context) ([]*role_entity.Role, error) {
+	args := m.Called(ctx)
+	result, _ := args.Get(0).([]*role_entity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByID(ctx context.Context, id string) (*role_entity.Role, error) {
+	args := m.Called(ctx, id)
+	result, _ := args.Get(0).(*role_entity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByName(ctx context.Context, name string) (*role_entity.Role, error) {
+	args := m.Called(ctx, name)
+	result, _ := args.Get(0).(*role_entity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByUserID(ctx context.Context, userID string) ([]*role_entity.Role, error) {
+	args := m.Called(ctx, userID)
+	result, _ := args.Get(0).([]*role_entity.Role)
+	return result, args.Error(1)
+}
+
+func (m *MockRoleRepository) AssignToUser(ctx context.Context, roleID, userID string) error {
+	args := m.Called(ctx, roleID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) UnassignFromUser(ctx context.Context, roleID, userID string) error {
+	args := m.Called(ctx, roleID, userID)
+	return args.Error(0)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Save(ctx context.Context, user *user_entity.User) (*user_entity.User, error) {
+	args := m.Called(ctx, user)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, id string, user *user_entity.User) (*user_entity.User, error) {
+	args := m.Called(ctx, id, user)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error {
+	args := m.Called(ctx, id, verifiedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*user_entity.User, error) {
+	args := m.Called(ctx, id)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*user_entity.User, error) {
+	args := m.Called(ctx, email)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserRepository) FindAll(ctx context.Context) ([]*user_entity.User, error) {
+	args := m.Called(ctx)
+	result, _ := args.Get(0).([]*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockOutbox struct {
+	mock.Mock
+}
+
+func (m *MockOutbox) Add(ctx context.Context, events ...shared_event.Event) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+func ignoreEvents() *MockOutbox {
+	events := new(MockOutbox)
+	events.On("Add", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return events
+}
+
+type MockAuditRecorder struct {
+	mock.Mock
+}
+
+func (m *MockAuditRecorder) Record(ctx context.Context, action audit_entity.Action, entityType, entityID string, before, after map[string]any) error {
+	args := m.Called(ctx, action, entityType, entityID, before, after)
+	return args.Error(0)
+}
+
+func ignoreAudit() *MockAuditRecorder {
+	audit := new(MockAuditRecorder)
+	audit.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return audit
+}
+
+// auditLog is a real audit trail over an in-memory audit_events table.
+func auditLog(t *testing.T) (*audit_usecase.AuditUsecase, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&audit_model.AuditEvent{}))
+	return audit_usecase.NewAuditUsecase(audit_repository.NewAuditGormRepository(db)), db
+}
+
+func auditRows(t *testing.T, db *gorm.DB) []audit_model.AuditEvent {
+	var rows []audit_model.AuditEvent
+	require.NoError(t, db.Order("occurred_at").Find(&rows).Error)
+	return rows
+}
+
+type stubTransactor struct{}
+
+func (stubTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func eventNamed(name string) interface{} {
+	return mock.MatchedBy(func(events []shared_event.Event) bool {
+		return len(events) == 1 && events[0].EventName() == name
+	})
+}
+
+// grantor holds every grant the roles in these tests hand out.
+func grantor() *auth_entity.Claims {
+	return &auth_entity.Claims{UserID: "admin-1", Grants: []string{"permissions:*", "students:*"}}
+}
+
+func TestRoleUsecase_Create(t *testing.T) {
+	t.Run("should create a role", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByName", mock.Anything, "teacher").Return(nil, port_role_repository.ErrNotFound)
+		repo.On("Save", mock.Anything, mock.MatchedBy(func(r *role_entity.Role) bool {
+			return r.ID != "" && r.Name == "teacher"
+		})).Return(&role_entity.Role{ID: "role-1", Name: "teacher"}, nil)
+
+		role, err := usecase.Create(context.Background(), grantor(), role_dtos.AddRoleDto{
+			Name:   " teacher ",
+			Grants: []string{"students:read"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "role-1", role.ID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a duplicated name", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByName", mock.Anything, "teacher").Return(&role_entity.Role{ID: "role-1", Name: "teacher"}, nil)
+
+		role, err := usecase.Create(context.Background(), grantor(), role_dtos.AddRoleDto{
+			Name:   "teacher",
+			Grants: []string{"students:read"},
+		})
+
+		assert.ErrorIs(t, err, port_role_repository.ErrAlreadyExists)
+		assert.Nil(t, role)
+		repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject a role without grants", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		_, err := usecase.Create(context.Background(), grantor(), role_dtos.AddRoleDto{Name: "teacher"})
+
+		var validationErr *role_entity.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("should refuse grants the principal does not hold", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+		principal := &auth_entity.Claims{UserID: "user-1", Grants: []string{"permissions:create", "students:read"}}
+
+		for _, grants := range [][]string{{"users:read"}, {"students:delete"}, {"students:*"}, {"permissions:*"}} {
+			_, err := usecase.Create(context.Background(), principal, role_dtos.AddRoleDto{Name: "escalate", Grants: grants})
+
+			assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor, "%v", grants)
+		}
+		repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should refuse a network-wide role to a school-limited principal", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+		principal := &auth_entity.Claims{
+			UserID:       "user-1",
+			Grants:       []string{"permissions:create"},
+			SchoolGrants: map[string][]string{"school-a": {"students:*"}},
+		}
+
+		_, err := usecase.Create(context.Background(), principal, role_dtos.AddRoleDto{Name: "teacher", Grants: []string{"students:read"}})
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRoleUsecase_Update(t *testing.T) {
+	t.Run("should keep its own name", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+		existing := &role_entity.Role{ID: "role-1", Name: "teacher", Grants: []string{"students:read"}}
+		grants := []string{"students:read", "students:update"}
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(existing, nil)
+		repo.On("FindByName", mock.Anything, "teacher").Return(existing, nil)
+		repo.On("Update", mock.Anything, "role-1", existing).Return(existing, nil)
+
+		role, err := usecase.Update(context.Background(), grantor(), "role-1", role_dtos.UpdateRoleDto{Grants: &grants})
+
+		assert.NoError(t, err)
+		assert.Equal(t, grants, role.Grants)
+	})
+
+	t.Run("should reject a name taken by another role", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+		name := "secretary"
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Name: "teacher", Grants: []string{"students:read"}}, nil)
+		repo.On("FindByName", mock.Anything, "secretary").Return(&role_entity.Role{ID: "role-2", Name: "secretary"}, nil)
+
+		_, err := usecase.Update(context.Background(), grantor(), "role-1", role_dtos.UpdateRoleDto{Name: &name})
+
+		assert.ErrorIs(t, err, port_role_repository.ErrAlreadyExists)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should refuse widening a role beyond the principal's grants", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+		principal := &auth_entity.Claims{UserID: "user-1", Grants: []string{"permissions:update", "students:read"}}
+		grants := []string{"students:read", "users:*"}
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Name: "teacher", Grants: []string{"students:read"}}, nil)
+
+		_, err := usecase.Update(context.Background(), principal, "role-1", role_dtos.UpdateRoleDto{Grants: &grants})
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should refuse changing a role above the principal's grants", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+		principal := &auth_entity.Claims{UserID: "user-1", Grants: []string{"permissions:update", "students:read"}}
+		grants := []string{"students:read"}
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Name: "admin", Grants: []string{"users:*"}}, nil)
+
+		_, err := usecase.Update(context.Background(), principal, "role-1", role_dtos.UpdateRoleDto{Grants: &grants})
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRoleUsecase_Delete(t *testing.T) {
+	t.Run("should delete a role the principal could grant", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"students:read"}}, nil)
+		repo.On("Delete", mock.Anything, "role-1").Return(nil)
+
+		assert.NoError(t, usecase.Delete(context.Background(), grantor(), "role-1"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should refuse deleting a role above the principal's grants", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+		principal := &auth_entity.Claims{UserID: "user-1", Grants: []string{"permissions:delete"}}
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"students:read"}}, nil)
+
+		err := usecase.Delete(context.Background(), principal, "role-1")
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRoleUsecase_AssignToUser(t *testing.T) {
+	t.Run("should assign an existing role to an existing user", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		users := new(MockUserRepository)
+		usecase := NewRoleUsecase(repo, users, ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"students:read"}}, nil)
+		users.On("FindByID", mock.Anything, "user-1").Return(&user_entity.User{ID: "user-1"}, nil)
+		repo.On("AssignToUser", mock.Anything, "role-1", "user-1").Return(nil)
+
+		assert.NoError(t, usecase.AssignToUser(context.Background(), grantor(), "role-1", "user-1"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should fail for an unknown user", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		users := new(MockUserRepository)
+		usecase := NewRoleUsecase(repo, users, ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"students:read"}}, nil)
+		users.On("FindByID", mock.Anything, "missing").Return(nil, port_user_repository.ErrUserNotFound)
+
+		err := usecase.AssignToUser(context.Background(), grantor(), "role-1", "missing")
+
+		assert.ErrorIs(t, err, port_user_repository.ErrUserNotFound)
+		repo.AssertNotCalled(t, "AssignToUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fail for an unknown role", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByID", mock.Anything, "missing").Return(nil, port_role_repository.ErrNotFound)
+
+		err := usecase.AssignToUser(context.Background(), grantor(), "missing", "user-1")
+
+		assert.ErrorIs(t, err, port_role_repository.ErrNotFound)
+	})
+
+	t.Run("should refuse assigning a role above the principal's grants", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		users := new(MockUserRepository)
+		usecase := NewRoleUsecase(repo, users, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		principal := &auth_entity.Claims{UserID: "user-1", Grants: []string{"permissions:update", "students:read"}}
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"students:*", "permissions:*"}}, nil)
+
+		err := usecase.AssignToUser(context.Background(), principal, "role-1", "user-1")
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		repo.AssertNotCalled(t, "AssignToUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRoleUsecase_UnassignFromUser(t *testing.T) {
+	t.Run("should unassign a role the principal could grant", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"students:read"}}, nil)
+		repo.On("UnassignFromUser", mock.Anything, "role-1", "user-1").Return(nil)
+
+		assert.NoError(t, usecase.UnassignFromUser(context.Background(), grantor(), "role-1", "user-1"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should refuse unassigning a role above the principal's grants", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, ignoreAudit())
+		principal := &auth_entity.Claims{UserID: "user-1", Grants: []string{"permissions:update"}}
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"users:*"}}, nil)
+
+		err := usecase.UnassignFromUser(context.Background(), principal, "role-1", "user-1")
+
+		assert.ErrorIs(t, err, permission_entity.ErrLevelAboveGrantor)
+		repo.AssertNotCalled(t, "UnassignFromUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRoleUsecase_Events(t *testing.T) {
+	t.Run("create adds role.created", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		events := new(MockOutbox)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), events, stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByName", mock.Anything, "teacher").Return(nil, port_role_repository.ErrNotFound)
+		repo.On("Save", mock.Anything, mock.Anything).Return(&role_entity.Role{ID: "role-1"}, nil)
+		events.On("Add", mock.Anything, eventNamed("role.created")).Return(nil).Once()
+
+		_, err := usecase.Create(context.Background(), grantor(), role_dtos.AddRoleDto{Name: "teacher", Grants: []string{"students:read"}})
+
+		assert.NoError(t, err)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("update adds role.updated", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		events := new(MockOutbox)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), events, stubTransactor{}, ignoreAudit())
+		existing := &role_entity.Role{ID: "role-1", Name: "teacher", Grants: []string{"students:read"}}
+		grants := []string{"students:read", "students:update"}
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(existing, nil)
+		repo.On("FindByName", mock.Anything, "teacher").Return(existing, nil)
+		repo.On("Update", mock.Anything, "role-1", existing).Return(existing, nil)
+		events.On("Add", mock.Anything, eventNamed("role.updated")).Return(nil).Once()
+
+		_, err := usecase.Update(context.Background(), grantor(), "role-1", role_dtos.UpdateRoleDto{Grants: &grants})
+
+		assert.NoError(t, err)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("delete adds role.deleted", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		events := new(MockOutbox)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), events, stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"students:read"}}, nil)
+		repo.On("Delete", mock.Anything, "role-1").Return(nil)
+		events.On("Add", mock.Anything, eventNamed("role.deleted")).Return(nil).Once()
+
+		assert.NoError(t, usecase.Delete(context.Background(), grantor(), "role-1"))
+		events.AssertExpectations(t)
+	})
+
+	t.Run("assign and unassign add role.assigned and role.unassigned", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		users := new(MockUserRepository)
+		events := new(MockOutbox)
+		usecase := NewRoleUsecase(repo, users, events, stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"students:read"}}, nil)
+		users.On("FindByID", mock.Anything, "user-1").Return(&user_entity.User{ID: "user-1"}, nil)
+		repo.On("AssignToUser", mock.Anything, "role-1", "user-1").Return(nil)
+		repo.On("UnassignFromUser", mock.Anything, "role-1", "user-1").Return(nil)
+		events.On("Add", mock.Anything, eventNamed("role.assigned")).Return(nil).Once()
+		events.On("Add", mock.Anything, eventNamed("role.unassigned")).Return(nil).Once()
+
+		assert.NoError(t, usecase.AssignToUser(context.Background(), grantor(), "role-1", "user-1"))
+		assert.NoError(t, usecase.UnassignFromUser(context.Background(), grantor(), "role-1", "user-1"))
+		events.AssertExpectations(t)
+	})
+
+	t.Run("a failing outbox fails the write", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		events := new(MockOutbox)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), events, stubTransactor{}, ignoreAudit())
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"students:read"}}, nil)
+		repo.On("Delete", mock.Anything, "role-1").Return(nil)
+		events.On("Add", mock.Anything, mock.Anything).Return(errors.New("outbox down"))
+
+		err := usecase.Delete(context.Background(), grantor(), "role-1")
+
+		assert.ErrorContains(t, err, "outbox down")
+	})
+}
+
+func TestRoleUsecase_RecordsAudit(t *testing.T) {
+	ctx := auth_entity.WithPrincipal(context.Background(), grantor())
+
+	t.Run("create, update and delete", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		audit, db := auditLog(t)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, audit)
+		existing := &role_entity.Role{ID: "role-1", Name: "teacher", Grants: []string{"students:read"}}
+		grants := []string{"students:read", "students:update"}
+
+		repo.On("FindByName", mock.Anything, "teacher").Return(nil, port_role_repository.ErrNotFound).Once()
+		repo.On("Save", mock.Anything, mock.Anything).Return(existing, nil)
+		repo.On("FindByID", mock.Anything, "role-1").Return(existing, nil)
+		repo.On("FindByName", mock.Anything, "teacher").Return(existing, nil)
+		repo.On("Update", mock.Anything, "role-1", existing).Return(existing, nil)
+		repo.On("Delete", mock.Anything, "role-1").Return(nil)
+
+		_, err := usecase.Create(ctx, grantor(), role_dtos.AddRoleDto{Name: "teacher", Grants: []string{"students:read"}})
+		require.NoError(t, err)
+		_, err = usecase.Update(ctx, grantor(), "role-1", role_dtos.UpdateRoleDto{Grants: &grants})
+		require.NoError(t, err)
+		require.NoError(t, usecase.Delete(ctx, grantor(), "role-1"))
+
+		rows := auditRows(t, db)
+		require.Len(t, rows, 3)
+		for i, action := range []audit_entity.Action{audit_entity.ActionCreate, audit_entity.ActionUpdate, audit_entity.ActionDelete} {
+			assert.Equal(t, string(action), rows[i].Action)
+			assert.Equal(t, "role", rows[i].EntityType)
+			assert.Equal(t, "role-1", rows[i].EntityID)
+			assert.Equal(t, "admin-1", *rows[i].ActorID)
+		}
+		assert.Contains(t, rows[1].Changes, "Grants")
+	})
+
+	t.Run("assign and unassign under the user", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		users := new(MockUserRepository)
+		audit, db := auditLog(t)
+		usecase := NewRoleUsecase(repo, users, ignoreEvents(), stubTransactor{}, audit)
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Name: "teacher", Grants: []string{"students:read"}}, nil)
+		users.On("FindByID", mock.Anything, "user-1").Return(&user_entity.User{ID: "user-1"}, nil)
+		repo.On("AssignToUser", mock.Anything, "role-1", "user-1").Return(nil)
+		repo.On("UnassignFromUser", mock.Anything, "role-1", "user-1").Return(nil)
+
+		require.NoError(t, usecase.AssignToUser(ctx, grantor(), "role-1", "user-1"))
+		require.NoError(t, usecase.UnassignFromUser(ctx, grantor(), "role-1", "user-1"))
+
+		rows := auditRows(t, db)
+		require.Len(t, rows, 2)
+		assert.Equal(t, "create", rows[0].Action)
+		assert.Equal(t, "delete", rows[1].Action)
+		for _, row := range rows {
+			assert.Equal(t, "user_role", row.EntityType)
+			assert.Equal(t, "user-1", row.EntityID)
+			assert.Equal(t, "admin-1", *row.ActorID)
+		}
+		assert.Equal(t, audit_entity.Change{From: nil, To: "role-1"}, rows[0].Changes["RoleID"])
+	})
+
+	t.Run("an audit failure fails the write", func(t *testing.T) {
+		repo := new(MockRoleRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewRoleUsecase(repo, new(MockUserRepository), ignoreEvents(), stubTransactor{}, audit)
+
+		repo.On("FindByID", mock.Anything, "role-1").Return(&role_entity.Role{ID: "role-1", Grants: []string{"students:read"}}, nil)
+		repo.On("Delete", mock.Anything, "role-1").Return(nil)
+		audit.On("Record", mock.Anything, audit_entity.ActionDelete, "role", "role-1", mock.Anything, mock.Anything).Return(errors.New("audit unavailable"))
+
+		assert.EqualError(t, usecase.Delete(ctx, grantor(), "role-1"), "audit unavailable")
+	})
+}
@@ -0,0 +1,83 @@
+package role_entity
+
+import (
+	"time"
+
+	role_event "github.com/williamkoller/system-education/internal/role/domain/event"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type Role struct {
+	shared_event.AggregateRoot
+	ID          string
+	Name        string
+	Description string
+	Grants      []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewRole(r *Role) (*Role, error) {
+	vr, err := ValidationRole(r)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &Role{
+		ID:          vr.ID,
+		Name:        vr.Name,
+		Description: vr.Description,
+		Grants:      vr.Grants,
+		CreatedAt:   vr.CreatedAt,
+		UpdatedAt:   vr.UpdatedAt,
+	}
+
+	role.AddDomainEvent(role_event.NewRoleCreatedEvent(role.ID, role.Name, role.Grants))
+
+	return role, nil
+}
+
+func (r *Role) UpdateRole(name, description *string, grants *[]string) error {
+	if name != nil {
+		r.Name = *name
+	}
+	if description != nil {
+		r.Description = *description
+	}
+	if grants != nil {
+		r.Grants = *grants
+	}
+
+	r.UpdatedAt = time.Now()
+
+	if _, err := ValidationRole(r); err != nil {
+		return err
+	}
+
+	r.AddDomainEvent(role_event.NewRoleUpdatedEvent(r.ID, r.Name, r.Grants))
+
+	return nil
+}
+
+// Delete records that the role is being removed; the repository does the
+// actual deletion along with its assignments.
+func (r *Role) Delete() {
+	r.AddDomainEvent(role_event.NewRoleDeletedEvent(r.ID))
+}
+
+// AssignTo records that the role is being given to a user.
+func (r *Role) AssignTo(userID string) {
+	r.AddDomainEvent(role_event.NewRoleAssignedEvent(r.ID, userID))
+}
+
+// UnassignFrom records that the role is being taken from a user.
+func (r *Role) UnassignFrom(userID string) {
+	r.AddDomainEvent(role_event.NewRoleUnassignedEvent(r.ID, userID))
+}
+
+func (r *Role) PullDomainEvents() []shared_event.Event {
+	if r == nil {
+		return nil
+	}
+	return r.AggregateRoot.PullDomainEvents()
+}
@@ -0,0 +1,80 @@
+package role_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRole(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputRole     *Role
+		expectedError string
+	}{
+		{
+			name: "Success",
+			inputRole: &Role{
+				ID:          "role-1",
+				Name:        "teacher",
+				Description: "Teaching staff",
+				Grants:      []string{"students:read", "students:update"},
+			},
+		},
+		{
+			name:          "Validation Failure",
+			inputRole:     &Role{},
+			expectedError: "validation failed: name is required, grants is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, err := NewRole(tt.inputRole)
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.inputRole.Name, role.Name)
+				assert.Equal(t, tt.inputRole.Grants, role.Grants)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.Nil(t, role)
+			}
+		})
+	}
+}
+
+func TestUpdateRole(t *testing.T) {
+	t.Run("should update only the given fields", func(t *testing.T) {
+		role := &Role{Name: "teacher", Description: "old", Grants: []string{"students:read"}}
+		name := "coordinator"
+		grants := []string{"students:read", "students:update"}
+
+		err := role.UpdateRole(&name, nil, &grants)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "coordinator", role.Name)
+		assert.Equal(t, "old", role.Description)
+		assert.Equal(t, grants, role.Grants)
+		assert.False(t, role.UpdatedAt.IsZero())
+	})
+
+	t.Run("should reject an empty grant set", func(t *testing.T) {
+		role := &Role{Name: "teacher", Grants: []string{"students:read"}}
+		grants := []string{}
+
+		err := role.UpdateRole(nil, nil, &grants)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("should reject a malformed grant", func(t *testing.T) {
+		role := &Role{Name: "teacher", Grants: []string{"students:read"}}
+		grants := []string{"students"}
+
+		err := role.UpdateRole(nil, nil, &grants)
+
+		assert.EqualError(t, err, `validation failed: grant "students" must be module:action`)
+	})
+}
@@ -0,0 +1,40 @@
+package role_entity
+
+import (
+	"fmt"
+	"strings"
+
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+type ValidationError struct {
+	Errors []string
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("validation failed: %s", strings.Join(v.Errors, ", "))
+}
+
+func ValidationRole(r *Role) (*Role, error) {
+	var errs []string
+
+	if strings.TrimSpace(r.Name) == "" {
+		errs = append(errs, "name is required")
+	}
+
+	if len(r.Grants) == 0 {
+		errs = append(errs, "grants is required")
+	}
+
+	for _, grant := range r.Grants {
+		if _, _, ok := permission_entity.ParseGrant(grant); !ok {
+			errs = append(errs, fmt.Sprintf("grant %q must be module:action", grant))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+
+	return r, nil
+}
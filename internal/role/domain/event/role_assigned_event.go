@@ -0,0 +1,29 @@
+package role_event
+
+import "time"
+
+type RoleAssignedEvent struct {
+	RoleID string
+	UserID string
+	Date   time.Time
+}
+
+func NewRoleAssignedEvent(roleID string, userID string) *RoleAssignedEvent {
+	return &RoleAssignedEvent{
+		RoleID: roleID,
+		UserID: userID,
+		Date:   time.Now(),
+	}
+}
+
+func (e *RoleAssignedEvent) EventName() string {
+	return "role.assigned"
+}
+
+func (e *RoleAssignedEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *RoleAssignedEvent) AggregateID() string {
+	return e.RoleID
+}
@@ -0,0 +1,31 @@
+package role_event
+
+import "time"
+
+type RoleCreatedEvent struct {
+	RoleID string
+	Name   string
+	Grants []string
+	Date   time.Time
+}
+
+func NewRoleCreatedEvent(roleID string, name string, grants []string) *RoleCreatedEvent {
+	return &RoleCreatedEvent{
+		RoleID: roleID,
+		Name:   name,
+		Grants: grants,
+		Date:   time.Now(),
+	}
+}
+
+func (e *RoleCreatedEvent) EventName() string {
+	return "role.created"
+}
+
+func (e *RoleCreatedEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *RoleCreatedEvent) AggregateID() string {
+	return e.RoleID
+}
@@ -0,0 +1,19 @@
+package role_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRoleCreatedEvent(t *testing.T) {
+	event := NewRoleCreatedEvent("role-1", "teacher", []string{"students:read"})
+
+	assert.Equal(t, "role-1", event.RoleID)
+	assert.Equal(t, "teacher", event.Name)
+	assert.Equal(t, []string{"students:read"}, event.Grants)
+	assert.Equal(t, "role.created", event.EventName())
+	assert.Equal(t, event.RoleID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
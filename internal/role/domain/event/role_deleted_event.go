@@ -0,0 +1,27 @@
+package role_event
+
+import "time"
+
+type RoleDeletedEvent struct {
+	RoleID string
+	Date   time.Time
+}
+
+func NewRoleDeletedEvent(roleID string) *RoleDeletedEvent {
+	return &RoleDeletedEvent{
+		RoleID: roleID,
+		Date:   time.Now(),
+	}
+}
+
+func (e *RoleDeletedEvent) EventName() string {
+	return "role.deleted"
+}
+
+func (e *RoleDeletedEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *RoleDeletedEvent) AggregateID() string {
+	return e.RoleID
+}
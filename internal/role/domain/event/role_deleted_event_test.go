@@ -0,0 +1,17 @@
+package role_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRoleDeletedEvent(t *testing.T) {
+	event := NewRoleDeletedEvent("role-1")
+
+	assert.Equal(t, "role-1", event.RoleID)
+	assert.Equal(t, "role.deleted", event.EventName())
+	assert.Equal(t, event.RoleID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
@@ -0,0 +1,29 @@
+package role_event
+
+import "time"
+
+type RoleUnassignedEvent struct {
+	RoleID string
+	UserID string
+	Date   time.Time
+}
+
+func NewRoleUnassignedEvent(roleID string, userID string) *RoleUnassignedEvent {
+	return &RoleUnassignedEvent{
+		RoleID: roleID,
+		UserID: userID,
+		Date:   time.Now(),
+	}
+}
+
+func (e *RoleUnassignedEvent) EventName() string {
+	return "role.unassigned"
+}
+
+func (e *RoleUnassignedEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *RoleUnassignedEvent) AggregateID() string {
+	return e.RoleID
+}
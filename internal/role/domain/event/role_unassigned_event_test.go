@@ -0,0 +1,18 @@
+package role_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRoleUnassignedEvent(t *testing.T) {
+	event := NewRoleUnassignedEvent("role-1", "user-1")
+
+	assert.Equal(t, "role-1", event.RoleID)
+	assert.Equal(t, "user-1", event.UserID)
+	assert.Equal(t, "role.unassigned", event.EventName())
+	assert.Equal(t, event.RoleID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
@@ -0,0 +1,31 @@
+package role_event
+
+import "time"
+
+type RoleUpdatedEvent struct {
+	RoleID string
+	Name   string
+	Grants []string
+	Date   time.Time
+}
+
+func NewRoleUpdatedEvent(roleID string, name string, grants []string) *RoleUpdatedEvent {
+	return &RoleUpdatedEvent{
+		RoleID: roleID,
+		Name:   name,
+		Grants: grants,
+		Date:   time.Now(),
+	}
+}
+
+func (e *RoleUpdatedEvent) EventName() string {
+	return "role.updated"
+}
+
+func (e *RoleUpdatedEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *RoleUpdatedEvent) AggregateID() string {
+	return e.RoleID
+}
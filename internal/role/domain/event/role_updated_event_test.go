@@ -0,0 +1,19 @@
+package role_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRoleUpdatedEvent(t *testing.T) {
+	event := NewRoleUpdatedEvent("role-1", "teacher", []string{"students:read"})
+
+	assert.Equal(t, "role-1", event.RoleID)
+	assert.Equal(t, "teacher", event.Name)
+	assert.Equal(t, []string{"students:read"}, event.Grants)
+	assert.Equal(t, "role.updated", event.EventName())
+	assert.Equal(t, event.RoleID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
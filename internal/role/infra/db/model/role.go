@@ -0,0 +1,67 @@
+package role_model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+)
+
+type Role struct {
+	ID          string `gorm:"primaryKey;type:uuid"`
+	Name        string `gorm:"uniqueIndex"`
+	Description string
+	Grants      pq.StringArray `gorm:"type:text[]"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+type UserRole struct {
+	UserID    string `gorm:"primaryKey;type:uuid"`
+	RoleID    string `gorm:"primaryKey;type:uuid"`
+	CreatedAt time.Time
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
+
+func FromEntity(r *role_entity.Role) *Role {
+	if r == nil {
+		return nil
+	}
+	return &Role{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Grants:      pq.StringArray(r.Grants),
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func ToEntity(r *Role) *role_entity.Role {
+	if r == nil {
+		return nil
+	}
+	return &role_entity.Role{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Grants:      []string(r.Grants),
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func ToEntities(rs []*Role) []*role_entity.Role {
+	entities := make([]*role_entity.Role, 0, len(rs))
+	for _, r := range rs {
+		entities = append(entities, ToEntity(r))
+	}
+	return entities
+}
@@ -0,0 +1,122 @@
+package role_repository
+
+import (
+	"context"
+	"errors"
+
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	role_model "github.com/williamkoller/system-education/internal/role/infra/db/model"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleGormRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleGormRepository(db *gorm.DB) *RoleGormRepository {
+	return &RoleGormRepository{db: db}
+}
+
+var _ port_role_repository.RoleRepository = &RoleGormRepository{}
+
+func (r *RoleGormRepository) Save(ctx context.Context, role *role_entity.Role) (*role_entity.Role, error) {
+	model := role_model.FromEntity(role)
+	if err := shared_database.Conn(ctx, r.db).Create(model).Error; err != nil {
+		return nil, err
+	}
+	return role_model.ToEntity(model), nil
+}
+
+func (r *RoleGormRepository) Update(ctx context.Context, id string, role *role_entity.Role) (*role_entity.Role, error) {
+	model := role_model.FromEntity(role)
+	result := shared_database.Conn(ctx, r.db).Model(&role_model.Role{}).
+		Where("id = ?", id).
+		Select("name", "description", "grants", "updated_at").
+		Updates(model)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, port_role_repository.ErrNotFound
+	}
+
+	return role_model.ToEntity(model), nil
+}
+
+func (r *RoleGormRepository) Delete(ctx context.Context, id string) error {
+	return shared_database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&role_model.UserRole{}, "role_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&role_model.Role{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return port_role_repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *RoleGormRepository) FindAll(ctx context.Context) ([]*role_entity.Role, error) {
+	var models []*role_model.Role
+	if err := shared_database.Conn(ctx, r.db).Order("name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return role_model.ToEntities(models), nil
+}
+
+func (r *RoleGormRepository) FindByID(ctx context.Context, id string) (*role_entity.Role, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *RoleGormRepository) FindByName(ctx context.Context, name string) (*role_entity.Role, error) {
+	return r.findOne(ctx, "name = ?", name)
+}
+
+func (r *RoleGormRepository) FindByUserID(ctx context.Context, userID string) ([]*role_entity.Role, error) {
+	var models []*role_model.Role
+	err := shared_database.Conn(ctx, r.db).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return role_model.ToEntities(models), nil
+}
+
+func (r *RoleGormRepository) AssignToUser(ctx context.Context, roleID, userID string) error {
+	return shared_database.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&role_model.UserRole{UserID: userID, RoleID: roleID}).Error
+}
+
+func (r *RoleGormRepository) UnassignFromUser(ctx context.Context, roleID, userID string) error {
+	result := shared_database.Conn(ctx, r.db).Delete(&role_model.UserRole{}, "role_id = ? AND user_id = ?", roleID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return port_role_repository.ErrAssignmentNotFound
+	}
+	return nil
+}
+
+func (r *RoleGormRepository) findOne(ctx context.Context, query string, args ...interface{}) (*role_entity.Role, error) {
+	var model role_model.Role
+	if err := shared_database.Conn(ctx, r.db).First(&model, append([]interface{}{query}, args...)...).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, port_role_repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return role_model.ToEntity(&model), nil
+}
@@ -0,0 +1,132 @@
+package role_repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	role_model "github.com/williamkoller/system-education/internal/role/infra/db/model"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type RoleGormRepositorySuite struct {
+	suite.Suite
+	db         *gorm.DB
+	repository *RoleGormRepository
+}
+
+func (s *RoleGormRepositorySuite) SetupTest() {
+	s.db = setupTestDB(s.T())
+	s.repository = NewRoleGormRepository(s.db)
+}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&role_model.Role{}, &role_model.UserRole{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func (s *RoleGormRepositorySuite) createRole(id, name string) *role_entity.Role {
+	role, err := s.repository.Save(context.Background(), &role_entity.Role{
+		ID:          id,
+		Name:        name,
+		Description: "role " + name,
+		Grants:      []string{"students:read"},
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	})
+	s.Require().NoError(err)
+	return role
+}
+
+func (s *RoleGormRepositorySuite) TestSaveAndFind() {
+	s.createRole("role-1", "teacher")
+
+	byID, err := s.repository.FindByID(context.Background(), "role-1")
+	s.NoError(err)
+	s.Equal("teacher", byID.Name)
+	s.Equal([]string{"students:read"}, byID.Grants)
+
+	byName, err := s.repository.FindByName(context.Background(), "teacher")
+	s.NoError(err)
+	s.Equal("role-1", byName.ID)
+
+	_, err = s.repository.FindByID(context.Background(), "missing")
+	s.ErrorIs(err, port_role_repository.ErrNotFound)
+}
+
+func (s *RoleGormRepositorySuite) TestFindAll() {
+	s.createRole("role-2", "secretary")
+	s.createRole("role-1", "coordinator")
+
+	roles, err := s.repository.FindAll(context.Background())
+
+	s.NoError(err)
+	s.Len(roles, 2)
+	s.Equal("coordinator", roles[0].Name)
+}
+
+func (s *RoleGormRepositorySuite) TestUpdate() {
+	role := s.createRole("role-1", "teacher")
+	role.Description = ""
+	role.Grants = []string{"students:read", "students:update"}
+
+	updated, err := s.repository.Update(context.Background(), role.ID, role)
+	s.NoError(err)
+	s.Equal([]string{"students:read", "students:update"}, updated.Grants)
+
+	found, err := s.repository.FindByID(context.Background(), role.ID)
+	s.NoError(err)
+	s.Empty(found.Description)
+	s.Equal([]string{"students:read", "students:update"}, found.Grants)
+
+	_, err = s.repository.Update(context.Background(), "missing", role)
+	s.ErrorIs(err, port_role_repository.ErrNotFound)
+}
+
+func (s *RoleGormRepositorySuite) TestAssignments() {
+	s.createRole("role-1", "teacher")
+	s.createRole("role-2", "secretary")
+
+	s.NoError(s.repository.AssignToUser(context.Background(), "role-1", "user-1"))
+	s.NoError(s.repository.AssignToUser(context.Background(), "role-1", "user-1"))
+	s.NoError(s.repository.AssignToUser(context.Background(), "role-2", "user-1"))
+	s.NoError(s.repository.AssignToUser(context.Background(), "role-2", "user-2"))
+
+	roles, err := s.repository.FindByUserID(context.Background(), "user-1")
+	s.NoError(err)
+	s.Len(roles, 2)
+
+	s.NoError(s.repository.UnassignFromUser(context.Background(), "role-1", "user-1"))
+	s.ErrorIs(s.repository.UnassignFromUser(context.Background(), "role-1", "user-1"), port_role_repository.ErrAssignmentNotFound)
+
+	roles, err = s.repository.FindByUserID(context.Background(), "user-1")
+	s.NoError(err)
+	s.Len(roles, 1)
+	s.Equal("secretary", roles[0].Name)
+}
+
+func (s *RoleGormRepositorySuite) TestDeleteRemovesAssignments() {
+	s.createRole("role-1", "teacher")
+	s.NoError(s.repository.AssignToUser(context.Background(), "role-1", "user-1"))
+
+	s.NoError(s.repository.Delete(context.Background(), "role-1"))
+	s.ErrorIs(s.repository.Delete(context.Background(), "role-1"), port_role_repository.ErrNotFound)
+
+	var count int64
+	s.db.Model(&role_model.UserRole{}).Count(&count)
+	s.Zero(count)
+}
+
+func TestRoleGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RoleGormRepositorySuite))
+}
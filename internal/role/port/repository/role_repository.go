@@ -0,0 +1,26 @@
+package port_role_repository
+
+import (
+	"context"
+	"errors"
+
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+)
+
+type RoleRepository interface {
+	Save(ctx context.Context, r *role_entity.Role) (*role_entity.Role, error)
+	Update(ctx context.Context, id string, r *role_entity.Role) (*role_entity.Role, error)
+	Delete(ctx context.Context, id string) error
+	FindAll(ctx context.Context) ([]*role_entity.Role, error)
+	FindByID(ctx context.Context, id string) (*role_entity.Role, error)
+	FindByName(ctx context.Context, name string) (*role_entity.Role, error)
+	FindByUserID(ctx context.Context, userID string) ([]*role_entity.Role, error)
+	AssignToUser(ctx context.Context, roleID, userID string) error
+	UnassignFromUser(ctx context.Context, roleID, userID string) error
+}
+
+var (
+	ErrNotFound           = errors.New("role not found")
+	ErrAlreadyExists      = errors.New("role already exists")
+	ErrAssignmentNotFound = errors.New("role is not assigned to user")
+)
@@ -0,0 +1,20 @@
+package port_role_usecase
+
+import (
+	"context"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	role_dtos "github.com/williamkoller/system-education/internal/role/presentation/dtos"
+)
+
+type RoleUsecase interface {
+	Create(ctx context.Context, principal *auth_entity.Claims, input role_dtos.AddRoleDto) (*role_entity.Role, error)
+	FindAll(ctx context.Context) ([]*role_entity.Role, error)
+	FindByID(ctx context.Context, id string) (*role_entity.Role, error)
+	FindByUserID(ctx context.Context, userID string) ([]*role_entity.Role, error)
+	Update(ctx context.Context, principal *auth_entity.Claims, id string, input role_dtos.UpdateRoleDto) (*role_entity.Role, error)
+	Delete(ctx context.Context, principal *auth_entity.Claims, id string) error
+	AssignToUser(ctx context.Context, principal *auth_entity.Claims, roleID, userID string) error
+	UnassignFromUser(ctx context.Context, principal *auth_entity.Claims, roleID, userID string) error
+}
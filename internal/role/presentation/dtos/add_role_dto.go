@@ -0,0 +1,7 @@
+package role_dtos
+
+type AddRoleDto struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Grants      []string `json:"grants" binding:"required"`
+}
@@ -0,0 +1,7 @@
+package role_dtos
+
+type UpdateRoleDto struct {
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	Grants      *[]string `json:"grants"`
+}
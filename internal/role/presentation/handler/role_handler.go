@@ -0,0 +1,174 @@
+package role_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	role_mapper "github.com/williamkoller/system-education/internal/role/application/mapper"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	port_role_handler "github.com/williamkoller/system-education/internal/role/port/handler"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+	port_role_usecase "github.com/williamkoller/system-education/internal/role/port/usecase"
+	role_dtos "github.com/williamkoller/system-education/internal/role/presentation/dtos"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type RoleHandler struct {
+	usecase port_role_usecase.RoleUsecase
+}
+
+var _ port_role_handler.RoleHandler = &RoleHandler{}
+
+func NewRoleHandler(usecase port_role_usecase.RoleUsecase) *RoleHandler {
+	return &RoleHandler{usecase: usecase}
+}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	var input role_dtos.AddRoleDto
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	role, err := h.usecase.Create(c.Request.Context(), claims, input)
+	if err != nil {
+		h.roleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, role_mapper.ToRoleResponse(role))
+}
+
+func (h *RoleHandler) FindAllRoles(c *gin.Context) {
+	roles, err := h.usecase.FindAll(c.Request.Context())
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, role_mapper.ToRoleResponses(roles))
+}
+
+func (h *RoleHandler) FindRoleByID(c *gin.Context) {
+	role, err := h.usecase.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.roleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, role_mapper.ToRoleResponse(role))
+}
+
+func (h *RoleHandler) FindRolesByUserID(c *gin.Context) {
+	roles, err := h.usecase.FindByUserID(c.Request.Context(), c.Param("user_id"))
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, role_mapper.ToRoleResponses(roles))
+}
+
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	var input role_dtos.UpdateRoleDto
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	role, err := h.usecase.Update(c.Request.Context(), claims, c.Param("id"), input)
+	if err != nil {
+		h.roleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, role_mapper.ToRoleResponse(role))
+}
+
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.Delete(c.Request.Context(), claims, c.Param("id")); err != nil {
+		h.roleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.AssignToUser(c.Request.Context(), claims, c.Param("id"), c.Param("user_id")); err != nil {
+		h.roleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleHandler) UnassignRole(c *gin.Context) {
+	claims, ok := auth_middleware.Principal(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		c.Error(errors.New("unauthorized")).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.UnassignFromUser(c.Request.Context(), claims, c.Param("id"), c.Param("user_id")); err != nil {
+		h.roleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleHandler) roleError(c *gin.Context, err error) {
+	var validationErr *role_entity.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.Status(http.StatusBadRequest)
+	case errors.Is(err, port_role_repository.ErrNotFound),
+		errors.Is(err, port_role_repository.ErrAssignmentNotFound),
+		errors.Is(err, port_user_repository.ErrUserNotFound):
+		c.Status(http.StatusNotFound)
+	case errors.Is(err, port_role_repository.ErrAlreadyExists):
+		c.Status(http.StatusConflict)
+	case errors.Is(err, permission_entity.ErrLevelAboveGrantor):
+		c.Status(http.StatusForbidden)
+	default:
+		c.Status(http.StatusInternalServerError)
+	}
+	c.Error(err).SetType(gin.ErrorTypePublic)
+}
@@ -0,0 +1,63 @@
+package role_router
+
+import (
+	"github.com/gin-gonic/gin"
+	audit_router "github.com/williamkoller/system-education/internal/audit/presentation/router"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	role_usecase "github.com/williamkoller/system-education/internal/role/application/usecase"
+	role_event "github.com/williamkoller/system-education/internal/role/domain/event"
+	role_repository "github.com/williamkoller/system-education/internal/role/infra/db/repository"
+	role_handler "github.com/williamkoller/system-education/internal/role/presentation/handler"
+	user_repository "github.com/williamkoller/system-education/internal/user/infra/db/repository"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_broker "github.com/williamkoller/system-education/shared/infra/broker"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	shared_outbox "github.com/williamkoller/system-education/shared/infra/outbox"
+	"gorm.io/gorm"
+)
+
+// Events are the domain events this module writes to the outbox.
+var Events = []shared_event.Event{
+	&role_event.RoleCreatedEvent{},
+	&role_event.RoleUpdatedEvent{},
+	&role_event.RoleDeletedEvent{},
+	&role_event.RoleAssignedEvent{},
+	&role_event.RoleUnassignedEvent{},
+}
+
+// RoleRouter registers role management. Roles are grants in disguise, so they
+// are guarded by the permissions module rather than a module of their own.
+func RoleRouter(e *gin.Engine, db *gorm.DB, jwt port_auth_cryptography.TokenManager, apiKeys port_auth_usecase.APIKeyAuthenticator, middleware port_permission_middleware.PermissionMiddleware, events shared_event.Subscriber, broker *shared_broker.EventPublisher) {
+	broker.Subscribe(events, Events)
+
+	repo := role_repository.NewRoleGormRepository(db)
+	users := user_repository.NewUserGormRepository(db)
+	revocations := auth_repository.NewTokenRevocationGormRepository(db)
+
+	usecase := role_usecase.NewRoleUsecase(repo, users, shared_outbox.NewGormOutbox(db), shared_database.NewGormTransactor(db), audit_router.NewAuditUsecase(db))
+	handler := role_handler.NewRoleHandler(usecase)
+
+	r := e.Group("/roles")
+	{
+		r.POST("", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"create"}), handler.CreateRole)
+		r.GET("", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}), handler.FindAllRoles)
+		r.GET("/user/:user_id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}), handler.FindRolesByUserID)
+		r.GET("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}), handler.FindRoleByID)
+		r.PUT("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"update"}), handler.UpdateRole)
+		r.DELETE("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"delete"}), handler.DeleteRole)
+		r.POST("/:id/users/:user_id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"update"}), handler.AssignRole)
+		r.DELETE("/:id/users/:user_id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"update"}), handler.UnassignRole)
+	}
+}
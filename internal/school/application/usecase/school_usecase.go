@@ -4,25 +4,44 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	school_entity "github.com/williamkoller/system-education/internal/school/domain/entity"
 	port_school_repository "github.com/williamkoller/system-education/internal/school/port/repository"
 	port_school_usecase "github.com/williamkoller/system-education/internal/school/port/usecase"
 	school_dtos "github.com/williamkoller/system-education/internal/school/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	port_transaction "github.com/williamkoller/system-education/shared/port/transaction"
 )
 
+const auditEntityType = "school"
+
 type SchoolUseCase struct {
-	repo port_school_repository.SchoolRepository
+	repo   port_school_repository.SchoolRepository
+	outbox shared_event.Outbox
+	tx     port_transaction.Transactor
+	audit  port_audit_usecase.Recorder
 }
 
-func NewSchoolUseCase(repo port_school_repository.SchoolRepository) *SchoolUseCase {
+func NewSchoolUseCase(repo port_school_repository.SchoolRepository, outbox shared_event.Outbox, tx port_transaction.Transactor, audit port_audit_usecase.Recorder) *SchoolUseCase {
 	return &SchoolUseCase{
-		repo: repo,
+		repo:   repo,
+		outbox: outbox,
+		tx:     tx,
+		audit:  audit,
 	}
 }
 
 var _ port_school_usecase.SchoolUseCase = &SchoolUseCase{}
 
 func (s *SchoolUseCase) Create(ctx context.Context, input school_dtos.AddSchoolDto) (*school_entity.School, error) {
+	// A new school is outside every school-limited grant, so only a
+	// network-wide grant may create one.
+	if !permission_entity.SchoolScopeFrom(ctx).All {
+		return nil, permission_entity.ErrSchoolOutOfScope
+	}
+
 	school, err := school_entity.NewSchool(&school_entity.School{
 		ID:          uuid.New().String(),
 		Name:        input.Name,
@@ -42,11 +61,9 @@ func (s *SchoolUseCase) Create(ctx context.Context, input school_dtos.AddSchoolD
 		return nil, err
 	}
 
-	school, err = s.repo.Save(ctx, school)
-	if err != nil {
-		return nil, err
-	}
-	return school, nil
+	return s.save(ctx, audit_entity.ActionCreate, nil, school, func(ctx context.Context) (*school_entity.School, error) {
+		return s.repo.Save(ctx, school)
+	})
 }
 
 func (s *SchoolUseCase) FindAll(ctx context.Context) ([]*school_entity.School, error) {
@@ -70,6 +87,7 @@ func (s *SchoolUseCase) Update(ctx context.Context, id string, update school_dto
 	if err != nil {
 		return nil, err
 	}
+	before := audit_entity.Snapshot(schoolFound)
 	err = schoolFound.UpdateSchool(
 		update.Name,
 		update.Code,
@@ -88,17 +106,40 @@ func (s *SchoolUseCase) Update(ctx context.Context, id string, update school_dto
 		return nil, err
 	}
 
-	school, err := s.repo.Update(ctx, id, schoolFound)
-	if err != nil {
-		return nil, err
-	}
-	return school, nil
+	return s.save(ctx, audit_entity.ActionUpdate, before, schoolFound, func(ctx context.Context) (*school_entity.School, error) {
+		return s.repo.Update(ctx, id, schoolFound)
+	})
 }
 
 func (s *SchoolUseCase) Delete(ctx context.Context, id string) error {
-	err := s.repo.Delete(ctx, id)
+	school, err := s.repo.FindById(ctx, id)
 	if err != nil {
 		return err
 	}
-	return nil
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, audit_entity.ActionDelete, auditEntityType, id, audit_entity.Snapshot(school), nil)
+	})
+}
+
+// save runs write and, in the same transaction, adds the school's pending
+// events to the outbox and records action against before in the audit log.
+func (s *SchoolUseCase) save(ctx context.Context, action audit_entity.Action, before map[string]any, school *school_entity.School, write func(ctx context.Context) (*school_entity.School, error)) (*school_entity.School, error) {
+	var saved *school_entity.School
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if saved, err = write(ctx); err != nil {
+			return err
+		}
+		if err := s.outbox.Add(ctx, school.PullDomainEvents()...); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, action, auditEntityType, saved.ID, before, audit_entity.Snapshot(saved))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
 }
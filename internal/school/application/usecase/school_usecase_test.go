@@ -7,8 +7,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	school_entity "github.com/williamkoller/system-education/internal/school/domain/entity"
 	school_dtos "github.com/williamkoller/system-education/internal/school/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
 )
 
 type MockSchoolRepository struct {
@@ -52,10 +55,52 @@ func (m *MockSchoolRepository) FindById(ctx context.Context, id string) (*school
 	return args.Get(0).(*school_entity.School), args.Error(1)
 }
 
+type MockAuditRecorder struct {
+	mock.Mock
+}
+
+func (m *MockAuditRecorder) Record(ctx context.Context, action audit_entity.Action, entityType, entityID string, before, after map[string]any) error {
+	args := m.Called(ctx, action, entityType, entityID, before, after)
+	return args.Error(0)
+}
+
+func ignoreAudit() *MockAuditRecorder {
+	audit := new(MockAuditRecorder)
+	audit.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return audit
+}
+
+type MockOutbox struct {
+	mock.Mock
+}
+
+func (m *MockOutbox) Add(ctx context.Context, events ...shared_event.Event) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+func ignoreEvents() *MockOutbox {
+	events := new(MockOutbox)
+	events.On("Add", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return events
+}
+
+type stubTransactor struct{}
+
+func (stubTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func eventNamed(name string) interface{} {
+	return mock.MatchedBy(func(events []shared_event.Event) bool {
+		return len(events) == 1 && events[0].EventName() == name
+	})
+}
+
 func TestSchoolUseCase_Create(t *testing.T) {
 	t.Run("should create school successfully", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		input := school_dtos.AddSchoolDto{
 			Name:        "Test School",
@@ -86,7 +131,7 @@ func TestSchoolUseCase_Create(t *testing.T) {
 			Description: input.Description,
 		}, nil)
 
-		school, err := usecase.Create(context.Background(), input)
+		school, err := usecase.Create(networkWide(), input)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, school)
@@ -96,13 +141,13 @@ func TestSchoolUseCase_Create(t *testing.T) {
 
 	t.Run("should return error when validation fails", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		input := school_dtos.AddSchoolDto{
 			Name: "", // Invalid: Name is required
 		}
 
-		school, err := usecase.Create(context.Background(), input)
+		school, err := usecase.Create(networkWide(), input)
 
 		assert.Error(t, err)
 		assert.Nil(t, school)
@@ -111,7 +156,7 @@ func TestSchoolUseCase_Create(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		input := school_dtos.AddSchoolDto{
 			Name:        "Test School",
@@ -129,18 +174,30 @@ func TestSchoolUseCase_Create(t *testing.T) {
 
 		mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*school_entity.School")).Return(nil, errors.New("db error"))
 
-		school, err := usecase.Create(context.Background(), input)
+		school, err := usecase.Create(networkWide(), input)
 
 		assert.Error(t, err)
 		assert.Nil(t, school)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("should require a network-wide grant", func(t *testing.T) {
+		mockRepo := new(MockSchoolRepository)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		ctx := permission_entity.WithSchoolScope(context.Background(), permission_entity.SchoolScope{SchoolIDs: []string{"school-1"}})
+
+		school, err := usecase.Create(ctx, school_dtos.AddSchoolDto{Name: "Test School", Code: "TS001"})
+
+		assert.ErrorIs(t, err, permission_entity.ErrSchoolOutOfScope)
+		assert.Nil(t, school)
+		mockRepo.AssertNotCalled(t, "Save")
+	})
 }
 
 func TestSchoolUseCase_FindAll(t *testing.T) {
 	t.Run("should return all schools", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		expectedSchools := []*school_entity.School{
 			{ID: "1", Name: "School 1"},
@@ -149,7 +206,7 @@ func TestSchoolUseCase_FindAll(t *testing.T) {
 
 		mockRepo.On("FindAll", mock.Anything).Return(expectedSchools, nil)
 
-		schools, err := usecase.FindAll(context.Background())
+		schools, err := usecase.FindAll(networkWide())
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedSchools, schools)
@@ -158,11 +215,11 @@ func TestSchoolUseCase_FindAll(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		mockRepo.On("FindAll", mock.Anything).Return(nil, errors.New("db error"))
 
-		schools, err := usecase.FindAll(context.Background())
+		schools, err := usecase.FindAll(networkWide())
 
 		assert.Error(t, err)
 		assert.Nil(t, schools)
@@ -173,13 +230,13 @@ func TestSchoolUseCase_FindAll(t *testing.T) {
 func TestSchoolUseCase_FindById(t *testing.T) {
 	t.Run("should return school by id", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		expectedSchool := &school_entity.School{ID: "123", Name: "Test School"}
 
 		mockRepo.On("FindById", mock.Anything, "123").Return(expectedSchool, nil)
 
-		school, err := usecase.FindById(context.Background(), "123")
+		school, err := usecase.FindById(networkWide(), "123")
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedSchool, school)
@@ -188,11 +245,11 @@ func TestSchoolUseCase_FindById(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		mockRepo.On("FindById", mock.Anything, "123").Return(nil, errors.New("db error"))
 
-		school, err := usecase.FindById(context.Background(), "123")
+		school, err := usecase.FindById(networkWide(), "123")
 
 		assert.Error(t, err)
 		assert.Nil(t, school)
@@ -203,7 +260,7 @@ func TestSchoolUseCase_FindById(t *testing.T) {
 func TestSchoolUseCase_Update(t *testing.T) {
 	t.Run("should update school successfully", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
 		existingSchool := &school_entity.School{
@@ -248,7 +305,7 @@ func TestSchoolUseCase_Update(t *testing.T) {
 			return s.ID == id && s.Name == *updateDto.Name && s.Description == *updateDto.Description
 		})).Return(updatedSchool, nil)
 
-		school, err := usecase.Update(context.Background(), id, updateDto)
+		school, err := usecase.Update(networkWide(), id, updateDto)
 
 		assert.NoError(t, err)
 		assert.Equal(t, updatedSchool, school)
@@ -257,7 +314,7 @@ func TestSchoolUseCase_Update(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
 		existingSchool := &school_entity.School{
@@ -285,7 +342,7 @@ func TestSchoolUseCase_Update(t *testing.T) {
 			return s.ID == id && s.Name == *updateDto.Name
 		})).Return(nil, errors.New("db error"))
 
-		school, err := usecase.Update(context.Background(), id, updateDto)
+		school, err := usecase.Update(networkWide(), id, updateDto)
 
 		assert.Error(t, err)
 		assert.Nil(t, school)
@@ -293,7 +350,7 @@ func TestSchoolUseCase_Update(t *testing.T) {
 	})
 	t.Run("should return error when validation fails", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
 		existingSchool := &school_entity.School{
@@ -318,7 +375,7 @@ func TestSchoolUseCase_Update(t *testing.T) {
 
 		mockRepo.On("FindById", mock.Anything, id).Return(existingSchool, nil)
 
-		school, err := usecase.Update(context.Background(), id, updateDto)
+		school, err := usecase.Update(networkWide(), id, updateDto)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "name is required")
@@ -330,13 +387,14 @@ func TestSchoolUseCase_Update(t *testing.T) {
 func TestSchoolUseCase_Delete(t *testing.T) {
 	t.Run("should delete school successfully", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
 
+		mockRepo.On("FindById", mock.Anything, id).Return(&school_entity.School{ID: id}, nil)
 		mockRepo.On("Delete", mock.Anything, id).Return(nil)
 
-		err := usecase.Delete(context.Background(), id)
+		err := usecase.Delete(networkWide(), id)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
@@ -344,15 +402,166 @@ func TestSchoolUseCase_Delete(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockSchoolRepository)
-		usecase := NewSchoolUseCase(mockRepo)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
 
 		id := "123"
 
+		mockRepo.On("FindById", mock.Anything, id).Return(&school_entity.School{ID: id}, nil)
 		mockRepo.On("Delete", mock.Anything, id).Return(errors.New("db error"))
 
-		err := usecase.Delete(context.Background(), id)
+		err := usecase.Delete(networkWide(), id)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestSchoolUseCase_Outbox(t *testing.T) {
+	newSchool := func() *school_entity.School {
+		return &school_entity.School{
+			ID:          "123",
+			Name:        "Test School",
+			Code:        "TS001",
+			Address:     "123 Test St",
+			City:        "Test City",
+			State:       "TS",
+			ZipCode:     "12345",
+			Country:     "Test Country",
+			PhoneNumber: "1234567890",
+			Email:       "test@school.com",
+			IsActive:    true,
+			Description: "A test school",
+		}
+	}
+
+	t.Run("create adds school.created", func(t *testing.T) {
+		mockRepo := new(MockSchoolRepository)
+		events := new(MockOutbox)
+		usecase := NewSchoolUseCase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*school_entity.School")).Return(newSchool(), nil)
+		events.On("Add", mock.Anything, eventNamed("school.created")).Return(nil).Once()
+
+		school := newSchool()
+		_, err := usecase.Create(networkWide(), school_dtos.AddSchoolDto{
+			Name:        school.Name,
+			Code:        school.Code,
+			Address:     school.Address,
+			City:        school.City,
+			State:       school.State,
+			ZipCode:     school.ZipCode,
+			Country:     school.Country,
+			PhoneNumber: school.PhoneNumber,
+			Email:       school.Email,
+			IsActive:    school.IsActive,
+			Description: school.Description,
+		})
+
+		assert.NoError(t, err)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("update adds school.updated", func(t *testing.T) {
+		mockRepo := new(MockSchoolRepository)
+		events := new(MockOutbox)
+		usecase := NewSchoolUseCase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindById", mock.Anything, "123").Return(newSchool(), nil)
+		mockRepo.On("Update", mock.Anything, "123", mock.Anything).Return(newSchool(), nil)
+		events.On("Add", mock.Anything, eventNamed("school.updated")).Return(nil).Once()
+
+		name := "New Name"
+		_, err := usecase.Update(networkWide(), "123", school_dtos.UpdateSchoolDto{Name: &name})
+
+		assert.NoError(t, err)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("a failed write adds nothing", func(t *testing.T) {
+		mockRepo := new(MockSchoolRepository)
+		events := new(MockOutbox)
+		usecase := NewSchoolUseCase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindById", mock.Anything, "123").Return(newSchool(), nil)
+		mockRepo.On("Update", mock.Anything, "123", mock.Anything).Return(nil, errors.New("db error"))
+
+		name := "New Name"
+		_, err := usecase.Update(networkWide(), "123", school_dtos.UpdateSchoolDto{Name: &name})
+
+		assert.Error(t, err)
+		events.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
+
+	t.Run("an outbox failure fails the write", func(t *testing.T) {
+		mockRepo := new(MockSchoolRepository)
+		events := new(MockOutbox)
+		audit := new(MockAuditRecorder)
+		usecase := NewSchoolUseCase(mockRepo, events, stubTransactor{}, audit)
+
+		mockRepo.On("FindById", mock.Anything, "123").Return(newSchool(), nil)
+		mockRepo.On("Update", mock.Anything, "123", mock.Anything).Return(newSchool(), nil)
+		events.On("Add", mock.Anything, mock.Anything).Return(errors.New("outbox unavailable"))
+
+		name := "New Name"
+		school, err := usecase.Update(networkWide(), "123", school_dtos.UpdateSchoolDto{Name: &name})
+
+		assert.Nil(t, school)
+		assert.EqualError(t, err, "outbox unavailable")
+		audit.AssertNotCalled(t, "Record")
+	})
+}
+
+func TestSchoolUseCase_Audit(t *testing.T) {
+	t.Run("update records the changed fields", func(t *testing.T) {
+		mockRepo := new(MockSchoolRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, audit)
+
+		existing := &school_entity.School{
+			ID:          "123",
+			Name:        "Old Name",
+			Code:        "OLD",
+			Address:     "Old Address",
+			City:        "Old City",
+			State:       "OS",
+			ZipCode:     "12345",
+			Country:     "Old Country",
+			PhoneNumber: "1234567890",
+			Email:       "old@school.com",
+			Description: "Old Description",
+		}
+		updated := *existing
+		updated.Name = "New Name"
+		mockRepo.On("FindById", mock.Anything, "123").Return(existing, nil)
+		mockRepo.On("Update", mock.Anything, "123", mock.Anything).Return(&updated, nil)
+		audit.On("Record", mock.Anything, audit_entity.ActionUpdate, "school", "123", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				changes := audit_entity.Diff(args.Get(4).(map[string]any), args.Get(5).(map[string]any))
+				assert.Equal(t, audit_entity.Change{From: "Old Name", To: "New Name"}, changes["Name"])
+				assert.NotContains(t, changes, "Code")
+			}).Return(nil).Once()
+
+		name := "New Name"
+		_, err := usecase.Update(networkWide(), "123", school_dtos.UpdateSchoolDto{Name: &name})
+
+		assert.NoError(t, err)
+		audit.AssertExpectations(t)
+	})
+
+	t.Run("failed delete records nothing", func(t *testing.T) {
+		mockRepo := new(MockSchoolRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewSchoolUseCase(mockRepo, ignoreEvents(), stubTransactor{}, audit)
+
+		mockRepo.On("FindById", mock.Anything, "123").Return(&school_entity.School{ID: "123"}, nil)
+		mockRepo.On("Delete", mock.Anything, "123").Return(errors.New("db error"))
+
+		assert.Error(t, usecase.Delete(networkWide(), "123"))
+		audit.AssertNotCalled(t, "Record")
+	})
+}
+
+// networkWide is the context of a request authorized in every school.
+func networkWide() context.Context {
+	return permission_entity.WithSchoolScope(context.Background(), permission_entity.AllSchools())
+}
@@ -43,3 +43,7 @@ func (e *SchoolCreatedEvent) EventName() string {
 func (e *SchoolCreatedEvent) OccurredOn() time.Time {
 	return e.Date
 }
+
+func (e *SchoolCreatedEvent) AggregateID() string {
+	return e.SchoolID
+}
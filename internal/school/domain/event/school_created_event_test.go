@@ -43,6 +43,7 @@ func TestNewSchoolCreatedEvent(t *testing.T) {
 func TestSchoolCreatedEvent_EventName(t *testing.T) {
 	event := &SchoolCreatedEvent{}
 	assert.Equal(t, "school.created", event.EventName())
+	assert.Equal(t, event.SchoolID, event.AggregateID())
 }
 
 func TestSchoolCreatedEvent_OccurredOn(t *testing.T) {
@@ -43,3 +43,7 @@ func (e *SchoolUpdatedEvent) EventName() string {
 func (e *SchoolUpdatedEvent) OccurredOn() time.Time {
 	return e.Date
 }
+
+func (e *SchoolUpdatedEvent) AggregateID() string {
+	return e.SchoolID
+}
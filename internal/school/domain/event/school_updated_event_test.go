@@ -26,6 +26,7 @@ func TestNewSchoolUpdatedEvent(t *testing.T) {
 
 		assert.NotNil(t, event)
 		assert.Equal(t, schoolID, event.SchoolID)
+		assert.Equal(t, schoolID, event.AggregateID())
 		assert.Equal(t, name, event.Name)
 		assert.Equal(t, code, event.Code)
 		assert.Equal(t, address, event.Address)
@@ -3,9 +3,11 @@ package school_repository
 import (
 	"context"
 
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	school_entity "github.com/williamkoller/system-education/internal/school/domain/entity"
 	school_model "github.com/williamkoller/system-education/internal/school/infra/db/model"
 	port_school_repository "github.com/williamkoller/system-education/internal/school/port/repository"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
 	"gorm.io/gorm"
 )
 
@@ -21,7 +23,7 @@ var _ port_school_repository.SchoolRepository = &SchoolGormRepository{}
 
 func (r *SchoolGormRepository) Save(ctx context.Context, s *school_entity.School) (*school_entity.School, error) {
 	model := school_model.FromEntity(s)
-	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+	if err := shared_database.Conn(ctx, r.db).Create(&model).Error; err != nil {
 		return nil, err
 	}
 	return school_model.ToEntity(model), nil
@@ -29,7 +31,7 @@ func (r *SchoolGormRepository) Save(ctx context.Context, s *school_entity.School
 
 func (r *SchoolGormRepository) Update(ctx context.Context, id string, s *school_entity.School) (*school_entity.School, error) {
 	model := school_model.FromEntity(s)
-	result := r.db.WithContext(ctx).Model(&school_model.School{}).Where("id = ?", id).Updates(&model)
+	result := r.scoped(ctx).Model(&school_model.School{}).Where("id = ?", id).Updates(&model)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -43,7 +45,7 @@ func (r *SchoolGormRepository) Update(ctx context.Context, id string, s *school_
 }
 
 func (r *SchoolGormRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Unscoped().Delete(&school_model.School{}, "id = ?", id)
+	result := r.scoped(ctx).Unscoped().Delete(&school_model.School{}, "id = ?", id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -56,7 +58,7 @@ func (r *SchoolGormRepository) Delete(ctx context.Context, id string) error {
 func (r *SchoolGormRepository) FindAll(ctx context.Context) ([]*school_entity.School, error) {
 	var schools []*school_entity.School
 	models := school_model.FromEntities(schools)
-	if err := r.db.WithContext(ctx).Find(&models).Error; err != nil {
+	if err := r.scoped(ctx).Find(&models).Error; err != nil {
 		return nil, err
 	}
 	return school_model.ToEntities(models), nil
@@ -66,7 +68,7 @@ func (r *SchoolGormRepository) FindById(ctx context.Context, id string) (*school
 	var school *school_entity.School
 	model := school_model.FromEntity(school)
 
-	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+	if err := r.scoped(ctx).First(&model, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, port_school_repository.ErrNotFound
 		}
@@ -75,3 +77,13 @@ func (r *SchoolGormRepository) FindById(ctx context.Context, id string) (*school
 
 	return school_model.ToEntity(model), nil
 }
+
+// scoped limits queries to the schools in the request's scope; a school
+// outside it behaves as if it did not exist.
+func (r *SchoolGormRepository) scoped(ctx context.Context) *gorm.DB {
+	db := shared_database.Conn(ctx, r.db)
+	if scope := permission_entity.SchoolScopeFrom(ctx); !scope.All {
+		db = db.Where("id IN ?", scope.SchoolIDs)
+	}
+	return db
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	school_entity "github.com/williamkoller/system-education/internal/school/domain/entity"
 	school_model "github.com/williamkoller/system-education/internal/school/infra/db/model"
 	port_school_repository "github.com/williamkoller/system-education/internal/school/port/repository"
@@ -18,11 +19,13 @@ type SchoolGormRepositorySuite struct {
 	suite.Suite
 	db         *gorm.DB
 	repository *SchoolGormRepository
+	ctx        context.Context
 }
 
 func (s *SchoolGormRepositorySuite) SetupTest() {
 	s.db = setupTestDB(s.T())
 	s.repository = NewSchoolGormRepository(s.db)
+	s.ctx = permission_entity.WithSchoolScope(context.Background(), permission_entity.AllSchools())
 }
 
 func setupTestDB(t *testing.T) *gorm.DB {
@@ -53,7 +56,7 @@ func (s *SchoolGormRepositorySuite) TestCreate() {
 		UpdatedAt:   time.Now(),
 	}
 
-	createdSchool, err := s.repository.Save(context.Background(), school)
+	createdSchool, err := s.repository.Save(s.ctx, school)
 
 	s.NoError(err)
 	s.NotNil(createdSchool)
@@ -71,7 +74,7 @@ func (s *SchoolGormRepositorySuite) TestCreate_Error() {
 	sqlDB, _ := s.db.DB()
 	sqlDB.Close()
 
-	createdSchool, err := s.repository.Save(context.Background(), school)
+	createdSchool, err := s.repository.Save(s.ctx, school)
 
 	s.Error(err)
 	s.Nil(createdSchool)
@@ -85,16 +88,16 @@ func (s *SchoolGormRepositorySuite) TestUpdate() {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-	created, _ := s.repository.Save(context.Background(), school)
+	created, _ := s.repository.Save(s.ctx, school)
 
 	created.Name = "Updated School Name"
-	updatedSchool, err := s.repository.Update(context.Background(), created.ID, created)
+	updatedSchool, err := s.repository.Update(s.ctx, created.ID, created)
 
 	s.NoError(err)
 	s.NotNil(updatedSchool)
 	s.Equal("Updated School Name", updatedSchool.Name)
 
-	found, _ := s.repository.FindById(context.Background(), created.ID)
+	found, _ := s.repository.FindById(s.ctx, created.ID)
 	s.Equal("Updated School Name", found.Name)
 }
 
@@ -104,7 +107,7 @@ func (s *SchoolGormRepositorySuite) TestUpdate_NotFound() {
 		Name: "Not Found School",
 	}
 
-	updatedSchool, err := s.repository.Update(context.Background(), "non-existent-id", school)
+	updatedSchool, err := s.repository.Update(s.ctx, "non-existent-id", school)
 
 	s.Error(err)
 	s.Equal(port_school_repository.ErrNotFound, err)
@@ -118,12 +121,12 @@ func (s *SchoolGormRepositorySuite) TestUpdate_DBError() {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-	created, _ := s.repository.Save(context.Background(), school)
+	created, _ := s.repository.Save(s.ctx, school)
 
 	sqlDB, _ := s.db.DB()
 	sqlDB.Close()
 
-	updatedSchool, err := s.repository.Update(context.Background(), created.ID, created)
+	updatedSchool, err := s.repository.Update(s.ctx, created.ID, created)
 
 	s.Error(err)
 	s.NotEqual(port_school_repository.ErrNotFound, err)
@@ -137,20 +140,20 @@ func (s *SchoolGormRepositorySuite) TestDelete() {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-	created, _ := s.repository.Save(context.Background(), school)
+	created, _ := s.repository.Save(s.ctx, school)
 
-	err := s.repository.Delete(context.Background(), created.ID)
+	err := s.repository.Delete(s.ctx, created.ID)
 
 	s.NoError(err)
 
-	found, err := s.repository.FindById(context.Background(), created.ID)
+	found, err := s.repository.FindById(s.ctx, created.ID)
 	s.Error(err)
 	s.Equal(port_school_repository.ErrNotFound, err)
 	s.Nil(found)
 }
 
 func (s *SchoolGormRepositorySuite) TestDelete_NotFound() {
-	err := s.repository.Delete(context.Background(), "non-existent-id")
+	err := s.repository.Delete(s.ctx, "non-existent-id")
 
 	s.Error(err)
 	s.Equal(port_school_repository.ErrNotFound, err)
@@ -160,7 +163,7 @@ func (s *SchoolGormRepositorySuite) TestDelete_DBError() {
 	sqlDB, _ := s.db.DB()
 	sqlDB.Close()
 
-	err := s.repository.Delete(context.Background(), "some-id")
+	err := s.repository.Delete(s.ctx, "some-id")
 
 	s.Error(err)
 	s.NotEqual(port_school_repository.ErrNotFound, err)
@@ -169,20 +172,46 @@ func (s *SchoolGormRepositorySuite) TestDelete_DBError() {
 func (s *SchoolGormRepositorySuite) TestFindAll() {
 	school1 := &school_entity.School{ID: "school-7", Name: "School 1", CreatedAt: time.Now(), UpdatedAt: time.Now()}
 	school2 := &school_entity.School{ID: "school-8", Name: "School 2", CreatedAt: time.Now(), UpdatedAt: time.Now()}
-	s.repository.Save(context.Background(), school1)
-	s.repository.Save(context.Background(), school2)
+	s.repository.Save(s.ctx, school1)
+	s.repository.Save(s.ctx, school2)
 
-	schools, err := s.repository.FindAll(context.Background())
+	schools, err := s.repository.FindAll(s.ctx)
 
 	s.NoError(err)
 	s.Len(schools, 2)
 }
 
+func (s *SchoolGormRepositorySuite) TestSchoolScope() {
+	schoolA := &school_entity.School{ID: "school-a", Name: "School A", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	schoolB := &school_entity.School{ID: "school-b", Name: "School B", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	s.repository.Save(s.ctx, schoolA)
+	s.repository.Save(s.ctx, schoolB)
+
+	ctx := permission_entity.WithSchoolScope(context.Background(), permission_entity.SchoolScope{SchoolIDs: []string{"school-a"}})
+
+	schools, err := s.repository.FindAll(ctx)
+	s.NoError(err)
+	s.Len(schools, 1)
+	s.Equal("school-a", schools[0].ID)
+
+	_, err = s.repository.FindById(ctx, "school-b")
+	s.ErrorIs(err, port_school_repository.ErrNotFound)
+
+	_, err = s.repository.Update(ctx, "school-b", &school_entity.School{Name: "Renamed"})
+	s.ErrorIs(err, port_school_repository.ErrNotFound)
+
+	s.ErrorIs(s.repository.Delete(ctx, "school-b"), port_school_repository.ErrNotFound)
+
+	schools, err = s.repository.FindAll(context.Background())
+	s.NoError(err)
+	s.Empty(schools, "a context without a scope reaches no school")
+}
+
 func (s *SchoolGormRepositorySuite) TestFindAll_Error() {
 	sqlDB, _ := s.db.DB()
 	sqlDB.Close()
 
-	schools, err := s.repository.FindAll(context.Background())
+	schools, err := s.repository.FindAll(s.ctx)
 
 	s.Error(err)
 	s.Nil(schools)
@@ -195,9 +224,9 @@ func (s *SchoolGormRepositorySuite) TestFindById() {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-	created, _ := s.repository.Save(context.Background(), school)
+	created, _ := s.repository.Save(s.ctx, school)
 
-	foundSchool, err := s.repository.FindById(context.Background(), created.ID)
+	foundSchool, err := s.repository.FindById(s.ctx, created.ID)
 
 	s.NoError(err)
 	s.NotNil(foundSchool)
@@ -206,7 +235,7 @@ func (s *SchoolGormRepositorySuite) TestFindById() {
 }
 
 func (s *SchoolGormRepositorySuite) TestFindById_NotFound() {
-	foundSchool, err := s.repository.FindById(context.Background(), "non-existent-id")
+	foundSchool, err := s.repository.FindById(s.ctx, "non-existent-id")
 
 	s.Error(err)
 	s.Equal(port_school_repository.ErrNotFound, err)
@@ -217,7 +246,7 @@ func (s *SchoolGormRepositorySuite) TestFindById_DBError() {
 	sqlDB, _ := s.db.DB()
 	sqlDB.Close()
 
-	foundSchool, err := s.repository.FindById(context.Background(), "some-id")
+	foundSchool, err := s.repository.FindById(s.ctx, "some-id")
 
 	s.Error(err)
 	s.NotEqual(port_school_repository.ErrNotFound, err)
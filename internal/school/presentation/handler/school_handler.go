@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	school_mapper "github.com/williamkoller/system-education/internal/school/application/mapper"
 	school_entity "github.com/williamkoller/system-education/internal/school/domain/entity"
 	port_school_handler "github.com/williamkoller/system-education/internal/school/port/handler"
@@ -35,6 +36,11 @@ func (s *SchoolHandler) CreateSchool(c *gin.Context) {
 
 	school, err := s.usecase.Create(c.Request.Context(), input)
 	if err != nil {
+		if errors.Is(err, permission_entity.ErrSchoolOutOfScope) {
+			c.Status(http.StatusForbidden)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
 		var validationErr *school_entity.ValidationError
 		if errors.As(err, &validationErr) {
 			c.Status(http.StatusBadRequest)
@@ -1,31 +1,44 @@
 package school_router
 
 import (
-	"time"
-
 	"github.com/gin-gonic/gin"
-	infra_cryptography "github.com/williamkoller/system-education/internal/auth/infra/cryptography"
+	audit_router "github.com/williamkoller/system-education/internal/audit/presentation/router"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
-	permission_middleware "github.com/williamkoller/system-education/internal/permission/presentation/middleware"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
 	school_usecase "github.com/williamkoller/system-education/internal/school/application/usecase"
+	school_event "github.com/williamkoller/system-education/internal/school/domain/event"
 	school_repository "github.com/williamkoller/system-education/internal/school/infra/db/repository"
 	school_handler "github.com/williamkoller/system-education/internal/school/presentation/handler"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_broker "github.com/williamkoller/system-education/shared/infra/broker"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	shared_outbox "github.com/williamkoller/system-education/shared/infra/outbox"
 	"gorm.io/gorm"
 )
 
-func SchoolRouter(g *gin.Engine, db *gorm.DB, secret string, expiresIn time.Duration) {
+// Events are the domain events this module writes to the outbox.
+var Events = []shared_event.Event{
+	&school_event.SchoolCreatedEvent{},
+	&school_event.SchoolUpdatedEvent{},
+}
+
+func SchoolRouter(g *gin.Engine, db *gorm.DB, jwt port_auth_cryptography.TokenManager, apiKeys port_auth_usecase.APIKeyAuthenticator, middleware port_permission_middleware.PermissionMiddleware, events shared_event.Subscriber, broker *shared_broker.EventPublisher) {
+	broker.Subscribe(events, Events)
+
 	schools := g.Group("/schools")
 	repo := school_repository.NewSchoolGormRepository(db)
-	usecase := school_usecase.NewSchoolUseCase(repo)
+	usecase := school_usecase.NewSchoolUseCase(repo, shared_outbox.NewGormOutbox(db), shared_database.NewGormTransactor(db), audit_router.NewAuditUsecase(db))
 	handler := school_handler.NewSchoolHandler(usecase)
-	middleware := permission_middleware.NewPermissionMiddleware()
-	jwt := infra_cryptography.NewJWTTokenManager(secret, expiresIn)
+	revocations := auth_repository.NewTokenRevocationGormRepository(db)
 
 	{
-		schools.POST("", auth_middleware.AuthMiddleware(jwt), middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"create"}), handler.CreateSchool)
-		schools.GET("", auth_middleware.AuthMiddleware(jwt), middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"read"}), handler.FindAllSchool)
-		schools.GET("/:id", auth_middleware.AuthMiddleware(jwt), middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"read"}), handler.FindByIdSchool)
-		schools.PUT("/:id", auth_middleware.AuthMiddleware(jwt), middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"update"}), handler.UpdateSchool)
-		schools.DELETE("/:id", auth_middleware.AuthMiddleware(jwt), middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"delete"}), handler.DeleteSchool)
+		schools.POST("", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"create"}), handler.CreateSchool)
+		schools.GET("", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"read"}), handler.FindAllSchool)
+		schools.GET("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"read"}), handler.FindByIdSchool)
+		schools.PUT("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"update"}), handler.UpdateSchool)
+		schools.DELETE("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"schools"}, []string{"delete"}), handler.DeleteSchool)
 	}
 }
@@ -3,18 +3,28 @@ package student_usecase
 import (
 	"context"
 
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	student_entity "github.com/williamkoller/system-education/internal/student/domain/entity"
 	port_student_repository "github.com/williamkoller/system-education/internal/student/port/repository"
 	port_student_usecase "github.com/williamkoller/system-education/internal/student/port/usecase"
 	student_dtos "github.com/williamkoller/system-education/internal/student/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	port_transaction "github.com/williamkoller/system-education/shared/port/transaction"
 )
 
+const auditEntityType = "student"
+
 type StudentUsecase struct {
-	repo port_student_repository.StudentRepository
+	repo   port_student_repository.StudentRepository
+	outbox shared_event.Outbox
+	tx     port_transaction.Transactor
+	audit  port_audit_usecase.Recorder
 }
 
-func NewStudentUsecase(repo port_student_repository.StudentRepository) *StudentUsecase {
-	return &StudentUsecase{repo: repo}
+func NewStudentUsecase(repo port_student_repository.StudentRepository, outbox shared_event.Outbox, tx port_transaction.Transactor, audit port_audit_usecase.Recorder) *StudentUsecase {
+	return &StudentUsecase{repo: repo, outbox: outbox, tx: tx, audit: audit}
 }
 
 var _ port_student_usecase.StudentUsecase = &StudentUsecase{}
@@ -61,7 +71,13 @@ func (s *StudentUsecase) Create(ctx context.Context, input student_dtos.AddStude
 		return nil, err
 	}
 
-	return s.repo.Save(ctx, newStudent)
+	if !permission_entity.SchoolScopeFrom(ctx).Includes(newStudent.School.SchoolID) {
+		return nil, permission_entity.ErrSchoolOutOfScope
+	}
+
+	return s.save(ctx, audit_entity.ActionCreate, nil, newStudent, func(ctx context.Context) (*student_entity.Student, error) {
+		return s.repo.Save(ctx, newStudent)
+	})
 }
 
 func (s *StudentUsecase) FindAll(ctx context.Context) ([]*student_entity.Student, error) {
@@ -77,6 +93,7 @@ func (s *StudentUsecase) Update(ctx context.Context, id string, input student_dt
 	if err != nil {
 		return nil, err
 	}
+	before := audit_entity.Snapshot(studentFound)
 
 	err = studentFound.Update(
 		input.FullName,
@@ -110,9 +127,49 @@ func (s *StudentUsecase) Update(ctx context.Context, id string, input student_dt
 		return nil, err
 	}
 
-	return s.repo.Update(ctx, id, studentFound)
+	// Moving a student requires the grant at the destination school too.
+	if !permission_entity.SchoolScopeFrom(ctx).Includes(studentFound.School.SchoolID) {
+		return nil, permission_entity.ErrSchoolOutOfScope
+	}
+
+	return s.save(ctx, audit_entity.ActionUpdate, before, studentFound, func(ctx context.Context) (*student_entity.Student, error) {
+		return s.repo.Update(ctx, id, studentFound)
+	})
 }
 
 func (s *StudentUsecase) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	student, err := s.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	student.Delete()
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := s.outbox.Add(ctx, student.PullDomainEvents()...); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, audit_entity.ActionDelete, auditEntityType, id, audit_entity.Snapshot(student), nil)
+	})
+}
+
+// save runs write and, in the same transaction, adds the student's pending
+// events to the outbox and records action against before in the audit log.
+func (s *StudentUsecase) save(ctx context.Context, action audit_entity.Action, before map[string]any, student *student_entity.Student, write func(ctx context.Context) (*student_entity.Student, error)) (*student_entity.Student, error) {
+	var saved *student_entity.Student
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if saved, err = write(ctx); err != nil {
+			return err
+		}
+		if err := s.outbox.Add(ctx, student.PullDomainEvents()...); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, action, auditEntityType, saved.ID, before, audit_entity.Snapshot(saved))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
 }
@@ -8,10 +8,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	student_usecase "github.com/williamkoller/system-education/internal/student/application/usecase"
 	student_entity "github.com/williamkoller/system-education/internal/student/domain/entity"
+	student_event "github.com/williamkoller/system-education/internal/student/domain/event"
 	port_student_repository "github.com/williamkoller/system-education/internal/student/port/repository"
 	student_dtos "github.com/williamkoller/system-education/internal/student/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
 )
 
 type MockStudentRepository struct {
@@ -55,11 +59,61 @@ func (m *MockStudentRepository) Delete(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+type MockAuditRecorder struct {
+	mock.Mock
+}
+
+func (m *MockAuditRecorder) Record(ctx context.Context, action audit_entity.Action, entityType, entityID string, before, after map[string]any) error {
+	args := m.Called(ctx, action, entityType, entityID, before, after)
+	return args.Error(0)
+}
+
+func ignoreAudit() *MockAuditRecorder {
+	audit := new(MockAuditRecorder)
+	audit.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return audit
+}
+
+type MockOutbox struct {
+	mock.Mock
+}
+
+func (m *MockOutbox) Add(ctx context.Context, events ...shared_event.Event) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+func ignoreEvents() *MockOutbox {
+	events := new(MockOutbox)
+	events.On("Add", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return events
+}
+
+type stubTransactor struct{}
+
+func (stubTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func eventsNamed(names ...string) interface{} {
+	return mock.MatchedBy(func(events []shared_event.Event) bool {
+		if len(events) != len(names) {
+			return false
+		}
+		for i, event := range events {
+			if event.EventName() != names[i] {
+				return false
+			}
+		}
+		return true
+	})
+}
+
 func TestStudentUsecase_Create(t *testing.T) {
 	t.Run("should create student successfully", func(t *testing.T) {
 		mockRepo := new(MockStudentRepository)
-		usecase := student_usecase.NewStudentUsecase(mockRepo)
-		ctx := context.Background()
+		usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		ctx := networkWide()
 
 		input := student_dtos.AddStudentDto{
 			FullName:       "John Doe",
@@ -107,8 +161,8 @@ func TestStudentUsecase_Create(t *testing.T) {
 
 	t.Run("should return error when validation fails", func(t *testing.T) {
 		mockRepo := new(MockStudentRepository)
-		usecase := student_usecase.NewStudentUsecase(mockRepo)
-		ctx := context.Background()
+		usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		ctx := networkWide()
 
 		input := student_dtos.AddStudentDto{
 			FullName: "", // Invalid: empty name
@@ -123,8 +177,8 @@ func TestStudentUsecase_Create(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockStudentRepository)
-		usecase := student_usecase.NewStudentUsecase(mockRepo)
-		ctx := context.Background()
+		usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		ctx := networkWide()
 
 		input := student_dtos.AddStudentDto{
 			FullName:       "John Doe",
@@ -165,11 +219,50 @@ func TestStudentUsecase_Create(t *testing.T) {
 	})
 }
 
+func TestStudentUsecase_Create_OutsideSchoolScope(t *testing.T) {
+	mockRepo := new(MockStudentRepository)
+	usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+	ctx := permission_entity.WithSchoolScope(context.Background(), permission_entity.SchoolScope{SchoolIDs: []string{"school-2"}})
+
+	input := student_dtos.AddStudentDto{
+		FullName:       "John Doe",
+		EnrollmentCode: "2023001",
+		Email:          "john@example.com",
+		PhoneNumber:    "1234567890",
+		DateOfBirth:    time.Now().AddDate(-10, 0, 0),
+		CPF:            "97093236014",
+		RG:             "1234567",
+		Address:        "123 Main St",
+		City:           "City",
+		State:          "ST",
+		ZipCode:        "12345",
+		Country:        "Country",
+		SchoolID:       "school-1",
+		SchoolName:     "School Name",
+		SchoolCode:     "SC001",
+		Grade:          "5th",
+		ClassRoom:      "A",
+		Shift:          "morning",
+		EnrollmentDate: time.Now(),
+		GuardianName:   "Guardian",
+		GuardianPhone:  "0987654321",
+		GuardianEmail:  "guardian@example.com",
+		GuardianCPF:    "97093236014",
+		IsActive:       true,
+	}
+
+	result, err := usecase.Create(ctx, input)
+
+	assert.ErrorIs(t, err, permission_entity.ErrSchoolOutOfScope)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
 func TestStudentUsecase_FindAll(t *testing.T) {
 	t.Run("should return all students", func(t *testing.T) {
 		mockRepo := new(MockStudentRepository)
-		usecase := student_usecase.NewStudentUsecase(mockRepo)
-		ctx := context.Background()
+		usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		ctx := networkWide()
 
 		expectedStudents := []*student_entity.Student{
 			{ID: "1", PersonalInfo: student_entity.PersonalInfo{FullName: "Student 1"}},
@@ -188,8 +281,8 @@ func TestStudentUsecase_FindAll(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockStudentRepository)
-		usecase := student_usecase.NewStudentUsecase(mockRepo)
-		ctx := context.Background()
+		usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		ctx := networkWide()
 
 		mockRepo.On("FindAll", ctx).Return(([]*student_entity.Student)(nil), errors.New("db error"))
 
@@ -204,8 +297,8 @@ func TestStudentUsecase_FindAll(t *testing.T) {
 func TestStudentUsecase_FindById(t *testing.T) {
 	t.Run("should return student by id", func(t *testing.T) {
 		mockRepo := new(MockStudentRepository)
-		usecase := student_usecase.NewStudentUsecase(mockRepo)
-		ctx := context.Background()
+		usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		ctx := networkWide()
 		id := "123"
 
 		expectedStudent := &student_entity.Student{ID: id, PersonalInfo: student_entity.PersonalInfo{FullName: "Student 1"}}
@@ -221,8 +314,8 @@ func TestStudentUsecase_FindById(t *testing.T) {
 
 	t.Run("should return error when student not found", func(t *testing.T) {
 		mockRepo := new(MockStudentRepository)
-		usecase := student_usecase.NewStudentUsecase(mockRepo)
-		ctx := context.Background()
+		usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		ctx := networkWide()
 		id := "123"
 
 		mockRepo.On("FindById", ctx, id).Return((*student_entity.Student)(nil), port_student_repository.ErrNotFound)
@@ -239,8 +332,8 @@ func TestStudentUsecase_FindById(t *testing.T) {
 func TestStudentUsecase_Update(t *testing.T) {
 	// Setup
 	mockRepo := new(MockStudentRepository)
-	usecase := student_usecase.NewStudentUsecase(mockRepo)
-	ctx := context.Background()
+	usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+	ctx := networkWide()
 
 	// Data
 	studentID := "student-123"
@@ -289,6 +382,18 @@ func TestStudentUsecase_Update(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("should not move a student to a school outside the scope", func(t *testing.T) {
+		scoped := permission_entity.WithSchoolScope(ctx, permission_entity.SchoolScope{SchoolIDs: []string{"school-1"}})
+		otherSchool := "school-2"
+		mockRepo.On("FindById", scoped, studentID).Return(existingStudent, nil)
+
+		result, err := usecase.Update(scoped, studentID, student_dtos.UpdateStudentDto{SchoolID: &otherSchool})
+
+		assert.ErrorIs(t, err, permission_entity.ErrSchoolOutOfScope)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "Update", scoped, studentID, mock.Anything)
+	})
+
 	t.Run("should return error when student not found", func(t *testing.T) {
 		mockRepo.On("FindById", ctx, "unknown").Return((*student_entity.Student)(nil), port_student_repository.ErrNotFound)
 
@@ -302,10 +407,11 @@ func TestStudentUsecase_Update(t *testing.T) {
 func TestStudentUsecase_Delete(t *testing.T) {
 	t.Run("should delete student successfully", func(t *testing.T) {
 		mockRepo := new(MockStudentRepository)
-		usecase := student_usecase.NewStudentUsecase(mockRepo)
-		ctx := context.Background()
+		usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		ctx := networkWide()
 		id := "123"
 
+		mockRepo.On("FindById", ctx, id).Return(&student_entity.Student{ID: id}, nil)
 		mockRepo.On("Delete", ctx, id).Return(nil)
 
 		err := usecase.Delete(ctx, id)
@@ -316,10 +422,11 @@ func TestStudentUsecase_Delete(t *testing.T) {
 
 	t.Run("should return error when delete fails", func(t *testing.T) {
 		mockRepo := new(MockStudentRepository)
-		usecase := student_usecase.NewStudentUsecase(mockRepo)
-		ctx := context.Background()
+		usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, ignoreAudit())
+		ctx := networkWide()
 		id := "123"
 
+		mockRepo.On("FindById", ctx, id).Return(&student_entity.Student{ID: id}, nil)
 		mockRepo.On("Delete", ctx, id).Return(errors.New("delete error"))
 
 		err := usecase.Delete(ctx, id)
@@ -329,3 +436,208 @@ func TestStudentUsecase_Delete(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestStudentUsecase_Update_RecordsAudit(t *testing.T) {
+	mockRepo := new(MockStudentRepository)
+	audit := new(MockAuditRecorder)
+	usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, audit)
+	ctx := networkWide()
+
+	existing := &student_entity.Student{
+		ID: "student-123",
+		PersonalInfo: student_entity.PersonalInfo{
+			FullName:    "John Doe",
+			Email:       "john@example.com",
+			CPF:         "97093236014",
+			DateOfBirth: time.Now().AddDate(-10, 0, 0),
+		},
+		Address: student_entity.AddressInfo{
+			Address: "123 Main St",
+			City:    "New York",
+			State:   "NY",
+			ZipCode: "10001",
+			Country: "USA",
+		},
+		School:   student_entity.SchoolInfo{SchoolID: "school-1", Shift: student_entity.StudentShiftMorning},
+		Guardian: student_entity.GuardianInfo{Name: "Jane Doe", CPF: "97093236014"},
+		IsActive: true,
+	}
+	mockRepo.On("FindById", ctx, "student-123").Return(existing, nil)
+	mockRepo.On("Update", ctx, "student-123", mock.Anything).Return(existing, nil)
+
+	var changes audit_entity.Changes
+	audit.On("Record", ctx, audit_entity.ActionUpdate, "student", "student-123", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			changes = audit_entity.Diff(args.Get(4).(map[string]any), args.Get(5).(map[string]any))
+		}).Return(nil).Once()
+
+	cpf := "11144477735"
+	_, err := usecase.Update(ctx, "student-123", student_dtos.UpdateStudentDto{CPF: &cpf})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "97093236014", changes["PersonalInfo.CPF"].From)
+	assert.Equal(t, "111.444.777-35", changes["PersonalInfo.CPF"].To)
+	assert.NotContains(t, changes, "PersonalInfo.FullName")
+}
+
+func TestStudentUsecase_Outbox(t *testing.T) {
+	existingStudent := func() *student_entity.Student {
+		return &student_entity.Student{
+			ID: "student-123",
+			PersonalInfo: student_entity.PersonalInfo{
+				FullName:    "John Doe",
+				Email:       "john@example.com",
+				CPF:         "111.444.777-35",
+				DateOfBirth: time.Now().AddDate(-10, 0, 0),
+			},
+			Address: student_entity.AddressInfo{
+				Address: "123 Main St",
+				City:    "New York",
+				State:   "NY",
+				ZipCode: "10001",
+				Country: "USA",
+			},
+			School:   student_entity.SchoolInfo{SchoolID: "school-1", Shift: student_entity.StudentShiftMorning},
+			Guardian: student_entity.GuardianInfo{Name: "Jane Doe", CPF: "111.444.777-35"},
+			IsActive: true,
+		}
+	}
+
+	t.Run("create adds student.enrolled", func(t *testing.T) {
+		mockRepo := new(MockStudentRepository)
+		events := new(MockOutbox)
+		usecase := student_usecase.NewStudentUsecase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("Save", mock.Anything, mock.Anything).Return(existingStudent(), nil)
+		events.On("Add", mock.Anything, eventsNamed("student.enrolled")).Return(nil).Once()
+
+		_, err := usecase.Create(networkWide(), student_dtos.AddStudentDto{
+			FullName:       "John Doe",
+			EnrollmentCode: "2023001",
+			Email:          "john@example.com",
+			PhoneNumber:    "1234567890",
+			DateOfBirth:    time.Now().AddDate(-10, 0, 0),
+			CPF:            "97093236014",
+			Address:        "123 Main St",
+			City:           "City",
+			State:          "ST",
+			ZipCode:        "12345",
+			Country:        "Country",
+			SchoolID:       "school-1",
+			SchoolName:     "School Name",
+			SchoolCode:     "SC001",
+			Grade:          "5th",
+			ClassRoom:      "A",
+			Shift:          "morning",
+			EnrollmentDate: time.Now(),
+			GuardianName:   "Guardian",
+			GuardianCPF:    "97093236014",
+			IsActive:       true,
+		})
+
+		assert.NoError(t, err)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("update adds student.updated with the change set", func(t *testing.T) {
+		mockRepo := new(MockStudentRepository)
+		events := new(MockOutbox)
+		usecase := student_usecase.NewStudentUsecase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindById", mock.Anything, "student-123").Return(existingStudent(), nil)
+		mockRepo.On("Update", mock.Anything, "student-123", mock.Anything).Return(existingStudent(), nil)
+		events.On("Add", mock.Anything, mock.MatchedBy(func(events []shared_event.Event) bool {
+			if len(events) != 1 {
+				return false
+			}
+			updated, ok := events[0].(*student_event.StudentUpdatedEvent)
+			return ok && len(updated.Changes) == 1 && updated.Changes["grade"] == student_event.FieldChange{From: "", To: "6th"}
+		})).Return(nil).Once()
+
+		grade := "6th"
+		_, err := usecase.Update(networkWide(), "student-123", student_dtos.UpdateStudentDto{Grade: &grade})
+
+		assert.NoError(t, err)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("update adds transfer and deactivation", func(t *testing.T) {
+		mockRepo := new(MockStudentRepository)
+		events := new(MockOutbox)
+		usecase := student_usecase.NewStudentUsecase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindById", mock.Anything, "student-123").Return(existingStudent(), nil)
+		mockRepo.On("Update", mock.Anything, "student-123", mock.Anything).Return(existingStudent(), nil)
+		events.On("Add", mock.Anything, eventsNamed("student.updated", "student.transferred", "student.deactivated")).Return(nil).Once()
+
+		school := "school-2"
+		active := false
+		_, err := usecase.Update(networkWide(), "student-123", student_dtos.UpdateStudentDto{SchoolID: &school, IsActive: &active})
+
+		assert.NoError(t, err)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("delete adds student.deleted", func(t *testing.T) {
+		mockRepo := new(MockStudentRepository)
+		events := new(MockOutbox)
+		usecase := student_usecase.NewStudentUsecase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindById", mock.Anything, "student-123").Return(existingStudent(), nil)
+		mockRepo.On("Delete", mock.Anything, "student-123").Return(nil)
+		events.On("Add", mock.Anything, eventsNamed("student.deleted")).Return(nil).Once()
+
+		assert.NoError(t, usecase.Delete(networkWide(), "student-123"))
+		events.AssertExpectations(t)
+	})
+
+	t.Run("a failed write adds nothing", func(t *testing.T) {
+		mockRepo := new(MockStudentRepository)
+		events := new(MockOutbox)
+		usecase := student_usecase.NewStudentUsecase(mockRepo, events, stubTransactor{}, ignoreAudit())
+
+		mockRepo.On("FindById", mock.Anything, "student-123").Return(existingStudent(), nil)
+		mockRepo.On("Delete", mock.Anything, "student-123").Return(errors.New("db error"))
+
+		assert.Error(t, usecase.Delete(networkWide(), "student-123"))
+		events.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
+
+	t.Run("an outbox failure fails the write", func(t *testing.T) {
+		mockRepo := new(MockStudentRepository)
+		events := new(MockOutbox)
+		audit := new(MockAuditRecorder)
+		usecase := student_usecase.NewStudentUsecase(mockRepo, events, stubTransactor{}, audit)
+
+		mockRepo.On("FindById", mock.Anything, "student-123").Return(existingStudent(), nil)
+		mockRepo.On("Update", mock.Anything, "student-123", mock.Anything).Return(existingStudent(), nil)
+		events.On("Add", mock.Anything, mock.Anything).Return(errors.New("outbox unavailable"))
+
+		grade := "6th"
+		student, err := usecase.Update(networkWide(), "student-123", student_dtos.UpdateStudentDto{Grade: &grade})
+
+		assert.Nil(t, student)
+		assert.EqualError(t, err, "outbox unavailable")
+		audit.AssertNotCalled(t, "Record")
+	})
+	t.Run("an audit failure fails the write", func(t *testing.T) {
+		mockRepo := new(MockStudentRepository)
+		audit := new(MockAuditRecorder)
+		usecase := student_usecase.NewStudentUsecase(mockRepo, ignoreEvents(), stubTransactor{}, audit)
+
+		mockRepo.On("FindById", mock.Anything, "student-123").Return(existingStudent(), nil)
+		mockRepo.On("Update", mock.Anything, "student-123", mock.Anything).Return(existingStudent(), nil)
+		audit.On("Record", mock.Anything, audit_entity.ActionUpdate, "student", "student-123", mock.Anything, mock.Anything).Return(errors.New("audit unavailable"))
+
+		grade := "6th"
+		student, err := usecase.Update(networkWide(), "student-123", student_dtos.UpdateStudentDto{Grade: &grade})
+
+		assert.Nil(t, student)
+		assert.EqualError(t, err, "audit unavailable")
+	})
+}
+
+// networkWide is the context of a request authorized in every school.
+func networkWide() context.Context {
+	return permission_entity.WithSchoolScope(context.Background(), permission_entity.AllSchools())
+}
@@ -1,9 +1,11 @@
 package student_entity
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	student_event "github.com/williamkoller/system-education/internal/student/domain/event"
 	shared_event "github.com/williamkoller/system-education/shared/domain/event"
 )
 
@@ -88,6 +90,7 @@ func NewStudent(s *Student) (*Student, error) {
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
+	student.AddDomainEvent(student_event.NewStudentEnrolledEvent(student.ID, student.PersonalInfo.FullName, student.PersonalInfo.EnrollmentCode, student.School.SchoolID, student.School.Grade, student.School.ClassRoom, string(student.School.Shift), student.School.EnrollmentDate))
 	return student, nil
 }
 
@@ -118,6 +121,10 @@ func (s *Student) Update(
 	isActive *bool,
 	observations *string,
 ) error {
+	before := s.fields()
+	fromSchoolID := s.School.SchoolID
+	wasActive := s.IsActive
+
 	// Personal Info
 	if fullName != nil {
 		s.PersonalInfo.FullName = *fullName
@@ -209,5 +216,83 @@ func (s *Student) Update(
 		return err
 	}
 
+	changes := diffFields(before, s.fields())
+	if len(changes) == 0 {
+		return nil
+	}
+	s.AddDomainEvent(student_event.NewStudentUpdatedEvent(s.ID, s.School.SchoolID, changes))
+	if s.School.SchoolID != fromSchoolID {
+		s.AddDomainEvent(student_event.NewStudentTransferredEvent(s.ID, fromSchoolID, s.School.SchoolID))
+	}
+	if wasActive && !s.IsActive {
+		s.AddDomainEvent(student_event.NewStudentDeactivatedEvent(s.ID, s.School.SchoolID))
+	}
+
 	return nil
 }
+
+// Delete records that the student is being removed; the repository does the
+// actual deletion.
+func (s *Student) Delete() {
+	s.AddDomainEvent(student_event.NewStudentDeletedEvent(s.ID, s.School.SchoolID, s.PersonalInfo.EnrollmentCode))
+}
+
+// fields flattens the updatable fields under the names the API uses, so
+// student.updated can say which of them changed.
+func (s *Student) fields() map[string]any {
+	return map[string]any{
+		"full_name":       s.PersonalInfo.FullName,
+		"enrollment_code": s.PersonalInfo.EnrollmentCode,
+		"email":           s.PersonalInfo.Email,
+		"phone_number":    s.PersonalInfo.PhoneNumber,
+		"date_of_birth":   s.PersonalInfo.DateOfBirth,
+		"cpf":             s.PersonalInfo.CPF,
+		"rg":              s.PersonalInfo.RG,
+		"address":         s.Address.Address,
+		"city":            s.Address.City,
+		"state":           s.Address.State,
+		"zip_code":        s.Address.ZipCode,
+		"country":         s.Address.Country,
+		"school_id":       s.School.SchoolID,
+		"school_name":     s.School.SchoolName,
+		"school_code":     s.School.SchoolCode,
+		"grade":           s.School.Grade,
+		"class_room":      s.School.ClassRoom,
+		"shift":           string(s.School.Shift),
+		"enrollment_date": s.School.EnrollmentDate,
+		"guardian_name":   s.Guardian.Name,
+		"guardian_phone":  s.Guardian.Phone,
+		"guardian_email":  s.Guardian.Email,
+		"guardian_cpf":    s.Guardian.CPF,
+		"is_active":       s.IsActive,
+		"observations":    s.Observations,
+	}
+}
+
+func diffFields(before, after map[string]any) map[string]student_event.FieldChange {
+	changes := make(map[string]student_event.FieldChange)
+	for name, from := range before {
+		to := after[name]
+		if equalField(from, to) {
+			continue
+		}
+		changes[name] = student_event.FieldChange{From: from, To: to}
+	}
+	return changes
+}
+
+// equalField compares times by instant: a date read back from the database
+// carries a different location than the one sent in a request.
+func equalField(a, b any) bool {
+	if at, ok := a.(time.Time); ok {
+		bt, ok := b.(time.Time)
+		return ok && at.Equal(bt)
+	}
+	return a == b
+}
+
+// IsGuardedBy reports whether email is the guardian's contact address.
+func (s *Student) IsGuardedBy(email string) bool {
+	guardian := strings.TrimSpace(s.Guardian.Email)
+	return guardian != "" && strings.EqualFold(guardian, strings.TrimSpace(email))
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	student_event "github.com/williamkoller/system-education/internal/student/domain/event"
 )
 
 func createValidStudent() *Student {
@@ -245,3 +247,98 @@ func TestNewStudent_WithID(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "existing-id", student.ID)
 }
+
+func TestStudent_IsGuardedBy(t *testing.T) {
+	student := &Student{Guardian: GuardianInfo{Email: "Guardian@Example.com"}}
+
+	assert.True(t, student.IsGuardedBy("guardian@example.com"))
+	assert.True(t, student.IsGuardedBy(" GUARDIAN@example.com "))
+	assert.False(t, student.IsGuardedBy("other@example.com"))
+	assert.False(t, (&Student{}).IsGuardedBy(""))
+}
+
+// updateEnrollment changes only the name, school and active flag.
+func updateEnrollment(s *Student, fullName, schoolID *string, isActive *bool) error {
+	return s.Update(fullName, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, schoolID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, isActive, nil)
+}
+
+func TestStudent_DomainEvents(t *testing.T) {
+	enrolled := func(t *testing.T) *Student {
+		student, err := NewStudent(createValidStudent())
+		require.NoError(t, err)
+		student.PullDomainEvents()
+		return student
+	}
+
+	t.Run("new students are enrolled", func(t *testing.T) {
+		student, err := NewStudent(createValidStudent())
+		require.NoError(t, err)
+
+		events := student.PullDomainEvents()
+		require.Len(t, events, 1)
+		event, ok := events[0].(*student_event.StudentEnrolledEvent)
+		require.True(t, ok)
+		assert.Equal(t, student.ID, event.StudentID)
+		assert.Equal(t, "SCH123", event.SchoolID)
+		assert.Equal(t, "morning", event.Shift)
+	})
+
+	t.Run("an update carries the changed fields", func(t *testing.T) {
+		student := enrolled(t)
+		name := "John Updated"
+
+		require.NoError(t, updateEnrollment(student, &name, nil, nil))
+
+		events := student.PullDomainEvents()
+		require.Len(t, events, 1)
+		event, ok := events[0].(*student_event.StudentUpdatedEvent)
+		require.True(t, ok)
+		assert.Equal(t, map[string]student_event.FieldChange{
+			"full_name": {From: "John Doe", To: "John Updated"},
+		}, event.Changes)
+	})
+
+	t.Run("an update that changes nothing raises nothing", func(t *testing.T) {
+		student := enrolled(t)
+		name := student.PersonalInfo.FullName
+		active := true
+
+		require.NoError(t, updateEnrollment(student, &name, nil, &active))
+
+		assert.Empty(t, student.PullDomainEvents())
+	})
+
+	t.Run("moving school and deactivating are their own events", func(t *testing.T) {
+		student := enrolled(t)
+		school := "SCH456"
+		active := false
+
+		require.NoError(t, updateEnrollment(student, nil, &school, &active))
+
+		events := student.PullDomainEvents()
+		require.Len(t, events, 3)
+		updated := events[0].(*student_event.StudentUpdatedEvent)
+		assert.Len(t, updated.Changes, 2)
+		assert.Equal(t, student_event.FieldChange{From: true, To: false}, updated.Changes["is_active"])
+		assert.Equal(t, &student_event.StudentTransferredEvent{StudentID: student.ID, FromSchoolID: "SCH123", ToSchoolID: "SCH456", Date: events[1].OccurredOn()}, events[1])
+		assert.Equal(t, "student.deactivated", events[2].EventName())
+	})
+
+	t.Run("a rejected update raises nothing", func(t *testing.T) {
+		student := enrolled(t)
+		name := ""
+
+		assert.Error(t, updateEnrollment(student, &name, nil, nil))
+		assert.Empty(t, student.PullDomainEvents())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		student := enrolled(t)
+
+		student.Delete()
+
+		events := student.PullDomainEvents()
+		require.Len(t, events, 1)
+		assert.Equal(t, &student_event.StudentDeletedEvent{StudentID: student.ID, SchoolID: "SCH123", EnrollmentCode: "ST123", Date: events[0].OccurredOn()}, events[0])
+	})
+}
@@ -0,0 +1,29 @@
+package student_event
+
+import "time"
+
+type StudentDeactivatedEvent struct {
+	StudentID string
+	SchoolID  string
+	Date      time.Time
+}
+
+func NewStudentDeactivatedEvent(studentID string, schoolID string) *StudentDeactivatedEvent {
+	return &StudentDeactivatedEvent{
+		StudentID: studentID,
+		SchoolID:  schoolID,
+		Date:      time.Now(),
+	}
+}
+
+func (e *StudentDeactivatedEvent) EventName() string {
+	return "student.deactivated"
+}
+
+func (e *StudentDeactivatedEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *StudentDeactivatedEvent) AggregateID() string {
+	return e.StudentID
+}
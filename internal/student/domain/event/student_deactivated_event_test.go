@@ -0,0 +1,18 @@
+package student_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStudentDeactivatedEvent(t *testing.T) {
+	event := NewStudentDeactivatedEvent("123", "school-1")
+
+	assert.Equal(t, "123", event.StudentID)
+	assert.Equal(t, "school-1", event.SchoolID)
+	assert.Equal(t, "student.deactivated", event.EventName())
+	assert.Equal(t, event.StudentID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
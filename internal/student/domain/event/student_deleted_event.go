@@ -0,0 +1,31 @@
+package student_event
+
+import "time"
+
+type StudentDeletedEvent struct {
+	StudentID      string
+	SchoolID       string
+	EnrollmentCode string
+	Date           time.Time
+}
+
+func NewStudentDeletedEvent(studentID string, schoolID string, enrollmentCode string) *StudentDeletedEvent {
+	return &StudentDeletedEvent{
+		StudentID:      studentID,
+		SchoolID:       schoolID,
+		EnrollmentCode: enrollmentCode,
+		Date:           time.Now(),
+	}
+}
+
+func (e *StudentDeletedEvent) EventName() string {
+	return "student.deleted"
+}
+
+func (e *StudentDeletedEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *StudentDeletedEvent) AggregateID() string {
+	return e.StudentID
+}
@@ -0,0 +1,19 @@
+package student_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStudentDeletedEvent(t *testing.T) {
+	event := NewStudentDeletedEvent("123", "school-1", "ST123")
+
+	assert.Equal(t, "123", event.StudentID)
+	assert.Equal(t, "school-1", event.SchoolID)
+	assert.Equal(t, "ST123", event.EnrollmentCode)
+	assert.Equal(t, "student.deleted", event.EventName())
+	assert.Equal(t, event.StudentID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
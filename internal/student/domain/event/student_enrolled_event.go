@@ -0,0 +1,41 @@
+package student_event
+
+import "time"
+
+type StudentEnrolledEvent struct {
+	StudentID      string
+	FullName       string
+	EnrollmentCode string
+	SchoolID       string
+	Grade          string
+	ClassRoom      string
+	Shift          string
+	EnrollmentDate time.Time
+	Date           time.Time
+}
+
+func NewStudentEnrolledEvent(studentID string, fullName string, enrollmentCode string, schoolID string, grade string, classRoom string, shift string, enrollmentDate time.Time) *StudentEnrolledEvent {
+	return &StudentEnrolledEvent{
+		StudentID:      studentID,
+		FullName:       fullName,
+		EnrollmentCode: enrollmentCode,
+		SchoolID:       schoolID,
+		Grade:          grade,
+		ClassRoom:      classRoom,
+		Shift:          shift,
+		EnrollmentDate: enrollmentDate,
+		Date:           time.Now(),
+	}
+}
+
+func (e *StudentEnrolledEvent) EventName() string {
+	return "student.enrolled"
+}
+
+func (e *StudentEnrolledEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *StudentEnrolledEvent) AggregateID() string {
+	return e.StudentID
+}
@@ -0,0 +1,25 @@
+package student_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStudentEnrolledEvent(t *testing.T) {
+	enrolledOn := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	event := NewStudentEnrolledEvent("123", "John Doe", "ST123", "school-1", "5th", "A", "morning", enrolledOn)
+
+	assert.Equal(t, "123", event.StudentID)
+	assert.Equal(t, "John Doe", event.FullName)
+	assert.Equal(t, "ST123", event.EnrollmentCode)
+	assert.Equal(t, "school-1", event.SchoolID)
+	assert.Equal(t, "5th", event.Grade)
+	assert.Equal(t, "A", event.ClassRoom)
+	assert.Equal(t, "morning", event.Shift)
+	assert.Equal(t, enrolledOn, event.EnrollmentDate)
+	assert.Equal(t, "student.enrolled", event.EventName())
+	assert.Equal(t, event.StudentID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
@@ -0,0 +1,31 @@
+package student_event
+
+import "time"
+
+type StudentTransferredEvent struct {
+	StudentID    string
+	FromSchoolID string
+	ToSchoolID   string
+	Date         time.Time
+}
+
+func NewStudentTransferredEvent(studentID string, fromSchoolID string, toSchoolID string) *StudentTransferredEvent {
+	return &StudentTransferredEvent{
+		StudentID:    studentID,
+		FromSchoolID: fromSchoolID,
+		ToSchoolID:   toSchoolID,
+		Date:         time.Now(),
+	}
+}
+
+func (e *StudentTransferredEvent) EventName() string {
+	return "student.transferred"
+}
+
+func (e *StudentTransferredEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *StudentTransferredEvent) AggregateID() string {
+	return e.StudentID
+}
@@ -0,0 +1,19 @@
+package student_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStudentTransferredEvent(t *testing.T) {
+	event := NewStudentTransferredEvent("123", "school-1", "school-2")
+
+	assert.Equal(t, "123", event.StudentID)
+	assert.Equal(t, "school-1", event.FromSchoolID)
+	assert.Equal(t, "school-2", event.ToSchoolID)
+	assert.Equal(t, "student.transferred", event.EventName())
+	assert.Equal(t, event.StudentID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
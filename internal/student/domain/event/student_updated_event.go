@@ -0,0 +1,39 @@
+package student_event
+
+import "time"
+
+// FieldChange is a field's value before and after an update.
+type FieldChange struct {
+	From any
+	To   any
+}
+
+// StudentUpdatedEvent carries only the fields that changed, keyed by the
+// names the API uses for them (full_name, school_id, ...).
+type StudentUpdatedEvent struct {
+	StudentID string
+	SchoolID  string
+	Changes   map[string]FieldChange
+	Date      time.Time
+}
+
+func NewStudentUpdatedEvent(studentID string, schoolID string, changes map[string]FieldChange) *StudentUpdatedEvent {
+	return &StudentUpdatedEvent{
+		StudentID: studentID,
+		SchoolID:  schoolID,
+		Changes:   changes,
+		Date:      time.Now(),
+	}
+}
+
+func (e *StudentUpdatedEvent) EventName() string {
+	return "student.updated"
+}
+
+func (e *StudentUpdatedEvent) OccurredOn() time.Time {
+	return e.Date
+}
+
+func (e *StudentUpdatedEvent) AggregateID() string {
+	return e.StudentID
+}
@@ -0,0 +1,20 @@
+package student_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStudentUpdatedEvent(t *testing.T) {
+	changes := map[string]FieldChange{"grade": {From: "5th", To: "6th"}}
+	event := NewStudentUpdatedEvent("123", "school-1", changes)
+
+	assert.Equal(t, "123", event.StudentID)
+	assert.Equal(t, "school-1", event.SchoolID)
+	assert.Equal(t, changes, event.Changes)
+	assert.Equal(t, "student.updated", event.EventName())
+	assert.Equal(t, event.StudentID, event.AggregateID())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	student_entity "github.com/williamkoller/system-education/internal/student/domain/entity"
 	student_model "github.com/williamkoller/system-education/internal/student/infra/db/model"
 	port_student_repository "github.com/williamkoller/system-education/internal/student/port/repository"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
 	"gorm.io/gorm"
 )
 
@@ -22,7 +24,7 @@ func NewStudentGormRepository(db *gorm.DB) *StudentGormRepository {
 
 func (r *StudentGormRepository) Save(ctx context.Context, s *student_entity.Student) (*student_entity.Student, error) {
 	model := student_model.FromEntity(s)
-	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+	if err := shared_database.Conn(ctx, r.db).Create(model).Error; err != nil {
 		return nil, err
 	}
 	return s, nil
@@ -30,7 +32,7 @@ func (r *StudentGormRepository) Save(ctx context.Context, s *student_entity.Stud
 
 func (r *StudentGormRepository) FindAll(ctx context.Context) ([]*student_entity.Student, error) {
 	var models []*student_model.Student
-	if err := r.db.WithContext(ctx).Preload("School").Find(&models).Error; err != nil {
+	if err := r.scoped(ctx).Preload("School").Find(&models).Error; err != nil {
 		return nil, err
 	}
 	return student_model.ToEntities(models), nil
@@ -38,7 +40,7 @@ func (r *StudentGormRepository) FindAll(ctx context.Context) ([]*student_entity.
 
 func (r *StudentGormRepository) FindById(ctx context.Context, id string) (*student_entity.Student, error) {
 	var model student_model.Student
-	if err := r.db.WithContext(ctx).Preload("School").First(&model, "id = ?", id).Error; err != nil {
+	if err := r.scoped(ctx).Preload("School").First(&model, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, port_student_repository.ErrNotFound
 		}
@@ -49,21 +51,23 @@ func (r *StudentGormRepository) FindById(ctx context.Context, id string) (*stude
 
 func (r *StudentGormRepository) Update(ctx context.Context, id string, s *student_entity.Student) (*student_entity.Student, error) {
 	var count int64
-	r.db.WithContext(ctx).Model(&student_model.Student{}).Where("id = ?", id).Count(&count)
+	if err := r.scoped(ctx).Model(&student_model.Student{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return nil, err
+	}
 	if count == 0 {
 		return nil, port_student_repository.ErrNotFound
 	}
 
 	model := student_model.FromEntity(s)
 	model.ID = id
-	if err := r.db.WithContext(ctx).Save(model).Error; err != nil {
+	if err := shared_database.Conn(ctx, r.db).Save(model).Error; err != nil {
 		return nil, err
 	}
 	return s, nil
 }
 
 func (r *StudentGormRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Delete(&student_model.Student{}, "id = ?", id)
+	result := r.scoped(ctx).Delete(&student_model.Student{}, "id = ?", id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -72,3 +76,12 @@ func (r *StudentGormRepository) Delete(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// scoped narrows queries to the schools the request was authorized for.
+func (r *StudentGormRepository) scoped(ctx context.Context) *gorm.DB {
+	db := shared_database.Conn(ctx, r.db)
+	if scope := permission_entity.SchoolScopeFrom(ctx); !scope.All {
+		db = db.Where("school_id IN ?", scope.SchoolIDs)
+	}
+	return db
+}
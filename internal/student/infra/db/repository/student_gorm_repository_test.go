@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	school_model "github.com/williamkoller/system-education/internal/school/infra/db/model"
 	student_entity "github.com/williamkoller/system-education/internal/student/domain/entity"
 	student_model "github.com/williamkoller/system-education/internal/student/infra/db/model"
@@ -19,11 +20,13 @@ type StudentGormRepositorySuite struct {
 	suite.Suite
 	db         *gorm.DB
 	repository *StudentGormRepository
+	ctx        context.Context
 }
 
 func (s *StudentGormRepositorySuite) SetupTest() {
 	s.db = setupTestDB(s.T())
 	s.repository = NewStudentGormRepository(s.db)
+	s.ctx = permission_entity.WithSchoolScope(context.Background(), permission_entity.AllSchools())
 }
 
 func setupTestDB(t *testing.T) *gorm.DB {
@@ -92,7 +95,7 @@ func (s *StudentGormRepositorySuite) TestSave() {
 
 	student := createValidStudent()
 
-	savedStudent, err := s.repository.Save(context.Background(), student)
+	savedStudent, err := s.repository.Save(s.ctx, student)
 
 	s.NoError(err)
 	s.NotNil(savedStudent)
@@ -107,7 +110,7 @@ func (s *StudentGormRepositorySuite) TestSave_Error() {
 	sqlDB, _ := s.db.DB()
 	sqlDB.Close()
 
-	savedStudent, err := s.repository.Save(context.Background(), student)
+	savedStudent, err := s.repository.Save(s.ctx, student)
 
 	s.Error(err)
 	s.Nil(savedStudent)
@@ -126,20 +129,59 @@ func (s *StudentGormRepositorySuite) TestFindAll() {
 	student2.PersonalInfo.Email = "student2@example.com"
 	student2.PersonalInfo.CPF = "222.222.222-22"
 
-	s.repository.Save(context.Background(), student1)
-	s.repository.Save(context.Background(), student2)
+	s.repository.Save(s.ctx, student1)
+	s.repository.Save(s.ctx, student2)
 
-	students, err := s.repository.FindAll(context.Background())
+	students, err := s.repository.FindAll(s.ctx)
 
 	s.NoError(err)
 	s.Len(students, 2)
 }
 
+func (s *StudentGormRepositorySuite) TestSchoolScope() {
+	atA := createValidStudent()
+	atA.ID = "student-a"
+	atA.School.SchoolID = "school-a"
+
+	atB := createValidStudent()
+	atB.ID = "student-b"
+	atB.PersonalInfo.EnrollmentCode = "ST2"
+	atB.School.SchoolID = "school-b"
+
+	s.repository.Save(s.ctx, atA)
+	s.repository.Save(s.ctx, atB)
+
+	ctx := permission_entity.WithSchoolScope(context.Background(), permission_entity.SchoolScope{SchoolIDs: []string{"school-a"}})
+
+	students, err := s.repository.FindAll(ctx)
+	s.NoError(err)
+	s.Len(students, 1)
+	s.Equal("student-a", students[0].ID)
+
+	_, err = s.repository.FindById(ctx, "student-b")
+	s.ErrorIs(err, port_student_repository.ErrNotFound)
+
+	_, err = s.repository.Update(ctx, "student-b", atB)
+	s.ErrorIs(err, port_student_repository.ErrNotFound)
+
+	s.ErrorIs(s.repository.Delete(ctx, "student-b"), port_student_repository.ErrNotFound)
+	s.NoError(s.repository.Delete(ctx, "student-a"))
+
+	nothing := permission_entity.WithSchoolScope(context.Background(), permission_entity.SchoolScope{})
+	students, err = s.repository.FindAll(nothing)
+	s.NoError(err)
+	s.Empty(students)
+
+	students, err = s.repository.FindAll(context.Background())
+	s.NoError(err)
+	s.Empty(students, "a context without a scope reaches no school")
+}
+
 func (s *StudentGormRepositorySuite) TestFindById() {
 	student := createValidStudent()
-	created, _ := s.repository.Save(context.Background(), student)
+	created, _ := s.repository.Save(s.ctx, student)
 
-	foundStudent, err := s.repository.FindById(context.Background(), created.ID)
+	foundStudent, err := s.repository.FindById(s.ctx, created.ID)
 
 	s.NoError(err)
 	s.NotNil(foundStudent)
@@ -148,7 +190,7 @@ func (s *StudentGormRepositorySuite) TestFindById() {
 }
 
 func (s *StudentGormRepositorySuite) TestFindById_NotFound() {
-	foundStudent, err := s.repository.FindById(context.Background(), "non-existent-id")
+	foundStudent, err := s.repository.FindById(s.ctx, "non-existent-id")
 
 	s.Error(err)
 	s.Equal(port_student_repository.ErrNotFound, err)
@@ -157,44 +199,55 @@ func (s *StudentGormRepositorySuite) TestFindById_NotFound() {
 
 func (s *StudentGormRepositorySuite) TestUpdate() {
 	student := createValidStudent()
-	created, _ := s.repository.Save(context.Background(), student)
+	created, _ := s.repository.Save(s.ctx, student)
 
 	created.PersonalInfo.FullName = "Updated Name"
-	updatedStudent, err := s.repository.Update(context.Background(), created.ID, created)
+	updatedStudent, err := s.repository.Update(s.ctx, created.ID, created)
 
 	s.NoError(err)
 	s.NotNil(updatedStudent)
 	s.Equal("Updated Name", updatedStudent.PersonalInfo.FullName)
 
-	found, _ := s.repository.FindById(context.Background(), created.ID)
+	found, _ := s.repository.FindById(s.ctx, created.ID)
 	s.Equal("Updated Name", found.PersonalInfo.FullName)
 }
 
 func (s *StudentGormRepositorySuite) TestUpdate_NotFound() {
 	student := createValidStudent()
-	updatedStudent, err := s.repository.Update(context.Background(), "non-existent-id", student)
+	updatedStudent, err := s.repository.Update(s.ctx, "non-existent-id", student)
 
 	s.Error(err)
 	s.Equal(port_student_repository.ErrNotFound, err)
 	s.Nil(updatedStudent)
 }
 
+func (s *StudentGormRepositorySuite) TestUpdate_DBError() {
+	sqlDB, _ := s.db.DB()
+	sqlDB.Close()
+
+	updatedStudent, err := s.repository.Update(s.ctx, "some-id", createValidStudent())
+
+	s.Error(err)
+	s.NotErrorIs(err, port_student_repository.ErrNotFound)
+	s.Nil(updatedStudent)
+}
+
 func (s *StudentGormRepositorySuite) TestDelete() {
 	student := createValidStudent()
-	created, _ := s.repository.Save(context.Background(), student)
+	created, _ := s.repository.Save(s.ctx, student)
 
-	err := s.repository.Delete(context.Background(), created.ID)
+	err := s.repository.Delete(s.ctx, created.ID)
 
 	s.NoError(err)
 
-	found, err := s.repository.FindById(context.Background(), created.ID)
+	found, err := s.repository.FindById(s.ctx, created.ID)
 	s.Error(err)
 	s.Equal(port_student_repository.ErrNotFound, err)
 	s.Nil(found)
 }
 
 func (s *StudentGormRepositorySuite) TestDelete_NotFound() {
-	err := s.repository.Delete(context.Background(), "non-existent-id")
+	err := s.repository.Delete(s.ctx, "non-existent-id")
 
 	s.Error(err)
 	s.Equal(port_student_repository.ErrNotFound, err)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	student_mapper "github.com/williamkoller/system-education/internal/student/application/mapper"
 	student_entity "github.com/williamkoller/system-education/internal/student/domain/entity"
 	port_student_handler "github.com/williamkoller/system-education/internal/student/port/handler"
@@ -33,6 +34,11 @@ func (s *StudentHandler) CreateStudent(c *gin.Context) {
 
 	student, err := s.usecase.Create(c.Request.Context(), input)
 	if err != nil {
+		if errors.Is(err, permission_entity.ErrSchoolOutOfScope) {
+			c.Status(http.StatusForbidden)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
 		var validationErr *student_entity.ValidationError
 		if errors.As(err, &validationErr) {
 			c.Status(http.StatusBadRequest)
@@ -87,6 +93,11 @@ func (s *StudentHandler) Update(c *gin.Context) {
 
 	student, err := s.usecase.Update(c.Request.Context(), id, input)
 	if err != nil {
+		if errors.Is(err, permission_entity.ErrSchoolOutOfScope) {
+			c.Status(http.StatusForbidden)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
 		if errors.Is(err, port_student_repository.ErrNotFound) {
 			c.Status(http.StatusNotFound)
 			c.Error(err).SetType(gin.ErrorTypePublic)
@@ -0,0 +1,52 @@
+package student_middleware
+
+import (
+	"context"
+	"errors"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	port_student_repository "github.com/williamkoller/system-education/internal/student/port/repository"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+var _ port_permission_middleware.OwnershipRule = &GuardianRule{}
+
+// GuardianRule lets a user reach the students whose guardian e-mail matches
+// their own. The address must be verified, otherwise anyone could claim a
+// child by signing up with the guardian's e-mail.
+type GuardianRule struct {
+	students port_student_repository.StudentRepository
+	users    port_user_repository.UserRepository
+}
+
+func NewGuardianRule(students port_student_repository.StudentRepository, users port_user_repository.UserRepository) *GuardianRule {
+	return &GuardianRule{students: students, users: users}
+}
+
+func (r *GuardianRule) Owns(ctx context.Context, principal *auth_entity.Claims, id string) (permission_entity.SchoolScope, bool, error) {
+	user, err := r.users.FindByID(ctx, principal.UserID)
+	if errors.Is(err, port_user_repository.ErrUserNotFound) {
+		return permission_entity.SchoolScope{}, false, nil
+	}
+	if err != nil {
+		return permission_entity.SchoolScope{}, false, err
+	}
+	if !user.IsEmailVerified() {
+		return permission_entity.SchoolScope{}, false, nil
+	}
+
+	// The rule decides the scope, so the lookup cannot be narrowed to one.
+	student, err := r.students.FindById(permission_entity.WithSchoolScope(ctx, permission_entity.AllSchools()), id)
+	if errors.Is(err, port_student_repository.ErrNotFound) {
+		return permission_entity.SchoolScope{}, false, nil
+	}
+	if err != nil {
+		return permission_entity.SchoolScope{}, false, err
+	}
+	if !student.IsGuardedBy(user.Email) {
+		return permission_entity.SchoolScope{}, false, nil
+	}
+	return permission_entity.SchoolScope{SchoolIDs: []string{student.School.SchoolID}}, true, nil
+}
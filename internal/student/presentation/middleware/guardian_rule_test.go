@@ -0,0 +1,163 @@
+package student_middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	student_entity "github.com/williamkoller/system-education/internal/student/domain/entity"
+	port_student_repository "github.com/williamkoller/system-education/internal/student/port/repository"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type MockStudentRepository struct {
+	mock.Mock
+}
+
+func (m *MockStudentRepository) Save(ctx context.Context, s *student_entity.Student) (*student_entity.Student, error) {
+	args := m.Called(ctx, s)
+	result, _ := args.Get(0).(*student_entity.Student)
+	return result, args.Error(1)
+}
+
+func (m *MockStudentRepository) FindAll(ctx context.Context) ([]*student_entity.Student, error) {
+	args := m.Called(ctx)
+	result, _ := args.Get(0).([]*student_entity.Student)
+	return result, args.Error(1)
+}
+
+func (m *MockStudentRepository) FindById(ctx context.Context, id string) (*student_entity.Student, error) {
+	args := m.Called(ctx, id)
+	result, _ := args.Get(0).(*student_entity.Student)
+	return result, args.Error(1)
+}
+
+func (m *MockStudentRepository) Update(ctx context.Context, id string, s *student_entity.Student) (*student_entity.Student, error) {
+	args := m.Called(ctx, id, s)
+	result, _ := args.Get(0).(*student_entity.Student)
+	return result, args.Error(1)
+}
+
+func (m *MockStudentRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Save(ctx context.Context, u *user_entity.User) (*user_entity.User, error) {
+	args := m.Called(ctx, u)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*user_entity.User, error) {
+	args := m.Called(ctx, id)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserRepository) FindAll(ctx context.Context) ([]*user_entity.User, error) {
+	args := m.Called(ctx)
+	result, _ := args.Get(0).([]*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*user_entity.User, error) {
+	args := m.Called(ctx, email)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, id string, u *user_entity.User) (*user_entity.User, error) {
+	args := m.Called(ctx, id, u)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error {
+	args := m.Called(ctx, id, verifiedAt)
+	return args.Error(0)
+}
+
+func TestGuardianRule_Owns(t *testing.T) {
+	ctx := context.Background()
+	everySchool := mock.MatchedBy(func(ctx context.Context) bool {
+		return permission_entity.SchoolScopeFrom(ctx).All
+	})
+	principal := &auth_entity.Claims{UserID: "user-1"}
+	verifiedAt := time.Now()
+	student := &student_entity.Student{
+		ID:       "student-1",
+		School:   student_entity.SchoolInfo{SchoolID: "school-a"},
+		Guardian: student_entity.GuardianInfo{Email: "Parent@example.com"},
+	}
+
+	newRule := func(user *user_entity.User, userErr error) (*GuardianRule, *MockStudentRepository) {
+		students := new(MockStudentRepository)
+		users := new(MockUserRepository)
+		users.On("FindByID", ctx, "user-1").Return(user, userErr)
+		students.On("FindById", everySchool, "student-1").Return(student, nil)
+		students.On("FindById", everySchool, "student-2").Return(nil, port_student_repository.ErrNotFound)
+		return NewGuardianRule(students, users), students
+	}
+
+	t.Run("verified guardian owns the student in its school", func(t *testing.T) {
+		rule, _ := newRule(&user_entity.User{ID: "user-1", Email: "parent@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+
+		scope, owned, err := rule.Owns(ctx, principal, "student-1")
+		assert.NoError(t, err)
+		assert.True(t, owned)
+		assert.Equal(t, []string{"school-a"}, scope.SchoolIDs)
+		assert.False(t, scope.All)
+	})
+
+	t.Run("unverified e-mail owns nothing", func(t *testing.T) {
+		rule, students := newRule(&user_entity.User{ID: "user-1", Email: "parent@example.com"}, nil)
+
+		_, owned, err := rule.Owns(ctx, principal, "student-1")
+		assert.NoError(t, err)
+		assert.False(t, owned)
+		students.AssertNotCalled(t, "FindById", mock.Anything, "student-1")
+	})
+
+	t.Run("another guardian's student", func(t *testing.T) {
+		rule, _ := newRule(&user_entity.User{ID: "user-1", Email: "other@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+
+		_, owned, err := rule.Owns(ctx, principal, "student-1")
+		assert.NoError(t, err)
+		assert.False(t, owned)
+	})
+
+	t.Run("missing student or user", func(t *testing.T) {
+		rule, _ := newRule(&user_entity.User{ID: "user-1", Email: "parent@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+		_, owned, err := rule.Owns(ctx, principal, "student-2")
+		assert.NoError(t, err)
+		assert.False(t, owned)
+
+		rule, _ = newRule(nil, port_user_repository.ErrUserNotFound)
+		_, owned, err = rule.Owns(ctx, principal, "student-1")
+		assert.NoError(t, err)
+		assert.False(t, owned)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		rule, _ := newRule(nil, errors.New("db down"))
+
+		_, _, err := rule.Owns(ctx, principal, "student-1")
+		assert.Error(t, err)
+	})
+}
@@ -1,39 +1,58 @@
 package student_router
 
 import (
-	"time"
-
 	"github.com/gin-gonic/gin"
-	infra_cryptography "github.com/williamkoller/system-education/internal/auth/infra/cryptography"
+	audit_router "github.com/williamkoller/system-education/internal/audit/presentation/router"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
-	permission_middleware "github.com/williamkoller/system-education/internal/permission/presentation/middleware"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
 	student_usecase "github.com/williamkoller/system-education/internal/student/application/usecase"
+	student_event "github.com/williamkoller/system-education/internal/student/domain/event"
 	student_repository "github.com/williamkoller/system-education/internal/student/infra/db/repository"
 	student_handler "github.com/williamkoller/system-education/internal/student/presentation/handler"
+	student_middleware "github.com/williamkoller/system-education/internal/student/presentation/middleware"
+	user_repository "github.com/williamkoller/system-education/internal/user/infra/db/repository"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_broker "github.com/williamkoller/system-education/shared/infra/broker"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	shared_outbox "github.com/williamkoller/system-education/shared/infra/outbox"
 	"gorm.io/gorm"
 )
 
-func StudentRouter(g *gin.Engine, db *gorm.DB, secret string, expiresIn time.Duration) {
+// Events are the domain events this module writes to the outbox.
+var Events = []shared_event.Event{
+	&student_event.StudentEnrolledEvent{},
+	&student_event.StudentUpdatedEvent{},
+	&student_event.StudentDeactivatedEvent{},
+	&student_event.StudentTransferredEvent{},
+	&student_event.StudentDeletedEvent{},
+}
+
+func StudentRouter(g *gin.Engine, db *gorm.DB, jwt port_auth_cryptography.TokenManager, apiKeys port_auth_usecase.APIKeyAuthenticator, middleware port_permission_middleware.PermissionMiddleware, events shared_event.Subscriber, broker *shared_broker.EventPublisher) {
+	broker.Subscribe(events, Events)
+
 	studentGroup := g.Group("/students")
 	repo := student_repository.NewStudentGormRepository(db)
-	usecase := student_usecase.NewStudentUsecase(repo)
+	usecase := student_usecase.NewStudentUsecase(repo, shared_outbox.NewGormOutbox(db), shared_database.NewGormTransactor(db), audit_router.NewAuditUsecase(db))
 	handler := student_handler.NewStudentHandler(usecase)
-	jwt := infra_cryptography.NewJWTTokenManager(secret, expiresIn)
-	middleware := permission_middleware.NewPermissionMiddleware()
+	revocations := auth_repository.NewTokenRevocationGormRepository(db)
+	guardian := student_middleware.NewGuardianRule(repo, user_repository.NewUserGormRepository(db))
 	{
-		studentGroup.POST("/", auth_middleware.AuthMiddleware(jwt),
+		studentGroup.POST("/", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
 			middleware.ModuleAccessMiddleware([]string{"students"}, []string{"create"}),
 			handler.CreateStudent)
-		studentGroup.GET("/", auth_middleware.AuthMiddleware(jwt),
+		studentGroup.GET("/", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
 			middleware.ModuleAccessMiddleware([]string{"students"}, []string{"read"}),
 			handler.FindAll)
-		studentGroup.GET("/:id", auth_middleware.AuthMiddleware(jwt),
-			middleware.ModuleAccessMiddleware([]string{"students"}, []string{"read"}),
+		studentGroup.GET("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.OwnerOrModuleAccessMiddleware(guardian, "id", []string{"students"}, []string{"read"}),
 			handler.FindById)
-		studentGroup.PUT("/:id", auth_middleware.AuthMiddleware(jwt),
+		studentGroup.PUT("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
 			middleware.ModuleAccessMiddleware([]string{"students"}, []string{"update"}),
 			handler.Update)
-		studentGroup.DELETE("/:id", auth_middleware.AuthMiddleware(jwt),
+		studentGroup.DELETE("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
 			middleware.ModuleAccessMiddleware([]string{"students"}, []string{"delete"}),
 			handler.Delete)
 	}
@@ -7,26 +7,28 @@ import (
 )
 
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Surname   string    `json:"surname"`
-	Nickname  string    `json:"nickname"`
-	Email     string    `json:"email"`
-	Age       int32     `json:"age"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Surname         string     `json:"surname"`
+	Nickname        string     `json:"nickname"`
+	Email           string     `json:"email"`
+	Age             int32      `json:"age"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt"`
 }
 
 func ToUser(d *userEntity.User) *UserResponse {
 	return &UserResponse{
-		ID:        d.ID,
-		Name:      d.Name,
-		Surname:   d.Surname,
-		Nickname:  d.Nickname,
-		Email:     d.Email,
-		Age:       d.Age,
-		CreatedAt: d.CreatedAt,
-		UpdatedAt: d.UpdatedAt,
+		ID:              d.ID,
+		Name:            d.Name,
+		Surname:         d.Surname,
+		Nickname:        d.Nickname,
+		Email:           d.Email,
+		Age:             d.Age,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+		EmailVerifiedAt: d.EmailVerifiedAt,
 	}
 }
 
@@ -1,28 +1,37 @@
 package user_usecase
 
 import (
-    "context"
-    "errors"
-    "fmt"
-    "log"
-
-    "github.com/google/uuid"
-    user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
-    port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
-    port_event "github.com/williamkoller/system-education/internal/user/port/event"
-    port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
-    port_user_usecase "github.com/williamkoller/system-education/internal/user/port/usecase"
-    "github.com/williamkoller/system-education/internal/user/presentation/dtos"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	port_session "github.com/williamkoller/system-education/internal/user/port/session"
+	port_user_usecase "github.com/williamkoller/system-education/internal/user/port/usecase"
+	"github.com/williamkoller/system-education/internal/user/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	port_transaction "github.com/williamkoller/system-education/shared/port/transaction"
 )
 
+const auditEntityType = "user"
+
 type UserUsecase struct {
-	repo   port_user_repository.UserRepository
-	crypto port_cryptography.Bcrypt
-	event  port_event.Dispacther
+	repo     port_user_repository.UserRepository
+	crypto   port_cryptography.Bcrypt
+	outbox   shared_event.Outbox
+	tx       port_transaction.Transactor
+	sessions port_session.SessionRevoker
+	audit    port_audit_usecase.Recorder
 }
 
-func NewUserUsecase(repo port_user_repository.UserRepository, crypto port_cryptography.Bcrypt, event port_event.Dispacther) *UserUsecase {
-	return &UserUsecase{repo: repo, crypto: crypto, event: event}
+func NewUserUsecase(repo port_user_repository.UserRepository, crypto port_cryptography.Bcrypt, outbox shared_event.Outbox, tx port_transaction.Transactor, sessions port_session.SessionRevoker, audit port_audit_usecase.Recorder) *UserUsecase {
+	return &UserUsecase{repo: repo, crypto: crypto, outbox: outbox, tx: tx, sessions: sessions, audit: audit}
 }
 
 var _ port_user_usecase.UserUsecase = &UserUsecase{}
@@ -58,13 +67,21 @@ func (u *UserUsecase) Create(ctx context.Context, input dtos.AddUserDto) (*user_
 		return nil, errors.New("invalid user data")
 	}
 
-    user, err := u.repo.Save(ctx, newUser)
-	if err != nil {
-		return nil, fmt.Errorf("failed to save user: %w", err)
-	}
+	var user *user_entity.User
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		saved, err := u.repo.Save(ctx, newUser)
+		if err != nil {
+			return fmt.Errorf("failed to save user: %w", err)
+		}
+		user = saved
 
-	for _, domainEvent := range newUser.PullDomainEvents() {
-		u.event.Dispatch(domainEvent)
+		if err := u.outbox.Add(ctx, newUser.PullDomainEvents()...); err != nil {
+			return fmt.Errorf("failed to record user events: %w", err)
+		}
+		return u.audit.Record(ctx, audit_entity.ActionCreate, auditEntityType, saved.ID, nil, audit_entity.Snapshot(saved))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return user, nil
@@ -72,7 +89,7 @@ func (u *UserUsecase) Create(ctx context.Context, input dtos.AddUserDto) (*user_
 }
 
 func (u *UserUsecase) FindAll(ctx context.Context) ([]*user_entity.User, error) {
-    users, err := u.repo.FindAll(ctx)
+	users, err := u.repo.FindAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find all users: %w", err)
 	}
@@ -85,7 +102,7 @@ func (u *UserUsecase) FindByID(ctx context.Context, id string) (*user_entity.Use
 		return nil, errors.New("user ID cannot be empty")
 	}
 
-    user, err := u.repo.FindByID(ctx, id)
+	user, err := u.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user by ID %s: %w", id, err)
 	}
@@ -100,13 +117,14 @@ func (u *UserUsecase) Update(ctx context.Context, id string, input dtos.UpdateUs
 
 	log.Printf("Updating user with ID: %s, Data: %+v", id, input)
 
-    userExists, err := u.FindByID(ctx, id)
+	userExists, err := u.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if userExists == nil {
 		return nil, errors.New("user not found")
 	}
+	before := audit_entity.Snapshot(userExists)
 
 	if input.Password != nil && *input.Password != "" {
 		hash, err := u.crypto.Hash(*input.Password)
@@ -124,9 +142,17 @@ func (u *UserUsecase) Update(ctx context.Context, id string, input dtos.UpdateUs
 		input.Age,
 	)
 
-    updatedUser, err := u.repo.Update(ctx, userExists.ID, userExists)
+	var updatedUser *user_entity.User
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		saved, err := u.repo.Update(ctx, userExists.ID, userExists)
+		if err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		updatedUser = saved
+		return u.audit.Record(ctx, audit_entity.ActionUpdate, auditEntityType, saved.ID, before, audit_entity.Snapshot(saved))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update user: %w", err)
+		return nil, err
 	}
 
 	return updatedUser, nil
@@ -137,15 +163,19 @@ func (u *UserUsecase) Delete(ctx context.Context, id string) error {
 		return errors.New("user ID cannot be empty")
 	}
 
-    userExists, err := u.FindByID(ctx, id)
+	userExists, err := u.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-    err = u.repo.Delete(ctx, userExists.ID)
-	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+	if err := u.sessions.RevokeAllForUser(ctx, userExists.ID); err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
 	}
 
-	return nil
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.Delete(ctx, userExists.ID); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+		return u.audit.Record(ctx, audit_entity.ActionDelete, auditEntityType, userExists.ID, audit_entity.Snapshot(userExists), nil)
+	})
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
 	user_usecase "github.com/williamkoller/system-education/internal/user/application/usecase"
 	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
 	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
@@ -22,16 +24,44 @@ type MockBcryptAdapter struct {
 	mock.Mock
 }
 
-type MockEvent struct {
+type MockOutbox struct {
 	mock.Mock
 }
 
-func (m *MockEvent) Register(eventName string, handler shared_event.Handler) {
-	m.Called(eventName, handler)
+func (m *MockOutbox) Add(ctx context.Context, events ...shared_event.Event) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+// stubTransactor runs fn directly; the repositories are mocked anyway.
+type stubTransactor struct{}
+
+func (stubTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockSessionRevoker struct {
+	mock.Mock
+}
+
+func (m *MockSessionRevoker) RevokeAllForUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type MockAuditRecorder struct {
+	mock.Mock
+}
+
+func (m *MockAuditRecorder) Record(ctx context.Context, action audit_entity.Action, entityType, entityID string, before, after map[string]any) error {
+	args := m.Called(ctx, action, entityType, entityID, before, after)
+	return args.Error(0)
 }
 
-func (m *MockEvent) Dispatch(event interface{}) {
-	m.Called(event)
+func ignoreAudit() *MockAuditRecorder {
+	audit := new(MockAuditRecorder)
+	audit.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return audit
 }
 
 func (m *MockBcryptAdapter) Hash(plaintext string) (string, error) {
@@ -56,6 +86,11 @@ func (m *MockUserRepository) Update(ctx context.Context, id string, user *user_e
 	return result, args.Error(1)
 }
 
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error {
+	args := m.Called(ctx, id, verifiedAt)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*user_entity.User, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(*user_entity.User), args.Error(1)
@@ -83,8 +118,9 @@ func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
 func TestCreate_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	input := dtos.AddUserDto{
 		Name:     "Alice",
@@ -107,9 +143,9 @@ func TestCreate_Success(t *testing.T) {
 		Email:    input.Email,
 		Password: "mocked:secure123",
 	}, nil)
-	mockEvent.On("Dispatch", mock.MatchedBy(func(e interface{}) bool {
-		return e != nil
-	})).Return()
+	mockOutbox.On("Add", mock.Anything, mock.MatchedBy(func(events []shared_event.Event) bool {
+		return len(events) == 1 && events[0].EventName() == "user.created"
+	})).Return(nil)
 
 	user, err := usecase.Create(context.Background(), input)
 
@@ -119,14 +155,38 @@ func TestCreate_Success(t *testing.T) {
 	assert.Equal(t, "mocked:secure123", user.Password)
 	mockRepo.AssertExpectations(t)
 	mockCrypto.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
+}
+
+func TestCreate_OutboxFails(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	mockOutbox := new(MockOutbox)
+	audit := new(MockAuditRecorder)
+
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, new(MockSessionRevoker), audit)
+
+	input := dtos.AddUserDto{Name: "Alice", Surname: "Silva", Nickname: "ali", Age: 30, Email: "alice@example.com", Password: "secure123"}
+
+	mockRepo.On("FindByEmail", mock.Anything, input.Email).Return(nil, port_user_repository.ErrUserNotFound)
+	mockCrypto.On("Hash", input.Password).Return("mocked:secure123", nil)
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(&user_entity.User{ID: "123", Email: input.Email}, nil)
+	mockOutbox.On("Add", mock.Anything, mock.Anything).Return(errors.New("outbox unavailable"))
+
+	user, err := usecase.Create(context.Background(), input)
+
+	assert.Nil(t, user)
+	assert.ErrorContains(t, err, "failed to record user events")
+	audit.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestCreate_FindByEmailError(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	input := dtos.AddUserDto{
 		Name:     "Alice",
@@ -151,9 +211,10 @@ func TestCreate_FindByEmailError(t *testing.T) {
 func TestCreate_AlreadyExists(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	existing := &user_entity.User{Email: "alice@example.com"}
 	mockRepo.On("FindByEmail", mock.Anything, "alice@example.com").Return(existing, nil)
@@ -167,9 +228,10 @@ func TestCreate_AlreadyExists(t *testing.T) {
 func TestCreate_SaveFails(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	input := dtos.AddUserDto{
 		Name:     "Alice",
@@ -186,9 +248,7 @@ func TestCreate_SaveFails(t *testing.T) {
 		return u.Email == input.Email
 	})).Return(nil, errors.New("save error"))
 
-	mockEvent.On("Dispatch", mock.MatchedBy(func(e interface{}) bool {
-		return e != nil
-	})).Return()
+	mockOutbox.On("Add", mock.Anything, mock.Anything).Return(nil)
 
 	_, err := usecase.Create(context.Background(), input)
 
@@ -202,9 +262,10 @@ func TestCreate_SaveFails(t *testing.T) {
 func TestCreate_HashFails(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	input := dtos.AddUserDto{
 		Name:     "Alice",
@@ -230,9 +291,10 @@ func TestCreate_HashFails(t *testing.T) {
 func TestCreate_InvalidUserData(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	input := dtos.AddUserDto{
 		Name:     "Alice",
@@ -257,9 +319,10 @@ func TestCreate_InvalidUserData(t *testing.T) {
 func TestFindAll_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	expectedUsers := []*user_entity.User{
 		{Name: "Alice"}, {Name: "Bob"},
@@ -278,9 +341,10 @@ func TestFindAll_Success(t *testing.T) {
 func TestFindByID_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	expectedUser := &user_entity.User{ID: "123", Name: "Alice"}
 	mockRepo.On("FindByID", mock.Anything, "123").Return(expectedUser, nil)
@@ -295,29 +359,53 @@ func TestFindByID_Success(t *testing.T) {
 func TestDelete_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	user := &user_entity.User{ID: "123"}
 	mockRepo.On("FindByID", mock.Anything, "123").Return(user, nil)
+	mockSessions.On("RevokeAllForUser", mock.Anything, "123").Return(nil)
 	mockRepo.On("Delete", mock.Anything, "123").Return(nil)
 
 	err := usecase.Delete(context.Background(), "123")
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestDelete_FailRevokeSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
+
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
+
+	user := &user_entity.User{ID: "123"}
+	mockRepo.On("FindByID", mock.Anything, "123").Return(user, nil)
+	mockSessions.On("RevokeAllForUser", mock.Anything, "123").Return(errors.New("db error"))
+
+	err := usecase.Delete(context.Background(), "123")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to revoke user sessions")
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
 }
 
 func TestDelete_FailDelete(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	user := &user_entity.User{ID: "123"}
 	mockRepo.On("FindByID", mock.Anything, "123").Return(user, nil)
+	mockSessions.On("RevokeAllForUser", mock.Anything, "123").Return(nil)
 	mockRepo.On("Delete", mock.Anything, "123").Return(errors.New("db error"))
 
 	err := usecase.Delete(context.Background(), "123")
@@ -331,9 +419,10 @@ func TestDelete_FailDelete(t *testing.T) {
 func TestUpdate_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	id := "123"
 	existingUser := &user_entity.User{ID: id, Email: "old@example.com"}
@@ -359,12 +448,40 @@ func TestUpdate_Success(t *testing.T) {
 	mockCrypto.AssertExpectations(t)
 }
 
+func TestUpdate_RecordsAuditWithoutPasswordValues(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	audit := new(MockAuditRecorder)
+
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, new(MockOutbox), stubTransactor{}, new(MockSessionRevoker), audit)
+
+	existingUser := &user_entity.User{ID: "123", Name: "Old", Email: "old@example.com", Password: "hashed:old"}
+	mockRepo.On("FindByID", mock.Anything, "123").Return(existingUser, nil)
+	mockCrypto.On("Hash", "newpass").Return("hashed:newpass", nil)
+	mockRepo.On("Update", mock.Anything, "123", mock.Anything).Return(existingUser, nil)
+
+	var changes audit_entity.Changes
+	audit.On("Record", mock.Anything, audit_entity.ActionUpdate, "user", "123", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			changes = audit_entity.Diff(args.Get(4).(map[string]any), args.Get(5).(map[string]any))
+		}).Return(nil).Once()
+
+	_, err := usecase.Update(context.Background(), "123", dtos.UpdateUserDto{Name: strPtr("New"), Password: strPtr("newpass")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, audit_entity.Changes{
+		"Name":     {From: "Old", To: "New"},
+		"Password": {From: audit_entity.Redacted, To: audit_entity.Redacted},
+	}, changes)
+}
+
 func TestFindAll_Error(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	mockRepo.On("FindAll", mock.Anything).Return([]*user_entity.User(nil), errors.New("database error"))
 
@@ -379,9 +496,10 @@ func TestFindAll_Error(t *testing.T) {
 func TestFindByID_EmptyID(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	user, err := usecase.FindByID(context.Background(), "")
 
@@ -393,9 +511,10 @@ func TestFindByID_EmptyID(t *testing.T) {
 func TestFindByID_Error(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	mockRepo.On("FindByID", mock.Anything, "123").Return((*user_entity.User)(nil), errors.New("not found"))
 
@@ -410,9 +529,10 @@ func TestFindByID_Error(t *testing.T) {
 func TestUpdate_EmptyID(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	input := dtos.UpdateUserDto{
 		Name: strPtr("Updated"),
@@ -428,9 +548,10 @@ func TestUpdate_EmptyID(t *testing.T) {
 func TestUpdate_UserNotFound(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	mockRepo.On("FindByID", mock.Anything, "999").Return((*user_entity.User)(nil), errors.New("not found"))
 
@@ -449,9 +570,10 @@ func TestUpdate_UserNotFound(t *testing.T) {
 func TestUpdate_UserNil(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	// Simulate FindByID returning nil without error (edge case)
 	mockRepo.On("FindByID", mock.Anything, "999").Return((*user_entity.User)(nil), nil)
@@ -471,9 +593,10 @@ func TestUpdate_UserNil(t *testing.T) {
 func TestUpdate_RepositoryError(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	id := "123"
 	existingUser := &user_entity.User{ID: id, Email: "old@example.com"}
@@ -496,9 +619,10 @@ func TestUpdate_RepositoryError(t *testing.T) {
 func TestUpdate_WithEmptyPassword(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	id := "123"
 	existingUser := &user_entity.User{ID: id, Email: "old@example.com", Password: "old-hash"}
@@ -526,9 +650,10 @@ func TestUpdate_WithEmptyPassword(t *testing.T) {
 func TestUpdate_PasswordHashError(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	id := "123"
 	existingUser := &user_entity.User{ID: id, Email: "test@example.com", Password: "old-hash"}
@@ -554,9 +679,10 @@ func TestUpdate_PasswordHashError(t *testing.T) {
 func TestDelete_EmptyID(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	err := usecase.Delete(context.Background(), "")
 
@@ -567,9 +693,10 @@ func TestDelete_EmptyID(t *testing.T) {
 func TestDelete_UserNotFound(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
-	mockEvent := new(MockEvent)
+	mockOutbox := new(MockOutbox)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockOutbox, stubTransactor{}, mockSessions, ignoreAudit())
 
 	mockRepo.On("FindByID", mock.Anything, "999").Return((*user_entity.User)(nil), errors.New("not found"))
 
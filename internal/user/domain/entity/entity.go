@@ -17,6 +17,9 @@ type User struct {
 	Password  string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// EmailVerifiedAt is nil until the owner follows the link mailed on
+	// sign-up.
+	EmailVerifiedAt *time.Time
 	sharedEvent.AggregateRoot
 }
 
@@ -66,6 +69,10 @@ func (u *User) GetPassword() string {
 	return u.Password
 }
 
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
 func (u *User) PullDomainEvents() []sharedEvent.Event {
 	if u == nil {
 		return nil
@@ -80,8 +87,10 @@ func (u *User) UpdateUser(name, nickname, email, password *string, age *int32) (
 	if nickname != nil {
 		u.Nickname = *nickname
 	}
-	if email != nil {
+	if email != nil && *email != u.Email {
 		u.Email = *email
+		// The new address has not been proven to belong to the user.
+		u.EmailVerifiedAt = nil
 	}
 	if password != nil {
 		u.Password = *password
@@ -442,3 +442,18 @@ func TestUpdateUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateUser_EmailChangeResetsVerification(t *testing.T) {
+	verifiedAt := time.Now()
+	same := "user@example.com"
+	other := "other@example.com"
+
+	user := &User{ID: "123", Name: "User", Email: same, Password: "pass123", EmailVerifiedAt: &verifiedAt}
+	_, err := user.UpdateUser(nil, nil, &same, nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, user.IsEmailVerified(), "resubmitting the same address keeps it verified")
+
+	_, err = user.UpdateUser(nil, nil, &other, nil, nil)
+	assert.NoError(t, err)
+	assert.False(t, user.IsEmailVerified())
+}
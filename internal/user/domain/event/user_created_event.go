@@ -20,3 +20,7 @@ func (e *UserCreatedEvent) EventName() string {
 func (e *UserCreatedEvent) OccurredOn() time.Time {
 	return e.Date
 }
+
+func (e *UserCreatedEvent) AggregateID() string {
+	return e.UserID
+}
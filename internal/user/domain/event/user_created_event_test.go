@@ -36,3 +36,9 @@ func TestUserCreatedEvent_OccurredOn(t *testing.T) {
 	assert.Equal(t, now, e.OccurredOn())
 	assert.WithinDuration(t, now, e.OccurredOn(), time.Millisecond)
 }
+
+func TestUserCreatedEvent_AggregateID(t *testing.T) {
+	e := NewUserCreatedEvent("1", "Will", "will@mail.com")
+
+	assert.Equal(t, "1", e.AggregateID())
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 
 	"errors"
+	"time"
 
 	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
 	user_model "github.com/williamkoller/system-education/internal/user/infra/db/model"
 	portUserRepository "github.com/williamkoller/system-education/internal/user/port/repository"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
 	"gorm.io/gorm"
 )
 
@@ -23,7 +25,7 @@ func NewUserGormRepository(db *gorm.DB) *UserGormRepository {
 
 func (r *UserGormRepository) Save(ctx context.Context, u *userEntity.User) (*userEntity.User, error) {
 	model := user_model.FromEntity(u)
-	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+	if err := shared_database.Conn(ctx, r.db).Create(&model).Error; err != nil {
 		return nil, err
 	}
 
@@ -33,7 +35,7 @@ func (r *UserGormRepository) Save(ctx context.Context, u *userEntity.User) (*use
 func (r *UserGormRepository) FindByID(ctx context.Context, id string) (*userEntity.User, error) {
 	var user *userEntity.User
 
-	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+	if err := shared_database.Conn(ctx, r.db).First(&user, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, portUserRepository.ErrUserNotFound
 		}
@@ -46,7 +48,7 @@ func (r *UserGormRepository) FindByID(ctx context.Context, id string) (*userEnti
 func (r *UserGormRepository) FindAll(ctx context.Context) ([]*userEntity.User, error) {
 	var users []*userEntity.User
 
-	if err := r.db.WithContext(ctx).Find(&users).Error; err != nil {
+	if err := shared_database.Conn(ctx, r.db).Find(&users).Error; err != nil {
 		return nil, err
 	}
 
@@ -54,14 +56,14 @@ func (r *UserGormRepository) FindAll(ctx context.Context) ([]*userEntity.User, e
 }
 
 func (r *UserGormRepository) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).Unscoped().Delete(&user_model.User{}, "id = ?", id).Error
+	return shared_database.Conn(ctx, r.db).Unscoped().Delete(&user_model.User{}, "id = ?", id).Error
 }
 
 func (r *UserGormRepository) FindByEmail(ctx context.Context, email string) (*userEntity.User, error) {
 	var user *userEntity.User
 	model := user_model.FromEntity(user)
 
-	if err := r.db.WithContext(ctx).First(&model, "email = ?", email).Error; err != nil {
+	if err := shared_database.Conn(ctx, r.db).First(&model, "email = ?", email).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, portUserRepository.ErrUserNotFound
 		}
@@ -74,11 +76,41 @@ func (r *UserGormRepository) FindByEmail(ctx context.Context, email string) (*us
 func (r *UserGormRepository) Update(ctx context.Context, id string, u *userEntity.User) (*userEntity.User, error) {
 	model := user_model.FromEntity(u)
 
-	if err := r.db.WithContext(ctx).Model(&user_model.User{}).
+	if err := shared_database.Conn(ctx, r.db).Model(&user_model.User{}).
 		Where("id = ?", id).
 		Updates(&model).Error; err != nil {
 		return nil, err
 	}
 
+	// Updates skips nil fields, so a cleared verification is written on its own.
+	if model.EmailVerifiedAt == nil {
+		if err := shared_database.Conn(ctx, r.db).Model(&user_model.User{}).
+			Where("id = ?", id).
+			Update("email_verified_at", nil).Error; err != nil {
+			return nil, err
+		}
+	}
+
 	return user_model.ToEntity(model), nil
 }
+
+// MarkEmailVerified keeps the first verification time when a link is
+// followed again.
+func (r *UserGormRepository) MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error {
+	result := shared_database.Conn(ctx, r.db).Model(&user_model.User{}).
+		Where("id = ?", id).
+		Where("email_verified_at IS NULL").
+		Update("email_verified_at", verifiedAt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if _, err := r.FindByID(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
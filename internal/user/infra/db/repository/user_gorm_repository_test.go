@@ -3,6 +3,7 @@ package user_repository_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
@@ -389,3 +390,57 @@ func TestUserGormRepository_Update_NonExistentUser(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 }
+
+func TestUserGormRepository_MarkEmailVerified(t *testing.T) {
+	db := setupTestDB(t)
+	repo := user_repository.NewUserGormRepository(db)
+
+	u := &user_entity.User{ID: "id-verify", Name: "Test", Email: "verify@example.com", Password: "pass123"}
+	_, err := repo.Save(context.Background(), u)
+	assert.NoError(t, err)
+
+	found, err := repo.FindByEmail(context.Background(), u.Email)
+	assert.NoError(t, err)
+	assert.False(t, found.IsEmailVerified())
+
+	verifiedAt := time.Now().Truncate(time.Second)
+	assert.NoError(t, repo.MarkEmailVerified(context.Background(), u.ID, verifiedAt))
+	assert.NoError(t, repo.MarkEmailVerified(context.Background(), u.ID, verifiedAt.Add(time.Hour)))
+
+	found, err = repo.FindByID(context.Background(), u.ID)
+	assert.NoError(t, err)
+	assert.True(t, found.IsEmailVerified())
+	assert.True(t, verifiedAt.Equal(*found.EmailVerifiedAt), "a second verification keeps the first timestamp")
+}
+
+func TestUserGormRepository_MarkEmailVerified_NotFound(t *testing.T) {
+	db := setupTestDB(t)
+	repo := user_repository.NewUserGormRepository(db)
+
+	err := repo.MarkEmailVerified(context.Background(), "missing", time.Now())
+
+	assert.ErrorIs(t, err, port_user_repository.ErrUserNotFound)
+}
+
+func TestUserGormRepository_UpdateClearsEmailVerification(t *testing.T) {
+	db := setupTestDB(t)
+	repo := user_repository.NewUserGormRepository(db)
+
+	u := &user_entity.User{ID: "id-reverify", Name: "Test", Email: "before@example.com", Password: "pass123"}
+	_, err := repo.Save(context.Background(), u)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.MarkEmailVerified(context.Background(), u.ID, time.Now()))
+
+	found, err := repo.FindByID(context.Background(), u.ID)
+	assert.NoError(t, err)
+	email := "after@example.com"
+	_, err = found.UpdateUser(nil, nil, &email, nil, nil)
+	assert.NoError(t, err)
+	_, err = repo.Update(context.Background(), u.ID, found)
+	assert.NoError(t, err)
+
+	found, err = repo.FindByID(context.Background(), u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "after@example.com", found.Email)
+	assert.False(t, found.IsEmailVerified())
+}
@@ -2,6 +2,7 @@ package infra_email
 
 import (
 	"fmt"
+	"html/template"
 	"time"
 
 	port_email_notifier "github.com/williamkoller/system-education/internal/user/port/email"
@@ -106,3 +107,141 @@ func (n *ResendEmailNotifier) SendWelcomeEmail(name, emailAddr string) error {
 
 	return nil
 }
+
+func (n *ResendEmailNotifier) SendPasswordResetEmail(name, emailAddr, resetURL string) error {
+	subject := "Redefinição de senha"
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="pt-BR">
+  <head>
+    <meta http-equiv="Content-Type" content="text/html; charset=UTF-8" />
+    <meta name="x-apple-disable-message-reformatting" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+
+    <style>
+      * {
+        font-family: 'Inter', Helvetica, Arial, sans-serif;
+      }
+    </style>
+  </head>
+
+  <body style="margin:0; padding:0; background-color:#f5f5f7;">
+    <table width="100%%" cellpadding="0" cellspacing="0" border="0" align="center">
+      <tr>
+        <td style="padding:24px;">
+          <table width="100%%" cellpadding="0" cellspacing="0" border="0" align="center" style="max-width:600px; background:#ffffff; border-radius:8px; padding:32px;">
+            <tr>
+              <td style="text-align:left;">
+
+                <h1 style="font-size:24px; font-weight:700; color:#111; margin:0 0 16px 0;">
+                  Olá, %s!
+                </h1>
+
+                <p style="font-size:16px; color:#444; margin:0 0 12px 0; line-height:1.5;">
+                  Recebemos um pedido para redefinir a senha da sua conta na <strong>System Education</strong>.
+                </p>
+
+                <p style="font-size:16px; color:#444; margin:0 0 24px 0; line-height:1.5;">
+                  Clique no botão abaixo para escolher uma nova senha. O link só pode ser usado uma vez e expira em breve.
+                </p>
+
+                <a href="%s"
+                  style="display:inline-block; padding:12px 20px; background:#7C3AED; color:#ffffff; text-decoration:none; font-size:16px; font-weight:600; border-radius:6px;">
+                  Redefinir senha
+                </a>
+
+                <p style="font-size:14px; color:#888; margin:24px 0 0 0; line-height:1.5;">
+                  Se você não solicitou a redefinição, ignore este e-mail. Sua senha atual continuará válida.
+                </p>
+
+              </td>
+            </tr>
+
+            <tr>
+              <td style="padding-top:32px; text-align:center; color:#888; font-size:12px;">
+                © %d System Education. Todos os direitos reservados.
+              </td>
+            </tr>
+
+          </table>
+        </td>
+      </tr>
+    </table>
+  </body>
+</html>`, template.HTMLEscapeString(name), template.HTMLEscapeString(resetURL), time.Now().Year())
+
+	if err := n.client.SendEmail(emailAddr, subject, html); err != nil {
+		return fmt.Errorf("failed to send password reset email to %s: %w", emailAddr, err)
+	}
+
+	return nil
+}
+
+func (n *ResendEmailNotifier) SendEmailVerificationEmail(name, emailAddr, verifyURL string) error {
+	subject := "Confirme seu e-mail"
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="pt-BR">
+  <head>
+    <meta http-equiv="Content-Type" content="text/html; charset=UTF-8" />
+    <meta name="x-apple-disable-message-reformatting" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+
+    <style>
+      * {
+        font-family: 'Inter', Helvetica, Arial, sans-serif;
+      }
+    </style>
+  </head>
+
+  <body style="margin:0; padding:0; background-color:#f5f5f7;">
+    <table width="100%%" cellpadding="0" cellspacing="0" border="0" align="center">
+      <tr>
+        <td style="padding:24px;">
+          <table width="100%%" cellpadding="0" cellspacing="0" border="0" align="center" style="max-width:600px; background:#ffffff; border-radius:8px; padding:32px;">
+            <tr>
+              <td style="text-align:left;">
+
+                <h1 style="font-size:24px; font-weight:700; color:#111; margin:0 0 16px 0;">
+                  Olá, %s!
+                </h1>
+
+                <p style="font-size:16px; color:#444; margin:0 0 12px 0; line-height:1.5;">
+                  Falta pouco para concluir seu cadastro na <strong>System Education</strong>.
+                </p>
+
+                <p style="font-size:16px; color:#444; margin:0 0 24px 0; line-height:1.5;">
+                  Clique no botão abaixo para confirmar seu endereço de e-mail. O link só pode ser usado uma vez e expira em breve.
+                </p>
+
+                <a href="%s"
+                  style="display:inline-block; padding:12px 20px; background:#7C3AED; color:#ffffff; text-decoration:none; font-size:16px; font-weight:600; border-radius:6px;">
+                  Confirmar e-mail
+                </a>
+
+                <p style="font-size:14px; color:#888; margin:24px 0 0 0; line-height:1.5;">
+                  Se você não criou uma conta, ignore este e-mail.
+                </p>
+
+              </td>
+            </tr>
+
+            <tr>
+              <td style="padding-top:32px; text-align:center; color:#888; font-size:12px;">
+                © %d System Education. Todos os direitos reservados.
+              </td>
+            </tr>
+
+          </table>
+        </td>
+      </tr>
+    </table>
+  </body>
+</html>`, template.HTMLEscapeString(name), template.HTMLEscapeString(verifyURL), time.Now().Year())
+
+	if err := n.client.SendEmail(emailAddr, subject, html); err != nil {
+		return fmt.Errorf("failed to send e-mail verification email to %s: %w", emailAddr, err)
+	}
+
+	return nil
+}
@@ -138,3 +138,79 @@ func TestResendEmailNotifier_SendWelcomeEmail_CorrectSubject(t *testing.T) {
 	mockClient.AssertExpectations(t)
 	mockClient.AssertCalled(t, "SendEmail", email, expectedSubject, mock.Anything)
 }
+
+func TestResendEmailNotifier_SendPasswordResetEmail_Success(t *testing.T) {
+	mockClient := new(MockResendClient)
+	notifier := NewResendEmailNotifier(mockClient)
+
+	resetURL := "https://systemeducation.com/reset-password?token=abc123"
+
+	var capturedHTML string
+	mockClient.On("SendEmail",
+		"john.doe@example.com",
+		"Redefinição de senha",
+		mock.MatchedBy(func(html string) bool {
+			capturedHTML = html
+			return true
+		}),
+	).Return(nil)
+
+	err := notifier.SendPasswordResetEmail("John Doe", "john.doe@example.com", resetURL)
+
+	assert.NoError(t, err)
+	assert.Contains(t, capturedHTML, "Olá, John Doe!")
+	assert.Contains(t, capturedHTML, resetURL)
+	mockClient.AssertExpectations(t)
+}
+
+func TestResendEmailNotifier_SendPasswordResetEmail_ClientError(t *testing.T) {
+	mockClient := new(MockResendClient)
+	notifier := NewResendEmailNotifier(mockClient)
+
+	mockClient.On("SendEmail", "john.doe@example.com", "Redefinição de senha", mock.Anything).
+		Return(errors.New("resend API error"))
+
+	err := notifier.SendPasswordResetEmail("John Doe", "john.doe@example.com", "https://example.com")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to send password reset email")
+	mockClient.AssertExpectations(t)
+}
+
+func TestResendEmailNotifier_SendEmailVerificationEmail_Success(t *testing.T) {
+	mockClient := new(MockResendClient)
+	notifier := NewResendEmailNotifier(mockClient)
+
+	verifyURL := "https://systemeducation.com/verify-email?token=abc123"
+
+	var capturedHTML string
+	mockClient.On("SendEmail",
+		"john.doe@example.com",
+		"Confirme seu e-mail",
+		mock.MatchedBy(func(html string) bool {
+			capturedHTML = html
+			return true
+		}),
+	).Return(nil)
+
+	err := notifier.SendEmailVerificationEmail("John Doe", "john.doe@example.com", verifyURL)
+
+	assert.NoError(t, err)
+	assert.Contains(t, capturedHTML, "Olá, John Doe!")
+	assert.Contains(t, capturedHTML, verifyURL)
+	mockClient.AssertExpectations(t)
+}
+
+func TestResendEmailNotifier_SendEmailVerificationEmail_ClientError(t *testing.T) {
+	mockClient := new(MockResendClient)
+	notifier := NewResendEmailNotifier(mockClient)
+
+	mockClient.On("SendEmail", "john.doe@example.com", "Confirme seu e-mail", mock.Anything).
+		Return(errors.New("resend API error"))
+
+	err := notifier.SendEmailVerificationEmail("John Doe", "john.doe@example.com", "https://example.com")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to send e-mail verification email")
+	mockClient.AssertExpectations(t)
+}
@@ -2,4 +2,6 @@ package port_email_notifier
 
 type EmailNotifier interface {
 	SendWelcomeEmail(name, email string) error
+	SendPasswordResetEmail(name, email, resetURL string) error
+	SendEmailVerificationEmail(name, email, verifyURL string) error
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package port_user_repository
 import (
     "context"
     "errors"
+    "time"
 
     userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
 )
@@ -14,6 +15,7 @@ type UserRepository interface {
     Delete(ctx context.Context, id string) error
     FindByEmail(ctx context.Context, email string) (*userEntity.User, error)
     Update(ctx context.Context, id string, u *userEntity.User) (*userEntity.User, error)
+    MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error
 }
 
 var (
@@ -0,0 +1,7 @@
+package port_session
+
+import "context"
+
+type SessionRevoker interface {
+	RevokeAllForUser(ctx context.Context, userID string) error
+}
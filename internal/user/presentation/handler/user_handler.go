@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	user_mapper "github.com/williamkoller/system-education/internal/user/application/mapper"
 	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
 	portUserHandler "github.com/williamkoller/system-education/internal/user/port/handler"
@@ -13,6 +14,11 @@ import (
 	"github.com/williamkoller/system-education/internal/user/presentation/dtos"
 )
 
+// ErrCredentialsNeedGrant is returned when an owner without users:update
+// tries to change the e-mail or password of their own account; those go
+// through the password reset and verification flows instead.
+var ErrCredentialsNeedGrant = errors.New("changing e-mail or password requires users:update")
+
 type UserHandler struct {
 	usecase portUserUsecase.UserUsecase
 }
@@ -96,6 +102,11 @@ func (h *UserHandler) Update(c *gin.Context) {
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
 	}
+	if permission_entity.OwnerAccess(c.Request.Context()) && (input.Email != nil || input.Password != nil) {
+		c.Status(http.StatusForbidden)
+		c.Error(ErrCredentialsNeedGrant).SetType(gin.ErrorTypePublic)
+		return
+	}
 
 	user, err := h.usecase.Update(c.Request.Context(), idParams, input)
 
@@ -0,0 +1,108 @@
+package user_handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	"github.com/williamkoller/system-education/internal/user/presentation/dtos"
+)
+
+type MockUserUsecase struct {
+	mock.Mock
+}
+
+func (m *MockUserUsecase) Create(ctx context.Context, input dtos.AddUserDto) (*user_entity.User, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user_entity.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) FindAll(ctx context.Context) ([]*user_entity.User, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user_entity.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) FindByID(ctx context.Context, id string) (*user_entity.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user_entity.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) Update(ctx context.Context, id string, input dtos.UpdateUserDto) (*user_entity.User, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user_entity.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestUserHandler_Update(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	update := func(usecase *MockUserUsecase, owner bool, body string) *httptest.ResponseRecorder {
+		g := gin.New()
+		g.PUT("/users/:id", func(c *gin.Context) {
+			if owner {
+				c.Request = c.Request.WithContext(permission_entity.WithOwnerAccess(c.Request.Context()))
+			}
+			c.Next()
+		}, NewUserHandler(usecase).Update)
+
+		req := httptest.NewRequest(http.MethodPut, "/users/user-1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should let an owner change their profile", func(t *testing.T) {
+		usecase := new(MockUserUsecase)
+		usecase.On("Update", mock.Anything, "user-1", mock.Anything).Return(&user_entity.User{ID: "user-1", Name: "Jane"}, nil)
+
+		w := update(usecase, true, `{"name":"Jane"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		usecase.AssertExpectations(t)
+	})
+
+	t.Run("should keep an owner from changing their e-mail or password", func(t *testing.T) {
+		for _, body := range []string{`{"email":"jane@example.com"}`, `{"name":"Jane","password":"newPassword123"}`} {
+			usecase := new(MockUserUsecase)
+
+			w := update(usecase, true, body)
+
+			assert.Equal(t, http.StatusForbidden, w.Code, body)
+			usecase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("should let a holder of users:update change credentials", func(t *testing.T) {
+		usecase := new(MockUserUsecase)
+		usecase.On("Update", mock.Anything, "user-1", mock.Anything).Return(&user_entity.User{ID: "user-1"}, nil)
+
+		w := update(usecase, false, `{"email":"jane@example.com","password":"newPassword123"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		usecase.AssertExpectations(t)
+	})
+}
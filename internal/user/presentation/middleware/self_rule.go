@@ -0,0 +1,26 @@
+package user_middleware
+
+import (
+	"context"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+)
+
+var _ port_permission_middleware.OwnershipRule = &SelfRule{}
+
+// SelfRule lets a user reach their own account. Users are not school-owned
+// records, so the scope is every school.
+type SelfRule struct{}
+
+func NewSelfRule() *SelfRule {
+	return &SelfRule{}
+}
+
+func (r *SelfRule) Owns(_ context.Context, principal *auth_entity.Claims, id string) (permission_entity.SchoolScope, bool, error) {
+	if id == "" || id != principal.UserID {
+		return permission_entity.SchoolScope{}, false, nil
+	}
+	return permission_entity.AllSchools(), true, nil
+}
@@ -0,0 +1,26 @@
+package user_middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+func TestSelfRule_Owns(t *testing.T) {
+	rule := NewSelfRule()
+	principal := &auth_entity.Claims{UserID: "user-1"}
+
+	scope, owned, err := rule.Owns(context.Background(), principal, "user-1")
+	assert.NoError(t, err)
+	assert.True(t, owned)
+	assert.True(t, scope.All)
+
+	_, owned, err = rule.Owns(context.Background(), principal, "user-2")
+	assert.NoError(t, err)
+	assert.False(t, owned)
+
+	_, owned, _ = rule.Owns(context.Background(), &auth_entity.Claims{}, "")
+	assert.False(t, owned)
+}
@@ -1,66 +1,96 @@
 package user_router
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	infra_cryptography "github.com/williamkoller/system-education/internal/auth/infra/cryptography"
+	audit_router "github.com/williamkoller/system-education/internal/audit/presentation/router"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
-	permission_middleware "github.com/williamkoller/system-education/internal/permission/presentation/middleware"
+	auth_router "github.com/williamkoller/system-education/internal/auth/presentation/router"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
 	user_usecase "github.com/williamkoller/system-education/internal/user/application/usecase"
 	user_event "github.com/williamkoller/system-education/internal/user/domain/event"
 	user_cryptography "github.com/williamkoller/system-education/internal/user/infra/cryptography"
 	user_repository "github.com/williamkoller/system-education/internal/user/infra/db/repository"
 	infra_email "github.com/williamkoller/system-education/internal/user/infra/email"
 	user_handler "github.com/williamkoller/system-education/internal/user/presentation/handler"
+	user_middleware "github.com/williamkoller/system-education/internal/user/presentation/middleware"
 	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_broker "github.com/williamkoller/system-education/shared/infra/broker"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
 	"github.com/williamkoller/system-education/shared/infra/email"
+	shared_outbox "github.com/williamkoller/system-education/shared/infra/outbox"
 	"gorm.io/gorm"
 )
 
-func UserRouter(e *gin.Engine, db *gorm.DB, apiKey string, fromAddress string, secret string, expiresIn time.Duration) {
+// Events are the domain events this module writes to the outbox.
+var Events = []shared_event.Event{
+	&user_event.UserCreatedEvent{},
+}
+
+func UserRouter(e *gin.Engine, db *gorm.DB, apiKey string, fromAddress string, verifyURL string, verifyExpiresIn time.Duration, jwt port_auth_cryptography.TokenManager, apiKeys port_auth_usecase.APIKeyAuthenticator, middleware port_permission_middleware.PermissionMiddleware, events shared_event.Subscriber, broker *shared_broker.EventPublisher) {
 	crypto := user_cryptography.NewBcryptHasher(12)
 	userRepo := user_repository.NewUserGormRepository(db)
-	event := shared_event.NewDispatcher()
-	jwt := infra_cryptography.NewJWTTokenManager(secret, expiresIn)
-	middleware := permission_middleware.NewPermissionMiddleware()
+	revocations := auth_repository.NewTokenRevocationGormRepository(db)
 
 	client := email.NewResendClient(apiKey, fromAddress)
 	notifier := infra_email.NewResendEmailNotifier(client)
-	event.Register("user.created", func(e interface{}) {
-		evt, ok := e.(*user_event.UserCreatedEvent)
-		if !ok {
-			log.Printf("Evento inesperado: %+v", e)
-			return
+	// The relay retries a handler that returns an error without rerunning the
+	// other, but one may still run twice if a relay dies mid-delivery.
+	events.Subscribe("welcome-email", &user_event.UserCreatedEvent{}, func(_ context.Context, e shared_event.Event) error {
+		evt := e.(*user_event.UserCreatedEvent)
+		if err := notifier.SendWelcomeEmail(evt.Name, evt.Email); err != nil {
+			return fmt.Errorf("falha ao enviar e‑mail de boas‑vindas: %w", err)
 		}
+		log.Printf("E‑mail de boas‑vindas enviado para: %s", evt.Email)
+		return nil
+	})
 
-		if err := notifier.SendWelcomeEmail(evt.Name, evt.Email); err != nil {
-			log.Printf("Falha ao enviar e‑mail de boas‑vindas: %v", err)
-		} else {
-			log.Printf("E‑mail de boas‑vindas enviado para: %s", evt.Email)
+	verification := auth_router.NewEmailVerificationUsecase(db, notifier, verifyURL, verifyExpiresIn)
+	events.Subscribe("verification-email", &user_event.UserCreatedEvent{}, func(ctx context.Context, e shared_event.Event) error {
+		evt := e.(*user_event.UserCreatedEvent)
+		if err := verification.Send(ctx, evt.UserID); err != nil {
+			return fmt.Errorf("falha ao enviar e‑mail de verificação: %w", err)
 		}
+		return nil
 	})
 
-	userUsecase := user_usecase.NewUserUsecase(userRepo, crypto, event)
+	broker.Subscribe(events, Events)
+
+	userUsecase := user_usecase.NewUserUsecase(userRepo, crypto, shared_outbox.NewGormOutbox(db), shared_database.NewGormTransactor(db), revocations, audit_router.NewAuditUsecase(db))
 	userHandler := user_handler.NewUserHandler(userUsecase)
+	self := user_middleware.NewSelfRule()
 
 	users := e.Group("/users")
 	{
-		users.POST("", userHandler.CreateUser)
-		users.GET("", userHandler.FindAllUsers)
-		users.GET(":id",
-			auth_middleware.AuthMiddleware(jwt),
+		users.POST("",
+			auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.ModuleAccessMiddleware([]string{"users"}, []string{"create"}),
+			userHandler.CreateUser,
+		)
+		users.GET("",
+			auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
 			middleware.ModuleAccessMiddleware([]string{"users"}, []string{"read"}),
+			userHandler.FindAllUsers,
+		)
+		users.GET(":id",
+			auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.OwnerOrModuleAccessMiddleware(self, "id", []string{"users"}, []string{"read"}),
 			userHandler.FindByID,
 		)
 		users.PUT(":id",
-			auth_middleware.AuthMiddleware(jwt),
-			middleware.ModuleAccessMiddleware([]string{"users"}, []string{"update"}),
+			auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
+			middleware.OwnerOrModuleAccessMiddleware(self, "id", []string{"users"}, []string{"update"}),
 			userHandler.Update,
 		)
 		users.DELETE(":id",
-			auth_middleware.AuthMiddleware(jwt),
+			auth_middleware.AuthMiddleware(jwt, revocations, apiKeys),
 			middleware.ModuleAccessMiddleware([]string{"users"}, []string{"delete"}),
 			userHandler.Delete,
 		)
@@ -0,0 +1,105 @@
+package webhook_mapper
+
+import (
+	"encoding/json"
+	"time"
+
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	webhook_dtos "github.com/williamkoller/system-education/internal/webhook/presentation/dtos"
+)
+
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type DeliveryResponse struct {
+	ID             string             `json:"id"`
+	SubscriptionID string             `json:"subscriptionId"`
+	EventID        string             `json:"eventId"`
+	Event          string             `json:"event"`
+	Payload        json.RawMessage    `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int                `json:"attempts"`
+	NextAttemptAt  time.Time          `json:"nextAttemptAt"`
+	ResponseCode   int                `json:"responseCode,omitempty"`
+	LastError      string             `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time         `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time          `json:"createdAt"`
+	AttemptLog     []*AttemptResponse `json:"attemptLog"`
+}
+
+type AttemptResponse struct {
+	ResponseCode int       `json:"responseCode"`
+	Error        string    `json:"error,omitempty"`
+	DurationMs   int64     `json:"durationMs"`
+	AttemptedAt  time.Time `json:"attemptedAt"`
+}
+
+func ToWebhookResponse(s *webhook_entity.Subscription) *WebhookResponse {
+	return &WebhookResponse{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    s.Events,
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func ToWebhookResponses(ss []*webhook_entity.Subscription) []*WebhookResponse {
+	responses := make([]*WebhookResponse, 0, len(ss))
+	for _, s := range ss {
+		responses = append(responses, ToWebhookResponse(s))
+	}
+	return responses
+}
+
+func ToDeliveryResponse(d *webhook_entity.Delivery) *DeliveryResponse {
+	attempts := make([]*AttemptResponse, 0, len(d.AttemptLog))
+	for _, a := range d.AttemptLog {
+		attempts = append(attempts, &AttemptResponse{
+			ResponseCode: a.ResponseCode,
+			Error:        a.Error,
+			DurationMs:   a.Duration.Milliseconds(),
+			AttemptedAt:  a.AttemptedAt,
+		})
+	}
+
+	return &DeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		Event:          d.EventName,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseCode:   d.ResponseCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		AttemptLog:     attempts,
+	}
+}
+
+func ToDeliveryResponses(ds []*webhook_entity.Delivery) []*DeliveryResponse {
+	responses := make([]*DeliveryResponse, 0, len(ds))
+	for _, d := range ds {
+		responses = append(responses, ToDeliveryResponse(d))
+	}
+	return responses
+}
+
+func ToDeliveryFilter(subscriptionID string, input webhook_dtos.FindDeliveriesDto) webhook_entity.DeliveryFilter {
+	return webhook_entity.DeliveryFilter{
+		SubscriptionID: subscriptionID,
+		Status:         webhook_entity.DeliveryStatus(input.Status),
+		Limit:          input.Limit,
+		Offset:         input.Offset,
+	}
+}
@@ -0,0 +1,73 @@
+package webhook_mapper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	webhook_dtos "github.com/williamkoller/system-education/internal/webhook/presentation/dtos"
+)
+
+func TestToWebhookResponse(t *testing.T) {
+	now := time.Now()
+	subscription := &webhook_entity.Subscription{
+		ID:        "sub-1",
+		URL:       "https://lms.example.com/hooks",
+		Secret:    "0123456789abcdef",
+		Events:    []string{"school.created"},
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	response := ToWebhookResponse(subscription)
+
+	assert.Equal(t, subscription.ID, response.ID)
+	assert.Equal(t, subscription.URL, response.URL)
+	assert.Equal(t, subscription.Events, response.Events)
+	assert.True(t, response.Active)
+
+	body, err := json.Marshal(response)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(body), subscription.Secret)
+}
+
+func TestToDeliveryResponse(t *testing.T) {
+	now := time.Now()
+	delivery := &webhook_entity.Delivery{
+		ID:             "del-1",
+		SubscriptionID: "sub-1",
+		EventID:        "evt-1",
+		EventName:      "school.created",
+		Payload:        `{"id":"evt-1"}`,
+		Status:         webhook_entity.DeliveryPending,
+		Attempts:       1,
+		ResponseCode:   500,
+		LastError:      "received status 500",
+		AttemptLog: []*webhook_entity.Attempt{
+			{ResponseCode: 500, Error: "received status 500", Duration: 1500 * time.Millisecond, AttemptedAt: now},
+		},
+	}
+
+	response := ToDeliveryResponse(delivery)
+
+	assert.Equal(t, "pending", response.Status)
+	assert.Equal(t, "school.created", response.Event)
+	assert.JSONEq(t, delivery.Payload, string(response.Payload))
+	assert.Len(t, response.AttemptLog, 1)
+	assert.Equal(t, int64(1500), response.AttemptLog[0].DurationMs)
+	assert.Equal(t, 500, response.AttemptLog[0].ResponseCode)
+}
+
+func TestToDeliveryFilter(t *testing.T) {
+	filter := ToDeliveryFilter("sub-1", webhook_dtos.FindDeliveriesDto{Status: "failed", Limit: 10, Offset: 20})
+
+	assert.Equal(t, webhook_entity.DeliveryFilter{
+		SubscriptionID: "sub-1",
+		Status:         webhook_entity.DeliveryFailed,
+		Limit:          10,
+		Offset:         20,
+	}, filter)
+}
@@ -0,0 +1,169 @@
+package webhook_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	port_webhook_repository "github.com/williamkoller/system-education/internal/webhook/port/repository"
+	port_webhook_sender "github.com/williamkoller/system-education/internal/webhook/port/sender"
+	port_webhook_usecase "github.com/williamkoller/system-education/internal/webhook/port/usecase"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+const (
+	batchSize = 50
+	// lease hides a claimed delivery from other workers. It must outlast the
+	// sender's timeout.
+	lease = 5 * time.Minute
+)
+
+// DeliveryUsecase fans outbox events out to matching subscriptions and
+// POSTs them, retrying failures with exponential backoff.
+type DeliveryUsecase struct {
+	subscriptions port_webhook_repository.SubscriptionRepository
+	deliveries    port_webhook_repository.DeliveryRepository
+	sender        port_webhook_sender.Sender
+	interval      time.Duration
+	maxAttempts   int
+	retryBackoff  time.Duration
+	now           func() time.Time
+}
+
+func NewDeliveryUsecase(subscriptions port_webhook_repository.SubscriptionRepository, deliveries port_webhook_repository.DeliveryRepository, sender port_webhook_sender.Sender, interval time.Duration, maxAttempts int, retryBackoff time.Duration) *DeliveryUsecase {
+	return &DeliveryUsecase{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		sender:        sender,
+		interval:      interval,
+		maxAttempts:   maxAttempts,
+		retryBackoff:  retryBackoff,
+		now:           time.Now,
+	}
+}
+
+var _ port_webhook_usecase.DeliveryUsecase = &DeliveryUsecase{}
+
+// Enqueue is subscribed to the outbox relay. The event's outbox id makes
+// enqueueing the same event twice harmless.
+func (d *DeliveryUsecase) Enqueue(ctx context.Context, event shared_event.Event) error {
+	eventID := shared_event.EventIDFrom(ctx)
+	if eventID == "" {
+		eventID = uuid.New().String()
+	}
+
+	subscriptions, err := d.subscriptions.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find webhook subscriptions: %w", err)
+	}
+
+	var deliveries []*webhook_entity.Delivery
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(event.EventName()) {
+			continue
+		}
+		delivery, err := webhook_entity.NewDelivery(subscription.ID, eventID, event)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", event.EventName(), err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return d.deliveries.Enqueue(ctx, deliveries...)
+}
+
+// Run delivers until ctx is cancelled, polling every interval once the
+// queue is drained.
+func (d *DeliveryUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			claimed, err := d.DeliverDue(ctx)
+			if err != nil {
+				log.Printf("webhooks: %v", err)
+			}
+			if err != nil || claimed < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends up to batchSize due deliveries and returns how many it
+// claimed.
+func (d *DeliveryUsecase) DeliverDue(ctx context.Context) (int, error) {
+	due, err := d.deliveries.ClaimDue(ctx, d.now(), batchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	for _, delivery := range due {
+		if err := d.deliver(ctx, delivery); err != nil {
+			log.Printf("webhooks: delivery %s of %s: %v", delivery.ID, delivery.EventName, err)
+		}
+	}
+	return len(due), nil
+}
+
+func (d *DeliveryUsecase) deliver(ctx context.Context, delivery *webhook_entity.Delivery) error {
+	subscription, err := d.subscriptions.FindByID(ctx, delivery.SubscriptionID)
+	if errors.Is(err, port_webhook_repository.ErrNotFound) {
+		// Deleting the subscription deletes its deliveries too.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !subscription.Active {
+		delivery.Abandon("subscription is inactive")
+		return d.deliveries.Record(ctx, delivery, nil)
+	}
+
+	attempt := d.send(ctx, subscription, delivery)
+	delivery.Record(attempt, d.maxAttempts, d.retryBackoff)
+	return d.deliveries.Record(ctx, delivery, attempt)
+}
+
+func (d *DeliveryUsecase) send(ctx context.Context, subscription *webhook_entity.Subscription, delivery *webhook_entity.Delivery) *webhook_entity.Attempt {
+	body := []byte(delivery.Payload)
+	sentAt := d.now()
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(webhook_entity.EventHeader, delivery.EventName)
+	header.Set(webhook_entity.DeliveryHeader, delivery.ID)
+	header.Set(webhook_entity.TimestampHeader, strconv.FormatInt(sentAt.Unix(), 10))
+	header.Set(webhook_entity.SignatureHeader, webhook_entity.Sign(subscription.Secret, sentAt.Unix(), body))
+
+	code, err := d.sender.Send(ctx, subscription.URL, header, body)
+
+	attempt := &webhook_entity.Attempt{
+		ID:           uuid.New().String(),
+		DeliveryID:   delivery.ID,
+		ResponseCode: code,
+		Duration:     d.now().Sub(sentAt),
+		AttemptedAt:  sentAt,
+	}
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+	case !webhook_entity.Succeeded(code):
+		attempt.Error = fmt.Sprintf("received status %d", code)
+	}
+	return attempt
+}
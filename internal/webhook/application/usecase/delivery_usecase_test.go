@@ -0,0 +1,202 @@
+package webhook_usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	infra_http "github.com/williamkoller/system-education/internal/webhook/infra/http"
+	port_webhook_repository "github.com/williamkoller/system-education/internal/webhook/port/repository"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type schoolCreated struct {
+	SchoolID string
+	Date     time.Time
+}
+
+func (e *schoolCreated) EventName() string     { return "school.created" }
+func (e *schoolCreated) OccurredOn() time.Time { return e.Date }
+
+// receiver is an httptest subscriber that answers with the queued status
+// codes and keeps what it received.
+type receiver struct {
+	*httptest.Server
+	codes    []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, codes ...int) *receiver {
+	r := &receiver{codes: codes}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		w.WriteHeader(r.codes[len(r.requests)-1])
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func TestDeliveryUsecase_Enqueue(t *testing.T) {
+	subscriptions := new(MockSubscriptionRepository)
+	deliveries := new(MockDeliveryRepository)
+	usecase := NewDeliveryUsecase(subscriptions, deliveries, nil, time.Second, 3, time.Second)
+
+	inactive := existingSubscription()
+	inactive.ID, inactive.Active = "sub-2", false
+	other := existingSubscription()
+	other.ID, other.Events = "sub-3", []string{"school.updated"}
+	subscriptions.On("FindAll", mock.Anything).Return([]*webhook_entity.Subscription{existingSubscription(), inactive, other}, nil)
+	deliveries.On("Enqueue", mock.Anything, mock.MatchedBy(func(ds []*webhook_entity.Delivery) bool {
+		return len(ds) == 1 && ds[0].SubscriptionID == "sub-1" && ds[0].EventID == "evt-1"
+	})).Return(nil).Once()
+
+	ctx := shared_event.WithEventID(context.Background(), "evt-1")
+	assert.NoError(t, usecase.Enqueue(ctx, &schoolCreated{SchoolID: "school-1"}))
+	deliveries.AssertExpectations(t)
+}
+
+func TestDeliveryUsecase_DeliverDue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	newUsecase := func(subscriptions *MockSubscriptionRepository, deliveries *MockDeliveryRepository) *DeliveryUsecase {
+		usecase := NewDeliveryUsecase(subscriptions, deliveries, infra_http.NewHTTPSender(time.Second, true), time.Second, 2, time.Minute)
+		usecase.now = func() time.Time { return now }
+		return usecase
+	}
+	pending := func(t *testing.T) *webhook_entity.Delivery {
+		d, err := webhook_entity.NewDelivery("sub-1", "evt-1", &schoolCreated{SchoolID: "school-1"})
+		require.NoError(t, err)
+		return d
+	}
+
+	t.Run("posts a signed payload", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		deliveries := new(MockDeliveryRepository)
+		hook := newReceiver(t, http.StatusNoContent)
+		subscription := existingSubscription()
+		subscription.URL = hook.URL
+		delivery := pending(t)
+
+		deliveries.On("ClaimDue", mock.Anything, now, batchSize, lease).Return([]*webhook_entity.Delivery{delivery}, nil)
+		subscriptions.On("FindByID", mock.Anything, "sub-1").Return(subscription, nil)
+		deliveries.On("Record", mock.Anything, delivery, mock.MatchedBy(func(a *webhook_entity.Attempt) bool {
+			return a.ResponseCode == http.StatusNoContent && a.Error == ""
+		})).Return(nil).Once()
+
+		claimed, err := newUsecase(subscriptions, deliveries).DeliverDue(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, claimed)
+		require.Len(t, hook.requests, 1)
+		req := hook.requests[0]
+		timestamp, _ := strconv.ParseInt(req.Header.Get(webhook_entity.TimestampHeader), 10, 64)
+		assert.Equal(t, now.Unix(), timestamp)
+		assert.True(t, webhook_entity.Verify("0123456789abcdef", timestamp, hook.bodies[0], req.Header.Get(webhook_entity.SignatureHeader)))
+		assert.Equal(t, "school.created", req.Header.Get(webhook_entity.EventHeader))
+		assert.Equal(t, delivery.ID, req.Header.Get(webhook_entity.DeliveryHeader))
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.JSONEq(t, delivery.Payload, string(hook.bodies[0]))
+		assert.Equal(t, webhook_entity.DeliverySucceeded, delivery.Status)
+		deliveries.AssertExpectations(t)
+	})
+
+	t.Run("retries with backoff, then gives up", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		deliveries := new(MockDeliveryRepository)
+		hook := newReceiver(t, http.StatusInternalServerError, http.StatusBadGateway)
+		subscription := existingSubscription()
+		subscription.URL = hook.URL
+		delivery := pending(t)
+		usecase := newUsecase(subscriptions, deliveries)
+
+		deliveries.On("ClaimDue", mock.Anything, mock.Anything, batchSize, lease).Return([]*webhook_entity.Delivery{delivery}, nil)
+		subscriptions.On("FindByID", mock.Anything, "sub-1").Return(subscription, nil)
+		deliveries.On("Record", mock.Anything, delivery, mock.Anything).Return(nil)
+
+		_, err := usecase.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, webhook_entity.DeliveryPending, delivery.Status)
+		assert.Equal(t, http.StatusInternalServerError, delivery.ResponseCode)
+		assert.Equal(t, "received status 500", delivery.LastError)
+		assert.Equal(t, now.Add(time.Minute), delivery.NextAttemptAt)
+
+		_, err = usecase.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, webhook_entity.DeliveryFailed, delivery.Status)
+		assert.Equal(t, http.StatusBadGateway, delivery.ResponseCode)
+		assert.Len(t, delivery.AttemptLog, 2)
+	})
+
+	t.Run("an unreachable subscriber is a failed attempt", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		deliveries := new(MockDeliveryRepository)
+		hook := newReceiver(t)
+		hook.Close()
+		subscription := existingSubscription()
+		subscription.URL = hook.URL
+		delivery := pending(t)
+
+		deliveries.On("ClaimDue", mock.Anything, now, batchSize, lease).Return([]*webhook_entity.Delivery{delivery}, nil)
+		subscriptions.On("FindByID", mock.Anything, "sub-1").Return(subscription, nil)
+		deliveries.On("Record", mock.Anything, delivery, mock.MatchedBy(func(a *webhook_entity.Attempt) bool {
+			return a.ResponseCode == 0 && a.Error != ""
+		})).Return(nil).Once()
+
+		_, err := newUsecase(subscriptions, deliveries).DeliverDue(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivery.Attempts)
+		deliveries.AssertExpectations(t)
+	})
+
+	t.Run("inactive subscriptions are not called", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		deliveries := new(MockDeliveryRepository)
+		subscription := existingSubscription()
+		subscription.Active = false
+		delivery := pending(t)
+
+		deliveries.On("ClaimDue", mock.Anything, now, batchSize, lease).Return([]*webhook_entity.Delivery{delivery}, nil)
+		subscriptions.On("FindByID", mock.Anything, "sub-1").Return(subscription, nil)
+		deliveries.On("Record", mock.Anything, delivery, (*webhook_entity.Attempt)(nil)).Return(nil).Once()
+
+		_, err := newUsecase(subscriptions, deliveries).DeliverDue(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, webhook_entity.DeliveryFailed, delivery.Status)
+		assert.Equal(t, "subscription is inactive", delivery.LastError)
+	})
+
+	t.Run("deleted subscriptions are skipped", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		deliveries := new(MockDeliveryRepository)
+
+		deliveries.On("ClaimDue", mock.Anything, now, batchSize, lease).Return([]*webhook_entity.Delivery{pending(t)}, nil)
+		subscriptions.On("FindByID", mock.Anything, "sub-1").Return(nil, port_webhook_repository.ErrNotFound)
+
+		_, err := newUsecase(subscriptions, deliveries).DeliverDue(context.Background())
+
+		require.NoError(t, err)
+		deliveries.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("claim fails", func(t *testing.T) {
+		deliveries := new(MockDeliveryRepository)
+		deliveries.On("ClaimDue", mock.Anything, now, batchSize, lease).Return(nil, errors.New("db error"))
+
+		_, err := newUsecase(new(MockSubscriptionRepository), deliveries).DeliverDue(context.Background())
+
+		assert.ErrorContains(t, err, "failed to claim deliveries")
+	})
+}
@@ -0,0 +1,199 @@
+package webhook_usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	port_audit_usecase "github.com/williamkoller/system-education/internal/audit/port/usecase"
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	port_webhook_repository "github.com/williamkoller/system-education/internal/webhook/port/repository"
+	port_webhook_usecase "github.com/williamkoller/system-education/internal/webhook/port/usecase"
+	webhook_dtos "github.com/williamkoller/system-education/internal/webhook/presentation/dtos"
+	port_transaction "github.com/williamkoller/system-education/shared/port/transaction"
+)
+
+const (
+	auditEntityType         = "webhook"
+	auditDeliveryEntityType = "webhook_delivery"
+)
+
+type WebhookUsecase struct {
+	subscriptions        port_webhook_repository.SubscriptionRepository
+	deliveries           port_webhook_repository.DeliveryRepository
+	events               []string
+	allowPrivateNetworks bool
+	tx                   port_transaction.Transactor
+	audit                port_audit_usecase.Recorder
+}
+
+// NewWebhookUsecase takes the names of the events subscriptions may ask for.
+// Unless allowPrivateNetworks is set, subscription URLs may not point at
+// localhost or a private address.
+func NewWebhookUsecase(subscriptions port_webhook_repository.SubscriptionRepository, deliveries port_webhook_repository.DeliveryRepository, events []string, allowPrivateNetworks bool, tx port_transaction.Transactor, audit port_audit_usecase.Recorder) *WebhookUsecase {
+	return &WebhookUsecase{subscriptions: subscriptions, deliveries: deliveries, events: events, allowPrivateNetworks: allowPrivateNetworks, tx: tx, audit: audit}
+}
+
+var _ port_webhook_usecase.WebhookUsecase = &WebhookUsecase{}
+
+func (w *WebhookUsecase) Create(ctx context.Context, input webhook_dtos.AddWebhookDto) (*webhook_entity.Subscription, error) {
+	active := true
+	if input.Active != nil {
+		active = *input.Active
+	}
+
+	now := time.Now()
+	subscription, err := webhook_entity.NewSubscription(&webhook_entity.Subscription{
+		ID:        uuid.New().String(),
+		URL:       strings.TrimSpace(input.URL),
+		Secret:    input.Secret,
+		Events:    input.Events,
+		Active:    active,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.check(subscription); err != nil {
+		return nil, err
+	}
+
+	var saved *webhook_entity.Subscription
+	err = w.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if saved, err = w.subscriptions.Save(ctx, subscription); err != nil {
+			return err
+		}
+		return w.audit.Record(ctx, audit_entity.ActionCreate, auditEntityType, saved.ID, nil, audit_entity.Snapshot(saved))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func (w *WebhookUsecase) FindAll(ctx context.Context) ([]*webhook_entity.Subscription, error) {
+	return w.subscriptions.FindAll(ctx)
+}
+
+func (w *WebhookUsecase) FindByID(ctx context.Context, id string) (*webhook_entity.Subscription, error) {
+	return w.subscriptions.FindByID(ctx, id)
+}
+
+func (w *WebhookUsecase) Update(ctx context.Context, id string, input webhook_dtos.UpdateWebhookDto) (*webhook_entity.Subscription, error) {
+	subscription, err := w.subscriptions.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := audit_entity.Snapshot(subscription)
+
+	if input.URL != nil {
+		url := strings.TrimSpace(*input.URL)
+		input.URL = &url
+	}
+
+	if err := subscription.UpdateSubscription(input.URL, input.Secret, input.Events, input.Active); err != nil {
+		return nil, err
+	}
+
+	if err := w.check(subscription); err != nil {
+		return nil, err
+	}
+
+	var updated *webhook_entity.Subscription
+	err = w.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = w.subscriptions.Update(ctx, id, subscription); err != nil {
+			return err
+		}
+		return w.audit.Record(ctx, audit_entity.ActionUpdate, auditEntityType, id, before, audit_entity.Snapshot(updated))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (w *WebhookUsecase) Delete(ctx context.Context, id string) error {
+	subscription, err := w.subscriptions.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return w.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := w.subscriptions.Delete(ctx, id); err != nil {
+			return err
+		}
+		return w.audit.Record(ctx, audit_entity.ActionDelete, auditEntityType, id, audit_entity.Snapshot(subscription), nil)
+	})
+}
+
+func (w *WebhookUsecase) FindDeliveries(ctx context.Context, filter webhook_entity.DeliveryFilter) ([]*webhook_entity.Delivery, error) {
+	if _, err := w.subscriptions.FindByID(ctx, filter.SubscriptionID); err != nil {
+		return nil, err
+	}
+	return w.deliveries.Find(ctx, filter)
+}
+
+func (w *WebhookUsecase) Redeliver(ctx context.Context, subscriptionID, deliveryID string) (*webhook_entity.Delivery, error) {
+	delivery, err := w.deliveries.FindByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.SubscriptionID != subscriptionID {
+		return nil, port_webhook_repository.ErrDeliveryNotFound
+	}
+	before := deliveryState(delivery)
+
+	delivery.Redeliver()
+	err = w.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := w.deliveries.Record(ctx, delivery, nil); err != nil {
+			return err
+		}
+		return w.audit.Record(ctx, audit_entity.ActionUpdate, auditDeliveryEntityType, delivery.ID, before, deliveryState(delivery))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (w *WebhookUsecase) check(subscription *webhook_entity.Subscription) error {
+	if !w.allowPrivateNetworks {
+		if err := webhook_entity.ValidationTarget(subscription.URL); err != nil {
+			return err
+		}
+	}
+	return w.checkEvents(subscription.Events)
+}
+
+func (w *WebhookUsecase) checkEvents(events []string) error {
+	var errs []string
+	for _, event := range events {
+		if event != webhook_entity.AllEvents && !slices.Contains(w.events, event) {
+			errs = append(errs, fmt.Sprintf("unknown event %q", event))
+		}
+	}
+	if len(errs) > 0 {
+		return &webhook_entity.ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// deliveryState is what a redelivery changes; the payload and attempt log
+// are left out of the audit trail.
+func deliveryState(d *webhook_entity.Delivery) map[string]any {
+	return map[string]any{
+		"Status":   string(d.Status),
+		"Attempts": d.Attempts,
+	}
+}
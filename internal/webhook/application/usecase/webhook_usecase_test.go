@@ -0,0 +1,323 @@
+package webhook_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	audit_entity "github.com/williamkoller/system-education/internal/audit/domain/entity"
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	port_webhook_repository "github.com/williamkoller/system-education/internal/webhook/port/repository"
+	webhook_dtos "github.com/williamkoller/system-education/internal/webhook/presentation/dtos"
+)
+
+type MockSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionRepository) Save(ctx context.Context, s *webhook_entity.Subscription) (*webhook_entity.Subscription, error) {
+	args := m.Called(ctx, s)
+	result, _ := args.Get(0).(*webhook_entity.Subscription)
+	return result, args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) Update(ctx context.Context, id string, s *webhook_entity.Subscription) (*webhook_entity.Subscription, error) {
+	args := m.Called(ctx, id, s)
+	result, _ := args.Get(0).(*webhook_entity.Subscription)
+	return result, args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockSubscriptionRepository) FindAll(ctx context.Context) ([]*webhook_entity.Subscription, error) {
+	args := m.Called(ctx)
+	result, _ := args.Get(0).([]*webhook_entity.Subscription)
+	return result, args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) FindByID(ctx context.Context, id string) (*webhook_entity.Subscription, error) {
+	args := m.Called(ctx, id)
+	result, _ := args.Get(0).(*webhook_entity.Subscription)
+	return result, args.Error(1)
+}
+
+type MockDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeliveryRepository) Enqueue(ctx context.Context, deliveries ...*webhook_entity.Delivery) error {
+	return m.Called(ctx, deliveries).Error(0)
+}
+
+func (m *MockDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*webhook_entity.Delivery, error) {
+	args := m.Called(ctx, now, limit, lease)
+	result, _ := args.Get(0).([]*webhook_entity.Delivery)
+	return result, args.Error(1)
+}
+
+func (m *MockDeliveryRepository) Record(ctx context.Context, d *webhook_entity.Delivery, attempt *webhook_entity.Attempt) error {
+	return m.Called(ctx, d, attempt).Error(0)
+}
+
+func (m *MockDeliveryRepository) FindByID(ctx context.Context, id string) (*webhook_entity.Delivery, error) {
+	args := m.Called(ctx, id)
+	result, _ := args.Get(0).(*webhook_entity.Delivery)
+	return result, args.Error(1)
+}
+
+func (m *MockDeliveryRepository) Find(ctx context.Context, filter webhook_entity.DeliveryFilter) ([]*webhook_entity.Delivery, error) {
+	args := m.Called(ctx, filter)
+	result, _ := args.Get(0).([]*webhook_entity.Delivery)
+	return result, args.Error(1)
+}
+
+// stubTransactor runs fn directly; the repositories are mocked anyway.
+type stubTransactor struct{}
+
+func (stubTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditRecorder struct {
+	mock.Mock
+}
+
+func (m *MockAuditRecorder) Record(ctx context.Context, action audit_entity.Action, entityType, entityID string, before, after map[string]any) error {
+	args := m.Called(ctx, action, entityType, entityID, before, after)
+	return args.Error(0)
+}
+
+func ignoreAudit() *MockAuditRecorder {
+	audit := new(MockAuditRecorder)
+	audit.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return audit
+}
+
+var knownEvents = []string{"school.created", "school.updated"}
+
+func existingSubscription() *webhook_entity.Subscription {
+	return &webhook_entity.Subscription{
+		ID:     "sub-1",
+		URL:    "https://lms.example.com/hooks",
+		Secret: "0123456789abcdef",
+		Events: []string{"school.created"},
+		Active: true,
+	}
+}
+
+func TestWebhookUsecase_Create(t *testing.T) {
+	t.Run("saves an active subscription", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewWebhookUsecase(subscriptions, new(MockDeliveryRepository), knownEvents, false, stubTransactor{}, audit)
+
+		subscriptions.On("Save", mock.Anything, mock.MatchedBy(func(s *webhook_entity.Subscription) bool {
+			return s.URL == "https://lms.example.com/hooks" && s.Active && s.ID != ""
+		})).Return(existingSubscription(), nil)
+		audit.On("Record", mock.Anything, audit_entity.ActionCreate, "webhook", "sub-1", map[string]any(nil), mock.MatchedBy(func(after map[string]any) bool {
+			return after["URL"] == "https://lms.example.com/hooks"
+		})).Return(nil).Once()
+
+		s, err := usecase.Create(context.Background(), webhook_dtos.AddWebhookDto{
+			URL:    " https://lms.example.com/hooks ",
+			Secret: "0123456789abcdef",
+			Events: []string{"school.created"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "sub-1", s.ID)
+		subscriptions.AssertExpectations(t)
+		audit.AssertExpectations(t)
+	})
+
+	t.Run("rejects unknown events", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		usecase := NewWebhookUsecase(subscriptions, new(MockDeliveryRepository), knownEvents, false, stubTransactor{}, ignoreAudit())
+
+		_, err := usecase.Create(context.Background(), webhook_dtos.AddWebhookDto{
+			URL:    "https://lms.example.com/hooks",
+			Secret: "0123456789abcdef",
+			Events: []string{"school.created", "school.exploded"},
+		})
+
+		var validationErr *webhook_entity.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{`unknown event "school.exploded"`}, validationErr.Errors)
+		subscriptions.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects private network targets", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		usecase := NewWebhookUsecase(subscriptions, new(MockDeliveryRepository), knownEvents, false, stubTransactor{}, ignoreAudit())
+
+		_, err := usecase.Create(context.Background(), webhook_dtos.AddWebhookDto{
+			URL:    "http://169.254.169.254/latest/meta-data",
+			Secret: "0123456789abcdef",
+			Events: []string{"school.created"},
+		})
+
+		var validationErr *webhook_entity.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		subscriptions.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("accepts private network targets when allowed", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		usecase := NewWebhookUsecase(subscriptions, new(MockDeliveryRepository), knownEvents, true, stubTransactor{}, ignoreAudit())
+		subscriptions.On("Save", mock.Anything, mock.Anything).Return(existingSubscription(), nil)
+
+		_, err := usecase.Create(context.Background(), webhook_dtos.AddWebhookDto{
+			URL:    "http://localhost:9000/hooks",
+			Secret: "0123456789abcdef",
+			Events: []string{"school.created"},
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("accepts every event", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		usecase := NewWebhookUsecase(subscriptions, new(MockDeliveryRepository), knownEvents, false, stubTransactor{}, ignoreAudit())
+		subscriptions.On("Save", mock.Anything, mock.Anything).Return(existingSubscription(), nil)
+
+		_, err := usecase.Create(context.Background(), webhook_dtos.AddWebhookDto{
+			URL:    "https://lms.example.com/hooks",
+			Secret: "0123456789abcdef",
+			Events: []string{webhook_entity.AllEvents},
+		})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestWebhookUsecase_Update(t *testing.T) {
+	t.Run("audits without the secret", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewWebhookUsecase(subscriptions, new(MockDeliveryRepository), knownEvents, false, stubTransactor{}, audit)
+
+		existing := existingSubscription()
+		subscriptions.On("FindByID", mock.Anything, "sub-1").Return(existing, nil)
+		subscriptions.On("Update", mock.Anything, "sub-1", existing).Return(existing, nil)
+
+		var changes audit_entity.Changes
+		audit.On("Record", mock.Anything, audit_entity.ActionUpdate, "webhook", "sub-1", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				changes = audit_entity.Diff(args.Get(4).(map[string]any), args.Get(5).(map[string]any))
+			}).Return(nil).Once()
+
+		secret := "fedcba9876543210"
+		active := false
+		_, err := usecase.Update(context.Background(), "sub-1", webhook_dtos.UpdateWebhookDto{Secret: &secret, Active: &active})
+
+		assert.NoError(t, err)
+		assert.Equal(t, audit_entity.Change{From: audit_entity.Redacted, To: audit_entity.Redacted}, changes["Secret"])
+		assert.Equal(t, audit_entity.Change{From: true, To: false}, changes["Active"])
+	})
+
+	t.Run("validates", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		usecase := NewWebhookUsecase(subscriptions, new(MockDeliveryRepository), knownEvents, false, stubTransactor{}, ignoreAudit())
+		subscriptions.On("FindByID", mock.Anything, "sub-1").Return(existingSubscription(), nil)
+
+		secret := "short"
+		_, err := usecase.Update(context.Background(), "sub-1", webhook_dtos.UpdateWebhookDto{Secret: &secret})
+
+		var validationErr *webhook_entity.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		subscriptions.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestWebhookUsecase_Delete(t *testing.T) {
+	subscriptions := new(MockSubscriptionRepository)
+	audit := new(MockAuditRecorder)
+	usecase := NewWebhookUsecase(subscriptions, new(MockDeliveryRepository), knownEvents, false, stubTransactor{}, audit)
+
+	subscriptions.On("FindByID", mock.Anything, "sub-1").Return(existingSubscription(), nil)
+	subscriptions.On("Delete", mock.Anything, "sub-1").Return(nil)
+	audit.On("Record", mock.Anything, audit_entity.ActionDelete, "webhook", "sub-1", mock.Anything, map[string]any(nil)).Return(nil).Once()
+
+	assert.NoError(t, usecase.Delete(context.Background(), "sub-1"))
+	audit.AssertExpectations(t)
+}
+
+func TestWebhookUsecase_FindDeliveries(t *testing.T) {
+	t.Run("unknown subscription", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		deliveries := new(MockDeliveryRepository)
+		usecase := NewWebhookUsecase(subscriptions, deliveries, knownEvents, false, stubTransactor{}, ignoreAudit())
+		subscriptions.On("FindByID", mock.Anything, "sub-1").Return(nil, port_webhook_repository.ErrNotFound)
+
+		_, err := usecase.FindDeliveries(context.Background(), webhook_entity.DeliveryFilter{SubscriptionID: "sub-1"})
+
+		assert.ErrorIs(t, err, port_webhook_repository.ErrNotFound)
+		deliveries.AssertNotCalled(t, "Find", mock.Anything, mock.Anything)
+	})
+
+	t.Run("found", func(t *testing.T) {
+		subscriptions := new(MockSubscriptionRepository)
+		deliveries := new(MockDeliveryRepository)
+		usecase := NewWebhookUsecase(subscriptions, deliveries, knownEvents, false, stubTransactor{}, ignoreAudit())
+		filter := webhook_entity.DeliveryFilter{SubscriptionID: "sub-1", Status: webhook_entity.DeliveryFailed}
+		subscriptions.On("FindByID", mock.Anything, "sub-1").Return(existingSubscription(), nil)
+		deliveries.On("Find", mock.Anything, filter).Return([]*webhook_entity.Delivery{{ID: "del-1"}}, nil)
+
+		found, err := usecase.FindDeliveries(context.Background(), filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, found, 1)
+	})
+}
+
+func TestWebhookUsecase_Redeliver(t *testing.T) {
+	t.Run("queues the delivery again", func(t *testing.T) {
+		deliveries := new(MockDeliveryRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewWebhookUsecase(new(MockSubscriptionRepository), deliveries, knownEvents, false, stubTransactor{}, audit)
+
+		delivery := &webhook_entity.Delivery{ID: "del-1", SubscriptionID: "sub-1", Status: webhook_entity.DeliveryFailed, Attempts: 8}
+		deliveries.On("FindByID", mock.Anything, "del-1").Return(delivery, nil)
+		deliveries.On("Record", mock.Anything, delivery, (*webhook_entity.Attempt)(nil)).Return(nil)
+		audit.On("Record", mock.Anything, audit_entity.ActionUpdate, "webhook_delivery", "del-1",
+			map[string]any{"Status": "failed", "Attempts": 8},
+			map[string]any{"Status": "pending", "Attempts": 0},
+		).Return(nil).Once()
+
+		redelivered, err := usecase.Redeliver(context.Background(), "sub-1", "del-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, webhook_entity.DeliveryPending, redelivered.Status)
+		audit.AssertExpectations(t)
+	})
+
+	t.Run("belongs to another subscription", func(t *testing.T) {
+		deliveries := new(MockDeliveryRepository)
+		usecase := NewWebhookUsecase(new(MockSubscriptionRepository), deliveries, knownEvents, false, stubTransactor{}, ignoreAudit())
+		deliveries.On("FindByID", mock.Anything, "del-1").Return(&webhook_entity.Delivery{ID: "del-1", SubscriptionID: "sub-2"}, nil)
+
+		_, err := usecase.Redeliver(context.Background(), "sub-1", "del-1")
+
+		assert.ErrorIs(t, err, port_webhook_repository.ErrDeliveryNotFound)
+		deliveries.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("record fails", func(t *testing.T) {
+		deliveries := new(MockDeliveryRepository)
+		audit := new(MockAuditRecorder)
+		usecase := NewWebhookUsecase(new(MockSubscriptionRepository), deliveries, knownEvents, false, stubTransactor{}, audit)
+		deliveries.On("FindByID", mock.Anything, "del-1").Return(&webhook_entity.Delivery{ID: "del-1", SubscriptionID: "sub-1"}, nil)
+		deliveries.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		_, err := usecase.Redeliver(context.Background(), "sub-1", "del-1")
+
+		assert.Error(t, err)
+		audit.AssertNotCalled(t, "Record")
+	})
+}
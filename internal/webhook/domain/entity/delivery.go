@@ -0,0 +1,137 @@
+package webhook_entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is one event on its way to one subscription. EventID is the
+// outbox id, so a redelivered event does not create a second delivery.
+type Delivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventName      string
+	Payload        string
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseCode   int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	AttemptLog     []*Attempt
+}
+
+// Attempt is one POST to the subscriber. ResponseCode is 0 when no response
+// arrived.
+type Attempt struct {
+	ID           string
+	DeliveryID   string
+	ResponseCode int
+	Error        string
+	Duration     time.Duration
+	AttemptedAt  time.Time
+}
+
+// envelope is the JSON body subscribers receive.
+type envelope struct {
+	ID         string             `json:"id"`
+	Event      string             `json:"event"`
+	OccurredAt time.Time          `json:"occurredAt"`
+	Data       shared_event.Event `json:"data"`
+}
+
+func NewDelivery(subscriptionID, eventID string, event shared_event.Event) (*Delivery, error) {
+	payload, err := json.Marshal(envelope{
+		ID:         eventID,
+		Event:      event.EventName(),
+		OccurredAt: event.OccurredOn(),
+		Data:       event,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Delivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventName:      event.EventName(),
+		Payload:        string(payload),
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// Succeeded reports whether the subscriber accepted the delivery.
+func Succeeded(responseCode int) bool {
+	return responseCode >= 200 && responseCode < 300
+}
+
+// MaxRetryDelay caps the exponential backoff between attempts.
+const MaxRetryDelay = 6 * time.Hour
+
+// Record applies the outcome of an attempt. A failed attempt is retried
+// after backoff, doubled for every earlier attempt, unless it was the last
+// one allowed.
+func (d *Delivery) Record(attempt *Attempt, maxAttempts int, backoff time.Duration) {
+	d.Attempts++
+	d.ResponseCode = attempt.ResponseCode
+	d.LastError = attempt.Error
+	d.UpdatedAt = attempt.AttemptedAt
+	d.AttemptLog = append(d.AttemptLog, attempt)
+
+	switch {
+	case attempt.Error == "" && Succeeded(attempt.ResponseCode):
+		d.Status = DeliverySucceeded
+		d.DeliveredAt = &attempt.AttemptedAt
+	case d.Attempts >= maxAttempts:
+		d.Status = DeliveryFailed
+	default:
+		d.NextAttemptAt = attempt.AttemptedAt.Add(RetryDelay(backoff, d.Attempts))
+	}
+}
+
+// Abandon fails the delivery without attempting it, e.g. when its
+// subscription has been deactivated. It can still be redelivered.
+func (d *Delivery) Abandon(reason string) {
+	d.Status = DeliveryFailed
+	d.LastError = reason
+	d.UpdatedAt = time.Now()
+}
+
+// RetryDelay is the wait after the given number of failed attempts.
+func RetryDelay(backoff time.Duration, attempts int) time.Duration {
+	delay := backoff
+	for i := 1; i < attempts && delay < MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, MaxRetryDelay)
+}
+
+// Redeliver queues the delivery again with a fresh set of attempts. Earlier
+// attempts stay in the log.
+func (d *Delivery) Redeliver() {
+	now := time.Now()
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.DeliveredAt = nil
+	d.UpdatedAt = now
+}
@@ -0,0 +1,62 @@
+package webhook_entity
+
+import (
+	"slices"
+	"time"
+)
+
+// AllEvents in a subscription's filter matches every event name.
+const AllEvents = "*"
+
+// Subscription is an endpoint that receives signed copies of domain events.
+// Secret is only ever written, never returned by the API.
+type Subscription struct {
+	ID        string
+	URL       string
+	Secret    string
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewSubscription(s *Subscription) (*Subscription, error) {
+	vs, err := ValidationSubscription(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Subscription{
+		ID:        vs.ID,
+		URL:       vs.URL,
+		Secret:    vs.Secret,
+		Events:    vs.Events,
+		Active:    vs.Active,
+		CreatedAt: vs.CreatedAt,
+		UpdatedAt: vs.UpdatedAt,
+	}, nil
+}
+
+func (s *Subscription) UpdateSubscription(url, secret *string, events *[]string, active *bool) error {
+	if url != nil {
+		s.URL = *url
+	}
+	if secret != nil {
+		s.Secret = *secret
+	}
+	if events != nil {
+		s.Events = *events
+	}
+	if active != nil {
+		s.Active = *active
+	}
+
+	s.UpdatedAt = time.Now()
+
+	_, err := ValidationSubscription(s)
+	return err
+}
+
+func (s *Subscription) Matches(eventName string) bool {
+	return s.Active && (slices.Contains(s.Events, AllEvents) || slices.Contains(s.Events, eventName))
+}
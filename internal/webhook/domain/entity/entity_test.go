@@ -0,0 +1,173 @@
+package webhook_entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type studentEnrolled struct {
+	StudentID string
+	Date      time.Time
+}
+
+func (e *studentEnrolled) EventName() string     { return "student.enrolled" }
+func (e *studentEnrolled) OccurredOn() time.Time { return e.Date }
+
+func validSubscription() *Subscription {
+	return &Subscription{
+		ID:     "sub-1",
+		URL:    "https://lms.example.com/hooks",
+		Secret: "0123456789abcdef",
+		Events: []string{"school.created"},
+		Active: true,
+	}
+}
+
+func TestNewSubscription(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		s, err := NewSubscription(validSubscription())
+		assert.NoError(t, err)
+		assert.Equal(t, "https://lms.example.com/hooks", s.URL)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewSubscription(&Subscription{URL: "ftp://example.com", Secret: "short"})
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{
+			"url must be an absolute http or https URL",
+			"secret must be at least 16 characters",
+			"events is required",
+		}, validationErr.Errors)
+	})
+}
+
+func TestValidationTarget(t *testing.T) {
+	for _, url := range []string{
+		"http://localhost:8080/hooks",
+		"http://api.localhost/hooks",
+		"http://127.0.0.1/hooks",
+		"http://10.0.0.5/hooks",
+		"http://192.168.1.10/hooks",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hooks",
+		"http://[::ffff:127.0.0.1]/hooks",
+		"http://[fe80::1]/hooks",
+		"http://0.0.0.0/hooks",
+	} {
+		assert.Error(t, ValidationTarget(url), url)
+	}
+
+	assert.NoError(t, ValidationTarget("https://lms.example.com/hooks"))
+	assert.NoError(t, ValidationTarget("https://93.184.216.34/hooks"))
+}
+
+func TestSubscription_UpdateSubscription(t *testing.T) {
+	s := validSubscription()
+	active := false
+	events := []string{AllEvents}
+
+	assert.NoError(t, s.UpdateSubscription(nil, nil, &events, &active))
+	assert.Equal(t, []string{AllEvents}, s.Events)
+	assert.False(t, s.Active)
+
+	url := "not a url"
+	assert.Error(t, s.UpdateSubscription(&url, nil, nil, nil))
+}
+
+func TestSubscription_Matches(t *testing.T) {
+	s := validSubscription()
+	assert.True(t, s.Matches("school.created"))
+	assert.False(t, s.Matches("school.updated"))
+
+	s.Events = []string{AllEvents}
+	assert.True(t, s.Matches("school.updated"))
+
+	s.Active = false
+	assert.False(t, s.Matches("school.updated"))
+}
+
+func TestNewDelivery(t *testing.T) {
+	occurred := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	d, err := NewDelivery("sub-1", "evt-1", &studentEnrolled{StudentID: "st-1", Date: occurred})
+
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryPending, d.Status)
+	assert.Equal(t, "student.enrolled", d.EventName)
+	assert.JSONEq(t, `{
+		"id": "evt-1",
+		"event": "student.enrolled",
+		"occurredAt": "2026-01-01T12:00:00Z",
+		"data": {"StudentID": "st-1", "Date": "2026-01-01T12:00:00Z"}
+	}`, d.Payload)
+	assert.True(t, json.Valid([]byte(d.Payload)))
+}
+
+func TestDelivery_Record(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		d := &Delivery{Status: DeliveryPending}
+		d.Record(&Attempt{ResponseCode: 204, AttemptedAt: now}, 3, time.Minute)
+
+		assert.Equal(t, DeliverySucceeded, d.Status)
+		assert.Equal(t, &now, d.DeliveredAt)
+		assert.Len(t, d.AttemptLog, 1)
+	})
+
+	t.Run("failure is retried until the last attempt", func(t *testing.T) {
+		d := &Delivery{Status: DeliveryPending}
+		d.Record(&Attempt{ResponseCode: 500, AttemptedAt: now}, 3, time.Minute)
+
+		assert.Equal(t, DeliveryPending, d.Status)
+		assert.Equal(t, now.Add(time.Minute), d.NextAttemptAt)
+		assert.Equal(t, 500, d.ResponseCode)
+
+		d.Record(&Attempt{ResponseCode: 503, AttemptedAt: now}, 3, time.Minute)
+		assert.Equal(t, now.Add(2*time.Minute), d.NextAttemptAt)
+
+		d.Record(&Attempt{Error: "connection refused", AttemptedAt: now}, 3, time.Minute)
+		assert.Equal(t, DeliveryFailed, d.Status)
+		assert.Equal(t, 0, d.ResponseCode)
+		assert.Equal(t, "connection refused", d.LastError)
+	})
+
+	t.Run("abandon", func(t *testing.T) {
+		d := &Delivery{Status: DeliveryPending}
+		d.Abandon("subscription is inactive")
+
+		assert.Equal(t, DeliveryFailed, d.Status)
+		assert.Equal(t, "subscription is inactive", d.LastError)
+		assert.Zero(t, d.Attempts)
+	})
+
+	t.Run("redeliver starts over", func(t *testing.T) {
+		d := &Delivery{Status: DeliveryFailed, Attempts: 2}
+		d.Redeliver()
+
+		assert.Equal(t, DeliveryPending, d.Status)
+		assert.Zero(t, d.Attempts)
+		assert.WithinDuration(t, time.Now(), d.NextAttemptAt, time.Second)
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryDelay(30*time.Second, 1))
+	assert.Equal(t, 4*time.Minute, RetryDelay(30*time.Second, 4))
+	assert.Equal(t, MaxRetryDelay, RetryDelay(30*time.Second, 40))
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"evt-1"}`)
+	signature := Sign("0123456789abcdef", 1767268800, body)
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.True(t, Verify("0123456789abcdef", 1767268800, body, signature))
+	assert.False(t, Verify("0123456789abcdef", 1767268801, body, signature))
+	assert.False(t, Verify("another-secret-value", 1767268800, body, signature))
+}
@@ -0,0 +1,29 @@
+package webhook_entity
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// DeliveryFilter selects a subscription's deliveries, newest first. An empty
+// Status matches every status.
+type DeliveryFilter struct {
+	SubscriptionID string
+	Status         DeliveryStatus
+	Limit          int
+	Offset         int
+}
+
+// Normalized clamps Limit to (0, MaxLimit] and Offset to zero or more.
+func (f DeliveryFilter) Normalized() DeliveryFilter {
+	if f.Limit <= 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	return f
+}
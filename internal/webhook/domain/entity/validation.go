@@ -0,0 +1,77 @@
+package webhook_entity
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// MinSecretLength keeps signatures from being brute-forced offline.
+const MinSecretLength = 16
+
+type ValidationError struct {
+	Errors []string
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("validation failed: %s", strings.Join(v.Errors, ", "))
+}
+
+// PrivateAddress reports whether addr is on the server's own network:
+// loopback, private, link-local or unspecified. Subscribers must not make
+// the server call such addresses.
+func PrivateAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast()
+}
+
+// ValidationTarget rejects a URL whose host is localhost or a private address
+// literal. A host name may still resolve to a private address, so the sender
+// checks the address it connects to as well.
+func ValidationTarget(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return &ValidationError{Errors: []string{"url must be an absolute http or https URL"}}
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return &ValidationError{Errors: []string{"url must not point to a private network"}}
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && PrivateAddress(addr) {
+		return &ValidationError{Errors: []string{"url must not point to a private network"}}
+	}
+
+	return nil
+}
+
+func ValidationSubscription(s *Subscription) (*Subscription, error) {
+	var errs []string
+
+	if u, err := url.Parse(s.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, "url must be an absolute http or https URL")
+	}
+
+	if len(s.Secret) < MinSecretLength {
+		errs = append(errs, fmt.Sprintf("secret must be at least %d characters", MinSecretLength))
+	}
+
+	if len(s.Events) == 0 {
+		errs = append(errs, "events is required")
+	}
+
+	for _, event := range s.Events {
+		if strings.TrimSpace(event) == "" {
+			errs = append(errs, "events must not contain empty names")
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,172 @@
+package webhook_model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+)
+
+type Subscription struct {
+	ID        string `gorm:"primaryKey;type:uuid"`
+	URL       string
+	Secret    string
+	Events    pq.StringArray `gorm:"type:text[]"`
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+type Delivery struct {
+	ID             string `gorm:"primaryKey;type:uuid"`
+	SubscriptionID string `gorm:"type:uuid;uniqueIndex:idx_webhook_deliveries_event"`
+	EventID        string `gorm:"type:uuid;uniqueIndex:idx_webhook_deliveries_event"`
+	EventName      string
+	Payload        string `gorm:"type:jsonb"`
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseCode   int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	AttemptLog     []*Attempt `gorm:"foreignKey:DeliveryID"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+type Attempt struct {
+	ID           string `gorm:"primaryKey;type:uuid"`
+	DeliveryID   string `gorm:"type:uuid;index"`
+	ResponseCode int
+	Error        string
+	DurationMs   int64
+	AttemptedAt  time.Time
+}
+
+func (Attempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
+
+func FromEntity(s *webhook_entity.Subscription) *Subscription {
+	if s == nil {
+		return nil
+	}
+	return &Subscription{
+		ID:        s.ID,
+		URL:       s.URL,
+		Secret:    s.Secret,
+		Events:    pq.StringArray(s.Events),
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func ToEntity(s *Subscription) *webhook_entity.Subscription {
+	if s == nil {
+		return nil
+	}
+	return &webhook_entity.Subscription{
+		ID:        s.ID,
+		URL:       s.URL,
+		Secret:    s.Secret,
+		Events:    []string(s.Events),
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func ToEntities(ss []*Subscription) []*webhook_entity.Subscription {
+	entities := make([]*webhook_entity.Subscription, 0, len(ss))
+	for _, s := range ss {
+		entities = append(entities, ToEntity(s))
+	}
+	return entities
+}
+
+// FromDelivery leaves out the attempt log, which is only ever appended to.
+func FromDelivery(d *webhook_entity.Delivery) *Delivery {
+	if d == nil {
+		return nil
+	}
+	return &Delivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventName:      d.EventName,
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseCode:   d.ResponseCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+func ToDelivery(d *Delivery) *webhook_entity.Delivery {
+	if d == nil {
+		return nil
+	}
+	attempts := make([]*webhook_entity.Attempt, 0, len(d.AttemptLog))
+	for _, a := range d.AttemptLog {
+		attempts = append(attempts, ToAttempt(a))
+	}
+	return &webhook_entity.Delivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventName:      d.EventName,
+		Payload:        d.Payload,
+		Status:         webhook_entity.DeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseCode:   d.ResponseCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+		AttemptLog:     attempts,
+	}
+}
+
+func ToDeliveries(ds []*Delivery) []*webhook_entity.Delivery {
+	entities := make([]*webhook_entity.Delivery, 0, len(ds))
+	for _, d := range ds {
+		entities = append(entities, ToDelivery(d))
+	}
+	return entities
+}
+
+func FromAttempt(a *webhook_entity.Attempt) *Attempt {
+	return &Attempt{
+		ID:           a.ID,
+		DeliveryID:   a.DeliveryID,
+		ResponseCode: a.ResponseCode,
+		Error:        a.Error,
+		DurationMs:   a.Duration.Milliseconds(),
+		AttemptedAt:  a.AttemptedAt,
+	}
+}
+
+func ToAttempt(a *Attempt) *webhook_entity.Attempt {
+	return &webhook_entity.Attempt{
+		ID:           a.ID,
+		DeliveryID:   a.DeliveryID,
+		ResponseCode: a.ResponseCode,
+		Error:        a.Error,
+		Duration:     time.Duration(a.DurationMs) * time.Millisecond,
+		AttemptedAt:  a.AttemptedAt,
+	}
+}
@@ -0,0 +1,113 @@
+package webhook_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	webhook_model "github.com/williamkoller/system-education/internal/webhook/infra/db/model"
+	port_webhook_repository "github.com/williamkoller/system-education/internal/webhook/port/repository"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeliveryGormRepository struct {
+	db *gorm.DB
+}
+
+func NewDeliveryGormRepository(db *gorm.DB) *DeliveryGormRepository {
+	return &DeliveryGormRepository{db: db}
+}
+
+var _ port_webhook_repository.DeliveryRepository = &DeliveryGormRepository{}
+
+func (r *DeliveryGormRepository) Enqueue(ctx context.Context, deliveries ...*webhook_entity.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	models := make([]*webhook_model.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		models = append(models, webhook_model.FromDelivery(d))
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "subscription_id"}, {Name: "event_id"}}, DoNothing: true}).
+		Create(&models).Error
+}
+
+func (r *DeliveryGormRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*webhook_entity.Delivery, error) {
+	var models []*webhook_model.Delivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", webhook_entity.DeliveryPending, now).
+			Order("next_attempt_at, created_at").
+			Limit(limit).
+			Find(&models).Error
+		if err != nil || len(models) == 0 {
+			return err
+		}
+
+		ids := make([]string, 0, len(models))
+		for _, m := range models {
+			ids = append(ids, m.ID)
+		}
+		return tx.Model(&webhook_model.Delivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return webhook_model.ToDeliveries(models), nil
+}
+
+func (r *DeliveryGormRepository) Record(ctx context.Context, d *webhook_entity.Delivery, attempt *webhook_entity.Attempt) error {
+	return shared_database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&webhook_model.Delivery{}).
+			Where("id = ?", d.ID).
+			Select("status", "attempts", "next_attempt_at", "response_code", "last_error", "delivered_at", "updated_at").
+			Updates(webhook_model.FromDelivery(d))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return port_webhook_repository.ErrDeliveryNotFound
+		}
+		if attempt == nil {
+			return nil
+		}
+		return tx.Create(webhook_model.FromAttempt(attempt)).Error
+	})
+}
+
+func (r *DeliveryGormRepository) FindByID(ctx context.Context, id string) (*webhook_entity.Delivery, error) {
+	var model webhook_model.Delivery
+	if err := r.withAttempts(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, port_webhook_repository.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return webhook_model.ToDelivery(&model), nil
+}
+
+func (r *DeliveryGormRepository) Find(ctx context.Context, filter webhook_entity.DeliveryFilter) ([]*webhook_entity.Delivery, error) {
+	filter = filter.Normalized()
+	query := r.withAttempts(ctx).Where("subscription_id = ?", filter.SubscriptionID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var models []*webhook_model.Delivery
+	err := query.Order("created_at DESC, id").Limit(filter.Limit).Offset(filter.Offset).Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return webhook_model.ToDeliveries(models), nil
+}
+
+func (r *DeliveryGormRepository) withAttempts(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Preload("AttemptLog", func(db *gorm.DB) *gorm.DB {
+		return db.Order("attempted_at, id")
+	})
+}
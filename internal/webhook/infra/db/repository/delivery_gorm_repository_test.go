@@ -0,0 +1,124 @@
+package webhook_repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	port_webhook_repository "github.com/williamkoller/system-education/internal/webhook/port/repository"
+	"gorm.io/gorm"
+)
+
+type schoolCreated struct {
+	SchoolID string
+	Date     time.Time
+}
+
+func (e *schoolCreated) EventName() string     { return "school.created" }
+func (e *schoolCreated) OccurredOn() time.Time { return e.Date }
+
+func newDelivery(t *testing.T, subscriptionID, eventID string) *webhook_entity.Delivery {
+	d, err := webhook_entity.NewDelivery(subscriptionID, eventID, &schoolCreated{SchoolID: "school-1"})
+	require.NoError(t, err)
+	return d
+}
+
+type DeliveryGormRepositorySuite struct {
+	suite.Suite
+	db         *gorm.DB
+	repository *DeliveryGormRepository
+	now        time.Time
+}
+
+func (s *DeliveryGormRepositorySuite) SetupTest() {
+	s.db = setupTestDB(s.T())
+	s.repository = NewDeliveryGormRepository(s.db)
+	s.now = time.Now().Add(time.Minute)
+	saveSubscription(s.T(), s.db, "sub-1", "school.created")
+}
+
+func (s *DeliveryGormRepositorySuite) TestEnqueue_SkipsDuplicates() {
+	ctx := context.Background()
+	s.NoError(s.repository.Enqueue(ctx, newDelivery(s.T(), "sub-1", "evt-1")))
+	s.NoError(s.repository.Enqueue(ctx, newDelivery(s.T(), "sub-1", "evt-1"), newDelivery(s.T(), "sub-1", "evt-2")))
+
+	found, err := s.repository.Find(ctx, webhook_entity.DeliveryFilter{SubscriptionID: "sub-1"})
+	s.NoError(err)
+	s.Len(found, 2)
+}
+
+func (s *DeliveryGormRepositorySuite) TestClaimDue_LeasesClaimedDeliveries() {
+	ctx := context.Background()
+	s.Require().NoError(s.repository.Enqueue(ctx, newDelivery(s.T(), "sub-1", "evt-1"), newDelivery(s.T(), "sub-1", "evt-2")))
+
+	claimed, err := s.repository.ClaimDue(ctx, s.now, 1, time.Minute)
+	s.NoError(err)
+	s.Len(claimed, 1)
+
+	claimed, err = s.repository.ClaimDue(ctx, s.now, 10, time.Minute)
+	s.NoError(err)
+	s.Len(claimed, 1, "the first delivery is leased")
+
+	claimed, err = s.repository.ClaimDue(ctx, s.now.Add(2*time.Minute), 10, time.Minute)
+	s.NoError(err)
+	s.Len(claimed, 2, "leases expire")
+}
+
+func (s *DeliveryGormRepositorySuite) TestRecord_KeepsAttemptLog() {
+	ctx := context.Background()
+	delivery := newDelivery(s.T(), "sub-1", "evt-1")
+	s.Require().NoError(s.repository.Enqueue(ctx, delivery))
+
+	first := &webhook_entity.Attempt{ID: "att-1", DeliveryID: delivery.ID, ResponseCode: 500, Error: "received status 500", Duration: 20 * time.Millisecond, AttemptedAt: s.now}
+	delivery.Record(first, 3, time.Minute)
+	s.Require().NoError(s.repository.Record(ctx, delivery, first))
+
+	second := &webhook_entity.Attempt{ID: "att-2", DeliveryID: delivery.ID, ResponseCode: 200, AttemptedAt: s.now.Add(time.Minute)}
+	delivery.Record(second, 3, time.Minute)
+	s.Require().NoError(s.repository.Record(ctx, delivery, second))
+
+	found, err := s.repository.FindByID(ctx, delivery.ID)
+	s.NoError(err)
+	s.Equal(webhook_entity.DeliverySucceeded, found.Status)
+	s.Equal(200, found.ResponseCode)
+	s.Equal(2, found.Attempts)
+	s.Require().Len(found.AttemptLog, 2)
+	s.Equal(500, found.AttemptLog[0].ResponseCode)
+	s.Equal(20*time.Millisecond, found.AttemptLog[0].Duration)
+	s.Equal(200, found.AttemptLog[1].ResponseCode)
+
+	claimed, _ := s.repository.ClaimDue(ctx, s.now.Add(time.Hour), 10, time.Minute)
+	s.Empty(claimed, "delivered deliveries are not claimed again")
+}
+
+func (s *DeliveryGormRepositorySuite) TestFind_FiltersByStatus() {
+	ctx := context.Background()
+	failed := newDelivery(s.T(), "sub-1", "evt-1")
+	s.Require().NoError(s.repository.Enqueue(ctx, failed, newDelivery(s.T(), "sub-1", "evt-2")))
+	failed.Abandon("subscription is inactive")
+	s.Require().NoError(s.repository.Record(ctx, failed, nil))
+
+	found, err := s.repository.Find(ctx, webhook_entity.DeliveryFilter{SubscriptionID: "sub-1", Status: webhook_entity.DeliveryFailed})
+	s.NoError(err)
+	s.Require().Len(found, 1)
+	s.Equal(failed.ID, found[0].ID)
+
+	found, err = s.repository.Find(ctx, webhook_entity.DeliveryFilter{SubscriptionID: "sub-2"})
+	s.NoError(err)
+	s.Empty(found)
+}
+
+func (s *DeliveryGormRepositorySuite) TestNotFound() {
+	_, err := s.repository.FindByID(context.Background(), "missing")
+	s.ErrorIs(err, port_webhook_repository.ErrDeliveryNotFound)
+
+	err = s.repository.Record(context.Background(), &webhook_entity.Delivery{ID: "missing"}, nil)
+	s.ErrorIs(err, port_webhook_repository.ErrDeliveryNotFound)
+}
+
+func TestDeliveryGormRepository(t *testing.T) {
+	suite.Run(t, new(DeliveryGormRepositorySuite))
+}
@@ -0,0 +1,88 @@
+package webhook_repository
+
+import (
+	"context"
+	"errors"
+
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	webhook_model "github.com/williamkoller/system-education/internal/webhook/infra/db/model"
+	port_webhook_repository "github.com/williamkoller/system-education/internal/webhook/port/repository"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	"gorm.io/gorm"
+)
+
+type SubscriptionGormRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionGormRepository(db *gorm.DB) *SubscriptionGormRepository {
+	return &SubscriptionGormRepository{db: db}
+}
+
+var _ port_webhook_repository.SubscriptionRepository = &SubscriptionGormRepository{}
+
+func (r *SubscriptionGormRepository) Save(ctx context.Context, s *webhook_entity.Subscription) (*webhook_entity.Subscription, error) {
+	model := webhook_model.FromEntity(s)
+	if err := shared_database.Conn(ctx, r.db).Create(model).Error; err != nil {
+		return nil, err
+	}
+	return webhook_model.ToEntity(model), nil
+}
+
+func (r *SubscriptionGormRepository) Update(ctx context.Context, id string, s *webhook_entity.Subscription) (*webhook_entity.Subscription, error) {
+	model := webhook_model.FromEntity(s)
+	result := shared_database.Conn(ctx, r.db).Model(&webhook_model.Subscription{}).
+		Where("id = ?", id).
+		Select("url", "secret", "events", "active", "updated_at").
+		Updates(model)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, port_webhook_repository.ErrNotFound
+	}
+
+	return webhook_model.ToEntity(model), nil
+}
+
+// Delete removes the subscription with its deliveries and their attempts.
+func (r *SubscriptionGormRepository) Delete(ctx context.Context, id string) error {
+	return shared_database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		deliveries := tx.Model(&webhook_model.Delivery{}).Select("id").Where("subscription_id = ?", id)
+		if err := tx.Delete(&webhook_model.Attempt{}, "delivery_id IN (?)", deliveries).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&webhook_model.Delivery{}, "subscription_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&webhook_model.Subscription{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return port_webhook_repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *SubscriptionGormRepository) FindAll(ctx context.Context) ([]*webhook_entity.Subscription, error) {
+	var models []*webhook_model.Subscription
+	if err := r.db.WithContext(ctx).Order("created_at, id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return webhook_model.ToEntities(models), nil
+}
+
+func (r *SubscriptionGormRepository) FindByID(ctx context.Context, id string) (*webhook_entity.Subscription, error) {
+	var model webhook_model.Subscription
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, port_webhook_repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return webhook_model.ToEntity(&model), nil
+}
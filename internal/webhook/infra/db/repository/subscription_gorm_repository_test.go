@@ -0,0 +1,113 @@
+package webhook_repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	webhook_model "github.com/williamkoller/system-education/internal/webhook/infra/db/model"
+	port_webhook_repository "github.com/williamkoller/system-education/internal/webhook/port/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type SubscriptionGormRepositorySuite struct {
+	suite.Suite
+	db         *gorm.DB
+	repository *SubscriptionGormRepository
+}
+
+func (s *SubscriptionGormRepositorySuite) SetupTest() {
+	s.db = setupTestDB(s.T())
+	s.repository = NewSubscriptionGormRepository(s.db)
+}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	// Each pooled connection would open its own in-memory database.
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	err = db.AutoMigrate(&webhook_model.Subscription{}, &webhook_model.Delivery{}, &webhook_model.Attempt{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func saveSubscription(t *testing.T, db *gorm.DB, id string, events ...string) *webhook_entity.Subscription {
+	now := time.Now()
+	s, err := NewSubscriptionGormRepository(db).Save(context.Background(), &webhook_entity.Subscription{
+		ID:        id,
+		URL:       "https://lms.example.com/hooks",
+		Secret:    "0123456789abcdef",
+		Events:    events,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	assert.NoError(t, err)
+	return s
+}
+
+func (s *SubscriptionGormRepositorySuite) TestSaveAndFind() {
+	saveSubscription(s.T(), s.db, "sub-1", "school.created", "school.updated")
+
+	found, err := s.repository.FindByID(context.Background(), "sub-1")
+	s.NoError(err)
+	s.Equal([]string{"school.created", "school.updated"}, found.Events)
+	s.Equal("0123456789abcdef", found.Secret)
+	s.True(found.Active)
+
+	all, err := s.repository.FindAll(context.Background())
+	s.NoError(err)
+	s.Len(all, 1)
+}
+
+func (s *SubscriptionGormRepositorySuite) TestFindByID_NotFound() {
+	_, err := s.repository.FindByID(context.Background(), "missing")
+	s.ErrorIs(err, port_webhook_repository.ErrNotFound)
+}
+
+func (s *SubscriptionGormRepositorySuite) TestUpdate() {
+	sub := saveSubscription(s.T(), s.db, "sub-1", "school.created")
+	sub.Active = false
+	sub.Events = []string{webhook_entity.AllEvents}
+
+	_, err := s.repository.Update(context.Background(), "sub-1", sub)
+	s.NoError(err)
+
+	found, _ := s.repository.FindByID(context.Background(), "sub-1")
+	s.False(found.Active)
+	s.Equal([]string{webhook_entity.AllEvents}, found.Events)
+
+	_, err = s.repository.Update(context.Background(), "missing", sub)
+	s.ErrorIs(err, port_webhook_repository.ErrNotFound)
+}
+
+func (s *SubscriptionGormRepositorySuite) TestDelete_RemovesDeliveries() {
+	saveSubscription(s.T(), s.db, "sub-1", "school.created")
+	deliveries := NewDeliveryGormRepository(s.db)
+	delivery := newDelivery(s.T(), "sub-1", "evt-1")
+	s.Require().NoError(deliveries.Enqueue(context.Background(), delivery))
+	s.Require().NoError(deliveries.Record(context.Background(), delivery, &webhook_entity.Attempt{ID: "att-1", DeliveryID: delivery.ID, ResponseCode: 500}))
+
+	s.NoError(s.repository.Delete(context.Background(), "sub-1"))
+
+	var remaining int64
+	s.db.Model(&webhook_model.Delivery{}).Count(&remaining)
+	s.Zero(remaining)
+	s.db.Model(&webhook_model.Attempt{}).Count(&remaining)
+	s.Zero(remaining)
+
+	s.ErrorIs(s.repository.Delete(context.Background(), "sub-1"), port_webhook_repository.ErrNotFound)
+}
+
+func TestSubscriptionGormRepository(t *testing.T) {
+	suite.Run(t, new(SubscriptionGormRepositorySuite))
+}
@@ -0,0 +1,84 @@
+package infra_http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	port_webhook_sender "github.com/williamkoller/system-education/internal/webhook/port/sender"
+)
+
+// maxResponseBody is how much of a response is read before the connection
+// is closed; subscribers only need to answer with a status.
+const maxResponseBody = 64 << 10
+
+// ErrPrivateAddress is returned for a subscriber whose host resolves to a
+// loopback, private or link-local address.
+var ErrPrivateAddress = errors.New("webhook url resolves to a private address")
+
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender refuses to connect to private addresses unless
+// allowPrivateNetworks is set. The check runs on the address being dialled,
+// after DNS resolution, so a host name that later resolves to a private
+// address is refused too.
+func NewHTTPSender(timeout time.Duration, allowPrivateNetworks bool) *HTTPSender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivateNetworks {
+		dialer := &net.Dialer{Timeout: timeout, Control: refusePrivateAddress}
+		transport.DialContext = dialer.DialContext
+		// Through a proxy, the proxy's address would be checked instead of
+		// the subscriber's.
+		transport.Proxy = nil
+	}
+
+	return &HTTPSender{client: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// A redirect is reported as the delivery's response rather than
+		// followed, so a subscriber cannot bounce signed payloads elsewhere.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func refusePrivateAddress(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if webhook_entity.PrivateAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+var _ port_webhook_sender.Sender = &HTTPSender{}
+
+func (s *HTTPSender) Send(ctx context.Context, url string, header http.Header, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header = header.Clone()
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+	return resp.StatusCode, nil
+}
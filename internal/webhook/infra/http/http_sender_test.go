@@ -0,0 +1,74 @@
+package infra_http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSender_Send(t *testing.T) {
+	t.Run("posts the body with its headers", func(t *testing.T) {
+		var got *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		header := http.Header{"Content-Type": {"application/json"}, "X-Webhook-Event": {"school.created"}}
+		code, err := NewHTTPSender(time.Second, true).Send(context.Background(), server.URL, header, []byte(`{"id":"evt-1"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, code)
+		assert.Equal(t, http.MethodPost, got.Method)
+		assert.Equal(t, "school.created", got.Header.Get("X-Webhook-Event"))
+		assert.JSONEq(t, `{"id":"evt-1"}`, string(body))
+	})
+
+	t.Run("reports redirects instead of following them", func(t *testing.T) {
+		server := httptest.NewServer(http.RedirectHandler("https://elsewhere.example.com", http.StatusFound))
+		defer server.Close()
+
+		code, err := NewHTTPSender(time.Second, true).Send(context.Background(), server.URL, http.Header{}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusFound, code)
+	})
+
+	t.Run("refuses private addresses", func(t *testing.T) {
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		code, err := NewHTTPSender(time.Second, false).Send(context.Background(), server.URL, http.Header{}, nil)
+
+		assert.True(t, errors.Is(err, ErrPrivateAddress))
+		assert.Zero(t, code)
+		assert.False(t, called)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		code, err := NewHTTPSender(50*time.Millisecond, true).Send(context.Background(), server.URL, http.Header{}, nil)
+
+		assert.Error(t, err)
+		assert.Zero(t, code)
+	})
+}
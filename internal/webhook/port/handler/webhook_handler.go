@@ -0,0 +1,13 @@
+package port_webhook_handler
+
+import "github.com/gin-gonic/gin"
+
+type WebhookHandler interface {
+	CreateWebhook(c *gin.Context)
+	FindAllWebhooks(c *gin.Context)
+	FindWebhookByID(c *gin.Context)
+	UpdateWebhook(c *gin.Context)
+	DeleteWebhook(c *gin.Context)
+	FindDeliveries(c *gin.Context)
+	Redeliver(c *gin.Context)
+}
@@ -0,0 +1,36 @@
+package port_webhook_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+)
+
+type SubscriptionRepository interface {
+	Save(ctx context.Context, s *webhook_entity.Subscription) (*webhook_entity.Subscription, error)
+	Update(ctx context.Context, id string, s *webhook_entity.Subscription) (*webhook_entity.Subscription, error)
+	Delete(ctx context.Context, id string) error
+	FindAll(ctx context.Context) ([]*webhook_entity.Subscription, error)
+	FindByID(ctx context.Context, id string) (*webhook_entity.Subscription, error)
+}
+
+type DeliveryRepository interface {
+	// Enqueue skips deliveries already queued for the same subscription and
+	// event.
+	Enqueue(ctx context.Context, deliveries ...*webhook_entity.Delivery) error
+	// ClaimDue returns up to limit pending deliveries due at now and hides
+	// them from other workers for lease.
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*webhook_entity.Delivery, error)
+	// Record stores the delivery's new state and, when not nil, the attempt
+	// that led to it.
+	Record(ctx context.Context, d *webhook_entity.Delivery, attempt *webhook_entity.Attempt) error
+	FindByID(ctx context.Context, id string) (*webhook_entity.Delivery, error)
+	Find(ctx context.Context, filter webhook_entity.DeliveryFilter) ([]*webhook_entity.Delivery, error)
+}
+
+var (
+	ErrNotFound         = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
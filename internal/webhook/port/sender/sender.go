@@ -0,0 +1,12 @@
+package port_webhook_sender
+
+import (
+	"context"
+	"net/http"
+)
+
+// Sender POSTs a delivery and returns the response status code, or an error
+// when no response arrived.
+type Sender interface {
+	Send(ctx context.Context, url string, header http.Header, body []byte) (int, error)
+}
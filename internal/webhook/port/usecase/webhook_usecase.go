@@ -0,0 +1,26 @@
+package port_webhook_usecase
+
+import (
+	"context"
+
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	webhook_dtos "github.com/williamkoller/system-education/internal/webhook/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type WebhookUsecase interface {
+	Create(ctx context.Context, input webhook_dtos.AddWebhookDto) (*webhook_entity.Subscription, error)
+	FindAll(ctx context.Context) ([]*webhook_entity.Subscription, error)
+	FindByID(ctx context.Context, id string) (*webhook_entity.Subscription, error)
+	Update(ctx context.Context, id string, input webhook_dtos.UpdateWebhookDto) (*webhook_entity.Subscription, error)
+	Delete(ctx context.Context, id string) error
+	FindDeliveries(ctx context.Context, filter webhook_entity.DeliveryFilter) ([]*webhook_entity.Delivery, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID string) (*webhook_entity.Delivery, error)
+}
+
+// DeliveryUsecase turns outbox events into deliveries and sends them.
+type DeliveryUsecase interface {
+	Enqueue(ctx context.Context, event shared_event.Event) error
+	DeliverDue(ctx context.Context) (int, error)
+	Run(ctx context.Context)
+}
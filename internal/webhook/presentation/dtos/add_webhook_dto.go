@@ -0,0 +1,8 @@
+package webhook_dtos
+
+type AddWebhookDto struct {
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret" binding:"required"`
+	Events []string `json:"events" binding:"required"`
+	Active *bool    `json:"active"`
+}
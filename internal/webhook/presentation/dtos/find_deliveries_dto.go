@@ -0,0 +1,9 @@
+package webhook_dtos
+
+// FindDeliveriesDto is bound from the query string of
+// GET /webhooks/:id/deliveries.
+type FindDeliveriesDto struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
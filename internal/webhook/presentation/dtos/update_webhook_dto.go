@@ -0,0 +1,8 @@
+package webhook_dtos
+
+type UpdateWebhookDto struct {
+	URL    *string   `json:"url"`
+	Secret *string   `json:"secret"`
+	Events *[]string `json:"events"`
+	Active *bool     `json:"active"`
+}
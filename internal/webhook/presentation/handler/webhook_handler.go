@@ -0,0 +1,131 @@
+package webhook_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	webhook_mapper "github.com/williamkoller/system-education/internal/webhook/application/mapper"
+	webhook_entity "github.com/williamkoller/system-education/internal/webhook/domain/entity"
+	port_webhook_handler "github.com/williamkoller/system-education/internal/webhook/port/handler"
+	port_webhook_repository "github.com/williamkoller/system-education/internal/webhook/port/repository"
+	port_webhook_usecase "github.com/williamkoller/system-education/internal/webhook/port/usecase"
+	webhook_dtos "github.com/williamkoller/system-education/internal/webhook/presentation/dtos"
+)
+
+type WebhookHandler struct {
+	usecase port_webhook_usecase.WebhookUsecase
+}
+
+var _ port_webhook_handler.WebhookHandler = &WebhookHandler{}
+
+func NewWebhookHandler(usecase port_webhook_usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{usecase: usecase}
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var input webhook_dtos.AddWebhookDto
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	subscription, err := h.usecase.Create(c.Request.Context(), input)
+	if err != nil {
+		h.webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook_mapper.ToWebhookResponse(subscription))
+}
+
+func (h *WebhookHandler) FindAllWebhooks(c *gin.Context) {
+	subscriptions, err := h.usecase.FindAll(c.Request.Context())
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook_mapper.ToWebhookResponses(subscriptions))
+}
+
+func (h *WebhookHandler) FindWebhookByID(c *gin.Context) {
+	subscription, err := h.usecase.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook_mapper.ToWebhookResponse(subscription))
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var input webhook_dtos.UpdateWebhookDto
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	subscription, err := h.usecase.Update(c.Request.Context(), c.Param("id"), input)
+	if err != nil {
+		h.webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook_mapper.ToWebhookResponse(subscription))
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.usecase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		h.webhookError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *WebhookHandler) FindDeliveries(c *gin.Context) {
+	var input webhook_dtos.FindDeliveriesDto
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	deliveries, err := h.usecase.FindDeliveries(c.Request.Context(), webhook_mapper.ToDeliveryFilter(c.Param("id"), input))
+	if err != nil {
+		h.webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook_mapper.ToDeliveryResponses(deliveries))
+}
+
+// Redeliver answers 202: the delivery is queued again and the worker sends it
+// on its next poll.
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	delivery, err := h.usecase.Redeliver(c.Request.Context(), c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		h.webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, webhook_mapper.ToDeliveryResponse(delivery))
+}
+
+func (h *WebhookHandler) webhookError(c *gin.Context, err error) {
+	var validationErr *webhook_entity.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.Status(http.StatusBadRequest)
+	case errors.Is(err, port_webhook_repository.ErrNotFound),
+		errors.Is(err, port_webhook_repository.ErrDeliveryNotFound):
+		c.Status(http.StatusNotFound)
+	default:
+		c.Status(http.StatusInternalServerError)
+	}
+	c.Error(err).SetType(gin.ErrorTypePublic)
+}
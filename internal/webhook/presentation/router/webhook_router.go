@@ -0,0 +1,81 @@
+package webhook_router
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	audit_router "github.com/williamkoller/system-education/internal/audit/presentation/router"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	permission_event "github.com/williamkoller/system-education/internal/permission/domain/event"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	school_event "github.com/williamkoller/system-education/internal/school/domain/event"
+	student_event "github.com/williamkoller/system-education/internal/student/domain/event"
+	user_event "github.com/williamkoller/system-education/internal/user/domain/event"
+	webhook_usecase "github.com/williamkoller/system-education/internal/webhook/application/usecase"
+	webhook_repository "github.com/williamkoller/system-education/internal/webhook/infra/db/repository"
+	infra_http "github.com/williamkoller/system-education/internal/webhook/infra/http"
+	webhook_handler "github.com/williamkoller/system-education/internal/webhook/presentation/handler"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	"gorm.io/gorm"
+)
+
+// Events are the domain events subscribers can receive.
+var Events = []shared_event.Event{
+	&user_event.UserCreatedEvent{},
+	&school_event.SchoolCreatedEvent{},
+	&school_event.SchoolUpdatedEvent{},
+	&student_event.StudentEnrolledEvent{},
+	&student_event.StudentUpdatedEvent{},
+	&student_event.StudentDeactivatedEvent{},
+	&student_event.StudentTransferredEvent{},
+	&student_event.StudentDeletedEvent{},
+	&permission_event.PermissionCreatedEvent{},
+	&permission_event.PermissionUpdatedEvent{},
+	&permission_event.PermissionDeletedEvent{},
+}
+
+func WebhookRouter(g *gin.Engine, db *gorm.DB, jwt port_auth_cryptography.TokenManager, apiKeys port_auth_usecase.APIKeyAuthenticator, middleware port_permission_middleware.PermissionMiddleware, events shared_event.Subscriber, deliveries *webhook_usecase.DeliveryUsecase, allowPrivateNetworks bool) {
+	names := make([]string, 0, len(Events))
+	for _, event := range Events {
+		events.Subscribe("webhooks", event, deliveries.Enqueue)
+		names = append(names, event.EventName())
+	}
+
+	usecase := webhook_usecase.NewWebhookUsecase(
+		webhook_repository.NewSubscriptionGormRepository(db),
+		webhook_repository.NewDeliveryGormRepository(db),
+		names,
+		allowPrivateNetworks,
+		shared_database.NewGormTransactor(db),
+		audit_router.NewAuditUsecase(db),
+	)
+	handler := webhook_handler.NewWebhookHandler(usecase)
+	revocations := auth_repository.NewTokenRevocationGormRepository(db)
+
+	w := g.Group("/webhooks")
+	{
+		w.POST("", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"webhooks"}, []string{"create"}), handler.CreateWebhook)
+		w.GET("", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"webhooks"}, []string{"read"}), handler.FindAllWebhooks)
+		w.GET("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"webhooks"}, []string{"read"}), handler.FindWebhookByID)
+		w.PUT("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"webhooks"}, []string{"update"}), handler.UpdateWebhook)
+		w.DELETE("/:id", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"webhooks"}, []string{"delete"}), handler.DeleteWebhook)
+		w.GET("/:id/deliveries", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"webhooks"}, []string{"read"}), handler.FindDeliveries)
+		w.POST("/:id/deliveries/:delivery_id/redeliver", auth_middleware.AuthMiddleware(jwt, revocations, apiKeys), middleware.ModuleAccessMiddleware([]string{"webhooks"}, []string{"update"}), handler.Redeliver)
+	}
+}
+
+// NewDeliveryUsecase builds the worker main runs next to the outbox relay.
+func NewDeliveryUsecase(db *gorm.DB, pollInterval, timeout time.Duration, maxAttempts int, retryBackoff time.Duration, allowPrivateNetworks bool) *webhook_usecase.DeliveryUsecase {
+	return webhook_usecase.NewDeliveryUsecase(
+		webhook_repository.NewSubscriptionGormRepository(db),
+		webhook_repository.NewDeliveryGormRepository(db),
+		infra_http.NewHTTPSender(timeout, allowPrivateNetworks),
+		pollInterval,
+		maxAttempts,
+		retryBackoff,
+	)
+}
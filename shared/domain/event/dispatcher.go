@@ -1,74 +1,232 @@
 package shared_event
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/williamkoller/system-education/pkg/logger"
+)
+
+var ErrDispatcherClosed = errors.New("dispatcher is shut down")
+
+// AggregateEvent is an event that names the aggregate that raised it. The
+// dispatcher keeps each aggregate's events in order; events without one are
+// ordered by name instead.
+type AggregateEvent interface {
+	Event
+	AggregateID() string
+}
 
-type Handler func(event interface{})
+// AggregateIDOf returns the event's aggregate id, or "" when it has none.
+func AggregateIDOf(event Event) string {
+	if e, ok := event.(AggregateEvent); ok {
+		return e.AggregateID()
+	}
+	return ""
+}
 
-type Dispatcher struct {
-	handlers map[string][]Handler
-	mu       sync.RWMutex
+// EventMetrics counts dispatches of one event name. A dispatch fails when any
+// of its handlers returns an error or panics.
+type EventMetrics struct {
+	Dispatched    int64
+	Succeeded     int64
+	Failed        int64
+	Panicked      int64
+	TotalDuration time.Duration
 }
 
-func NewDispatcher() *Dispatcher {
-	return &Dispatcher{handlers: make(map[string][]Handler)}
+type subscription struct {
+	name    string
+	handler DeliveryHandler
 }
 
-func (d *Dispatcher) Register(eventName string, handler Handler) {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-	d.handlers[eventName] = append(d.handlers[eventName], handler)
+type dispatch struct {
+	ctx   context.Context
+	event Event
+	done  func(error)
 }
 
-func (d *Dispatcher) Dispatch(event interface{}) {
-	if event == nil {
-		return
+// Dispatcher runs handlers on a fixed pool of workers, each with its own
+// bounded queue. Every event of an aggregate goes to the same worker, so they
+// are handled in the order they were dispatched.
+type Dispatcher struct {
+	handlersMu sync.RWMutex
+	handlers   map[string][]subscription
+
+	// mu guards closed. Dispatch holds it for reading while it waits for
+	// room in a queue, so workers must never take it.
+	mu      sync.RWMutex
+	queues  []chan dispatch
+	closed  bool
+	workers sync.WaitGroup
+
+	metricsMu sync.Mutex
+	metrics   map[string]*EventMetrics
+}
+
+func NewDispatcher(workers, queueSize int) *Dispatcher {
+	d := &Dispatcher{
+		handlers: make(map[string][]subscription),
+		queues:   make([]chan dispatch, workers),
+		metrics:  make(map[string]*EventMetrics),
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan dispatch, queueSize)
+		d.workers.Add(1)
+		go d.work(d.queues[i])
 	}
+	return d
+}
+
+var _ Subscriber = &Dispatcher{}
+
+// Subscribe panics when subscriber already handles the event, since a second
+// handler under the same name would be taken for the first.
+func (d *Dispatcher) Subscribe(subscriber string, prototype Event, handler DeliveryHandler) {
+	d.handlersMu.Lock()
+	defer d.handlersMu.Unlock()
+	name := prototype.EventName()
+	for _, s := range d.handlers[name] {
+		if s.name == subscriber {
+			panic(fmt.Sprintf("shared_event: %s already subscribed to %s", subscriber, name))
+		}
+	}
+	d.handlers[name] = append(d.handlers[name], subscription{name: subscriber, handler: handler})
+}
 
+// Dispatch queues event for its handlers and calls done, if not nil, with
+// their joined errors once they have all run. It blocks while the event's
+// queue is full, and fails without calling done if ctx ends first or the
+// dispatcher is shut down.
+func (d *Dispatcher) Dispatch(ctx context.Context, event Event, done func(error)) error {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
+	if d.closed {
+		return ErrDispatcherClosed
+	}
 
-	ev, ok := event.(Event)
-	if !ok {
-		return
+	select {
+	case d.queues[d.worker(event)] <- dispatch{ctx: ctx, event: event, done: done}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown stops accepting events and waits for the queued ones to be
+// handled, or for ctx to end.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	}
+	d.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		d.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
+
+// Metrics returns a snapshot of the counters, keyed by event name.
+func (d *Dispatcher) Metrics() map[string]EventMetrics {
+	d.metricsMu.Lock()
+	defer d.metricsMu.Unlock()
 
-	handlers, ok := d.handlers[ev.EventName()]
-	if !ok || len(handlers) == 0 {
-		return
+	snapshot := make(map[string]EventMetrics, len(d.metrics))
+	for name, m := range d.metrics {
+		snapshot[name] = *m
 	}
+	return snapshot
+}
 
-	for _, h := range handlers {
-		go func(handler Handler, e interface{}) {
-			defer func() { recover() }()
-			handler(e)
-		}(h, event)
+func (d *Dispatcher) worker(event Event) int {
+	key := AggregateIDOf(event)
+	if key == "" {
+		key = event.EventName()
 	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(d.queues)))
+}
 
+func (d *Dispatcher) work(queue <-chan dispatch) {
+	defer d.workers.Done()
+	for job := range queue {
+		err := d.handle(job.ctx, job.event)
+		if job.done != nil {
+			job.done(err)
+		}
+	}
 }
 
-func (d *Dispatcher) DispatchSync(event interface{}) {
-	if event == nil {
-		return
+func (d *Dispatcher) handle(ctx context.Context, event Event) error {
+	d.handlersMu.RLock()
+	subscriptions := d.handlers[event.EventName()]
+	d.handlersMu.RUnlock()
+
+	started := time.Now()
+	var errs []error
+	panicked := false
+	for _, s := range subscriptions {
+		recovered, err := run(ctx, s.handler, event)
+		if recovered {
+			panicked = true
+			logger.Error("event handler panicked", "event", event.EventName(), "subscriber", s.name, "aggregate_id", AggregateIDOf(event), "panic", err)
+		} else if err != nil {
+			logger.Error("event handler failed", "event", event.EventName(), "subscriber", s.name, "aggregate_id", AggregateIDOf(event), "error", err)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	d.mu.RLock()
-	defer d.mu.RUnlock()
+	err := errors.Join(errs...)
+	d.observe(event.EventName(), time.Since(started), err, panicked)
+	return err
+}
+
+func run(ctx context.Context, handler DeliveryHandler, event Event) (panicked bool, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			panicked, err = true, fmt.Errorf("handler panicked: %v", recovered)
+		}
+	}()
+	return false, handler(ctx, event)
+}
 
-	ev, ok := event.(Event)
+func (d *Dispatcher) observe(name string, duration time.Duration, err error, panicked bool) {
+	d.metricsMu.Lock()
+	defer d.metricsMu.Unlock()
+
+	m, ok := d.metrics[name]
 	if !ok {
-		return
+		m = &EventMetrics{}
+		d.metrics[name] = m
 	}
-
-	handlers, ok := d.handlers[ev.EventName()]
-	if !ok || len(handlers) == 0 {
-		return
+	m.Dispatched++
+	m.TotalDuration += duration
+	if err != nil {
+		m.Failed++
+	} else {
+		m.Succeeded++
 	}
-
-	for _, h := range handlers {
-		func(handler Handler, e interface{}) {
-			defer func() { recover() }()
-			handler(e)
-		}(h, event)
+	if panicked {
+		m.Panicked++
 	}
-
 }
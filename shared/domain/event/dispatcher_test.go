@@ -0,0 +1,170 @@
+package shared_event
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderPlaced struct {
+	OrderID string
+	Seq     int
+}
+
+func (e *orderPlaced) EventName() string     { return "order.placed" }
+func (e *orderPlaced) OccurredOn() time.Time { return time.Time{} }
+func (e *orderPlaced) AggregateID() string   { return e.OrderID }
+
+type pinged struct{}
+
+func (e *pinged) EventName() string     { return "pinged" }
+func (e *pinged) OccurredOn() time.Time { return time.Time{} }
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeps each aggregate's events in order", func(t *testing.T) {
+		dispatcher := NewDispatcher(4, 100)
+		var mu sync.Mutex
+		seen := make(map[string][]int)
+		dispatcher.Subscribe("test", &orderPlaced{}, func(_ context.Context, e Event) error {
+			event := e.(*orderPlaced)
+			mu.Lock()
+			seen[event.OrderID] = append(seen[event.OrderID], event.Seq)
+			mu.Unlock()
+			return nil
+		})
+
+		orders := []string{"order-1", "order-2", "order-3", "order-4", "order-5"}
+		for seq := range 20 {
+			for _, id := range orders {
+				require.NoError(t, dispatcher.Dispatch(ctx, &orderPlaced{OrderID: id, Seq: seq}, nil))
+			}
+		}
+		require.NoError(t, dispatcher.Shutdown(ctx))
+
+		for _, id := range orders {
+			require.Len(t, seen[id], 20)
+			for i, seq := range seen[id] {
+				assert.Equal(t, i, seq, "events of %s out of order", id)
+			}
+		}
+	})
+
+	t.Run("reports handler errors and panics to done", func(t *testing.T) {
+		dispatcher := NewDispatcher(1, 10)
+		dispatcher.Subscribe("failing", &pinged{}, func(context.Context, Event) error {
+			return errors.New("unreachable")
+		})
+		dispatcher.Subscribe("panicking", &pinged{}, func(context.Context, Event) error {
+			panic("boom")
+		})
+
+		result := make(chan error, 1)
+		require.NoError(t, dispatcher.Dispatch(ctx, &pinged{}, func(err error) { result <- err }))
+
+		err := <-result
+		assert.ErrorContains(t, err, "unreachable")
+		assert.ErrorContains(t, err, "handler panicked: boom")
+		require.NoError(t, dispatcher.Shutdown(ctx))
+	})
+
+	t.Run("counts dispatches per event name", func(t *testing.T) {
+		dispatcher := NewDispatcher(2, 10)
+		dispatcher.Subscribe("test", &orderPlaced{}, func(_ context.Context, e Event) error {
+			if e.(*orderPlaced).Seq < 0 {
+				return errors.New("invalid order")
+			}
+			return nil
+		})
+		dispatcher.Subscribe("test", &pinged{}, func(context.Context, Event) error {
+			panic("boom")
+		})
+
+		require.NoError(t, dispatcher.Dispatch(ctx, &orderPlaced{OrderID: "order-1", Seq: 1}, nil))
+		require.NoError(t, dispatcher.Dispatch(ctx, &orderPlaced{OrderID: "order-1", Seq: -1}, nil))
+		require.NoError(t, dispatcher.Dispatch(ctx, &pinged{}, nil))
+		require.NoError(t, dispatcher.Shutdown(ctx))
+
+		metrics := dispatcher.Metrics()
+		assert.Equal(t, int64(2), metrics["order.placed"].Dispatched)
+		assert.Equal(t, int64(1), metrics["order.placed"].Succeeded)
+		assert.Equal(t, int64(1), metrics["order.placed"].Failed)
+		assert.Equal(t, int64(1), metrics["pinged"].Panicked)
+		assert.Equal(t, int64(1), metrics["pinged"].Failed)
+	})
+}
+
+func TestDispatcher_Subscribe(t *testing.T) {
+	dispatcher := NewDispatcher(1, 10)
+	defer dispatcher.Shutdown(context.Background())
+	handler := func(context.Context, Event) error { return nil }
+
+	dispatcher.Subscribe("mailer", &pinged{}, handler)
+	dispatcher.Subscribe("mailer", &orderPlaced{}, handler)
+
+	assert.Panics(t, func() { dispatcher.Subscribe("mailer", &pinged{}, handler) })
+}
+
+func TestDispatcher_Shutdown(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("drains queued events", func(t *testing.T) {
+		dispatcher := NewDispatcher(1, 10)
+		release := make(chan struct{})
+		var handled int
+		dispatcher.Subscribe("test", &pinged{}, func(context.Context, Event) error {
+			<-release
+			handled++
+			return nil
+		})
+		for range 5 {
+			require.NoError(t, dispatcher.Dispatch(ctx, &pinged{}, nil))
+		}
+
+		close(release)
+		require.NoError(t, dispatcher.Shutdown(ctx))
+
+		assert.Equal(t, 5, handled)
+		assert.ErrorIs(t, dispatcher.Dispatch(ctx, &pinged{}, nil), ErrDispatcherClosed)
+	})
+
+	t.Run("gives up when ctx ends first", func(t *testing.T) {
+		dispatcher := NewDispatcher(1, 10)
+		release := make(chan struct{})
+		defer close(release)
+		dispatcher.Subscribe("test", &pinged{}, func(context.Context, Event) error {
+			<-release
+			return nil
+		})
+		require.NoError(t, dispatcher.Dispatch(ctx, &pinged{}, nil))
+
+		timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, dispatcher.Shutdown(timeout), context.DeadlineExceeded)
+	})
+
+	t.Run("a full queue waits for ctx", func(t *testing.T) {
+		dispatcher := NewDispatcher(1, 1)
+		release := make(chan struct{})
+		dispatcher.Subscribe("test", &pinged{}, func(context.Context, Event) error {
+			<-release
+			return nil
+		})
+		require.NoError(t, dispatcher.Dispatch(ctx, &pinged{}, nil))
+		require.NoError(t, dispatcher.Dispatch(ctx, &pinged{}, nil))
+
+		timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, dispatcher.Dispatch(timeout, &pinged{}, nil), context.DeadlineExceeded)
+
+		close(release)
+		require.NoError(t, dispatcher.Shutdown(ctx))
+	})
+}
@@ -0,0 +1,37 @@
+package shared_event
+
+import "context"
+
+// Outbox stores events alongside the aggregate that raised them, in the
+// transaction found in ctx, for a relay to deliver once it commits.
+type Outbox interface {
+	Add(ctx context.Context, events ...Event) error
+}
+
+// DeliveryHandler reacts to an event taken from the outbox. Returning an
+// error schedules the event again for that handler, so handlers must
+// tolerate duplicates.
+type DeliveryHandler func(ctx context.Context, event Event) error
+
+// Subscriber registers handlers for outbox events. subscriber names the
+// handler, uniquely per event, and must stay the same across releases: the
+// relay remembers by name which handlers already took an event, so a retry
+// only reaches the ones that failed. prototype is a pointer to the event
+// type, which payloads are decoded into.
+type Subscriber interface {
+	Subscribe(subscriber string, prototype Event, handler DeliveryHandler)
+}
+
+type eventIDKey struct{}
+
+// WithEventID stores the id the outbox gave an event, which stays the same
+// across redeliveries.
+func WithEventID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, eventIDKey{}, id)
+}
+
+// EventIDFrom returns the outbox id of the event being delivered, or "".
+func EventIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(eventIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,89 @@
+package shared_event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultStoreLimit = 100
+	MaxStoreLimit     = 1000
+)
+
+// Versioned is an event whose payload has changed shape since it was first
+// stored. Events that don't implement it are version 1.
+type Versioned interface {
+	SchemaVersion() int
+}
+
+func SchemaVersionOf(event Event) int {
+	if v, ok := event.(Versioned); ok {
+		return v.SchemaVersion()
+	}
+	return 1
+}
+
+// StoredEvent is an event as the event store keeps it. Position orders the
+// store; ID is the id the outbox gave the event.
+type StoredEvent struct {
+	Position      int64
+	ID            string
+	EventName     string
+	AggregateID   string
+	Payload       string
+	OccurredOn    time.Time
+	SchemaVersion int
+	RecordedAt    time.Time
+}
+
+func NewStoredEvent(id string, event Event) (*StoredEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", event.EventName(), err)
+	}
+	return &StoredEvent{
+		ID:            id,
+		EventName:     event.EventName(),
+		AggregateID:   AggregateIDOf(event),
+		Payload:       string(payload),
+		OccurredOn:    event.OccurredOn(),
+		SchemaVersion: SchemaVersionOf(event),
+		RecordedAt:    time.Now(),
+	}, nil
+}
+
+// EventFilter selects stored events in the order they were stored. Empty
+// fields match everything; From and To bound OccurredOn inclusively and
+// AfterPosition resumes a previous read.
+type EventFilter struct {
+	AggregateID   string
+	EventName     string
+	From          *time.Time
+	To            *time.Time
+	AfterPosition int64
+	Limit         int
+}
+
+// Normalized clamps Limit to (0, MaxStoreLimit] and AfterPosition to zero or
+// more.
+func (f EventFilter) Normalized() EventFilter {
+	if f.Limit <= 0 {
+		f.Limit = DefaultStoreLimit
+	}
+	if f.Limit > MaxStoreLimit {
+		f.Limit = MaxStoreLimit
+	}
+	if f.AfterPosition < 0 {
+		f.AfterPosition = 0
+	}
+	return f
+}
+
+// EventStore is append-only: events are added in the transaction found in
+// ctx and never changed.
+type EventStore interface {
+	Append(ctx context.Context, events ...*StoredEvent) error
+	Find(ctx context.Context, filter EventFilter) ([]*StoredEvent, error)
+}
@@ -0,0 +1,105 @@
+package shared_broker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var ErrNotConfirmed = errors.New("broker rejected the message")
+
+// AMQPPublisher publishes persistent messages to a durable topic exchange,
+// with the routing key as topic, and waits for the broker to confirm each
+// one. A dropped connection is dialled again on the next Publish.
+type AMQPPublisher struct {
+	url      string
+	exchange string
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+func NewAMQPPublisher(url, exchange string, timeout time.Duration) (*AMQPPublisher, error) {
+	p := &AMQPPublisher{url: url, exchange: exchange, timeout: timeout}
+	if _, err := p.open(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+var _ Publisher = &AMQPPublisher{}
+
+func (p *AMQPPublisher) Publish(ctx context.Context, topic string, message []byte) error {
+	channel, err := p.open()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, p.exchange, topic, false, false, amqp.Publishing{
+		ContentType:  ContentType,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Body:         message,
+	})
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return ErrNotConfirmed
+	}
+	return nil
+}
+
+func (p *AMQPPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil || p.conn.IsClosed() {
+		return nil
+	}
+	return p.conn.Close()
+}
+
+// open returns the current channel, dialling again if it was closed.
+func (p *AMQPPublisher) open() (*amqp.Channel, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.channel != nil && !p.channel.IsClosed() {
+		return p.channel, nil
+	}
+	if p.conn == nil || p.conn.IsClosed() {
+		conn, err := amqp.Dial(p.url)
+		if err != nil {
+			return nil, err
+		}
+		p.conn = conn
+	}
+
+	channel, err := p.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	if err := channel.ExchangeDeclare(p.exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		_ = channel.Close()
+		return nil, err
+	}
+	if err := channel.Confirm(false); err != nil {
+		_ = channel.Close()
+		return nil, err
+	}
+	p.channel = channel
+	return channel, nil
+}
@@ -0,0 +1,44 @@
+package shared_broker
+
+import (
+	"encoding/json"
+	"time"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+const CloudEventsSpecVersion = "1.0"
+
+// CloudEvent is the CloudEvents 1.0 JSON envelope published for a domain
+// event. Subject is the aggregate id and SchemaVersion an extension
+// attribute carrying the payload version.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	SchemaVersion   int             `json:"schemaversion"`
+	Data            json.RawMessage `json:"data"`
+}
+
+func NewCloudEvent(id, source string, event shared_event.Event) (*CloudEvent, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              id,
+		Source:          source,
+		Type:            event.EventName(),
+		Subject:         shared_event.AggregateIDOf(event),
+		Time:            event.OccurredOn().UTC(),
+		DataContentType: "application/json",
+		SchemaVersion:   shared_event.SchemaVersionOf(event),
+		Data:            data,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package shared_broker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type studentEnrolled struct {
+	StudentID string    `json:"studentId"`
+	At        time.Time `json:"-"`
+}
+
+func (e *studentEnrolled) EventName() string     { return "student.enrolled" }
+func (e *studentEnrolled) OccurredOn() time.Time { return e.At }
+func (e *studentEnrolled) AggregateID() string   { return e.StudentID }
+
+type schoolRenamed struct {
+	Name string `json:"name"`
+}
+
+func (e *schoolRenamed) EventName() string     { return "school.renamed" }
+func (e *schoolRenamed) OccurredOn() time.Time { return time.Time{} }
+func (e *schoolRenamed) SchemaVersion() int    { return 2 }
+
+func TestNewCloudEvent(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+
+	t.Run("wraps the event in a CloudEvents 1.0 envelope", func(t *testing.T) {
+		envelope, err := NewCloudEvent("event-1", "/system-education", &studentEnrolled{StudentID: "student-1", At: at})
+		require.NoError(t, err)
+
+		body, err := json.Marshal(envelope)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"specversion": "1.0",
+			"id": "event-1",
+			"source": "/system-education",
+			"type": "student.enrolled",
+			"subject": "student-1",
+			"time": "2026-03-01T15:00:00Z",
+			"datacontenttype": "application/json",
+			"schemaversion": 1,
+			"data": {"studentId": "student-1"}
+		}`, string(body))
+	})
+
+	t.Run("omits the subject of events without an aggregate", func(t *testing.T) {
+		envelope, err := NewCloudEvent("event-2", "/system-education", &schoolRenamed{Name: "Central"})
+		require.NoError(t, err)
+
+		body, err := json.Marshal(envelope)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), `"subject"`)
+		assert.Equal(t, 2, envelope.SchemaVersion)
+		assert.JSONEq(t, `{"name": "Central"}`, string(envelope.Data))
+	})
+}
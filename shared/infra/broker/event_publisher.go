@@ -0,0 +1,68 @@
+package shared_broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/williamkoller/system-education/pkg/logger"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+// EventPublisher forwards relayed events to a broker as CloudEvents. It
+// subscribes as "broker", so the relay tracks its deliveries apart from the
+// other handlers: a broker outage retries only the publish, and a failing
+// handler elsewhere does not publish the event twice. Only the event names in
+// events are published.
+type EventPublisher struct {
+	publisher Publisher
+	topics    *TopicMapper
+	source    string
+	events    []string
+}
+
+func NewEventPublisher(publisher Publisher, topics *TopicMapper, source string, events []string) *EventPublisher {
+	return &EventPublisher{publisher: publisher, topics: topics, source: source, events: events}
+}
+
+// Subscribe publishes the prototypes a module offers as they are relayed and
+// logs those left off the broker. A nil publisher, used when no broker is
+// configured, subscribes nothing.
+func (p *EventPublisher) Subscribe(events shared_event.Subscriber, prototypes []shared_event.Event) {
+	if p == nil {
+		return
+	}
+	for _, prototype := range prototypes {
+		if !slices.Contains(p.events, prototype.EventName()) {
+			logger.Info("event not published to the broker", "event", prototype.EventName())
+			continue
+		}
+		events.Subscribe("broker", prototype, p.Publish)
+	}
+}
+
+// Publish uses the outbox id as the CloudEvent id, so consumers can drop the
+// duplicates a retried relay sends.
+func (p *EventPublisher) Publish(ctx context.Context, event shared_event.Event) error {
+	id := shared_event.EventIDFrom(ctx)
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	envelope, err := NewCloudEvent(id, p.source, event)
+	if err != nil {
+		return err
+	}
+	message, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	topic := p.topics.Topic(event.EventName())
+	if err := p.publisher.Publish(ctx, topic, message); err != nil {
+		return fmt.Errorf("publish %s to %s: %w", event.EventName(), topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package shared_broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+func TestEventPublisher_Publish(t *testing.T) {
+	topics := NewTopicMapper("system-education.", nil)
+
+	t.Run("publishes a CloudEvent under the outbox id", func(t *testing.T) {
+		memory := NewMemoryPublisher()
+		publisher := NewEventPublisher(memory, topics, "/system-education", []string{"student.enrolled"})
+
+		ctx := shared_event.WithEventID(context.Background(), "event-1")
+		require.NoError(t, publisher.Publish(ctx, &studentEnrolled{StudentID: "student-1"}))
+
+		messages := memory.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, "system-education.student.enrolled", messages[0].Topic)
+
+		var envelope CloudEvent
+		require.NoError(t, json.Unmarshal(messages[0].Body, &envelope))
+		assert.Equal(t, "event-1", envelope.ID)
+		assert.Equal(t, "student.enrolled", envelope.Type)
+		assert.Equal(t, "student-1", envelope.Subject)
+	})
+
+	t.Run("returns the broker error so the relay retries", func(t *testing.T) {
+		memory := NewMemoryPublisher()
+		memory.Fail(errors.New("connection refused"))
+		publisher := NewEventPublisher(memory, topics, "/system-education", []string{"student.enrolled"})
+
+		err := publisher.Publish(context.Background(), &studentEnrolled{StudentID: "student-1"})
+		assert.ErrorContains(t, err, "publish student.enrolled to system-education.student.enrolled: connection refused")
+		assert.Empty(t, memory.Messages())
+	})
+}
+
+func TestEventPublisher_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemoryPublisher()
+	dispatcher := shared_event.NewDispatcher(1, 10)
+	NewEventPublisher(memory, NewTopicMapper("", nil), "/system-education", []string{"student.enrolled"}).Subscribe(dispatcher, []shared_event.Event{&studentEnrolled{}, &schoolRenamed{}})
+
+	require.NoError(t, dispatcher.Dispatch(ctx, &studentEnrolled{StudentID: "student-1"}, nil))
+	require.NoError(t, dispatcher.Dispatch(ctx, &schoolRenamed{Name: "Central"}, nil))
+	require.NoError(t, dispatcher.Shutdown(ctx))
+
+	messages := memory.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "student.enrolled", messages[0].Topic)
+}
+
+type recordingSubscriber struct {
+	names []string
+}
+
+func (s *recordingSubscriber) Subscribe(subscriber string, prototype shared_event.Event, _ shared_event.DeliveryHandler) {
+	s.names = append(s.names, subscriber+":"+prototype.EventName())
+}
+
+func TestEventPublisher_SubscribesUnderItsOwnName(t *testing.T) {
+	events := &recordingSubscriber{}
+
+	NewEventPublisher(NewMemoryPublisher(), NewTopicMapper("", nil), "/system-education", []string{"student.enrolled", "school.renamed"}).Subscribe(events, []shared_event.Event{&studentEnrolled{}, &schoolRenamed{}})
+
+	assert.Equal(t, []string{"broker:student.enrolled", "broker:school.renamed"}, events.names)
+}
+
+func TestEventPublisher_SubscribeWithoutBroker(t *testing.T) {
+	events := &recordingSubscriber{}
+
+	var publisher *EventPublisher
+	publisher.Subscribe(events, []shared_event.Event{&studentEnrolled{}})
+
+	assert.Empty(t, events.names)
+}
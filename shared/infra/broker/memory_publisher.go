@@ -0,0 +1,51 @@
+package shared_broker
+
+import (
+	"context"
+	"sync"
+)
+
+type Message struct {
+	Topic string
+	Body  []byte
+}
+
+// MemoryPublisher keeps published messages in memory, for tests.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages []Message
+	err      error
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+var _ Publisher = &MemoryPublisher{}
+
+func (p *MemoryPublisher) Publish(_ context.Context, topic string, message []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, Message{Topic: topic, Body: append([]byte(nil), message...)})
+	return nil
+}
+
+// Fail makes every later Publish return err, until called with nil.
+func (p *MemoryPublisher) Fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+func (p *MemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.messages...)
+}
+
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,44 @@
+package shared_broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+type NATSPublisher struct {
+	conn    *nats.Conn
+	timeout time.Duration
+}
+
+// NewNATSPublisher connects to url and keeps reconnecting for as long as the
+// publisher is open. timeout bounds each Publish.
+func NewNATSPublisher(url string, timeout time.Duration) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("system-education"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+	return &NATSPublisher{conn: conn, timeout: timeout}, nil
+}
+
+var _ Publisher = &NATSPublisher{}
+
+func (p *NATSPublisher) Publish(ctx context.Context, topic string, message []byte) error {
+	msg := nats.NewMsg(topic)
+	msg.Header.Set("Content-Type", ContentType)
+	msg.Data = message
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	// Core NATS has no acknowledgements; a flush at least confirms the
+	// server has the message before the outbox forgets it.
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,14 @@
+package shared_broker
+
+import "context"
+
+// ContentType marks a message body as a structured CloudEvent.
+const ContentType = "application/cloudevents+json"
+
+// Publisher sends a message to a broker. topic is a NATS subject or an AMQP
+// routing key. Publish returns once the broker has accepted the message, so
+// a failure leaves the event in the outbox to be retried.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, message []byte) error
+	Close() error
+}
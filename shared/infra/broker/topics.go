@@ -0,0 +1,21 @@
+package shared_broker
+
+// TopicMapper names the topic each event is published to: the override for
+// its name if there is one, otherwise the name behind prefix. Event names
+// are dot separated, which suits both NATS subjects and AMQP topic
+// exchanges.
+type TopicMapper struct {
+	prefix    string
+	overrides map[string]string
+}
+
+func NewTopicMapper(prefix string, overrides map[string]string) *TopicMapper {
+	return &TopicMapper{prefix: prefix, overrides: overrides}
+}
+
+func (m *TopicMapper) Topic(eventName string) string {
+	if topic, ok := m.overrides[eventName]; ok {
+		return topic
+	}
+	return m.prefix + eventName
+}
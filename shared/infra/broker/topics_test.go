@@ -0,0 +1,15 @@
+package shared_broker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicMapper_Topic(t *testing.T) {
+	mapper := NewTopicMapper("system-education.", map[string]string{"student.enrolled": "students.new"})
+
+	assert.Equal(t, "students.new", mapper.Topic("student.enrolled"))
+	assert.Equal(t, "system-education.school.created", mapper.Topic("school.created"))
+	assert.Equal(t, "user.created", NewTopicMapper("", nil).Topic("user.created"))
+}
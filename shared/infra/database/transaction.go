@@ -0,0 +1,39 @@
+package shared_database
+
+import (
+	"context"
+
+	port_transaction "github.com/williamkoller/system-education/shared/port/transaction"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+type GormTransactor struct {
+	db *gorm.DB
+}
+
+func NewGormTransactor(db *gorm.DB) *GormTransactor {
+	return &GormTransactor{db: db}
+}
+
+var _ port_transaction.Transactor = &GormTransactor{}
+
+// WithinTransaction joins the transaction already in ctx, if any, so use
+// cases can be composed without nesting transactions.
+func (t *GormTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction in ctx, or db outside of one.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,67 @@
+package shared_database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type record struct {
+	ID string `gorm:"primaryKey"`
+}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	// Each pooled connection would open its own in-memory database.
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	err = db.AutoMigrate(&record{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func count(db *gorm.DB) int64 {
+	var n int64
+	db.Model(&record{}).Count(&n)
+	return n
+}
+
+func TestGormTransactor(t *testing.T) {
+	t.Run("commits", func(t *testing.T) {
+		db := setupTestDB(t)
+		err := NewGormTransactor(db).WithinTransaction(context.Background(), func(ctx context.Context) error {
+			return Conn(ctx, db).Create(&record{ID: "1"}).Error
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count(db))
+	})
+
+	t.Run("rolls back every write on error", func(t *testing.T) {
+		db := setupTestDB(t)
+		transactor := NewGormTransactor(db)
+		err := transactor.WithinTransaction(context.Background(), func(ctx context.Context) error {
+			if err := Conn(ctx, db).Create(&record{ID: "1"}).Error; err != nil {
+				return err
+			}
+			return transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				if err := Conn(ctx, db).Create(&record{ID: "2"}).Error; err != nil {
+					return err
+				}
+				return errors.New("boom")
+			})
+		})
+
+		assert.EqualError(t, err, "boom")
+		assert.Equal(t, int64(0), count(db))
+	})
+}
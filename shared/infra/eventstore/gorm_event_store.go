@@ -0,0 +1,62 @@
+package shared_eventstore
+
+import (
+	"context"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	"gorm.io/gorm"
+)
+
+type GormEventStore struct {
+	db *gorm.DB
+}
+
+func NewGormEventStore(db *gorm.DB) *GormEventStore {
+	return &GormEventStore{db: db}
+}
+
+var _ shared_event.EventStore = &GormEventStore{}
+
+func (s *GormEventStore) Append(ctx context.Context, events ...*shared_event.StoredEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	models := make([]*StoredEvent, 0, len(events))
+	for _, e := range events {
+		models = append(models, FromEntity(e))
+	}
+	if err := shared_database.Conn(ctx, s.db).Create(&models).Error; err != nil {
+		return err
+	}
+	for i, model := range models {
+		events[i].Position = model.Position
+	}
+	return nil
+}
+
+func (s *GormEventStore) Find(ctx context.Context, filter shared_event.EventFilter) ([]*shared_event.StoredEvent, error) {
+	query := shared_database.Conn(ctx, s.db).Model(&StoredEvent{}).Where("position > ?", filter.AfterPosition)
+	if filter.AggregateID != "" {
+		query = query.Where("aggregate_id = ?", filter.AggregateID)
+	}
+	if filter.EventName != "" {
+		query = query.Where("event_name = ?", filter.EventName)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_on >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_on <= ?", *filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var models []*StoredEvent
+	if err := query.Order("position").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return ToEntities(models), nil
+}
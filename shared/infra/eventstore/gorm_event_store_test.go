@@ -0,0 +1,118 @@
+package shared_eventstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type studentEnrolled struct {
+	StudentID string
+	Date      time.Time
+}
+
+func (e *studentEnrolled) EventName() string     { return "student.enrolled" }
+func (e *studentEnrolled) OccurredOn() time.Time { return e.Date }
+func (e *studentEnrolled) AggregateID() string   { return e.StudentID }
+
+type studentDeleted struct {
+	StudentID string
+	Date      time.Time
+}
+
+func (e *studentDeleted) EventName() string     { return "student.deleted" }
+func (e *studentDeleted) OccurredOn() time.Time { return e.Date }
+func (e *studentDeleted) AggregateID() string   { return e.StudentID }
+func (e *studentDeleted) SchemaVersion() int    { return 2 }
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&StoredEvent{}))
+	return db
+}
+
+func appendEvents(t *testing.T, store *GormEventStore, events ...shared_event.Event) []*shared_event.StoredEvent {
+	stored := make([]*shared_event.StoredEvent, 0, len(events))
+	for _, event := range events {
+		record, err := shared_event.NewStoredEvent(uuid.New().String(), event)
+		require.NoError(t, err)
+		stored = append(stored, record)
+	}
+	require.NoError(t, store.Append(context.Background(), stored...))
+	return stored
+}
+
+func TestGormEventStore_Append(t *testing.T) {
+	store := NewGormEventStore(setupTestDB(t))
+	occurred := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	stored := appendEvents(t, store,
+		&studentEnrolled{StudentID: "student-1", Date: occurred},
+		&studentDeleted{StudentID: "student-1", Date: occurred},
+	)
+
+	assert.Less(t, stored[0].Position, stored[1].Position)
+
+	found, err := store.Find(context.Background(), shared_event.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, stored[0].ID, found[0].ID)
+	assert.Equal(t, "student.enrolled", found[0].EventName)
+	assert.Equal(t, "student-1", found[0].AggregateID)
+	assert.JSONEq(t, `{"StudentID": "student-1", "Date": "2026-03-01T08:00:00Z"}`, found[0].Payload)
+	assert.True(t, occurred.Equal(found[0].OccurredOn))
+	assert.Equal(t, 1, found[0].SchemaVersion)
+	assert.Equal(t, 2, found[1].SchemaVersion)
+}
+
+func TestGormEventStore_Find(t *testing.T) {
+	store := NewGormEventStore(setupTestDB(t))
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 8, 0, 0, 0, time.UTC) }
+	stored := appendEvents(t, store,
+		&studentEnrolled{StudentID: "student-1", Date: day(1)},
+		&studentEnrolled{StudentID: "student-2", Date: day(2)},
+		&studentDeleted{StudentID: "student-1", Date: day(3)},
+		&studentEnrolled{StudentID: "student-3", Date: day(4)},
+	)
+	ids := func(events []*shared_event.StoredEvent) []string {
+		found := make([]string, 0, len(events))
+		for _, e := range events {
+			found = append(found, e.ID)
+		}
+		return found
+	}
+	from, to := day(2), day(3)
+
+	tests := []struct {
+		name   string
+		filter shared_event.EventFilter
+		want   []*shared_event.StoredEvent
+	}{
+		{"by aggregate", shared_event.EventFilter{AggregateID: "student-1"}, []*shared_event.StoredEvent{stored[0], stored[2]}},
+		{"by name", shared_event.EventFilter{EventName: "student.enrolled"}, []*shared_event.StoredEvent{stored[0], stored[1], stored[3]}},
+		{"by time range", shared_event.EventFilter{From: &from, To: &to}, []*shared_event.StoredEvent{stored[1], stored[2]}},
+		{"after a position", shared_event.EventFilter{AfterPosition: stored[1].Position}, []*shared_event.StoredEvent{stored[2], stored[3]}},
+		{"limited", shared_event.EventFilter{Limit: 1}, []*shared_event.StoredEvent{stored[0]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := store.Find(context.Background(), tt.filter)
+
+			require.NoError(t, err)
+			assert.Equal(t, ids(tt.want), ids(found))
+		})
+	}
+}
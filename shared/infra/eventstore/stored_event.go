@@ -0,0 +1,62 @@
+package shared_eventstore
+
+import (
+	"time"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type StoredEvent struct {
+	Position      int64  `gorm:"primaryKey;autoIncrement"`
+	ID            string `gorm:"type:uuid;uniqueIndex"`
+	EventName     string `gorm:"index"`
+	AggregateID   string `gorm:"index"`
+	Payload       string `gorm:"type:jsonb"`
+	OccurredOn    time.Time
+	SchemaVersion int
+	RecordedAt    time.Time
+}
+
+func (StoredEvent) TableName() string {
+	return "domain_events"
+}
+
+func FromEntity(e *shared_event.StoredEvent) *StoredEvent {
+	if e == nil {
+		return nil
+	}
+	return &StoredEvent{
+		Position:      e.Position,
+		ID:            e.ID,
+		EventName:     e.EventName,
+		AggregateID:   e.AggregateID,
+		Payload:       e.Payload,
+		OccurredOn:    e.OccurredOn,
+		SchemaVersion: e.SchemaVersion,
+		RecordedAt:    e.RecordedAt,
+	}
+}
+
+func ToEntity(e *StoredEvent) *shared_event.StoredEvent {
+	if e == nil {
+		return nil
+	}
+	return &shared_event.StoredEvent{
+		Position:      e.Position,
+		ID:            e.ID,
+		EventName:     e.EventName,
+		AggregateID:   e.AggregateID,
+		Payload:       e.Payload,
+		OccurredOn:    e.OccurredOn,
+		SchemaVersion: e.SchemaVersion,
+		RecordedAt:    e.RecordedAt,
+	}
+}
+
+func ToEntities(es []*StoredEvent) []*shared_event.StoredEvent {
+	entities := make([]*shared_event.StoredEvent, 0, len(es))
+	for _, e := range es {
+		entities = append(entities, ToEntity(e))
+	}
+	return entities
+}
@@ -0,0 +1,55 @@
+package shared_outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	shared_eventstore "github.com/williamkoller/system-education/shared/infra/eventstore"
+	"gorm.io/gorm"
+)
+
+// GormOutbox also appends every event to the event store, under the same id
+// and in the same transaction, so the history matches what was relayed.
+type GormOutbox struct {
+	db    *gorm.DB
+	store shared_event.EventStore
+}
+
+func NewGormOutbox(db *gorm.DB) *GormOutbox {
+	return &GormOutbox{db: db, store: shared_eventstore.NewGormEventStore(db)}
+}
+
+var _ shared_event.Outbox = &GormOutbox{}
+
+func (o *GormOutbox) Add(ctx context.Context, events ...shared_event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	messages := make([]*Message, 0, len(events))
+	stored := make([]*shared_event.StoredEvent, 0, len(events))
+	for _, event := range events {
+		record, err := shared_event.NewStoredEvent(uuid.New().String(), event)
+		if err != nil {
+			return err
+		}
+		stored = append(stored, record)
+		messages = append(messages, &Message{
+			EventID:       record.ID,
+			EventName:     record.EventName,
+			AggregateID:   record.AggregateID,
+			Payload:       record.Payload,
+			OccurredAt:    record.OccurredOn,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	if err := shared_database.Conn(ctx, o.db).Create(&messages).Error; err != nil {
+		return err
+	}
+	return o.store.Append(ctx, stored...)
+}
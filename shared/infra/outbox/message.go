@@ -0,0 +1,30 @@
+package shared_outbox
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Message is an event waiting in the outbox. Position orders delivery;
+// EventID stays the same across redeliveries so receivers can drop
+// duplicates. DeliveredTo names the subscribers that already handled it,
+// which a retry skips. AggregateID is empty for events without one.
+type Message struct {
+	Position      int64  `gorm:"primaryKey;autoIncrement"`
+	EventID       string `gorm:"type:uuid;uniqueIndex"`
+	EventName     string
+	AggregateID   string `gorm:"not null;default:''"`
+	Payload       string `gorm:"type:jsonb"`
+	OccurredAt    time.Time
+	Attempts      int
+	NextAttemptAt time.Time
+	DeliveredAt   *time.Time
+	DeliveredTo   pq.StringArray `gorm:"type:text[];default:'{}'"`
+	LastError     string
+	CreatedAt     time.Time
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
@@ -0,0 +1,299 @@
+package shared_outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// lease is how long a claimed message is hidden from other relays. A relay
+	// that dies mid-delivery leaves it to be picked up again afterwards.
+	lease = time.Minute
+
+	maxRetryBackoff = time.Hour
+)
+
+// errHeldBack marks a message that was not delivered because an earlier one
+// of its aggregate failed in the same batch.
+var errHeldBack = errors.New("held back behind an earlier message")
+
+// Relay delivers outbox messages to subscribed handlers at least once,
+// through the dispatcher's workers. A failed message is retried with
+// exponential backoff until maxAttempts, after which it stays in the table,
+// undelivered, with its last error. Each retry only runs the subscribers
+// that have not yet handled the message. An aggregate's messages are
+// delivered in order: a later one waits while an earlier one is pending,
+// until that one is delivered or given up on.
+type Relay struct {
+	db           *gorm.DB
+	dispatcher   *shared_event.Dispatcher
+	interval     time.Duration
+	batchSize    int
+	maxAttempts  int
+	retryBackoff time.Duration
+	now          func() time.Time
+	mu           sync.RWMutex
+	eventTypes   map[string]reflect.Type
+}
+
+func NewRelay(db *gorm.DB, dispatcher *shared_event.Dispatcher, interval time.Duration, batchSize, maxAttempts int, retryBackoff time.Duration) *Relay {
+	return &Relay{
+		db:           db,
+		dispatcher:   dispatcher,
+		interval:     interval,
+		batchSize:    batchSize,
+		maxAttempts:  maxAttempts,
+		retryBackoff: retryBackoff,
+		now:          time.Now,
+		eventTypes:   make(map[string]reflect.Type),
+	}
+}
+
+var _ shared_event.Subscriber = &Relay{}
+
+func (r *Relay) Subscribe(subscriber string, prototype shared_event.Event, handler shared_event.DeliveryHandler) {
+	r.mu.Lock()
+	r.eventTypes[prototype.EventName()] = reflect.TypeOf(prototype).Elem()
+	r.mu.Unlock()
+
+	r.dispatcher.Subscribe(subscriber, prototype, func(ctx context.Context, event shared_event.Event) error {
+		receipts, ok := ctx.Value(receiptsKey{}).(*receipts)
+		if !ok {
+			return handler(ctx, event)
+		}
+		if slices.Contains(receipts.delivered, subscriber) {
+			return nil
+		}
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+		receipts.delivered = append(receipts.delivered, subscriber)
+		return nil
+	})
+}
+
+type receiptsKey struct{}
+
+// receipts collects the subscribers that have handled one message, starting
+// from those that did on earlier attempts. The dispatcher runs an event's
+// handlers one after another, and the relay reads it only once they are
+// done.
+type receipts struct {
+	delivered []string
+}
+
+// Run relays until ctx is cancelled, polling every interval while the outbox
+// is drained.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			delivered, err := r.RelayBatch(ctx)
+			if err != nil {
+				log.Printf("outbox relay: %v", err)
+			}
+			if err != nil || delivered < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch claims up to batchSize due messages and dispatches them, then
+// waits for their handlers. Messages are grouped the way the dispatcher
+// orders them; each group is delivered in the order it was added, and once
+// one of its messages fails the rest are released without an attempt. It
+// returns how many it claimed.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	messages, err := r.claim(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim messages: %w", err)
+	}
+
+	// Handlers already running finish even if ctx is cancelled, so shutting
+	// down does not turn a delivery into a failed attempt half-way through.
+	deliverCtx := context.WithoutCancel(ctx)
+	results := make([]error, len(messages))
+	delivered := make([]*receipts, len(messages))
+	for i, message := range messages {
+		delivered[i] = &receipts{delivered: append([]string{}, message.DeliveredTo...)}
+	}
+
+	var wg sync.WaitGroup
+	for _, group := range orderingGroups(messages) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n, i := range group {
+				results[i] = r.deliverAndWait(context.WithValue(deliverCtx, receiptsKey{}, delivered[i]), messages[i])
+				if results[i] != nil {
+					for _, held := range group[n+1:] {
+						results[held] = errHeldBack
+					}
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, message := range messages {
+		switch {
+		case errors.Is(results[i], errHeldBack):
+			r.release(deliverCtx, message)
+		case results[i] != nil:
+			r.fail(deliverCtx, message, delivered[i].delivered, results[i])
+		default:
+			r.complete(deliverCtx, message)
+		}
+	}
+	return len(messages), nil
+}
+
+// orderingGroups splits messages, by index, on the key the dispatcher keeps
+// in order: the aggregate, or the event name for events without one.
+func orderingGroups(messages []*Message) [][]int {
+	var groups [][]int
+	index := make(map[string]int)
+	for i, message := range messages {
+		key := "aggregate:" + message.AggregateID
+		if message.AggregateID == "" {
+			key = "event:" + message.EventName
+		}
+		g, ok := index[key]
+		if !ok {
+			g = len(groups)
+			index[key] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+func (r *Relay) claim(ctx context.Context) ([]*Message, error) {
+	var messages []*Message
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := r.now()
+		// A message waits while an earlier one of its aggregate is pending and
+		// not due, such as one leased to another relay or backing off.
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("delivered_at IS NULL AND attempts < ? AND next_attempt_at <= ?", r.maxAttempts, now).
+			Where(`NOT EXISTS (SELECT 1 FROM outbox_messages earlier
+				WHERE earlier.aggregate_id = outbox_messages.aggregate_id AND earlier.aggregate_id <> ''
+				AND earlier.position < outbox_messages.position AND earlier.delivered_at IS NULL
+				AND earlier.attempts < ? AND earlier.next_attempt_at > ?)`, r.maxAttempts, now).
+			Order("position").
+			Limit(r.batchSize).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		positions := make([]int64, 0, len(messages))
+		for _, message := range messages {
+			positions = append(positions, message.Position)
+		}
+		return tx.Model(&Message{}).Where("position IN ?", positions).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return messages, err
+}
+
+// deliverAndWait delivers message and returns once its handlers have run.
+func (r *Relay) deliverAndWait(ctx context.Context, message *Message) error {
+	result := make(chan error, 1)
+	if err := r.deliver(ctx, message, func(err error) { result <- err }); err != nil {
+		return err
+	}
+	return <-result
+}
+
+// deliver decodes message and hands it to the dispatcher, which calls done
+// once the handlers have run. A message nobody subscribed to is done at once.
+func (r *Relay) deliver(ctx context.Context, message *Message, done func(error)) error {
+	r.mu.RLock()
+	eventType, ok := r.eventTypes[message.EventName]
+	r.mu.RUnlock()
+	if !ok {
+		done(nil)
+		return nil
+	}
+
+	event := reflect.New(eventType).Interface().(shared_event.Event)
+	if err := json.Unmarshal([]byte(message.Payload), event); err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	return r.dispatcher.Dispatch(shared_event.WithEventID(ctx, message.EventID), event, done)
+}
+
+func (r *Relay) complete(ctx context.Context, message *Message) {
+	err := r.db.WithContext(ctx).Model(&Message{}).
+		Where("position = ?", message.Position).
+		Update("delivered_at", r.now()).Error
+	if err != nil {
+		log.Printf("outbox relay: failed to mark %s %s delivered: %v", message.EventName, message.EventID, err)
+	}
+}
+
+// release ends the lease on a held back message without counting an attempt.
+// The claim skips it until the message it waits for is delivered or due.
+func (r *Relay) release(ctx context.Context, message *Message) {
+	err := r.db.WithContext(ctx).Model(&Message{}).
+		Where("position = ?", message.Position).
+		Update("next_attempt_at", r.now()).Error
+	if err != nil {
+		log.Printf("outbox relay: failed to release %s %s: %v", message.EventName, message.EventID, err)
+	}
+}
+
+// fail schedules message again, remembering which subscribers took it.
+func (r *Relay) fail(ctx context.Context, message *Message, deliveredTo []string, cause error) {
+	attempts := message.Attempts + 1
+	if attempts >= r.maxAttempts {
+		log.Printf("outbox relay: giving up on %s %s after %d attempts: %v", message.EventName, message.EventID, attempts, cause)
+	} else {
+		log.Printf("outbox relay: delivery of %s %s failed (attempt %d): %v", message.EventName, message.EventID, attempts, cause)
+	}
+
+	err := r.db.WithContext(ctx).Model(&Message{}).
+		Where("position = ?", message.Position).
+		Updates(map[string]any{
+			"attempts":        attempts,
+			"next_attempt_at": r.now().Add(r.backoff(attempts)),
+			"last_error":      cause.Error(),
+			"delivered_to":    pq.StringArray(deliveredTo),
+		}).Error
+	if err != nil {
+		log.Printf("outbox relay: failed to reschedule %s %s: %v", message.EventName, message.EventID, err)
+	}
+}
+
+// backoff doubles the retry delay with every attempt, up to maxRetryBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.retryBackoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
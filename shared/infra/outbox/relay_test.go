@@ -0,0 +1,322 @@
+package shared_outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	shared_database "github.com/williamkoller/system-education/shared/infra/database"
+	shared_eventstore "github.com/williamkoller/system-education/shared/infra/eventstore"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type accountOpened struct {
+	AccountID string
+	Date      time.Time
+}
+
+func (e *accountOpened) EventName() string     { return "account.opened" }
+func (e *accountOpened) OccurredOn() time.Time { return e.Date }
+
+type accountClosed struct {
+	AccountID string
+	Date      time.Time
+}
+
+func (e *accountClosed) EventName() string     { return "account.closed" }
+func (e *accountClosed) OccurredOn() time.Time { return e.Date }
+
+type orderPlaced struct {
+	OrderID string
+}
+
+func (e *orderPlaced) EventName() string     { return "order.placed" }
+func (e *orderPlaced) OccurredOn() time.Time { return time.Time{} }
+func (e *orderPlaced) AggregateID() string   { return e.OrderID }
+
+type orderShipped struct {
+	OrderID string
+}
+
+func (e *orderShipped) EventName() string     { return "order.shipped" }
+func (e *orderShipped) OccurredOn() time.Time { return time.Time{} }
+func (e *orderShipped) AggregateID() string   { return e.OrderID }
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// Each pooled connection would open its own in-memory database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&Message{}, &shared_eventstore.StoredEvent{}))
+	return db
+}
+
+func newDispatcher(t *testing.T) *shared_event.Dispatcher {
+	dispatcher := shared_event.NewDispatcher(2, 10)
+	t.Cleanup(func() { _ = dispatcher.Shutdown(context.Background()) })
+	return dispatcher
+}
+
+func messages(t *testing.T, db *gorm.DB) []*Message {
+	var found []*Message
+	require.NoError(t, db.Order("position").Find(&found).Error)
+	return found
+}
+
+func TestGormOutbox_Add(t *testing.T) {
+	db := setupTestDB(t)
+	outbox := NewGormOutbox(db)
+	occurred := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("is part of the caller's transaction", func(t *testing.T) {
+		err := shared_database.NewGormTransactor(db).WithinTransaction(context.Background(), func(ctx context.Context) error {
+			if err := outbox.Add(ctx, &accountOpened{AccountID: "acc-1", Date: occurred}); err != nil {
+				return err
+			}
+			return errors.New("rolled back")
+		})
+
+		assert.Error(t, err)
+		assert.Empty(t, messages(t, db))
+	})
+
+	t.Run("stores the encoded events in order", func(t *testing.T) {
+		require.NoError(t, outbox.Add(context.Background(),
+			&accountOpened{AccountID: "acc-1", Date: occurred},
+			&accountClosed{AccountID: "acc-1", Date: occurred},
+		))
+
+		stored := messages(t, db)
+		require.Len(t, stored, 2)
+		assert.Equal(t, "account.opened", stored[0].EventName)
+		assert.Equal(t, "account.closed", stored[1].EventName)
+		assert.JSONEq(t, `{"AccountID": "acc-1", "Date": "2026-01-01T12:00:00Z"}`, stored[0].Payload)
+		assert.True(t, occurred.Equal(stored[0].OccurredAt))
+		assert.NotEqual(t, stored[0].EventID, stored[1].EventID)
+	})
+
+	t.Run("stores the aggregate of events that have one", func(t *testing.T) {
+		db := setupTestDB(t)
+		require.NoError(t, NewGormOutbox(db).Add(context.Background(), &orderPlaced{OrderID: "order-1"}, &accountOpened{AccountID: "acc-1"}))
+
+		stored := messages(t, db)
+		require.Len(t, stored, 2)
+		assert.Equal(t, "order-1", stored[0].AggregateID)
+		assert.Empty(t, stored[1].AggregateID)
+	})
+
+	t.Run("appends the events to the store under the same ids", func(t *testing.T) {
+		history, err := shared_eventstore.NewGormEventStore(db).Find(context.Background(), shared_event.EventFilter{})
+		require.NoError(t, err)
+
+		stored := messages(t, db)
+		require.Len(t, history, 2)
+		assert.Equal(t, stored[0].EventID, history[0].ID)
+		assert.Equal(t, stored[1].EventID, history[1].ID)
+		assert.Equal(t, stored[0].Payload, history[0].Payload)
+	})
+}
+
+func TestRelay_RelayBatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delivers decoded events once", func(t *testing.T) {
+		db := setupTestDB(t)
+		relay := NewRelay(db, newDispatcher(t), time.Second, 10, 3, time.Second)
+
+		var received, eventIDs []string
+		relay.Subscribe("test", &accountOpened{}, func(ctx context.Context, event shared_event.Event) error {
+			received = append(received, event.(*accountOpened).AccountID)
+			eventIDs = append(eventIDs, shared_event.EventIDFrom(ctx))
+			return nil
+		})
+		require.NoError(t, NewGormOutbox(db).Add(ctx, &accountOpened{AccountID: "acc-1"}, &accountClosed{AccountID: "acc-1"}, &accountOpened{AccountID: "acc-2"}))
+
+		claimed, err := relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, claimed)
+		assert.Equal(t, []string{"acc-1", "acc-2"}, received)
+		stored := messages(t, db)
+		assert.Equal(t, []string{stored[0].EventID, stored[2].EventID}, eventIDs)
+		for _, message := range messages(t, db) {
+			assert.NotNil(t, message.DeliveredAt, "events without subscribers are delivered too")
+		}
+
+		claimed, err = relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, claimed)
+	})
+
+	t.Run("retries failures with backoff and gives up after max attempts", func(t *testing.T) {
+		db := setupTestDB(t)
+		relay := NewRelay(db, newDispatcher(t), time.Second, 10, 3, time.Second)
+		now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		relay.now = func() time.Time { return now }
+
+		calls := 0
+		relay.Subscribe("test", &accountOpened{}, func(context.Context, shared_event.Event) error {
+			calls++
+			return errors.New("mail server down")
+		})
+		require.NoError(t, NewGormOutbox(db).Add(ctx, &accountOpened{AccountID: "acc-1"}))
+		require.NoError(t, db.Model(&Message{}).Where("1 = 1").Update("next_attempt_at", now).Error)
+
+		_, err := relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		stored := messages(t, db)[0]
+		assert.Equal(t, 1, stored.Attempts)
+		assert.Equal(t, "mail server down", stored.LastError)
+		assert.True(t, now.Add(time.Second).Equal(stored.NextAttemptAt))
+
+		claimed, _ := relay.RelayBatch(ctx)
+		assert.Zero(t, claimed, "not due before the backoff elapses")
+
+		now = now.Add(time.Second)
+		_, _ = relay.RelayBatch(ctx)
+		assert.True(t, now.Add(2*time.Second).Equal(messages(t, db)[0].NextAttemptAt))
+
+		now = now.Add(2 * time.Second)
+		_, _ = relay.RelayBatch(ctx)
+		now = now.Add(time.Hour)
+		claimed, _ = relay.RelayBatch(ctx)
+
+		assert.Zero(t, claimed)
+		assert.Equal(t, 3, calls)
+		stored = messages(t, db)[0]
+		assert.Equal(t, 3, stored.Attempts)
+		assert.Nil(t, stored.DeliveredAt)
+	})
+
+	t.Run("retries only the subscribers that failed", func(t *testing.T) {
+		db := setupTestDB(t)
+		relay := NewRelay(db, newDispatcher(t), time.Second, 10, 3, time.Second)
+		now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		relay.now = func() time.Time { return now }
+
+		sent, failures := 0, 1
+		relay.Subscribe("mailer", &accountOpened{}, func(context.Context, shared_event.Event) error {
+			sent++
+			return nil
+		})
+		relay.Subscribe("ledger", &accountOpened{}, func(context.Context, shared_event.Event) error {
+			if failures > 0 {
+				failures--
+				return errors.New("ledger down")
+			}
+			return nil
+		})
+		require.NoError(t, NewGormOutbox(db).Add(ctx, &accountOpened{AccountID: "acc-1"}))
+		require.NoError(t, db.Model(&Message{}).Where("1 = 1").Update("next_attempt_at", now).Error)
+
+		_, err := relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		stored := messages(t, db)[0]
+		assert.Nil(t, stored.DeliveredAt)
+		assert.Equal(t, []string{"mailer"}, []string(stored.DeliveredTo))
+
+		now = now.Add(time.Second)
+		_, err = relay.RelayBatch(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, sent, "the mailer already took the event")
+		assert.NotNil(t, messages(t, db)[0].DeliveredAt)
+	})
+
+	t.Run("holds back an aggregate's later messages behind a failed one", func(t *testing.T) {
+		db := setupTestDB(t)
+		relay := NewRelay(db, newDispatcher(t), time.Second, 10, 3, time.Second)
+		now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		relay.now = func() time.Time { return now }
+
+		var mu sync.Mutex
+		var handled []string
+		failures := 1
+		relay.Subscribe("test", &orderPlaced{}, func(_ context.Context, event shared_event.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			id := event.(*orderPlaced).OrderID
+			if id == "order-1" && failures > 0 {
+				failures--
+				return errors.New("warehouse down")
+			}
+			handled = append(handled, "placed "+id)
+			return nil
+		})
+		relay.Subscribe("test", &orderShipped{}, func(_ context.Context, event shared_event.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handled = append(handled, "shipped "+event.(*orderShipped).OrderID)
+			return nil
+		})
+		require.NoError(t, NewGormOutbox(db).Add(ctx, &orderPlaced{OrderID: "order-1"}, &orderShipped{OrderID: "order-1"}, &orderPlaced{OrderID: "order-2"}))
+		require.NoError(t, db.Model(&Message{}).Where("1 = 1").Update("next_attempt_at", now).Error)
+
+		_, err := relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"placed order-2"}, handled, "other aggregates are not held back")
+		stored := messages(t, db)
+		assert.Equal(t, 1, stored[0].Attempts)
+		assert.Nil(t, stored[1].DeliveredAt)
+		assert.Zero(t, stored[1].Attempts, "a held back message is not an attempt")
+		assert.NotNil(t, stored[2].DeliveredAt)
+
+		claimed, err := relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, claimed, "the later message waits while the failed one backs off")
+
+		now = now.Add(time.Second)
+		claimed, err = relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, claimed)
+		assert.Equal(t, []string{"placed order-2", "placed order-1", "shipped order-1"}, handled)
+		for _, message := range messages(t, db) {
+			assert.NotNil(t, message.DeliveredAt)
+		}
+	})
+
+	t.Run("a panicking handler is a failed delivery", func(t *testing.T) {
+		db := setupTestDB(t)
+		relay := NewRelay(db, newDispatcher(t), time.Second, 10, 3, time.Second)
+		relay.Subscribe("test", &accountOpened{}, func(context.Context, shared_event.Event) error {
+			panic("boom")
+		})
+		require.NoError(t, NewGormOutbox(db).Add(ctx, &accountOpened{AccountID: "acc-1"}))
+
+		_, err := relay.RelayBatch(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "handler panicked: boom", messages(t, db)[0].LastError)
+	})
+
+	t.Run("claimed messages are leased", func(t *testing.T) {
+		db := setupTestDB(t)
+		relay := NewRelay(db, newDispatcher(t), time.Second, 10, 3, time.Second)
+		require.NoError(t, NewGormOutbox(db).Add(ctx, &accountOpened{AccountID: "acc-1"}))
+
+		claimed, err := relay.claim(ctx)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		again, err := relay.claim(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, again, "a relay that died mid-delivery is retried after the lease")
+	})
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := NewRelay(nil, nil, time.Second, 10, 30, time.Second)
+
+	assert.Equal(t, time.Second, relay.backoff(1))
+	assert.Equal(t, 8*time.Second, relay.backoff(4))
+	assert.Equal(t, maxRetryBackoff, relay.backoff(25))
+}
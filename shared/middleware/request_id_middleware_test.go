@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(header string) (*httptest.ResponseRecorder, string) {
+		var seen string
+		router := gin.New()
+		router.Use(RequestIDMiddleware())
+		router.GET("/test", func(c *gin.Context) {
+			seen = RequestIDFrom(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		if header != "" {
+			req.Header.Set(RequestIDHeader, header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w, seen
+	}
+
+	t.Run("keeps the caller's id", func(t *testing.T) {
+		w, seen := serve("req-123")
+		assert.Equal(t, "req-123", seen)
+		assert.Equal(t, "req-123", w.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("assigns one when missing or oversized", func(t *testing.T) {
+		w, seen := serve("")
+		assert.NotEmpty(t, seen)
+		assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+
+		_, seen = serve(strings.Repeat("x", maxRequestIDLength+1))
+		assert.Len(t, seen, 36)
+	})
+}
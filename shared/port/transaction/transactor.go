@@ -0,0 +1,10 @@
+package port_transaction
+
+import "context"
+
+// Transactor runs fn in a database transaction, committed when fn returns nil
+// and rolled back otherwise. Repositories called with the ctx given to fn take
+// part in it.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}